@@ -0,0 +1,77 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/palantir/distgo/distgo/affected"
+	"github.com/palantir/distgo/distgo/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	affectedSinceFlagVal string
+
+	affectedCmd = &cobra.Command{
+		Use:   "affected",
+		Short: "Print the IDs of the products affected by changes since a git revision",
+		Long: `Print the IDs of the products affected by the changes made since the merge base of the provided git revision and
+HEAD, including uncommitted and untracked files. A product is affected if a file required to build its main package,
+the input directory of one of its disters, the context directory or Dockerfile of one of its Docker builders or the
+configuration changed, or if one of the products that it depends on is affected. The output can be provided as
+arguments to other commands, such as "build $(./godelw affected --since origin/main)".`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectInfo, projectParam, err := distgoProjectParamFromFlags()
+			if err != nil {
+				return err
+			}
+			configFiles, err := distgoConfigFiles()
+			if err != nil {
+				return err
+			}
+			return affected.Run(projectInfo, projectParam, configFiles, affectedSinceFlagVal, cmd.OutOrStdout())
+		},
+	}
+)
+
+// distgoConfigFiles returns the distgo configuration file and the configuration files that it includes.
+func distgoConfigFiles() ([]string, error) {
+	cfgFile := globalFlagValsAndFactories.DistgoConfigFileFlagVal
+	if cfgFile == "" {
+		return nil, nil
+	}
+	cfgBytes, err := os.ReadFile(cfgFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read configuration file")
+	}
+	includedFiles, err := config.IncludedConfigFiles(cfgFile, cfgBytes)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{cfgFile}, includedFiles...), nil
+}
+
+func init() {
+	affectedCmd.Flags().StringVar(&affectedSinceFlagVal, "since", "", "git revision whose merge base with HEAD is compared to the working tree")
+	if err := affectedCmd.MarkFlagRequired("since"); err != nil {
+		panic(err)
+	}
+	rootCmd.AddCommand(affectedCmd)
+}
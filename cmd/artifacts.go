@@ -18,6 +18,7 @@ import (
 	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/distgo/distgo/artifacts"
 	"github.com/palantir/distgo/distgo/docker"
+	"github.com/palantir/distgo/distgo/productinfo"
 	"github.com/spf13/cobra"
 )
 
@@ -29,37 +30,49 @@ var (
 	artifactsBuildSubcmd = &cobra.Command{
 		Use:   "build [flags] [product-build-ids]",
 		Short: "Print the paths to the build artifacts for products",
+		Long:  "Print the paths to the build artifacts for products.\n\n" + distgo.ProductSelectorHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectInfo, projectParam, err := distgoProjectParamFromFlags()
 			if err != nil {
 				return err
 			}
-			return artifacts.PrintBuildArtifacts(projectInfo, projectParam, distgo.ToProductBuildIDs(args), artifactsAbsPathFlagVal, artifactsRequiresBuildFlagVal, cmd.OutOrStdout())
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
+			return artifacts.PrintBuildArtifacts(projectInfo, projectParam, distgo.ToProductBuildIDs(args), artifactsAbsPathFlagVal, artifactsRequiresBuildFlagVal, productinfo.Format(artifactsFormatFlagVal), cmd.OutOrStdout())
 		},
 	}
 	artifactsDistSubcmd = &cobra.Command{
 		Use:   "dist [flags] [product-dist-ids]",
 		Short: "Print the paths to the distribution artifacts for products",
+		Long:  "Print the paths to the distribution artifacts for products.\n\n" + distgo.ProductSelectorHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectInfo, projectParam, err := distgoProjectParamFromFlags()
 			if err != nil {
 				return err
 			}
-			return artifacts.PrintDistArtifacts(projectInfo, projectParam, distgo.ToProductDistIDs(args), artifactsAbsPathFlagVal, cmd.OutOrStdout())
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
+			return artifacts.PrintDistArtifacts(projectInfo, projectParam, distgo.ToProductDistIDs(args), artifactsAbsPathFlagVal, productinfo.Format(artifactsFormatFlagVal), cmd.OutOrStdout())
 		},
 	}
 	artifactsDockerSubcmd = &cobra.Command{
 		Use:   "docker [flags] [product-docker-ids]",
 		Short: "Print the tags for the Docker images for products",
+		Long:  "Print the tags for the Docker images for products followed by the paths to the archives exported for the Docker images.\n\n" + distgo.ProductSelectorHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectInfo, projectParam, err := distgoProjectParamFromFlags()
 			if err != nil {
 				return err
 			}
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
 			if artifactsDockerRepositoryFlagVal != "" {
 				docker.SetDockerRepository(projectParam, artifactsDockerRepositoryFlagVal)
 			}
-			return artifacts.PrintDockerArtifacts(projectInfo, projectParam, distgo.ToProductDockerIDs(args), cmd.OutOrStdout())
+			return artifacts.PrintDockerArtifacts(projectInfo, projectParam, distgo.ToProductDockerIDs(args), artifactsAbsPathFlagVal, productinfo.Format(artifactsFormatFlagVal), cmd.OutOrStdout())
 		},
 	}
 )
@@ -68,17 +81,22 @@ var (
 	artifactsAbsPathFlagVal          bool
 	artifactsRequiresBuildFlagVal    bool
 	artifactsDockerRepositoryFlagVal string
+	artifactsFormatFlagVal           string
 )
 
 func init() {
 	artifactsBuildSubcmd.Flags().BoolVar(&artifactsAbsPathFlagVal, "absolute", false, "print the absolute path for artifacts")
 	artifactsBuildSubcmd.Flags().BoolVar(&artifactsRequiresBuildFlagVal, "requires-build", false, "only prints the artifacts that require building (omits artifacts that are already built and are up-to-date)")
+	artifactsBuildSubcmd.Flags().StringVar(&artifactsFormatFlagVal, "format", string(productinfo.FormatText), "output format (text or json)")
 	artifactsCmd.AddCommand(artifactsBuildSubcmd)
 
 	artifactsDistSubcmd.Flags().BoolVar(&artifactsAbsPathFlagVal, "absolute", false, "print the absolute path for artifacts")
+	artifactsDistSubcmd.Flags().StringVar(&artifactsFormatFlagVal, "format", string(productinfo.FormatText), "output format (text or json)")
 	artifactsCmd.AddCommand(artifactsDistSubcmd)
 
 	artifactsDockerSubcmd.Flags().StringVar(&artifactsDockerRepositoryFlagVal, "repository", "", "specifies the value that should be used for the Docker repository (overrides any value(s) specified in configuration)")
+	artifactsDockerSubcmd.Flags().BoolVar(&artifactsAbsPathFlagVal, "absolute", false, "print the absolute path for artifacts")
+	artifactsDockerSubcmd.Flags().StringVar(&artifactsFormatFlagVal, "format", string(productinfo.FormatText), "output format (text or json)")
 	artifactsCmd.AddCommand(artifactsDockerSubcmd)
 
 	rootCmd.AddCommand(artifactsCmd)
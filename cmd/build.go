@@ -17,6 +17,7 @@ package cmd
 import (
 	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/distgo/distgo/build"
+	"github.com/palantir/distgo/distgo/build/sizereport"
 	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
@@ -26,11 +27,21 @@ var (
 	buildCmd = &cobra.Command{
 		Use:   "build [flags] [product-build-ids]",
 		Short: "Build the executables for products",
+		Long:  "Build the executables for products.\n\n" + distgo.ProductSelectorHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectInfo, projectParam, err := distgoProjectParamFromFlags()
 			if err != nil {
 				return err
 			}
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
+			if len(buildCoverpkgFlagVal) > 0 && !buildCoverFlagVal {
+				return errors.Errorf("--coverpkg can only be specified with --cover")
+			}
+			if buildSizeReportPackagesFlagVal && buildSizeReportFlagVal == "" {
+				return errors.Errorf("--size-report-packages can only be specified with --size-report")
+			}
 			var osArchs []osarch.OSArch
 			for _, osArchStr := range buildOSArchsFlagVal {
 				osArchVal, err := osarch.New(osArchStr)
@@ -40,24 +51,89 @@ var (
 				osArchs = append(osArchs, osArchVal)
 			}
 			return build.Products(projectInfo, projectParam, distgo.ToProductBuildIDs(args), build.Options{
-				Parallel: buildParallelFlagVal,
-				DryRun:   buildDryRunFlagVal,
-				OSArchs:  osArchs,
+				Parallel:           buildParallelFlagVal,
+				DryRun:             buildDryRunFlagVal,
+				OSArchs:            osArchs,
+				Cover:              buildCoverFlagVal,
+				Coverpkg:           buildCoverpkgFlagVal,
+				SizeReport:         buildSizeReportFlagVal,
+				SizeReportPackages: buildSizeReportPackagesFlagVal,
 			}, cmd.OutOrStdout())
 		},
 	}
+
+	sizeReportPreviousFlagVal string
+	sizeReportOutputFlagVal   string
+	sizeReportPackagesFlagVal bool
+
+	buildSizeReportSubCmd = &cobra.Command{
+		Use:   "size-report [flags] [product-build-ids]",
+		Short: "Report the sizes of the build outputs of products",
+		Long: `Report the sizes of the existing build outputs of products. If the "--previous" flag specifies a size report
+written by a previous build or run of this task, the size of every output is compared with its previous size. If the
+"--output" flag is specified, the report is written to the specified file as JSON.
+
+` + distgo.ProductSelectorHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if sizeReportPackagesFlagVal && sizeReportOutputFlagVal == "" {
+				return errors.Errorf("--packages can only be specified with --output")
+			}
+			projectInfo, projectParam, err := distgoProjectParamFromFlags()
+			if err != nil {
+				return err
+			}
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
+			productParams, err := distgo.ProductParamsForBuildProductArgs(projectParam.Products, nil, distgo.ToProductBuildIDs(args)...)
+			if err != nil {
+				return err
+			}
+			var previous *sizereport.Report
+			if sizeReportPreviousFlagVal != "" {
+				previousReport, err := sizereport.Read(sizeReportPreviousFlagVal)
+				if err != nil {
+					return err
+				}
+				previous = &previousReport
+			}
+			current, err := sizereport.New(projectInfo, productParams, sizeReportPackagesFlagVal)
+			if err != nil {
+				return err
+			}
+			if sizeReportOutputFlagVal != "" {
+				if err := current.Write(sizeReportOutputFlagVal); err != nil {
+					return err
+				}
+			}
+			return sizereport.PrintComparison(cmd.OutOrStdout(), previous, current)
+		},
+	}
 )
 
 var (
-	buildParallelFlagVal bool
-	buildOSArchsFlagVal  []string
-	buildDryRunFlagVal   bool
+	buildParallelFlagVal           bool
+	buildOSArchsFlagVal            []string
+	buildDryRunFlagVal             bool
+	buildCoverFlagVal              bool
+	buildCoverpkgFlagVal           []string
+	buildSizeReportFlagVal         string
+	buildSizeReportPackagesFlagVal bool
 )
 
 func init() {
 	buildCmd.Flags().BoolVar(&buildParallelFlagVal, "parallel", true, "build binaries in parallel")
 	buildCmd.Flags().StringSliceVar(&buildOSArchsFlagVal, "os-arch", nil, "if specified, only builds the binaries for the specified GOOS-GOARCH(s)")
 	buildCmd.Flags().BoolVar(&buildDryRunFlagVal, "dry-run", false, "print the operations that would be performed")
+	buildCmd.Flags().BoolVar(&buildCoverFlagVal, "cover", false, "build coverage-instrumented binaries in the cover output directory instead of the regular binaries")
+	buildCmd.Flags().StringSliceVar(&buildCoverpkgFlagVal, "coverpkg", nil, "if specified, overrides the coverpkg patterns of the coverage-instrumented builds")
+	buildCmd.Flags().StringVar(&buildSizeReportFlagVal, "size-report", "", "if specified, writes the size report of the build outputs as JSON to this file")
+	buildCmd.Flags().BoolVar(&buildSizeReportPackagesFlagVal, "size-report-packages", false, "attribute the sizes of the build outputs to Go packages in the size report")
+
+	buildSizeReportSubCmd.Flags().StringVar(&sizeReportPreviousFlagVal, "previous", "", "size report to compare the sizes of the build outputs with")
+	buildSizeReportSubCmd.Flags().StringVar(&sizeReportOutputFlagVal, "output", "", "if specified, writes the size report as JSON to this file")
+	buildSizeReportSubCmd.Flags().BoolVar(&sizeReportPackagesFlagVal, "packages", false, "attribute the sizes of the build outputs to Go packages in the written report")
+	buildCmd.AddCommand(buildSizeReportSubCmd)
 
 	rootCmd.AddCommand(buildCmd)
 }
@@ -24,11 +24,15 @@ var (
 	cleanCmd = &cobra.Command{
 		Use:   "clean [flags] [product-ids]",
 		Short: "Remove the build and dist outputs for products",
+		Long:  "Remove the build and dist outputs for products.\n\n" + distgo.ProductSelectorHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectInfo, projectParam, err := distgoProjectParamFromFlags()
 			if err != nil {
 				return err
 			}
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
 			return clean.Products(projectInfo, projectParam, distgo.ToProductIDs(args), cleanDryRunFlagVal, cmd.OutOrStdout())
 		},
 	}
@@ -0,0 +1,149 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config"
+	"github.com/palantir/distgo/distgo/printconfig"
+	"github.com/palantir/distgo/internal/cmdinternal"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the dist-plugin configuration",
+	}
+
+	configSchemaSubCmd = &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema for the dist-plugin configuration",
+		Long: `Print the JSON Schema for the dist-plugin configuration. The schema includes the schemas for the configuration of
+the disters, Docker builders and publishers that are available, including the ones provided by assets.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			schema, err := config.ProjectConfigSchema(
+				globalFlagValsAndFactories.CLIDisterFactory,
+				globalFlagValsAndFactories.CLIDockerBuilderFactory,
+				globalFlagValsAndFactories.CLIPublisherFactory,
+			)
+			if err != nil {
+				return err
+			}
+			schemaJSON, err := json.MarshalIndent(schema, "", "  ")
+			if err != nil {
+				return errors.Wrapf(err, "failed to marshal JSON Schema")
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), string(schemaJSON))
+			return nil
+		},
+	}
+
+	configResolvedFormatFlagVal string
+
+	configResolvedSubCmd = &cobra.Command{
+		Use:   "resolved [flags] [product-ids]",
+		Short: "Print the resolved configuration of products",
+		Long: `Print the effective configuration of products after the product defaults, built-in default values and script
+includes are applied. The source of each value (explicit, product-defaults, default or legacy-upgrade) is printed as
+a comment for YAML output and as a separate "sources" object for JSON output. If no product IDs are provided, the
+configuration of all products is printed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resolved, err := cmdinternal.ResolvedDistgoConfigFromFlagVals(globalFlagValsAndFactories)
+			if err != nil {
+				return err
+			}
+			return printconfig.Run(resolved, distgo.ToProductIDs(args), printconfig.Format(configResolvedFormatFlagVal), cmd.OutOrStdout())
+		},
+	}
+
+	configValidateSubCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the dist-plugin configuration",
+		Long: `Validate the dist-plugin configuration and the configuration files that it includes. Unknown keys, duplicate keys
+and values of the wrong type are reported with their position in the configuration file. If the configuration is
+structurally valid, it is also checked for semantic errors such as references to products that do not exist.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfgFile := globalFlagValsAndFactories.DistgoConfigFileFlagVal
+			var cfgBytes []byte
+			if cfgFile != "" {
+				bytes, err := os.ReadFile(cfgFile)
+				if err != nil && !os.IsNotExist(err) {
+					return errors.Wrapf(err, "failed to read configuration file")
+				}
+				cfgBytes = bytes
+			}
+			numErrs, err := validateConfigFile(cmd, cfgFile, cfgBytes)
+			if err != nil {
+				return err
+			}
+			if cfgFile != "" {
+				// included files are validated when the configuration file can be parsed
+				includedFiles, _ := config.IncludedConfigFiles(cfgFile, cfgBytes)
+				for _, includedFile := range includedFiles {
+					includedBytes, err := os.ReadFile(includedFile)
+					if err != nil {
+						return errors.Wrapf(err, "failed to read included configuration file")
+					}
+					currNumErrs, err := validateConfigFile(cmd, includedFile, includedBytes)
+					if err != nil {
+						return err
+					}
+					numErrs += currNumErrs
+				}
+			}
+			if numErrs > 0 {
+				return errors.Errorf("configuration is invalid: %d error(s)", numErrs)
+			}
+			if _, _, err := distgoProjectParamFromFlags(); err != nil {
+				return errors.Wrapf(err, "configuration is invalid")
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Configuration is valid")
+			return nil
+		},
+	}
+)
+
+// validateConfigFile prints the structural errors of the provided configuration file and returns the number of errors.
+func validateConfigFile(cmd *cobra.Command, cfgFile string, cfgBytes []byte) (int, error) {
+	validationErrs, err := config.ValidateConfig(
+		cfgBytes,
+		globalFlagValsAndFactories.CLIDisterFactory,
+		globalFlagValsAndFactories.CLIDockerBuilderFactory,
+		globalFlagValsAndFactories.CLIPublisherFactory,
+	)
+	if err != nil {
+		return 0, err
+	}
+	for _, validationErr := range validationErrs {
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s:%v\n", cfgFile, validationErr)
+	}
+	return len(validationErrs), nil
+}
+
+func init() {
+	configCmd.AddCommand(configSchemaSubCmd)
+
+	configResolvedSubCmd.Flags().StringVar(&configResolvedFormatFlagVal, "format", string(printconfig.FormatYAML), "output format (yaml or json)")
+	configCmd.AddCommand(configResolvedSubCmd)
+	configCmd.AddCommand(configValidateSubCmd)
+
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/palantir/distgo/distgo/cover"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	coverCmd = &cobra.Command{
+		Use:   "cover",
+		Short: "Process the coverage data of coverage-instrumented builds",
+	}
+
+	coverMergeInputFlagVal       []string
+	coverMergeOutputFlagVal      string
+	coverMergeTextProfileFlagVal string
+
+	coverMergeSubCmd = &cobra.Command{
+		Use:   "merge [flags]",
+		Short: "Merge and summarize the coverage data written by coverage-instrumented builds",
+		Long: `Merge the coverage data that executables created by "build --cover" wrote to their GOCOVERDIR directories and
+print the coverage percentage of each package.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if coverMergeOutputFlagVal == "" {
+				return errors.Errorf("--output must be specified")
+			}
+			return cover.Merge(coverMergeInputFlagVal, coverMergeOutputFlagVal, coverMergeTextProfileFlagVal, cmd.OutOrStdout())
+		},
+	}
+)
+
+func init() {
+	coverMergeSubCmd.Flags().StringSliceVar(&coverMergeInputFlagVal, "input", nil, "GOCOVERDIR directories that contain the coverage data to merge")
+	coverMergeSubCmd.Flags().StringVar(&coverMergeOutputFlagVal, "output", "", "directory to which the merged coverage data is written")
+	coverMergeSubCmd.Flags().StringVar(&coverMergeTextProfileFlagVal, "text-profile", "", "if specified, the merged coverage data is also written to this file in the text format used by \"go tool cover\"")
+	coverCmd.AddCommand(coverMergeSubCmd)
+	rootCmd.AddCommand(coverCmd)
+}
@@ -26,11 +26,15 @@ var (
 	distCmd = &cobra.Command{
 		Use:   "dist [flags] [product-dist-ids]",
 		Short: "Create distributions for products",
+		Long:  "Create distributions for products.\n\n" + distgo.ProductSelectorHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectInfo, projectParam, err := distgoProjectParamFromFlags()
 			if err != nil {
 				return err
 			}
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
 
 			var configFileModTime *time.Time
 			if !distForceFlagVal {
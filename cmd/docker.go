@@ -23,43 +23,68 @@ import (
 var (
 	dockerCmd = &cobra.Command{
 		Use:   "docker",
-		Short: "Create or push Docker images for products",
+		Short: "Create, test or push Docker images for products",
 	}
 	dockerBuildSubCmd = &cobra.Command{
 		Use:   "build [flags] [product-docker-ids]",
 		Short: "Create Docker images for products",
+		Long:  "Create Docker images for products.\n\n" + distgo.ProductSelectorHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectInfo, projectParam, err := distgoProjectParamFromFlags()
 			if err != nil {
 				return err
 			}
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
 			if dockerBuildRepositoryFlagVal != "" {
 				docker.SetDockerRepository(projectParam, dockerBuildRepositoryFlagVal)
 			}
-			return docker.BuildProducts(projectInfo, projectParam, distgoConfigModTime(), distgo.ToProductDockerIDs(args), dockerBuildTagKeysFlagVal, dockerBuildVerboseFlagVal, dockerBuildDryRunFlagVal, cmd.OutOrStdout())
+			return docker.BuildProducts(projectInfo, projectParam, distgoConfigModTime(), distgo.ToProductDockerIDs(args), dockerBuildTagKeysFlagVal, dockerBuildVerboseFlagVal, dockerBuildDryRunFlagVal, dockerBuildParallelFlagVal, dockerBuildConcurrencyFlagVal, cmd.OutOrStdout())
 		},
 	}
 	dockerPushSubCmd = &cobra.Command{
 		Use:   "push [flags] [product-docker-ids]",
 		Short: "Push Docker images for products",
+		Long:  "Push Docker images for products.\n\n" + distgo.ProductSelectorHelp,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			projectInfo, projectParam, err := distgoProjectParamFromFlags()
 			if err != nil {
 				return err
 			}
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
 			if dockerPushRepositoryFlagVal != "" {
 				docker.SetDockerRepository(projectParam, dockerPushRepositoryFlagVal)
 			}
 			return docker.PushProducts(projectInfo, projectParam, distgo.ToProductDockerIDs(args), dockerPushTagKeysFlagVal, dockerPushDryRunFlagVal, dockerPushInsecureFlagVal, cmd.OutOrStdout())
 		},
 	}
+	dockerTestSubCmd = &cobra.Command{
+		Use:   "test [flags] [product-docker-ids]",
+		Short: "Run the image tests declared for Docker images of products against their OCI layouts",
+		Long:  "Run the image tests declared for Docker images of products against their OCI layouts.\n\n" + distgo.ProductSelectorHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectInfo, projectParam, err := distgoProjectParamFromFlags()
+			if err != nil {
+				return err
+			}
+			if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+				return err
+			}
+			return docker.TestProducts(projectInfo, projectParam, distgo.ToProductDockerIDs(args), cmd.OutOrStdout())
+		},
+	}
 )
 
 var (
-	dockerBuildRepositoryFlagVal string
-	dockerBuildVerboseFlagVal    bool
-	dockerBuildDryRunFlagVal     bool
-	dockerBuildTagKeysFlagVal    []string
+	dockerBuildRepositoryFlagVal  string
+	dockerBuildVerboseFlagVal     bool
+	dockerBuildDryRunFlagVal      bool
+	dockerBuildParallelFlagVal    bool
+	dockerBuildConcurrencyFlagVal int
+	dockerBuildTagKeysFlagVal     []string
 
 	dockerPushRepositoryFlagVal string
 	dockerPushDryRunFlagVal     bool
@@ -71,15 +96,19 @@ func init() {
 	addRepositoryFlag(dockerBuildSubCmd, &dockerBuildRepositoryFlagVal)
 	dockerBuildSubCmd.Flags().BoolVar(&dockerBuildVerboseFlagVal, "verbose", false, "print verbose output for the operation")
 	addDryRunFlag(dockerBuildSubCmd, &dockerBuildDryRunFlagVal)
+	dockerBuildSubCmd.Flags().BoolVar(&dockerBuildParallelFlagVal, "parallel", false, "build Docker images that do not depend on each other in parallel")
+	dockerBuildSubCmd.Flags().IntVar(&dockerBuildConcurrencyFlagVal, "concurrency", 0, "maximum number of Docker images built at the same time when --parallel is specified (defaults to the number of logical processors)")
 	addTagKeysFlag(dockerBuildSubCmd, &dockerBuildTagKeysFlagVal)
 	dockerCmd.AddCommand(dockerBuildSubCmd)
 
 	addRepositoryFlag(dockerPushSubCmd, &dockerPushRepositoryFlagVal)
 	addDryRunFlag(dockerPushSubCmd, &dockerPushDryRunFlagVal)
 	addTagKeysFlag(dockerPushSubCmd, &dockerPushTagKeysFlagVal)
-	dockerPushSubCmd.Flags().BoolVar(&dockerPushInsecureFlagVal, "insecure", false, "allow push to insecure Docker registries")
+	dockerPushSubCmd.Flags().BoolVar(&dockerPushInsecureFlagVal, "insecure", false, "allow push to insecure Docker registries (applies to all registries; use the registries configuration to allow specific registries)")
 	dockerCmd.AddCommand(dockerPushSubCmd)
 
+	dockerCmd.AddCommand(dockerTestSubCmd)
+
 	rootCmd.AddCommand(dockerCmd)
 }
 
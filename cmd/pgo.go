@@ -0,0 +1,61 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/pgo"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pgoCmd = &cobra.Command{
+		Use:   "pgo",
+		Short: "Manage the profile-guided optimization profiles of products",
+	}
+
+	pgoMergeProfilesDirFlagVal string
+
+	pgoMergeSubCmd = &cobra.Command{
+		Use:   "merge [flags] [product-id]",
+		Short: "Merge CPU profiles into the profile-guided optimization profile of a product",
+		Long: `Merge the CPU profiles in the directory specified by the "--profiles-dir" flag into the profile used for the
+profile-guided optimization of the specified product. The profile is the "pgo-profile" specified in the build
+configuration of the product or, if a profile is not specified, the "default.pgo" file in the directory of the main
+package. Any existing content of the profile is replaced.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if pgoMergeProfilesDirFlagVal == "" {
+				return errors.Errorf("--profiles-dir must be specified")
+			}
+			projectInfo, projectParam, err := distgoProjectParamFromFlags()
+			if err != nil {
+				return err
+			}
+			productParams, err := distgo.ProductParamsForProductArgs(projectParam.Products, distgo.ProductID(args[0]))
+			if err != nil {
+				return err
+			}
+			return pgo.Merge(projectInfo, productParams[0], pgoMergeProfilesDirFlagVal, cmd.OutOrStdout())
+		},
+	}
+)
+
+func init() {
+	pgoMergeSubCmd.Flags().StringVar(&pgoMergeProfilesDirFlagVal, "profiles-dir", "", "directory that contains the CPU profiles to merge")
+	pgoCmd.AddCommand(pgoMergeSubCmd)
+	rootCmd.AddCommand(pgoCmd)
+}
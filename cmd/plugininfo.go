@@ -33,15 +33,20 @@ var (
 			pluginapi.GlobalFlagOptionsParamGodelConfigFlag("--"+pluginapi.GodelConfigFlagName),
 			pluginapi.GlobalFlagOptionsParamConfigFlag("--"+pluginapi.ConfigFlagName),
 		),
+		newTaskInfoFromCmd(affectedCmd),
 		newTaskInfoFromCmd(artifactsCmd),
 		newTaskInfoFromCmd(buildCmd),
 		newTaskInfoFromCmd(cleanCmd),
+		newTaskInfoFromCmd(configCmd),
+		newTaskInfoFromCmd(coverCmd),
 		newTaskInfoFromCmd(distCmd),
 		newTaskInfoFromCmd(dockerCmd),
+		newTaskInfoFromCmd(pgoCmd),
 		newTaskInfoFromCmd(productMavenCoordCmd),
 		newTaskInfoFromCmd(productsCmd),
 		newTaskInfoFromCmd(projectVersionCmd),
 		newTaskInfoFromCmd(publishCmd),
+		newTaskInfoFromCmd(releaseCmd),
 		newTaskInfoFromCmd(runCmd),
 		newTaskInfoFromCmd(distgoTaskCmd,
 			pluginapi.TaskInfoVerifyOptions(
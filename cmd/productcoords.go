@@ -16,10 +16,13 @@ package cmd
 
 import (
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/productinfo"
 	"github.com/palantir/distgo/distgo/productmavencoord"
 	"github.com/spf13/cobra"
 )
 
+var productMavenCoordFormatFlagVal string
+
 var productMavenCoordCmd = &cobra.Command{
 	Use:   "product-maven-coord [product-ids]",
 	Short: "Print the maven coordinate(s) of the specified product(s) in this project",
@@ -28,10 +31,11 @@ var productMavenCoordCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		return productmavencoord.Run(projectInfo, projectParam, distgo.ToProductIDs(args), cmd.OutOrStdout())
+		return productmavencoord.Run(projectInfo, projectParam, distgo.ToProductIDs(args), productinfo.Format(productMavenCoordFormatFlagVal), cmd.OutOrStdout())
 	},
 }
 
 func init() {
+	productMavenCoordCmd.Flags().StringVar(&productMavenCoordFormatFlagVal, "format", string(productinfo.FormatText), "output format (text or json)")
 	rootCmd.AddCommand(productMavenCoordCmd)
 }
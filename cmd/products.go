@@ -15,22 +15,56 @@
 package cmd
 
 import (
+	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/distgo/distgo/printproducts"
+	"github.com/palantir/distgo/distgo/productgraph"
+	"github.com/palantir/distgo/distgo/productinfo"
+	"github.com/palantir/distgo/internal/cmdinternal"
 	"github.com/spf13/cobra"
 )
 
+var productsFormatFlagVal string
+
 var productsCmd = &cobra.Command{
-	Use:   "products",
+	Use:   "products [flags] [product-ids]",
 	Short: "Print the IDs of the products in this project",
+	Long:  "Print the IDs of the products in this project.\n\n" + distgo.ProductSelectorHelp,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		_, projectParam, err := distgoProjectParamFromFlags()
+		projectInfo, projectParam, err := distgoProjectParamFromFlags()
 		if err != nil {
 			return err
 		}
-		return printproducts.Run(projectParam, cmd.OutOrStdout())
+		if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+			return err
+		}
+		return printproducts.Run(projectInfo, projectParam, distgo.ToProductIDs(args), productinfo.Format(productsFormatFlagVal), cmd.OutOrStdout())
 	},
 }
 
+var (
+	productsGraphFormatFlagVal string
+
+	productsGraphSubCmd = &cobra.Command{
+		Use:   "graph",
+		Short: "Print the graph of the products in this project",
+		Long: `Print the graph of the products in this project in the Graphviz DOT language or as a Mermaid flowchart. The edges
+are the dependencies of products, the builds and dists of other products that are inputs to their Docker images and the
+Docker images of dependencies that are used in the FROM instructions of their Dockerfiles. If the dependencies contain
+a cycle, the graph is still printed and the path of the cycle is highlighted.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			projectInfo, projectParam, err := cmdinternal.DistgoGraphProjectParamFromFlagVals(globalFlagValsAndFactories)
+			if err != nil {
+				return err
+			}
+			return productgraph.Run(projectInfo, projectParam.Products, productgraph.Format(productsGraphFormatFlagVal), cmd.OutOrStdout())
+		},
+	}
+)
+
 func init() {
+	productsCmd.Flags().StringVar(&productsFormatFlagVal, "format", string(productinfo.FormatText), "output format (text or json)")
+	productsGraphSubCmd.Flags().StringVar(&productsGraphFormatFlagVal, "format", string(productgraph.FormatDOT), "output format (dot or mermaid)")
+	productsCmd.AddCommand(productsGraphSubCmd)
 	rootCmd.AddCommand(productsCmd)
 }
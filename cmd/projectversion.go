@@ -15,10 +15,13 @@
 package cmd
 
 import (
+	"github.com/palantir/distgo/distgo/productinfo"
 	"github.com/palantir/distgo/distgo/projectversion"
 	"github.com/spf13/cobra"
 )
 
+var projectVersionFormatFlagVal string
+
 var projectVersionCmd = &cobra.Command{
 	Use:   "project-version",
 	Short: "Print the version of the project",
@@ -27,10 +30,11 @@ var projectVersionCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		return projectversion.Run(projectInfo, cmd.OutOrStdout())
+		return projectversion.Run(projectInfo, productinfo.Format(projectVersionFormatFlagVal), cmd.OutOrStdout())
 	},
 }
 
 func init() {
+	projectVersionCmd.Flags().StringVar(&projectVersionFormatFlagVal, "format", string(productinfo.FormatText), "output format (text or json)")
 	rootCmd.AddCommand(projectVersionCmd)
 }
@@ -27,6 +27,7 @@ var (
 	publishCmd = &cobra.Command{
 		Use:   "publish [action] [flags] [product-dist-ids]",
 		Short: "Publish products",
+		Long:  "Publish products.\n\n" + distgo.ProductSelectorHelp,
 	}
 )
 
@@ -52,6 +53,9 @@ func addPublishSubcommands(publisherTypes []string, publishers []distgo.Publishe
 				if err != nil {
 					return err
 				}
+				if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+					return err
+				}
 				flagVals := make(map[distgo.PublisherFlagName]any)
 				for _, currFlag := range currFlags {
 					// if flag was not explicitly provided, don't add it to the flagVals map
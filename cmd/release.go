@@ -0,0 +1,138 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/release"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	releaseCmd = &cobra.Command{
+		Use:   "release [flags] [products]",
+		Short: "Build, dist and build Docker images for products, optionally pushing the images and publishing the products",
+		Long: `Build, dist and build Docker images for products. If --push is specified, the Docker images are pushed, and if
+a publisher subcommand is used, the dist artifacts are published using that publisher.
+
+All of the build, dist, Docker build, Docker push and publish operations are scheduled as a single graph: an operation
+starts as soon as all of the operations it depends on have completed, up to the number of operations specified by
+--concurrency. If an operation fails, no new operations are started unless --keep-going is specified, in which case
+only the operations that depend on the failed operation are skipped.
+
+` + distgo.ProductSelectorHelp,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRelease(cmd, args, nil, nil)
+		},
+	}
+)
+
+var (
+	releaseConcurrencyFlagVal        int
+	releaseKeepGoingFlagVal          bool
+	releasePushFlagVal               bool
+	releaseInsecureFlagVal           bool
+	releaseDryRunFlagVal             bool
+	releaseForceFlagVal              bool
+	releaseParallelFlagVal           bool
+	releaseSizeReportFlagVal         string
+	releaseSizeReportPackagesFlagVal bool
+)
+
+func init() {
+	releaseCmd.PersistentFlags().IntVar(&releaseConcurrencyFlagVal, "concurrency", 0, "maximum number of operations that run at the same time (if not positive, the number of CPUs is used)")
+	releaseCmd.PersistentFlags().BoolVar(&releaseKeepGoingFlagVal, "keep-going", false, "continue running the operations that do not depend on a failed operation")
+	releaseCmd.PersistentFlags().BoolVar(&releasePushFlagVal, "push", false, "push the Docker images of the products")
+	releaseCmd.PersistentFlags().BoolVar(&releaseInsecureFlagVal, "insecure", false, "allow push to insecure Docker registries")
+	releaseCmd.PersistentFlags().BoolVar(&releaseDryRunFlagVal, "dry-run", false, "print the operations that would be performed")
+	releaseCmd.PersistentFlags().BoolVar(&releaseForceFlagVal, "force", false, "create distribution outputs even if they are considered up-to-date")
+	releaseCmd.PersistentFlags().BoolVar(&releaseParallelFlagVal, "parallel", true, "build binaries in parallel")
+	releaseCmd.PersistentFlags().StringVar(&releaseSizeReportFlagVal, "size-report", "", "if specified, writes the size report of the build outputs as JSON to this file")
+	releaseCmd.PersistentFlags().BoolVar(&releaseSizeReportPackagesFlagVal, "size-report-packages", false, "attribute the sizes of the build outputs to Go packages in the size report")
+
+	rootCmd.AddCommand(releaseCmd)
+}
+
+func addReleaseSubcommands(publisherTypes []string, publishers []distgo.Publisher) {
+	for i, publisher := range publishers {
+		publisherType := publisherTypes[i]
+		currFlags, err := publisher.Flags()
+		if err != nil {
+			panic(errors.Wrapf(err, "failed to get flags for publisher %s", publisherType))
+		}
+		currPublisherSubCmd := &cobra.Command{
+			Use:   fmt.Sprintf("%s [flags] [products]", publisherType),
+			Short: fmt.Sprintf("Release products and publish them using the %s publisher", publisherType),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				flagVals := make(map[distgo.PublisherFlagName]any)
+				for _, currFlag := range currFlags {
+					// if flag was not explicitly provided, don't add it to the flagVals map
+					if !cmd.Flags().Changed(string(currFlag.Name)) {
+						continue
+					}
+					val, err := currFlag.GetFlagValue(cmd.Flags())
+					if err != nil {
+						return err
+					}
+					flagVals[currFlag.Name] = val
+				}
+				return runRelease(cmd, args, publisher, flagVals)
+			},
+		}
+		for _, currFlag := range currFlags {
+			if _, err := currFlag.AddFlag(currPublisherSubCmd.Flags()); err != nil {
+				panic(errors.Wrapf(err, "failed to add flag %v for publisher %s", currFlag, publisherType))
+			}
+		}
+		releaseCmd.AddCommand(currPublisherSubCmd)
+	}
+}
+
+func runRelease(cmd *cobra.Command, args []string, publisher distgo.Publisher, publisherFlagVals map[distgo.PublisherFlagName]any) error {
+	projectInfo, projectParam, err := distgoProjectParamFromFlags()
+	if err != nil {
+		return err
+	}
+	if args, err = distgo.ExpandProductSelectors(projectParam.Products, args); err != nil {
+		return err
+	}
+	if releaseSizeReportPackagesFlagVal && releaseSizeReportFlagVal == "" {
+		return errors.Errorf("--size-report-packages can only be specified with --size-report")
+	}
+	var configFileModTime *time.Time
+	if !releaseForceFlagVal {
+		// if force flag is false, use modification time of configuration file
+		configFileModTime = distgoConfigModTime()
+	}
+	return release.Run(projectInfo, projectParam, distgo.ToProductIDs(args), release.Options{
+		ExecuteOptions: release.ExecuteOptions{
+			Concurrency: releaseConcurrencyFlagVal,
+			KeepGoing:   releaseKeepGoingFlagVal,
+		},
+		DryRun:             releaseDryRunFlagVal,
+		ConfigModTime:      configFileModTime,
+		BuildParallel:      releaseParallelFlagVal,
+		SizeReport:         releaseSizeReportFlagVal,
+		SizeReportPackages: releaseSizeReportPackagesFlagVal,
+		Push:               releasePushFlagVal,
+		Insecure:           releaseInsecureFlagVal,
+		Publisher:          publisher,
+		PublisherFlagVals:  publisherFlagVals,
+	}, cmd.OutOrStdout())
+}
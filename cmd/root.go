@@ -15,12 +15,14 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/palantir/distgo/dister"
 	"github.com/palantir/distgo/dister/disterfactory"
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config"
 	"github.com/palantir/distgo/dockerbuilder"
 	"github.com/palantir/distgo/dockerbuilder/dockerbuilderfactory"
 	"github.com/palantir/distgo/internal/assetapi"
@@ -55,11 +57,13 @@ func restoreRootFlagsFn() func() {
 	origDistgoConfigFileFlagVal := globalFlagValsAndFactories.DistgoConfigFileFlagVal
 	origGodelConfigFileFlagVal := globalFlagValsAndFactories.GodelConfigFileFlagVal
 	origAssetsFlagVal := globalFlagValsAndFactories.AssetsFlagVal
+	origProfilesFlagVal := globalFlagValsAndFactories.ProfilesFlagVal
 	return func() {
 		globalFlagValsAndFactories.ProjectDirFlagVal = origProjectDirFlagVal
 		globalFlagValsAndFactories.DistgoConfigFileFlagVal = origDistgoConfigFileFlagVal
 		globalFlagValsAndFactories.GodelConfigFileFlagVal = origGodelConfigFileFlagVal
 		globalFlagValsAndFactories.AssetsFlagVal = origAssetsFlagVal
+		globalFlagValsAndFactories.ProfilesFlagVal = origProfilesFlagVal
 	}
 }
 
@@ -104,6 +108,7 @@ func init() {
 	pluginapi.AddConfigPFlagPtr(rootCmd.PersistentFlags(), &globalFlagValsAndFactories.DistgoConfigFileFlagVal)
 	pluginapi.AddGodelConfigPFlagPtr(rootCmd.PersistentFlags(), &globalFlagValsAndFactories.GodelConfigFileFlagVal)
 	pluginapi.AddAssetsPFlagPtr(rootCmd.PersistentFlags(), &globalFlagValsAndFactories.AssetsFlagVal)
+	rootCmd.PersistentFlags().StringSliceVar(&globalFlagValsAndFactories.ProfilesFlagVal, "profile", nil, fmt.Sprintf("configuration profiles to apply in order (defaults to the comma-separated value of $%s)", config.ProfileEnvVar))
 
 	// Performs global initialization that can return errors.
 	// The logic in the function is run after the CLI command tree has been set up, so it cannot add or modify state
@@ -173,8 +178,9 @@ func addPublishSubcommandsFromAssets(publisherAssets []string) error {
 		publishers = append(publishers, currPublisher)
 	}
 
-	// add publish commands from assets
+	// add publish and release commands from assets
 	addPublishSubcommands(publisherTypeNames, publishers)
+	addReleaseSubcommands(publisherTypeNames, publishers)
 	return nil
 }
 
@@ -182,14 +188,30 @@ func distgoProjectParamFromFlags() (distgo.ProjectInfo, distgo.ProjectParam, err
 	return cmdinternal.DistgoProjectParamFromFlagVals(globalFlagValsAndFactories)
 }
 
+// distgoConfigModTime returns the latest modification time of the distgo configuration file and the configuration
+// files that it includes.
 func distgoConfigModTime() *time.Time {
-	if globalFlagValsAndFactories.DistgoConfigFileFlagVal == "" {
+	cfgFile := globalFlagValsAndFactories.DistgoConfigFileFlagVal
+	if cfgFile == "" {
 		return nil
 	}
-	fi, err := os.Stat(globalFlagValsAndFactories.DistgoConfigFileFlagVal)
+	fi, err := os.Stat(cfgFile)
 	if err != nil {
 		return nil
 	}
 	modTime := fi.ModTime()
+	cfgBytes, err := os.ReadFile(cfgFile)
+	if err != nil {
+		return &modTime
+	}
+	includedFiles, err := config.IncludedConfigFiles(cfgFile, cfgBytes)
+	if err != nil {
+		return &modTime
+	}
+	for _, includedFile := range includedFiles {
+		if fi, err := os.Stat(includedFile); err == nil && fi.ModTime().After(modTime) {
+			modTime = fi.ModTime()
+		}
+	}
 	return &modTime
 }
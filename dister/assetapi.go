@@ -40,6 +40,7 @@ func AssetRootCmd(creator Creator, upgradeConfigFn pluginapi.UpgradeConfigFn, sh
 	rootCmd.AddCommand(newRunDistCmd(creatorFn))
 	rootCmd.AddCommand(newGenerateDistArtifactsCmd(creatorFn))
 	rootCmd.AddCommand(pluginapi.CobraUpgradeConfigCmd(upgradeConfigFn))
+	rootCmd.AddCommand(assetapi.NewConfigSchemaCmd(configSchemaFn(creator)))
 
 	return rootCmd
 }
@@ -239,3 +240,13 @@ func mustMarkFlagsRequired(cmd *cobra.Command, flagNames ...string) {
 		}
 	}
 }
+
+// configSchemaFn returns the function that provides the configuration schema for the creator, or nil if the creator
+// does not provide one.
+func configSchemaFn(creator Creator) func() ([]byte, error) {
+	schemaCreator, ok := creator.(ConfigSchemaCreator)
+	if !ok {
+		return nil
+	}
+	return schemaCreator.ConfigSchema
+}
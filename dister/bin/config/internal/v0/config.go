@@ -15,9 +15,20 @@
 package v0
 
 import (
-	"github.com/palantir/godel/v2/pkg/versionedconfig"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
 )
 
+type Config struct {
+	// IncludeWasmExec specifies that the "wasm_exec.js" file of the Go toolchain is included next to the executables
+	// for the "js-wasm" OS/Arch.
+	IncludeWasmExec bool `yaml:"include-wasm-exec,omitempty"`
+}
+
 func UpgradeConfig(cfgBytes []byte) ([]byte, error) {
-	return versionedconfig.ConfigNotSupported("bin dister", cfgBytes)
+	var cfg Config
+	if err := yaml.UnmarshalStrict(cfgBytes, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal bin dister v0 configuration")
+	}
+	return cfgBytes, nil
 }
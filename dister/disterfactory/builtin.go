@@ -23,22 +23,31 @@ import (
 	"github.com/palantir/distgo/dister/osarchbin"
 	osarchbinconfig "github.com/palantir/distgo/dister/osarchbin/config"
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config/configschema"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
 
 type creatorWithUpgrader struct {
-	creator  dister.CreatorFunction
-	upgrader distgo.ConfigUpgrader
+	creator      dister.CreatorFunction
+	upgrader     distgo.ConfigUpgrader
+	configSchema func() ([]byte, error)
 }
 
 func builtinDisters() map[string]creatorWithUpgrader {
 	return map[string]creatorWithUpgrader{
 		bin.TypeName: {
 			creator: func(cfgYML []byte) (distgo.Dister, error) {
-				return bin.New(), nil
+				var cfg binconfig.Bin
+				if err := yaml.UnmarshalStrict(cfgYML, &cfg); err != nil {
+					return nil, errors.Wrapf(err, "failed to unmarshal YAML")
+				}
+				return cfg.ToDister(), nil
 			},
 			upgrader: distgo.NewConfigUpgrader(bin.TypeName, binconfig.UpgradeConfig),
+			configSchema: func() ([]byte, error) {
+				return configschema.JSON(binconfig.Bin{})
+			},
 		},
 		osarchbin.TypeName: {
 			creator: func(cfgYML []byte) (distgo.Dister, error) {
@@ -49,6 +58,9 @@ func builtinDisters() map[string]creatorWithUpgrader {
 				return cfg.ToDister(), nil
 			},
 			upgrader: distgo.NewConfigUpgrader(osarchbin.TypeName, osarchbinconfig.UpgradeConfig),
+			configSchema: func() ([]byte, error) {
+				return configschema.JSON(osarchbinconfig.OSArchBin{})
+			},
 		},
 		manual.TypeName: {
 			creator: func(cfgYML []byte) (distgo.Dister, error) {
@@ -59,6 +71,9 @@ func builtinDisters() map[string]creatorWithUpgrader {
 				return cfg.ToDister(), nil
 			},
 			upgrader: distgo.NewConfigUpgrader(manual.TypeName, manualconfig.UpgradeConfig),
+			configSchema: func() ([]byte, error) {
+				return configschema.JSON(manualconfig.Manual{})
+			},
 		},
 	}
 }
@@ -0,0 +1,68 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disterfactory_test
+
+import (
+	"testing"
+
+	"github.com/palantir/distgo/dister/bin"
+	"github.com/palantir/distgo/dister/disterfactory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBinDisterConfig verifies that the bin dister accepts the "include-wasm-exec" key and rejects any other key. The
+// bin dister did not support any configuration before "include-wasm-exec" was added, so its creator and upgrader
+// unmarshal strictly to match.
+func TestBinDisterConfig(t *testing.T) {
+	disterFactory, err := disterfactory.New(nil, nil)
+	require.NoError(t, err)
+	upgrader, err := disterFactory.ConfigUpgrader(bin.TypeName)
+	require.NoError(t, err)
+
+	for i, tc := range []struct {
+		name    string
+		cfgYML  string
+		wantErr string
+	}{
+		{
+			"empty configuration",
+			``,
+			"",
+		},
+		{
+			"include-wasm-exec",
+			`include-wasm-exec: true`,
+			"",
+		},
+		{
+			"unknown key",
+			`unknown-key: true`,
+			"field unknown-key not found in type",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := disterFactory.NewDister(bin.TypeName, []byte(tc.cfgYML))
+			_, upgradeErr := upgrader.UpgradeConfig([]byte(tc.cfgYML))
+			if tc.wantErr == "" {
+				assert.NoError(t, err, "Case %d: %s", i, tc.name)
+				assert.NoError(t, upgradeErr, "Case %d: %s", i, tc.name)
+				return
+			}
+			assert.ErrorContains(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+			assert.ErrorContains(t, upgradeErr, tc.wantErr, "Case %d: %s", i, tc.name)
+		})
+	}
+}
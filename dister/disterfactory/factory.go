@@ -25,11 +25,13 @@ func New(providedDisterCreators []dister.Creator, providedConfigUpgraders []dist
 	seenTypes := make(map[string]struct{})
 	disterCreators := make(map[string]dister.CreatorFunction)
 	configUpgraders := make(map[string]distgo.ConfigUpgrader)
+	configSchemas := make(map[string]func() ([]byte, error))
 	for k, v := range builtinDisters() {
 		types = append(types, k)
 		seenTypes[k] = struct{}{}
 		disterCreators[k] = v.creator
 		configUpgraders[k] = v.upgrader
+		configSchemas[k] = v.configSchema
 	}
 	for _, currCreator := range providedDisterCreators {
 		if _, ok := seenTypes[currCreator.TypeName()]; ok {
@@ -38,6 +40,9 @@ func New(providedDisterCreators []dister.Creator, providedConfigUpgraders []dist
 		seenTypes[currCreator.TypeName()] = struct{}{}
 		types = append(types, currCreator.TypeName())
 		disterCreators[currCreator.TypeName()] = currCreator.Creator()
+		if schemaCreator, ok := currCreator.(dister.ConfigSchemaCreator); ok {
+			configSchemas[currCreator.TypeName()] = schemaCreator.ConfigSchema
+		}
 	}
 	for _, currUpgrader := range providedConfigUpgraders {
 		configUpgraders[currUpgrader.TypeName()] = currUpgrader
@@ -46,6 +51,7 @@ func New(providedDisterCreators []dister.Creator, providedConfigUpgraders []dist
 		types:                 types,
 		disterCreators:        disterCreators,
 		disterConfigUpgraders: configUpgraders,
+		disterConfigSchemas:   configSchemas,
 	}, nil
 }
 
@@ -53,8 +59,11 @@ type disterFactoryImpl struct {
 	types                 []string
 	disterCreators        map[string]dister.CreatorFunction
 	disterConfigUpgraders map[string]distgo.ConfigUpgrader
+	disterConfigSchemas   map[string]func() ([]byte, error)
 }
 
+var _ distgo.ConfigSchemaProvider = (*disterFactoryImpl)(nil)
+
 func (f *disterFactoryImpl) Types() []string {
 	return f.types
 }
@@ -81,3 +90,14 @@ func (f *disterFactoryImpl) ConfigUpgrader(typeName string) (distgo.ConfigUpgrad
 	}
 	return upgrader, nil
 }
+
+func (f *disterFactoryImpl) ConfigSchema(typeName string) ([]byte, error) {
+	if _, ok := f.disterCreators[typeName]; !ok {
+		return nil, errors.Errorf("no disters registered for dister type %q (registered disters: %v)", typeName, f.types)
+	}
+	configSchemaFn, ok := f.disterConfigSchemas[typeName]
+	if !ok {
+		return nil, nil
+	}
+	return configSchemaFn()
+}
@@ -18,6 +18,7 @@ import (
 	"sort"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/internal/assetapi"
 	"github.com/pkg/errors"
 )
 
@@ -48,6 +49,32 @@ func NewCreator(typeName string, creatorFn CreatorFunction) Creator {
 	}
 }
 
+// ConfigSchemaCreator is a Creator that provides the JSON Schema for the configuration of the dister that it creates.
+type ConfigSchemaCreator interface {
+	Creator
+	ConfigSchema() ([]byte, error)
+}
+
+type configSchemaCreatorStruct struct {
+	creatorStruct
+	configSchema func() ([]byte, error)
+}
+
+func (c *configSchemaCreatorStruct) ConfigSchema() ([]byte, error) {
+	return c.configSchema()
+}
+
+// NewCreatorWithConfigSchema returns a ConfigSchemaCreator whose configuration schema is provided by configSchemaFn.
+func NewCreatorWithConfigSchema(typeName string, creatorFn CreatorFunction, configSchemaFn func() ([]byte, error)) Creator {
+	return &configSchemaCreatorStruct{
+		creatorStruct: creatorStruct{
+			typeName: typeName,
+			creator:  creatorFn,
+		},
+		configSchema: configSchemaFn,
+	}
+}
+
 func AssetDisterCreators(assetPaths ...string) ([]Creator, []distgo.ConfigUpgrader, error) {
 	var disterCreators []Creator
 	var configUpgraders []distgo.ConfigUpgrader
@@ -61,7 +88,7 @@ func AssetDisterCreators(assetPaths ...string) ([]Creator, []distgo.ConfigUpgrad
 			return nil, nil, errors.Wrapf(err, "failed to determine dister type name for asset %s", currAssetPath)
 		}
 		disterNameToAssets[disterName] = append(disterNameToAssets[disterName], currAssetPath)
-		disterCreators = append(disterCreators, NewCreator(disterName,
+		disterCreators = append(disterCreators, NewCreatorWithConfigSchema(disterName,
 			func(cfgYML []byte) (distgo.Dister, error) {
 				newDister := assetDister{
 					assetPath: currAssetPath,
@@ -71,6 +98,9 @@ func AssetDisterCreators(assetPaths ...string) ([]Creator, []distgo.ConfigUpgrad
 					return nil, err
 				}
 				return &newDister, nil
+			},
+			func() ([]byte, error) {
+				return assetapi.GetConfigSchema(currAssetPath)
 			}))
 		configUpgraders = append(configUpgraders, &assetConfigUpgrader{
 			typeName:  disterName,
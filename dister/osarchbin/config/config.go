@@ -29,6 +29,7 @@ func (cfg *OSArchBin) ToDister() distgo.Dister {
 		osArchs = []osarch.OSArch{osarch.Current()}
 	}
 	return &osarchbin.Dister{
-		OSArchs: osArchs,
+		OSArchs:         osArchs,
+		IncludeWasmExec: cfg.IncludeWasmExec,
 	}
 }
@@ -24,6 +24,10 @@ type Config struct {
 	// OSArchs specifies the GOOS and GOARCH pairs for which TGZ distributions are created. If blank, defaults to
 	// the GOOS and GOARCH of the host system at runtime.
 	OSArchs []osarch.OSArch `yaml:"os-archs,omitempty"`
+
+	// IncludeWasmExec specifies that the "wasm_exec.js" file of the Go toolchain is included next to the executables
+	// in the distributions for the "js-wasm" OS/Arch.
+	IncludeWasmExec bool `yaml:"include-wasm-exec,omitempty"`
 }
 
 func UpgradeConfig(cfgBytes []byte) ([]byte, error) {
@@ -33,8 +33,14 @@ import (
 
 const TypeName = "os-arch-bin" // distribution that consists of the binaries for a specific OS/Architecture
 
+// wasmExecOSArch is the OS/Arch whose executables require "wasm_exec.js" to run.
+var wasmExecOSArch = osarch.OSArch{OS: "js", Arch: "wasm"}
+
 type Dister struct {
 	OSArchs []osarch.OSArch
+	// IncludeWasmExec specifies that the "wasm_exec.js" file of the Go toolchain is included in the distributions for
+	// the "js-wasm" OS/Arch.
+	IncludeWasmExec bool
 }
 
 func New(osArchs ...osarch.OSArch) distgo.Dister {
@@ -69,8 +75,9 @@ func (d *Dister) osArchFromArtifactPath(distID distgo.DistID, artifactPath strin
 }
 
 func (d *Dister) RunDist(distID distgo.DistID, productTaskOutputInfo distgo.ProductTaskOutputInfo) ([]byte, error) {
+	variant := productTaskOutputInfo.Product.DistOutputInfos.DistInfos[distID].BuildVariant
 	for _, osArch := range d.OSArchs {
-		if err := verifyDistTargetSupported(osArch, productTaskOutputInfo); err != nil {
+		if err := verifyDistTargetSupported(osArch, productTaskOutputInfo, variant); err != nil {
 			return nil, err
 		}
 	}
@@ -78,8 +85,19 @@ func (d *Dister) RunDist(distID distgo.DistID, productTaskOutputInfo distgo.Prod
 	outputPathsForOSArchs := make(map[string][]string)
 	for _, osArch := range d.OSArchs {
 		for _, currProductOutputInfo := range productTaskOutputInfo.AllProductOutputInfos() {
-			// copy executable for current product
-			dst, err := copyArtifactForOSArch(distWorkDir, productTaskOutputInfo.Project, currProductOutputInfo, osArch)
+			// copy build artifacts for current product. The build variant only applies to the product itself.
+			currVariant := variant
+			if currProductOutputInfo.ID != productTaskOutputInfo.Product.ID {
+				currVariant = ""
+			}
+			dsts, err := copyArtifactsForOSArch(distWorkDir, productTaskOutputInfo.Project, currProductOutputInfo, osArch, currVariant)
+			if err != nil {
+				return nil, err
+			}
+			outputPathsForOSArchs[osArch.String()] = append(outputPathsForOSArchs[osArch.String()], dsts...)
+		}
+		if d.IncludeWasmExec && osArch == wasmExecOSArch {
+			dst, err := copyWasmExecJS(distWorkDir, productTaskOutputInfo, osArch)
 			if err != nil {
 				return nil, err
 			}
@@ -121,8 +139,8 @@ func (d *Dister) GenerateDistArtifacts(distID distgo.DistID, productTaskOutputIn
 	return nil
 }
 
-func verifyDistTargetSupported(osArch osarch.OSArch, productTaskOutputInfo distgo.ProductTaskOutputInfo) error {
-	if err := verifySingleProduct(osArch, productTaskOutputInfo.Product); err != nil {
+func verifyDistTargetSupported(osArch osarch.OSArch, productTaskOutputInfo distgo.ProductTaskOutputInfo, variant distgo.BuildVariantID) error {
+	if err := verifySingleProduct(osArch, productTaskOutputInfo.Product, variant); err != nil {
 		return err
 	}
 	var keys []distgo.ProductID
@@ -132,18 +150,22 @@ func verifyDistTargetSupported(osArch osarch.OSArch, productTaskOutputInfo distg
 	sort.Sort(distgo.ByProductID(keys))
 	for _, currKey := range keys {
 		currSpec := productTaskOutputInfo.Deps[currKey]
-		if err := verifySingleProduct(osArch, currSpec); err != nil {
+		if err := verifySingleProduct(osArch, currSpec, ""); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func verifySingleProduct(osArch osarch.OSArch, productOutputInfo distgo.ProductOutputInfo) error {
-	if !osArchInBuildSpec(osArch, productOutputInfo) {
+func verifySingleProduct(osArch osarch.OSArch, productOutputInfo distgo.ProductOutputInfo, variant distgo.BuildVariantID) error {
+	if !osArchInBuildSpec(osArch, productOutputInfo, variant) {
 		buildOSArchs := "[none]"
 		if productOutputInfo.BuildOutputInfo != nil {
-			buildOSArchs = fmt.Sprint(productOutputInfo.BuildOutputInfo.OSArchs)
+			buildOSArchs = fmt.Sprint(productOutputInfo.BuildOutputInfo.VariantOSArchs(variant))
+		}
+		if variant != "" {
+			return errors.Errorf("the OS/Arch specified for the distribution of a product must be specified as a build target for the build variant used by the distribution, "+
+				"but build variant %s of product %s does not specify %s as one of its build targets (current build targets: %s)", variant, productOutputInfo.ID, osArch, buildOSArchs)
 		}
 		return errors.Errorf("the OS/Arch specified for the distribution of a product must be specified as a build target for the product, "+
 			"but product %s does not specify %s as one of its build targets (current build targets: %s)", productOutputInfo.ID, osArch, buildOSArchs)
@@ -151,26 +173,53 @@ func verifySingleProduct(osArch osarch.OSArch, productOutputInfo distgo.ProductO
 	return nil
 }
 
-func osArchInBuildSpec(osArch osarch.OSArch, productOutputInfo distgo.ProductOutputInfo) bool {
+func osArchInBuildSpec(osArch osarch.OSArch, productOutputInfo distgo.ProductOutputInfo, variant distgo.BuildVariantID) bool {
 	if productOutputInfo.BuildOutputInfo == nil {
 		return false
 	}
-	found := slices.Contains(productOutputInfo.BuildOutputInfo.OSArchs, osArch)
+	found := slices.Contains(productOutputInfo.BuildOutputInfo.VariantOSArchs(variant), osArch)
 	return found
 }
 
-func copyArtifactForOSArch(outputDir string, projectInfo distgo.ProjectInfo, productInfo distgo.ProductOutputInfo, osArch osarch.OSArch) (string, error) {
-	artifactPath, ok := distgo.ProductBuildArtifactPaths(projectInfo, productInfo)[osArch]
+// copyArtifactsForOSArch copies all of the files created by the build of the product for the provided OS/Arch to the
+// OS/Arch directory in the output directory and returns the paths of the copies.
+func copyArtifactsForOSArch(outputDir string, projectInfo distgo.ProjectInfo, productInfo distgo.ProductOutputInfo, osArch osarch.OSArch, variant distgo.BuildVariantID) ([]string, error) {
+	artifactPaths, ok := distgo.ProductBuildArtifactFilePaths(projectInfo, productInfo, variant)[osArch]
 	if !ok {
-		return "", errors.Errorf("no build artifacts exist for %s", osArch)
+		return nil, errors.Errorf("no build artifacts exist for %s", osArch)
 	}
 
-	dst := path.Join(outputDir, osArch.String(), distgo.ExecutableName(productInfo.BuildOutputInfo.BuildNameTemplateRendered, osArch.OS))
+	var dsts []string
+	for _, artifactPath := range artifactPaths {
+		dst := path.Join(outputDir, osArch.String(), path.Base(artifactPath))
+		if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
+			return nil, errors.Wrapf(err, "failed to create output directory for artifact")
+		}
+		if _, err := shutil.Copy(artifactPath, dst, false); err != nil {
+			return nil, errors.Wrapf(err, "failed to copy build artifact from %s to %s", artifactPath, dst)
+		}
+		dsts = append(dsts, dst)
+	}
+	return dsts, nil
+}
+
+// copyWasmExecJS copies the "wasm_exec.js" file of the Go toolchain that builds the product to the OS/Arch directory in
+// the output directory and returns the path of the copy.
+func copyWasmExecJS(outputDir string, productTaskOutputInfo distgo.ProductTaskOutputInfo, osArch osarch.OSArch) (string, error) {
+	var goToolchain distgo.GoToolchainParam
+	if productTaskOutputInfo.Product.BuildOutputInfo != nil {
+		goToolchain = productTaskOutputInfo.Product.BuildOutputInfo.GoToolchain
+	}
+	src, err := distgo.WasmExecJSPath(productTaskOutputInfo.Project.ProjectDir, goToolchain)
+	if err != nil {
+		return "", err
+	}
+	dst := path.Join(outputDir, osArch.String(), path.Base(src))
 	if err := os.MkdirAll(path.Dir(dst), 0755); err != nil {
-		return "", errors.Wrapf(err, "failed to create output directory for artifact")
+		return "", errors.Wrapf(err, "failed to create output directory for wasm_exec.js")
 	}
-	if _, err := shutil.Copy(artifactPath, dst, false); err != nil {
-		return "", errors.Wrapf(err, "failed to copy build artifact from %s to %s", artifactPath, dst)
+	if _, err := shutil.Copy(src, dst, false); err != nil {
+		return "", errors.Wrapf(err, "failed to copy wasm_exec.js from %s to %s", src, dst)
 	}
 	return dst, nil
 }
@@ -0,0 +1,210 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package affected
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/build/imports"
+	"github.com/palantir/distgo/pkg/git"
+	"github.com/pkg/errors"
+)
+
+// Run prints the IDs of the products that are affected by the changes made since the provided git revision, one per
+// line in sorted order.
+func Run(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, configFiles []string, since string, stdout io.Writer) error {
+	changedFiles, err := ChangedFiles(projectInfo.ProjectDir, since)
+	if err != nil {
+		return err
+	}
+	productIDs, err := Products(projectInfo, projectParam, configFiles, changedFiles)
+	if err != nil {
+		return err
+	}
+	for _, productID := range productIDs {
+		_, _ = fmt.Fprintln(stdout, productID)
+	}
+	return nil
+}
+
+// ChangedFiles returns the absolute paths of the files in the git repository that contains projectDir that differ
+// between the merge base of the provided revision and HEAD and the working tree, including untracked files that are not
+// ignored.
+func ChangedFiles(projectDir, since string) ([]string, error) {
+	repoRoot, err := git.CmdOutput(projectDir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine root of git repository")
+	}
+	mergeBase, err := git.CmdOutput(projectDir, "merge-base", since, "HEAD")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine merge base of %s and HEAD", since)
+	}
+	diffOutput, err := git.CmdOutput(repoRoot, "diff", "--name-only", "--no-renames", mergeBase)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine files changed since %s", since)
+	}
+	untrackedOutput, err := git.CmdOutput(repoRoot, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine untracked files")
+	}
+	var changedFiles []string
+	for _, output := range []string{diffOutput, untrackedOutput} {
+		for line := range strings.SplitSeq(output, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				changedFiles = append(changedFiles, filepath.Join(repoRoot, line))
+			}
+		}
+	}
+	slices.Sort(changedFiles)
+	return slices.Compact(changedFiles), nil
+}
+
+// Products returns the IDs of the products that are affected by the provided changed files in sorted order. A product
+// is affected if any of its inputs changed or if any of the products that it depends on is affected. The inputs of a
+// product are:
+//   - the files required to build its main package for each of its OS/architectures, the directories of the packages
+//     that contain them and the go.mod and go.sum files of the project
+//   - the input directories of its disters
+//   - the context directories and Dockerfiles of its Docker builders
+//   - the provided configuration files, which include the scripts of the product
+//
+// The changed files must be absolute paths.
+func Products(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, configFiles, changedFiles []string) ([]distgo.ProductID, error) {
+	if len(changedFiles) == 0 {
+		return nil, nil
+	}
+	// paths reported by git have symbolic links resolved
+	if projectDir, err := filepath.EvalSymlinks(projectInfo.ProjectDir); err == nil {
+		projectInfo.ProjectDir = projectDir
+	}
+	for _, configFile := range configFiles {
+		if configFile, err := filepath.EvalSymlinks(absPath(projectInfo.ProjectDir, configFile)); err == nil && slices.Contains(changedFiles, configFile) {
+			// a change to the configuration can affect any product
+			return slices.Sorted(maps.Keys(projectParam.Products)), nil
+		}
+	}
+
+	directlyAffected := make(map[distgo.ProductID]struct{})
+	for productID, productParam := range projectParam.Products {
+		inputs, err := productInputs(projectInfo, productParam)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to determine inputs of product %s", productID)
+		}
+		if slices.ContainsFunc(changedFiles, inputs.contains) {
+			directlyAffected[productID] = struct{}{}
+		}
+	}
+
+	var affected []distgo.ProductID
+	for productID, productParam := range projectParam.Products {
+		if _, ok := directlyAffected[productID]; ok {
+			affected = append(affected, productID)
+			continue
+		}
+		for depID := range productParam.AllDependencies {
+			if _, ok := directlyAffected[depID]; ok {
+				affected = append(affected, productID)
+				break
+			}
+		}
+	}
+	slices.Sort(affected)
+	return affected, nil
+}
+
+type inputs struct {
+	// files are the absolute paths of input files.
+	files map[string]struct{}
+	// dirs are the absolute paths of input directories: a change to any file in a directory (or, for recursive
+	// directories, in any of its subdirectories) is a change to the input.
+	dirs          map[string]struct{}
+	recursiveDirs map[string]struct{}
+}
+
+func (i inputs) contains(changedFile string) bool {
+	if _, ok := i.files[changedFile]; ok {
+		return true
+	}
+	if _, ok := i.dirs[filepath.Dir(changedFile)]; ok && !strings.HasSuffix(changedFile, "_test.go") {
+		return true
+	}
+	for dir := range i.recursiveDirs {
+		if strings.HasPrefix(changedFile, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func productInputs(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam) (inputs, error) {
+	in := inputs{
+		files:         make(map[string]struct{}),
+		dirs:          make(map[string]struct{}),
+		recursiveDirs: make(map[string]struct{}),
+	}
+	if productParam.Build != nil {
+		mainPkgDir := absPath(projectInfo.ProjectDir, productParam.Build.MainPkg)
+		osArchs := slices.Clone(productParam.Build.OSArchs)
+		for _, variantParam := range productParam.Build.Variants {
+			osArchs = append(osArchs, variantParam.OSArchs...)
+		}
+		for _, osArch := range osArchs {
+			// a universal binary is built from the files for each of its architectures
+			for _, targetOSArch := range distgo.TargetOSArchs(osArch) {
+				buildFiles, err := imports.AllFiles(mainPkgDir, targetOSArch.OS, targetOSArch.Arch)
+				if err != nil {
+					return inputs{}, errors.Wrapf(err, "failed to determine files required to build %s for %s", productParam.Build.MainPkg, targetOSArch)
+				}
+				for _, pkgFiles := range buildFiles {
+					for _, pkgFile := range pkgFiles {
+						in.files[pkgFile] = struct{}{}
+						in.dirs[filepath.Dir(pkgFile)] = struct{}{}
+					}
+				}
+			}
+		}
+		in.dirs[mainPkgDir] = struct{}{}
+		in.files[filepath.Join(projectInfo.ProjectDir, "go.mod")] = struct{}{}
+		in.files[filepath.Join(projectInfo.ProjectDir, "go.sum")] = struct{}{}
+	}
+	if productParam.Dist != nil {
+		for _, disterParam := range productParam.Dist.DistParams {
+			if disterParam.InputDir.Path != "" {
+				in.recursiveDirs[absPath(projectInfo.ProjectDir, disterParam.InputDir.Path)] = struct{}{}
+			}
+		}
+	}
+	if productParam.Docker != nil {
+		for _, dockerBuilderParam := range productParam.Docker.DockerBuilderParams {
+			contextDir := absPath(projectInfo.ProjectDir, dockerBuilderParam.ContextDir)
+			in.recursiveDirs[contextDir] = struct{}{}
+			in.files[filepath.Join(contextDir, dockerBuilderParam.DockerfilePath)] = struct{}{}
+		}
+	}
+	return in, nil
+}
+
+func absPath(projectDir, p string) string {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	return filepath.Join(projectDir, p)
+}
@@ -0,0 +1,215 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package affected_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nmiyake/pkg/gofiles"
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/affected"
+	distgoconfig "github.com/palantir/distgo/distgo/config"
+	"github.com/palantir/distgo/distgo/testfuncs"
+	"github.com/palantir/pkg/gittest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const testConfig = `
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+  bar:
+    build:
+      main-pkg: ./bar
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+          input-dir: bar-dist
+  baz:
+    docker:
+      docker-builders:
+        baz-image:
+          type: default
+          context-dir: docker/baz
+          tag-templates:
+            - baz:latest
+    dependencies:
+      - foo
+  qux:
+    build:
+      main-pkg: ./qux
+      os-archs:
+        - os: darwin
+          arch: universal
+`
+
+func TestAffected(t *testing.T) {
+	for i, tc := range []struct {
+		name   string
+		change func(projectDir string)
+		want   []distgo.ProductID
+	}{
+		{
+			"no changes",
+			func(projectDir string) {},
+			nil,
+		},
+		{
+			"change to main package",
+			func(projectDir string) {
+				writeFile(t, projectDir, "bar/main.go", "package main\n\nfunc main() {}\n")
+			},
+			[]distgo.ProductID{"bar"},
+		},
+		{
+			"change to imported package propagates to dependent products",
+			func(projectDir string) {
+				writeFile(t, projectDir, "lib/lib.go", "package lib\n\nconst Name = \"updated\"\n")
+			},
+			[]distgo.ProductID{"baz", "foo"},
+		},
+		{
+			"new file in imported package",
+			func(projectDir string) {
+				writeFile(t, projectDir, "lib/other.go", "package lib\n")
+			},
+			[]distgo.ProductID{"baz", "foo"},
+		},
+		{
+			"test file in imported package does not affect products",
+			func(projectDir string) {
+				writeFile(t, projectDir, "lib/lib_test.go", "package lib\n")
+			},
+			nil,
+		},
+		{
+			"change to package imported only for an architecture of a universal binary",
+			func(projectDir string) {
+				writeFile(t, projectDir, "armlib/lib.go", "package armlib\n\nconst Name = \"updated\"\n")
+			},
+			[]distgo.ProductID{"qux"},
+		},
+		{
+			"change to dist input directory",
+			func(projectDir string) {
+				writeFile(t, projectDir, "bar-dist/nested/README.md", "updated")
+			},
+			[]distgo.ProductID{"bar"},
+		},
+		{
+			"change to Docker context directory",
+			func(projectDir string) {
+				writeFile(t, projectDir, "docker/baz/Dockerfile", "FROM scratch\n")
+			},
+			[]distgo.ProductID{"baz"},
+		},
+		{
+			"change to go.mod affects products that are built",
+			func(projectDir string) {
+				writeFile(t, projectDir, "go.mod", "module github.com/test/project\n\ngo 1.21\n")
+			},
+			[]distgo.ProductID{"bar", "baz", "foo", "qux"},
+		},
+		{
+			"change to configuration affects all products",
+			func(projectDir string) {
+				writeFile(t, projectDir, "dist-plugin.yml", testConfig+"\n")
+			},
+			[]distgo.ProductID{"bar", "baz", "foo", "qux"},
+		},
+		{
+			"change to unrelated file",
+			func(projectDir string) {
+				writeFile(t, projectDir, "README.md", "updated")
+			},
+			nil,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			_, err := gofiles.Write(projectDir, []gofiles.GoFileSpec{
+				{
+					RelPath: "go.mod",
+					Src:     "module github.com/test/project\n",
+				},
+				{
+					RelPath: "foo/main.go",
+					Src:     "package main\n\nimport _ \"github.com/test/project/lib\"\n",
+				},
+				{
+					RelPath: "bar/main.go",
+					Src:     "package main\n",
+				},
+				{
+					RelPath: "lib/lib.go",
+					Src:     "package lib\n",
+				},
+				{
+					RelPath: "qux/main.go",
+					Src:     "package main\n",
+				},
+				{
+					RelPath: "qux/main_darwin_arm64.go",
+					Src:     "package main\n\nimport _ \"github.com/test/project/armlib\"\n",
+				},
+				{
+					RelPath: "armlib/lib.go",
+					Src:     "package armlib\n",
+				},
+			})
+			require.NoError(t, err)
+			writeFile(t, projectDir, "bar-dist/nested/README.md", "bar")
+			writeFile(t, projectDir, "docker/baz/Dockerfile", "FROM alpine\n")
+			writeFile(t, projectDir, "dist-plugin.yml", testConfig)
+			writeFile(t, projectDir, "README.md", "project")
+			gittest.InitGitDir(t, projectDir)
+
+			var projectCfg distgoconfig.ProjectConfig
+			require.NoError(t, yaml.Unmarshal([]byte(testConfig), &projectCfg))
+			projectParam := testfuncs.NewProjectParam(t, projectCfg, projectDir, fmt.Sprintf("Case %d: %s", i, tc.name))
+
+			tc.change(projectDir)
+
+			changedFiles, err := affected.ChangedFiles(projectDir, "HEAD")
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			got, err := affected.Products(distgo.ProjectInfo{ProjectDir: projectDir}, projectParam, []string{"dist-plugin.yml"}, changedFiles)
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			assert.Equal(t, tc.want, got, "Case %d: %s", i, tc.name)
+
+			buf := &bytes.Buffer{}
+			err = affected.Run(distgo.ProjectInfo{ProjectDir: projectDir}, projectParam, []string{"dist-plugin.yml"}, "HEAD", buf)
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			var wantOutput string
+			for _, productID := range tc.want {
+				wantOutput += string(productID) + "\n"
+			}
+			assert.Equal(t, wantOutput, buf.String(), "Case %d: %s", i, tc.name)
+		})
+	}
+}
+
+func writeFile(t *testing.T, projectDir, relPath, content string) {
+	filePath := filepath.Join(projectDir, relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0755))
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0644))
+}
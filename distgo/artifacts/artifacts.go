@@ -24,15 +24,29 @@ import (
 
 	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/distgo/distgo/build"
-	"github.com/palantir/godel/v2/pkg/osarch"
+	"github.com/palantir/distgo/distgo/productinfo"
 	"github.com/pkg/errors"
 )
 
-func PrintBuildArtifacts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productBuildIDs []distgo.ProductBuildID, absPath, requiresBuild bool, stdout io.Writer) error {
+// PrintBuildArtifacts prints the paths to the build artifacts of the specified products. If the format is
+// productinfo.FormatJSON, the information about the products is printed as JSON instead: if requiresBuild is true, it
+// only includes the products and OS/architectures that need to be built.
+func PrintBuildArtifacts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productBuildIDs []distgo.ProductBuildID, absPath, requiresBuild bool, format productinfo.Format, stdout io.Writer) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
 	productParams, err := distgo.ProductParamsForBuildProductArgs(projectParam.Products, nil, productBuildIDs...)
 	if err != nil {
 		return err
 	}
+	if format == productinfo.FormatJSON {
+		if requiresBuild {
+			if productParams, err = requiresBuildProductParams(projectInfo, productParams); err != nil {
+				return err
+			}
+		}
+		return productinfo.Print(projectInfo, productParams, absPath, stdout)
+	}
 	artifacts, err := Build(projectInfo, productParams, requiresBuild)
 	if err != nil {
 		return err
@@ -46,33 +60,25 @@ func PrintBuildArtifacts(projectInfo distgo.ProjectInfo, projectParam distgo.Pro
 // Build returns a map from product name to build artifact paths. If requiresBuild is true, only returns the artifacts
 // that need to be built.
 func Build(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam, requiresBuild bool) (map[distgo.ProductID][]string, error) {
-	outputPaths := make(map[distgo.ProductID]map[osarch.OSArch]string)
-	for _, currProductParam := range productParams {
-		if requiresBuild {
-			requiresBuildParam, err := build.RequiresBuild(projectInfo, currProductParam)
-			if err != nil {
-				return nil, errors.Wrapf(err, "failed to determine if product %s needs to be built", currProductParam.ID)
-			}
-			if requiresBuildParam == nil {
-				continue
-			}
-			currProductParam = *requiresBuildParam
+	if requiresBuild {
+		var err error
+		if productParams, err = requiresBuildProductParams(projectInfo, productParams); err != nil {
+			return nil, err
 		}
+	}
+	buildArtifacts := make(map[distgo.ProductID][]string)
+	for _, currProductParam := range productParams {
 		outputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, currProductParam)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to compute output info for %s", currProductParam.ID)
 		}
-		currOutputPaths := outputInfo.ProductBuildArtifactPaths()
-		if len(currOutputPaths) == 0 {
+		if outputInfo.Product.BuildOutputInfo == nil {
 			continue
 		}
-		outputPaths[currProductParam.ID] = currOutputPaths
-	}
-
-	buildArtifacts := make(map[distgo.ProductID][]string)
-	for k, v := range outputPaths {
-		for _, currPath := range v {
-			buildArtifacts[k] = append(buildArtifacts[k], currPath)
+		for _, variant := range append([]distgo.BuildVariantID{""}, outputInfo.Product.BuildOutputInfo.VariantIDs()...) {
+			for _, currPaths := range outputInfo.ProductBuildArtifactFilePaths(variant) {
+				buildArtifacts[currProductParam.ID] = append(buildArtifacts[currProductParam.ID], currPaths...)
+			}
 		}
 	}
 	for _, v := range buildArtifacts {
@@ -81,11 +87,36 @@ func Build(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam,
 	return buildArtifacts, nil
 }
 
-func PrintDistArtifacts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productDistIDs []distgo.ProductDistID, absPath bool, stdout io.Writer) error {
+// requiresBuildProductParams returns the parameters for the provided products that only contain the OS/architectures
+// that need to be built. Products that do not need to be built are omitted.
+func requiresBuildProductParams(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam) ([]distgo.ProductParam, error) {
+	var out []distgo.ProductParam
+	for _, currProductParam := range productParams {
+		requiresBuildParam, err := build.RequiresBuild(projectInfo, currProductParam)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to determine if product %s needs to be built", currProductParam.ID)
+		}
+		if requiresBuildParam == nil {
+			continue
+		}
+		out = append(out, *requiresBuildParam)
+	}
+	return out, nil
+}
+
+// PrintDistArtifacts prints the paths to the dist artifacts of the specified products. If the format is
+// productinfo.FormatJSON, the information about the products is printed as JSON instead.
+func PrintDistArtifacts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productDistIDs []distgo.ProductDistID, absPath bool, format productinfo.Format, stdout io.Writer) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
 	productParams, err := distgo.ProductParamsForDistProductArgs(projectParam.Products, productDistIDs...)
 	if err != nil {
 		return err
 	}
+	if format == productinfo.FormatJSON {
+		return productinfo.Print(projectInfo, productParams, absPath, stdout)
+	}
 	artifacts, err := Dist(projectInfo, productParams)
 	if err != nil {
 		return err
@@ -114,19 +145,39 @@ func Dist(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam) (
 	return outputPaths, nil
 }
 
-func PrintDockerArtifacts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productDockerIDs []distgo.ProductDockerID, stdout io.Writer) error {
+// PrintDockerArtifacts prints the tags of the Docker images of the specified products followed by the paths to the
+// archives exported for the images. If the format is productinfo.FormatJSON, the information about the products,
+// including the tags and exported archives of their Docker images, is printed as JSON instead.
+func PrintDockerArtifacts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productDockerIDs []distgo.ProductDockerID, absPath bool, format productinfo.Format, stdout io.Writer) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
 	productParams, err := distgo.ProductParamsForDockerProductArgs(projectParam.Products, productDockerIDs...)
 	if err != nil {
 		return err
 	}
+	if format == productinfo.FormatJSON {
+		return productinfo.Print(projectInfo, productParams, absPath, stdout)
+	}
 	artifacts, err := Docker(projectInfo, productParams)
 	if err != nil {
 		return err
 	}
-	return printArtifacts(artifacts, nil, stdout)
+	if err := printArtifacts(artifacts, nil, stdout); err != nil {
+		return err
+	}
+	exportArtifacts, err := DockerExports(projectInfo, productParams)
+	if err != nil {
+		return err
+	}
+	return printArtifacts(exportArtifacts, &printArtifactOptions{
+		projectDir: projectInfo.ProjectDir,
+		wantAbs:    absPath,
+	}, stdout)
 }
 
-// Docker returns a map from ProductID all of the tags for the Docker images for the product.
+// Docker returns a map from ProductID all of the tags for the Docker images for the product, including the tags of
+// their mirrors.
 func Docker(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam) (map[distgo.ProductID][]string, error) {
 	outputPaths := make(map[distgo.ProductID][]string)
 	for _, currProductParam := range productParams {
@@ -144,12 +195,34 @@ func Docker(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam)
 		}
 		sort.Sort(distgo.ByDockerID(dockerIDs))
 		for _, dockerID := range dockerIDs {
-			outputPaths[currProductParam.ID] = append(outputPaths[currProductParam.ID], currDockerOutputInfos[dockerID].RenderedTags...)
+			outputPaths[currProductParam.ID] = append(outputPaths[currProductParam.ID], currDockerOutputInfos[dockerID].PushTags()...)
 		}
 	}
 	return outputPaths, nil
 }
 
+// DockerExports returns a map from ProductID to the paths of all of the archives exported for the Docker images for
+// the product.
+func DockerExports(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam) (map[distgo.ProductID][]string, error) {
+	outputPaths := make(map[distgo.ProductID][]string)
+	for _, currProductParam := range productParams {
+		currOutputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, currProductParam)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compute output info for %s", currProductParam.ID)
+		}
+		var currPaths []string
+		for _, currExportPaths := range currOutputInfo.ProductDockerExportArtifactPaths() {
+			currPaths = append(currPaths, currExportPaths...)
+		}
+		if len(currPaths) == 0 {
+			continue
+		}
+		sort.Strings(currPaths)
+		outputPaths[currProductParam.ID] = currPaths
+	}
+	return outputPaths, nil
+}
+
 func printArtifacts(artifacts map[distgo.ProductID][]string, opts *printArtifactOptions, stdout io.Writer) error {
 	var wd string
 	var outputs []string
@@ -32,6 +32,7 @@ import (
 	"github.com/palantir/distgo/distgo/artifacts"
 	"github.com/palantir/distgo/distgo/build"
 	distgoconfig "github.com/palantir/distgo/distgo/config"
+	"github.com/palantir/distgo/distgo/productinfo"
 	"github.com/palantir/distgo/distgo/testfuncs"
 	"github.com/palantir/distgo/dockerbuilder/defaultdockerbuilder"
 	"github.com/palantir/distgo/dockerbuilder/dockerbuilderfactory"
@@ -130,12 +131,12 @@ out/build/foo/unspecified/%v/foo
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 
 			buf := &bytes.Buffer{}
-			err = artifacts.PrintBuildArtifacts(projectInfo, projectParam, nil, false, false, buf)
+			err = artifacts.PrintBuildArtifacts(projectInfo, projectParam, nil, false, false, productinfo.FormatText, buf)
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 			assert.Equal(t, tc.wantAbsFalse(projectDir), buf.String(), "Case %d: %s", i, tc.name)
 
 			buf = &bytes.Buffer{}
-			err = artifacts.PrintBuildArtifacts(projectInfo, projectParam, nil, true, false, buf)
+			err = artifacts.PrintBuildArtifacts(projectInfo, projectParam, nil, true, false, productinfo.FormatText, buf)
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 			assert.Equal(t, tc.wantAbsTrue(projectDir), buf.String(), "Case %d: %s", i, tc.name)
 		})
@@ -479,6 +480,9 @@ func TestPrintDockerArtifacts(t *testing.T) {
 								"latest", "{{Repository}}foo-db-1:latest",
 								"release", "{{Repository}}foo-db-1:release",
 							)),
+							Export: distgoconfig.ToDockerExportConfig(&distgoconfig.DockerExportConfig{
+								DockerArchive: new(true),
+							}),
 						}),
 						"docker-builder-2": distgoconfig.ToDockerBuilderConfig(distgoconfig.DockerBuilderConfig{
 							Type:       new(defaultdockerbuilder.TypeName),
@@ -544,6 +548,7 @@ repo/bar-db-1:release
 repo/foo-db-1:latest
 repo/foo-db-1:release
 repo/foo-db-2:latest
+out/docker/foo/0.1.0/foo-0.1.0-docker-builder-1.docker.tar
 `,
 		},
 		{
@@ -554,6 +559,7 @@ repo/foo-db-2:latest
 			`repo/foo-db-1:latest
 repo/foo-db-1:release
 repo/foo-db-2:latest
+out/docker/foo/0.1.0/foo-0.1.0-docker-builder-1.docker.tar
 `,
 		},
 		{
@@ -563,6 +569,7 @@ repo/foo-db-2:latest
 			},
 			`repo/foo-db-1:latest
 repo/foo-db-1:release
+out/docker/foo/0.1.0/foo-0.1.0-docker-builder-1.docker.tar
 `,
 		},
 		{
@@ -571,12 +578,13 @@ repo/foo-db-1:release
 				"foo.docker-builder-1.latest",
 			},
 			`repo/foo-db-1:latest
+out/docker/foo/0.1.0/foo-0.1.0-docker-builder-1.docker.tar
 `,
 		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			buf := &bytes.Buffer{}
-			err := artifacts.PrintDockerArtifacts(projectInfo, projectParam, tc.productDockerIDs, buf)
+			err := artifacts.PrintDockerArtifacts(projectInfo, projectParam, tc.productDockerIDs, false, productinfo.FormatText, buf)
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 			assert.Equal(t, tc.want, buf.String(), "Case %d: %s\nGot:\n%s", i, tc.name, buf.String())
 		})
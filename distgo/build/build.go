@@ -19,7 +19,6 @@ import (
 	"io"
 	"maps"
 	"os"
-	"os/exec"
 	"path"
 	"path/filepath"
 	"regexp"
@@ -30,6 +29,7 @@ import (
 	"time"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/build/sizereport"
 	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/pkg/errors"
 )
@@ -38,12 +38,23 @@ type buildUnit struct {
 	buildParam            distgo.BuildParam
 	productTaskOutputInfo distgo.ProductTaskOutputInfo
 	osArch                osarch.OSArch
+	// variant is the build variant built by the unit. Empty for the default build.
+	variant distgo.BuildVariantID
 }
 
 type Options struct {
 	Parallel bool
 	DryRun   bool
 	OSArchs  []osarch.OSArch
+	// Cover specifies that the coverage-instrumented builds of the products are created instead of the regular builds.
+	Cover bool
+	// Coverpkg overrides the "-coverpkg" patterns of the coverage-instrumented builds if non-empty.
+	Coverpkg []string
+	// SizeReport is the path of the file to which the size report of the outputs of the build is written. No report is
+	// written if empty.
+	SizeReport string
+	// SizeReportPackages specifies that the sizes of the outputs are attributed to Go packages in the size report.
+	SizeReportPackages bool
 }
 
 func Products(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productBuildIDs []distgo.ProductBuildID, buildOpts Options, stdout io.Writer) error {
@@ -56,12 +67,14 @@ func Products(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam,
 
 // Run builds the executables for the products specified by productParams using the options specified in buildOpts. If
 // buildOpts.Parallel is true, then the products will be built in parallel with N workers, where N is the number of
-// logical processors reported by Go. When builds occur in parallel, each (Product, OSArch) pair is treated as an
-// individual unit of work. Thus, it is possible that different products may be built in parallel. If any build process
-// returns an error, the first error returned is propagated back (and any builds that have not started will not be
-// started).
+// logical processors reported by Go. When builds occur in parallel, each (Product, OSArch) pair of the default build
+// and of every build variant is treated as an individual unit of work. Thus, it is possible that different products
+// may be built in parallel. If any build process returns an error, the first error returned is propagated back (and any
+// builds that have not started will not be started). If buildOpts.SizeReport is non-empty, the size report of the
+// outputs is written once all of the builds are complete.
 func Run(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam, buildOpts Options, stdout io.Writer) error {
 	var units []buildUnit
+	var builtProductParams []distgo.ProductParam
 	for _, currProductParam := range productParams {
 		currProductTaskOutputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, currProductParam)
 		if err != nil {
@@ -70,6 +83,14 @@ func Run(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam, bu
 		if currProductParam.Build == nil {
 			continue
 		}
+		if buildOpts.Cover {
+			currProductParam = coverProductParam(currProductParam, buildOpts.Coverpkg)
+			if currProductTaskOutputInfo, err = distgo.ToProductTaskOutputInfo(projectInfo, currProductParam); err != nil {
+				return errors.Wrapf(err, "failed to compute output information for %s", currProductParam.ID)
+			}
+		}
+
+		builtProductParams = append(builtProductParams, currProductParam)
 
 		// execute build script
 		if err := distgo.WriteAndExecuteScript(projectInfo, currProductParam.Build.Script, distgo.BuildScriptEnvVariables(currProductTaskOutputInfo), stdout); err != nil {
@@ -83,6 +104,16 @@ func Run(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam, bu
 				osArch:                currOSArch,
 			})
 		}
+		for _, variant := range slices.Sorted(maps.Keys(currProductParam.Build.Variants)) {
+			for _, currOSArch := range currProductParam.Build.Variants[variant].OSArchs {
+				units = append(units, buildUnit{
+					buildParam:            *currProductParam.Build,
+					productTaskOutputInfo: currProductTaskOutputInfo,
+					osArch:                currOSArch,
+					variant:               variant,
+				})
+			}
+		}
 	}
 
 	if len(units) == 1 || !buildOpts.Parallel {
@@ -118,7 +149,35 @@ func Run(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam, bu
 		}
 	}
 
-	return nil
+	return WriteSizeReport(projectInfo, builtProductParams, buildOpts, stdout)
+}
+
+// WriteSizeReport writes the size report of the build outputs of the provided products to buildOpts.SizeReport. Does
+// nothing if buildOpts.SizeReport is empty.
+func WriteSizeReport(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam, buildOpts Options, stdout io.Writer) error {
+	if buildOpts.SizeReport == "" {
+		return nil
+	}
+	if buildOpts.DryRun {
+		distgo.DryRunPrintln(stdout, fmt.Sprintf("Write size report to %s", buildOpts.SizeReport))
+		return nil
+	}
+	report, err := sizereport.New(projectInfo, productParams, buildOpts.SizeReportPackages)
+	if err != nil {
+		return err
+	}
+	return report.Write(buildOpts.SizeReport)
+}
+
+// coverProductParam returns a copy of the provided product parameter whose build creates the coverage-instrumented
+// outputs. If coverpkg is non-empty, it replaces the "-coverpkg" patterns of the build.
+func coverProductParam(productParam distgo.ProductParam, coverpkg []string) distgo.ProductParam {
+	coverBuild := productParam.Build.CoverBuild()
+	if len(coverpkg) > 0 {
+		coverBuild.Cover.Coverpkg = coverpkg
+	}
+	productParam.Build = &coverBuild
+	return productParam
 }
 
 // merge handles "fanning in" the result of multiple output channels into a single output channel. If a signal is
@@ -162,11 +221,14 @@ func worker(in <-chan buildUnit, buildOpts Options, stdout io.Writer) <-chan err
 }
 
 func executeBuild(unit buildUnit, buildOpts Options, stdout io.Writer) error {
-	name := unit.productTaskOutputInfo.Product.ID
+	name := string(unit.productTaskOutputInfo.Product.ID)
+	if unit.variant != "" {
+		name = fmt.Sprintf("%s (%s)", name, unit.variant)
+	}
 
 	osArch := unit.osArch
 	start := time.Now()
-	outputArtifactPath, ok := unit.productTaskOutputInfo.ProductBuildArtifactPaths()[osArch]
+	outputArtifactPath, ok := unit.productTaskOutputInfo.ProductBuildVariantArtifactPaths(unit.variant)[osArch]
 	if !ok {
 		return fmt.Errorf("failed to determine artifact path for %s for %s", name, osArch.String())
 	}
@@ -183,19 +245,74 @@ func executeBuild(unit buildUnit, buildOpts Options, stdout io.Writer) error {
 			return errors.Wrapf(err, "failed to create directories for %s", path.Dir(outputArtifactPath))
 		}
 	}
-	if err := doBuildAction(unit, outputArtifactPath, buildOpts.DryRun, stdout); err != nil {
+	if err := buildArtifact(unit, outputArtifactPath, buildOpts.DryRun, stdout); err != nil {
 		return errors.Wrapf(err, "go build failed")
 	}
+	if !buildOpts.DryRun {
+		if err := checkSizeBudget(unit, outputArtifactPath); err != nil {
+			return errors.Wrapf(err, "failed to build %s for %s", name, osArch.String())
+		}
+	}
 
 	elapsed := time.Since(start)
 	distgo.PrintlnOrDryRunPrintln(stdout, fmt.Sprintf("Finished building %s for %s (%.3fs)", name, osArch.String(), elapsed.Seconds()), buildOpts.DryRun)
 	return nil
 }
 
+// checkSizeBudget returns an error if the output at outputArtifactPath exceeds the size budget of the provided unit.
+// The output is removed if it exceeds its budget so that it is rebuilt (and checked again) by the next build.
+func checkSizeBudget(unit buildUnit, outputArtifactPath string) error {
+	if unit.buildParam.SizeBudget.MaxSizeForOSArch(unit.osArch) == 0 {
+		return nil
+	}
+	fi, err := os.Stat(outputArtifactPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to determine size of %s", outputArtifactPath)
+	}
+	budgetErr := unit.buildParam.SizeBudget.CheckSize(unit.osArch, outputArtifactPath, fi.Size())
+	if budgetErr == nil {
+		return nil
+	}
+	if err := os.Remove(outputArtifactPath); err != nil {
+		return errors.Wrapf(err, "failed to remove %s", outputArtifactPath)
+	}
+	return budgetErr
+}
+
+// buildArtifact builds the output of the provided unit at outputArtifactPath. The output for the darwin-universal
+// OS/Arch is created by building the executable for every darwin architecture and merging the executables into a
+// universal binary.
+func buildArtifact(unit buildUnit, outputArtifactPath string, dryRun bool, stdout io.Writer) error {
+	if unit.osArch != distgo.DarwinUniversal {
+		return doBuildAction(unit, outputArtifactPath, dryRun, stdout)
+	}
+
+	var archArtifactPaths []string
+	for _, osArch := range distgo.DarwinUniversalOSArchs() {
+		archUnit := unit
+		archUnit.osArch = osArch
+		archArtifactPath := outputArtifactPath + "-" + osArch.Arch
+		if err := doBuildAction(archUnit, archArtifactPath, dryRun, stdout); err != nil {
+			return err
+		}
+		archArtifactPaths = append(archArtifactPaths, archArtifactPath)
+	}
+	if dryRun {
+		distgo.DryRunPrintln(stdout, fmt.Sprintf("Merge %s into universal binary %s", strings.Join(archArtifactPaths, " and "), outputArtifactPath))
+		return nil
+	}
+	defer func() {
+		for _, archArtifactPath := range archArtifactPaths {
+			_ = os.Remove(archArtifactPath)
+		}
+	}()
+	return writeUniversalBinary(outputArtifactPath, archArtifactPaths...)
+}
+
 func doBuildAction(unit buildUnit, outputArtifactPath string, dryRun bool, stdout io.Writer) error {
 	osArch := unit.osArch
 
-	cmd := exec.Command("go")
+	cmd := unit.buildParam.GoToolchain.Command(unit.productTaskOutputInfo.Project.ProjectDir)
 	cmd.Dir = unit.productTaskOutputInfo.Project.ProjectDir
 
 	var env []string
@@ -205,14 +322,20 @@ func doBuildAction(unit buildUnit, outputArtifactPath string, dryRun bool, stdou
 	if osArch.Arch != "" {
 		env = append(env, "GOARCH="+osArch.Arch)
 	}
-	for _, k := range slices.Sorted(maps.Keys(unit.buildParam.Environment)) {
-		env = append(env, fmt.Sprintf("%s=%s", k, unit.buildParam.Environment[k]))
-	}
-	for _, k := range slices.Sorted(maps.Keys(unit.buildParam.OSEnvironment[osArch.OS])) {
-		env = append(env, fmt.Sprintf("%s=%s", k, unit.buildParam.OSEnvironment[osArch.OS][k]))
-	}
-	for _, k := range slices.Sorted(maps.Keys(unit.buildParam.OSArchsEnvironment[osArch.String()])) {
-		env = append(env, fmt.Sprintf("%s=%s", k, unit.buildParam.OSArchsEnvironment[osArch.String()][k]))
+	env = append(env, unit.buildParam.GoToolchain.Environment()...)
+	for _, envVars := range []map[string]string{
+		unit.buildParam.Environment,
+		unit.buildParam.OSEnvironment[osArch.OS],
+		unit.buildParam.OSArchsEnvironment[osArch.String()],
+		unit.buildParam.Variants[unit.variant].Environment,
+	} {
+		for _, k := range slices.Sorted(maps.Keys(envVars)) {
+			val, err := renderEnvironmentValue(envVars[k], unit)
+			if err != nil {
+				return errors.Wrapf(err, "failed to render value of environment variable %s", k)
+			}
+			env = append(env, fmt.Sprintf("%s=%s", k, val))
+		}
 	}
 	cmd.Env = append(os.Environ(), env...)
 
@@ -226,7 +349,7 @@ func doBuildAction(unit buildUnit, outputArtifactPath string, dryRun bool, stdou
 	}
 	args = append(args, "-o", outputArtifactPath)
 
-	buildArgs, err := unit.buildParam.BuildArgs(unit.productTaskOutputInfo)
+	buildArgs, err := unit.buildParam.BuildArgs(unit.productTaskOutputInfo, osArch, unit.variant)
 	if err != nil {
 		return err
 	}
@@ -236,6 +359,12 @@ func doBuildAction(unit buildUnit, outputArtifactPath string, dryRun bool, stdou
 	args = append(args, mainPkg)
 	cmd.Args = args
 
+	cleanupResources, err := writeWindowsResources(unit, outputArtifactPath, dryRun, stdout)
+	if err != nil {
+		return err
+	}
+	defer cleanupResources()
+
 	if dryRun {
 		dryRunMsg := fmt.Sprintf("Run: %s", strings.Join(cmd.Args, " "))
 		if len(env) > 0 {
@@ -248,7 +377,7 @@ func doBuildAction(unit buildUnit, outputArtifactPath string, dryRun bool, stdou
 			err = fmt.Errorf("build command %v run in directory %s with additional environment variables %v failed with output:\n%s", cmd.Args, cmd.Dir, env, errOutput)
 			if regexp.MustCompile(installPermissionDenied).MatchString(errOutput) {
 				// if "install" command failed due to lack of permissions, return error that contains explanation
-				return fmt.Errorf("%s", goInstallErrorMsg(osArch, err))
+				return fmt.Errorf("%s", goInstallErrorMsg(cmd.Path, osArch, err))
 			}
 			return err
 		}
@@ -258,11 +387,20 @@ func doBuildAction(unit buildUnit, outputArtifactPath string, dryRun bool, stdou
 
 const installPermissionDenied = `(?s)^go build [a-zA-Z0-9_/]+: mkdir [^:]+: permission denied.+`
 
-func goInstallErrorMsg(osArch osarch.OSArch, err error) string {
-	goBinary := "go"
-	if output, err := exec.Command("command", "-v", "go").CombinedOutput(); err == nil {
-		goBinary = strings.TrimSpace(string(output))
+// renderEnvironmentValue renders the "OSArch", "Product" and "Version" template functions in the provided environment
+// variable value for the provided build unit.
+func renderEnvironmentValue(val string, unit buildUnit) (string, error) {
+	if !strings.Contains(val, "{{") {
+		return val, nil
 	}
+	return distgo.RenderTemplate(val, nil,
+		distgo.OSArchTemplateFunction(unit.osArch),
+		distgo.ProductTemplateFunction(unit.productTaskOutputInfo.Product.Name),
+		distgo.VersionTemplateFunction(unit.productTaskOutputInfo.Project.Version),
+	)
+}
+
+func goInstallErrorMsg(goBinary string, osArch osarch.OSArch, err error) string {
 	return strings.Join([]string{
 		`failed to install a Go standard library package due to insufficient permissions to create directory.`,
 		`This typically means that the standard library for the OS/architecture combination have not been installed locally and the current user does not have write permissions to GOROOT/pkg.`,
@@ -16,6 +16,9 @@ package build_test
 
 import (
 	"bytes"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"os"
@@ -25,6 +28,8 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
+	"unicode/utf16"
 
 	"github.com/nmiyake/pkg/dirs"
 	"github.com/palantir/distgo/distgo"
@@ -146,6 +151,22 @@ echo "-X \"main.testVersionVar=$VALUE\""`
 			runExecutable: true,
 			wantOutput:    "foo bar",
 		},
+		{
+			productName:     "structuredFlagsProduct",
+			mainFileContent: testMain,
+			mainFilePath:    "main.go",
+			productParam: createBuildProductParam(func(param *distgo.ProductParam) {
+				param.Build.Flags = distgo.BuildFlagsParam{
+					LdflagsVars: map[string]string{
+						"main.testVersionVar": "{{Product}} {{Version}} dirty={{Dirty}}",
+					},
+					Trimpath: new(true),
+					Strip:    new(true),
+				}
+			}),
+			runExecutable: true,
+			wantOutput:    "testProduct " + testVersionValue + ".dirty dirty=true",
+		},
 		{
 			productName:     "foo",
 			mainFileContent: testMain,
@@ -289,6 +310,123 @@ func TestBuildEnvVars(t *testing.T) {
 				regexp.QuoteMeta(`[DRY RUN] Run: `) + `.+/windows-amd64/.+ \./foo with additional environment variables ` + regexp.QuoteMeta(`[GOOS=windows GOARCH=amd64 TEST_ENV_VAR_KEY=TEST_ENV_VAR_VALUE]`),
 			},
 		},
+		{
+			name: "Environment variable values are rendered with the OS-Arch of the target",
+			productParam: createBuildProductParam(func(param *distgo.ProductParam) {
+				param.Build.MainPkg = "./foo"
+				param.Build.OSArchs = []osarch.OSArch{
+					{
+						OS:   "darwin",
+						Arch: "arm64",
+					},
+					{
+						OS:   "linux",
+						Arch: "amd64",
+					},
+				}
+				param.Build.Environment = map[string]string{
+					"TEST_TARGET": "{{OSArch}}",
+					"TEST_CC":     "{{(OSArch).Arch}}-{{(OSArch).OS}}-cc",
+				}
+			}),
+			wantBuildOutputs: []string{
+				regexp.QuoteMeta(`[DRY RUN] Run: `) + `.+/darwin-arm64/.+ \./foo with additional environment variables ` + regexp.QuoteMeta(`[GOOS=darwin GOARCH=arm64 TEST_CC=arm64-darwin-cc TEST_TARGET=darwin-arm64]`),
+				regexp.QuoteMeta(`[DRY RUN] Run: `) + `.+/linux-amd64/.+ \./foo with additional environment variables ` + regexp.QuoteMeta(`[GOOS=linux GOARCH=amd64 TEST_CC=amd64-linux-cc TEST_TARGET=linux-amd64]`),
+			},
+		},
+		{
+			name: "Build variants are built with their environment and flags in their own output directories",
+			productParam: createBuildProductParam(func(param *distgo.ProductParam) {
+				param.Build.MainPkg = "./foo"
+				param.Build.OSArchs = []osarch.OSArch{
+					{
+						OS:   "darwin",
+						Arch: "arm64",
+					},
+					{
+						OS:   "linux",
+						Arch: "amd64",
+					},
+				}
+				param.Build.Environment = map[string]string{
+					"CGO_ENABLED": "0",
+				}
+				param.Build.Variants = map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"race": {
+						Environment: map[string]string{
+							"CGO_ENABLED": "1",
+						},
+						Flags: distgo.BuildFlagsParam{
+							Race: new(true),
+						},
+						OSArchs: []osarch.OSArch{
+							{
+								OS:   "linux",
+								Arch: "amd64",
+							},
+						},
+					},
+				}
+			}),
+			wantBuildOutputs: []string{
+				regexp.QuoteMeta(`[DRY RUN] Run: `) + `.+/darwin-arm64/testProduct \./foo with additional environment variables ` + regexp.QuoteMeta(`[GOOS=darwin GOARCH=arm64 CGO_ENABLED=0]`),
+				regexp.QuoteMeta(`[DRY RUN] Run: `) + `.+/linux-amd64/testProduct \./foo with additional environment variables ` + regexp.QuoteMeta(`[GOOS=linux GOARCH=amd64 CGO_ENABLED=0]`),
+				regexp.QuoteMeta(`[DRY RUN] Building testProduct (race) for linux-amd64 at `) + `.+/linux-amd64-race/testProduct`,
+				regexp.QuoteMeta(`[DRY RUN] Run: `) + `.+/linux-amd64-race/testProduct -race \./foo with additional environment variables ` + regexp.QuoteMeta(`[GOOS=linux GOARCH=amd64 CGO_ENABLED=0 CGO_ENABLED=1]`),
+			},
+		},
+		{
+			name: "Go toolchain selected using GOTOOLCHAIN is set in the environment",
+			productParam: createBuildProductParam(func(param *distgo.ProductParam) {
+				param.Build.MainPkg = "./foo"
+				param.Build.OSArchs = []osarch.OSArch{
+					{
+						OS:   "linux",
+						Arch: "amd64",
+					},
+				}
+				param.Build.GoToolchain = distgo.GoToolchainParam{
+					Toolchain: "go1.22.3+path",
+				}
+			}),
+			wantBuildOutputs: []string{
+				regexp.QuoteMeta(`[DRY RUN] Run: `) + `.+/linux-amd64/testProduct \./foo with additional environment variables ` + regexp.QuoteMeta(`[GOOS=linux GOARCH=amd64 GOTOOLCHAIN=go1.22.3+path]`),
+			},
+		},
+		{
+			name: "PGO profile is resolved against the project directory",
+			productParam: createBuildProductParam(func(param *distgo.ProductParam) {
+				param.Build.MainPkg = "./foo"
+				param.Build.OSArchs = []osarch.OSArch{
+					{
+						OS:   "linux",
+						Arch: "amd64",
+					},
+				}
+				param.Build.PGOProfile = "profiles/service.pgo"
+			}),
+			wantBuildOutputs: []string{
+				regexp.QuoteMeta(`[DRY RUN] Run: `) + `.+/linux-amd64/testProduct -pgo /.+/profiles/service\.pgo \./foo with additional environment variables ` + regexp.QuoteMeta(`[GOOS=linux GOARCH=amd64]`),
+			},
+		},
+		{
+			name: "Go toolchain binary is resolved against the project directory",
+			productParam: createBuildProductParam(func(param *distgo.ProductParam) {
+				param.Build.MainPkg = "./foo"
+				param.Build.OSArchs = []osarch.OSArch{
+					{
+						OS:   "linux",
+						Arch: "amd64",
+					},
+				}
+				param.Build.GoToolchain = distgo.GoToolchainParam{
+					Binary: "./toolchains/go1.22.3/bin/go",
+				}
+			}),
+			wantBuildOutputs: []string{
+				regexp.QuoteMeta(`[DRY RUN] Run: `) + `/.+/toolchains/go1\.22\.3/bin/go build -o .+/linux-amd64/testProduct \./foo with additional environment variables ` + regexp.QuoteMeta(`[GOOS=linux GOARCH=amd64]`),
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			currTmpDir := t.TempDir()
@@ -335,6 +473,346 @@ func TestBuildEnvVars(t *testing.T) {
 	}
 }
 
+func TestBuildLibraryModes(t *testing.T) {
+	const libraryMain = `package main
+
+import "C"
+
+//export Add
+func Add(a, b C.int) C.int {
+	return a + b
+}
+
+func main() {}
+`
+	for _, mode := range []distgo.BuildMode{
+		distgo.BuildModeCShared,
+		distgo.BuildModeCArchive,
+	} {
+		t.Run(string(mode), func(t *testing.T) {
+			currTmpDir := t.TempDir()
+			gittest.InitGitDir(t, currTmpDir)
+
+			err := os.WriteFile(path.Join(currTmpDir, "go.mod"), []byte("module foo"), 0644)
+			require.NoError(t, err)
+			err = os.WriteFile(path.Join(currTmpDir, "main.go"), []byte(libraryMain), 0644)
+			require.NoError(t, err)
+
+			projectInfo := distgo.ProjectInfo{
+				ProjectDir: currTmpDir,
+				Version:    testVersionValue,
+			}
+			productParam := createBuildProductParam(func(param *distgo.ProductParam) {
+				param.Build.Mode = mode
+			})
+			err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{}, io.Discard)
+			require.NoError(t, err)
+
+			productOutputInfo, err := productParam.ToProductOutputInfo(projectInfo.Version)
+			require.NoError(t, err)
+			filePaths := distgo.ProductBuildArtifactFilePaths(projectInfo, productOutputInfo, "")[osarch.Current()]
+			require.Len(t, filePaths, 2)
+			for _, filePath := range filePaths {
+				assert.FileExists(t, filePath)
+			}
+			assert.Equal(t, ".h", path.Ext(filePaths[1]))
+		})
+	}
+}
+
+func TestRequiresBuildGoToolchainVersion(t *testing.T) {
+	currTmpDir := t.TempDir()
+	gittest.InitGitDir(t, currTmpDir)
+
+	err := os.WriteFile(path.Join(currTmpDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(currTmpDir, "main.go"), []byte(testMain), 0644)
+	require.NoError(t, err)
+
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: currTmpDir,
+		Version:    testVersionValue,
+	}
+	productParam := createBuildProductParam(nil)
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{}, io.Discard)
+	require.NoError(t, err)
+
+	requiresBuild, err := build.RequiresBuild(projectInfo, productParam)
+	require.NoError(t, err)
+	assert.Nil(t, requiresBuild, "output built by the toolchain of the product should be up-to-date")
+
+	// a toolchain that reports a different version requires the output to be rebuilt
+	otherGoBinary := path.Join(t.TempDir(), "go")
+	err = os.WriteFile(otherGoBinary, []byte("#!/bin/sh\necho go1.0.0\n"), 0755)
+	require.NoError(t, err)
+	productParam.Build.GoToolchain = distgo.GoToolchainParam{
+		Binary: otherGoBinary,
+	}
+	requiresBuild, err = build.RequiresBuild(projectInfo, productParam)
+	require.NoError(t, err)
+	require.NotNil(t, requiresBuild)
+	assert.Equal(t, []osarch.OSArch{osarch.Current()}, requiresBuild.Build.OSArchs)
+}
+
+func TestRequiresBuildGoToolchainVersionDeterminedOnce(t *testing.T) {
+	currTmpDir := t.TempDir()
+	gittest.InitGitDir(t, currTmpDir)
+
+	err := os.WriteFile(path.Join(currTmpDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(currTmpDir, "main.go"), []byte(testMain), 0644)
+	require.NoError(t, err)
+
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: currTmpDir,
+		Version:    testVersionValue,
+	}
+	invocationsPath := path.Join(t.TempDir(), "invocations")
+	goBinary := path.Join(t.TempDir(), "go")
+	err = os.WriteFile(goBinary, []byte("#!/bin/sh\necho invoked >> "+invocationsPath+"\necho go1.0.0\n"), 0755)
+	require.NoError(t, err)
+
+	productParam := createBuildProductParam(nil)
+	productParam.Build.GoToolchain = distgo.GoToolchainParam{
+		Binary: goBinary,
+	}
+	for range 3 {
+		_, err = build.RequiresBuild(projectInfo, productParam)
+		require.NoError(t, err)
+	}
+	invocations, err := os.ReadFile(invocationsPath)
+	require.NoError(t, err)
+	assert.Equal(t, "invoked\n", string(invocations))
+}
+
+func TestRequiresBuildPGOProfile(t *testing.T) {
+	currTmpDir := t.TempDir()
+	gittest.InitGitDir(t, currTmpDir)
+
+	err := os.WriteFile(path.Join(currTmpDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(currTmpDir, "main.go"), []byte(testMain), 0644)
+	require.NoError(t, err)
+
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: currTmpDir,
+		Version:    testVersionValue,
+	}
+	productParam := createBuildProductParam(nil)
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{}, io.Discard)
+	require.NoError(t, err)
+
+	requiresBuild, err := build.RequiresBuild(projectInfo, productParam)
+	require.NoError(t, err)
+	assert.Nil(t, requiresBuild)
+
+	// the "default.pgo" profile of the main package is an input of the build
+	profilePath := path.Join(currTmpDir, distgo.DefaultPGOProfileName)
+	err = os.WriteFile(profilePath, []byte("profile"), 0644)
+	require.NoError(t, err)
+	future := time.Now().Add(time.Hour)
+	err = os.Chtimes(profilePath, future, future)
+	require.NoError(t, err)
+
+	requiresBuild, err = build.RequiresBuild(projectInfo, productParam)
+	require.NoError(t, err)
+	require.NotNil(t, requiresBuild)
+	assert.Equal(t, []osarch.OSArch{osarch.Current()}, requiresBuild.Build.OSArchs)
+
+	// the profile is not an input of the build if profile-guided optimization is disabled
+	productParam.Build.PGOProfile = distgo.PGOProfileOff
+	requiresBuild, err = build.RequiresBuild(projectInfo, productParam)
+	require.NoError(t, err)
+	assert.Nil(t, requiresBuild)
+}
+
+func TestBuildDarwinUniversal(t *testing.T) {
+	currTmpDir := t.TempDir()
+	gittest.InitGitDir(t, currTmpDir)
+
+	err := os.WriteFile(path.Join(currTmpDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(currTmpDir, "main.go"), []byte(testMain), 0644)
+	require.NoError(t, err)
+
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: currTmpDir,
+		Version:    testVersionValue,
+	}
+	productParam := createBuildProductParam(func(param *distgo.ProductParam) {
+		param.Build.OSArchs = []osarch.OSArch{distgo.DarwinUniversal}
+	})
+
+	outBuf := &bytes.Buffer{}
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{
+		DryRun: true,
+	}, outBuf)
+	require.NoError(t, err)
+	assert.Regexp(t, regexp.QuoteMeta(`[DRY RUN] Run: `)+`.+/darwin-universal/testProduct-amd64 .+`+regexp.QuoteMeta(`[GOOS=darwin GOARCH=amd64]`), outBuf.String())
+	assert.Regexp(t, regexp.QuoteMeta(`[DRY RUN] Run: `)+`.+/darwin-universal/testProduct-arm64 .+`+regexp.QuoteMeta(`[GOOS=darwin GOARCH=arm64]`), outBuf.String())
+	assert.Regexp(t, regexp.QuoteMeta(`[DRY RUN] Merge `)+`.+/testProduct-amd64 and .+/testProduct-arm64 into universal binary .+/darwin-universal/testProduct`, outBuf.String())
+
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{}, io.Discard)
+	require.NoError(t, err)
+
+	outputPath := path.Join(currTmpDir, "out", "build", "testProduct", testVersionValue, "darwin-universal", "testProduct")
+	fatFile, err := macho.OpenFat(outputPath)
+	require.NoError(t, err)
+	defer func() {
+		_ = fatFile.Close()
+	}()
+	var cpus []macho.Cpu
+	for _, arch := range fatFile.Arches {
+		cpus = append(cpus, arch.Cpu)
+		assert.Equal(t, macho.TypeExec, arch.Type)
+	}
+	assert.Equal(t, []macho.Cpu{macho.CpuAmd64, macho.CpuArm64}, cpus)
+
+	// the executables of the architectures are removed after they are merged
+	for _, arch := range []string{"amd64", "arm64"} {
+		assert.NoFileExists(t, outputPath+"-"+arch)
+	}
+
+	requiresBuild, err := build.RequiresBuild(projectInfo, productParam)
+	require.NoError(t, err)
+	assert.Nil(t, requiresBuild)
+}
+
+func TestBuildWindowsResources(t *testing.T) {
+	currTmpDir := t.TempDir()
+	gittest.InitGitDir(t, currTmpDir)
+
+	err := os.WriteFile(path.Join(currTmpDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(currTmpDir, "main.go"), []byte(testMain), 0644)
+	require.NoError(t, err)
+	const manifest = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><assembly xmlns="urn:schemas-microsoft-com:asm.v1" manifestVersion="1.0"></assembly>`
+	err = os.WriteFile(path.Join(currTmpDir, "app.manifest"), []byte(manifest), 0644)
+	require.NoError(t, err)
+
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: currTmpDir,
+		Version:    testVersionValue,
+	}
+	windowsAMD64 := osarch.OSArch{OS: "windows", Arch: "amd64"}
+	productParam := createBuildProductParam(func(param *distgo.ProductParam) {
+		param.Build.OSArchs = []osarch.OSArch{windowsAMD64}
+		param.Build.WindowsResources = &distgo.WindowsResourcesParam{
+			CompanyName:     "Palantir Technologies, Inc.",
+			FileDescription: "{{Product}} {{Version}}",
+			Manifest:        "app.manifest",
+		}
+	})
+
+	outBuf := &bytes.Buffer{}
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{
+		DryRun: true,
+	}, outBuf)
+	require.NoError(t, err)
+	assert.Contains(t, outBuf.String(), fmt.Sprintf("[DRY RUN] Write Windows resources to %s", path.Join(currTmpDir, "distgo_resources_windows_amd64.syso")))
+	assert.NoFileExists(t, path.Join(currTmpDir, "distgo_resources_windows_amd64.syso"))
+
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{}, io.Discard)
+	require.NoError(t, err)
+
+	// the resources file is removed after the build
+	assert.NoFileExists(t, path.Join(currTmpDir, "distgo_resources_windows_amd64.syso"))
+
+	outputPath := path.Join(currTmpDir, "out", "build", "testProduct", testVersionValue, "windows-amd64", "testProduct.exe")
+	peFile, err := pe.Open(outputPath)
+	require.NoError(t, err)
+	defer func() {
+		_ = peFile.Close()
+	}()
+	rsrc := peFile.Section(".rsrc")
+	require.NotNil(t, rsrc)
+	rsrcData, err := rsrc.Data()
+	require.NoError(t, err)
+	for _, want := range []string{"VS_VERSION_INFO", "Palantir Technologies, Inc.", "testProduct " + testVersionValue, "testProduct.exe"} {
+		assert.True(t, bytes.Contains(rsrcData, utf16Bytes(want)), "resources do not contain %q", want)
+	}
+	assert.True(t, bytes.Contains(rsrcData, []byte(manifest)), "resources do not contain the manifest")
+
+	requiresBuild, err := build.RequiresBuild(projectInfo, productParam)
+	require.NoError(t, err)
+	assert.Nil(t, requiresBuild)
+
+	// the output requires building if a resource file is modified after it was built
+	future := time.Now().Add(time.Hour)
+	err = os.Chtimes(path.Join(currTmpDir, "app.manifest"), future, future)
+	require.NoError(t, err)
+	requiresBuild, err = build.RequiresBuild(projectInfo, productParam)
+	require.NoError(t, err)
+	require.NotNil(t, requiresBuild)
+	assert.Equal(t, []osarch.OSArch{windowsAMD64}, requiresBuild.Build.OSArchs)
+}
+
+func utf16Bytes(s string) []byte {
+	buf := &bytes.Buffer{}
+	_ = binary.Write(buf, binary.LittleEndian, utf16.Encode([]rune(s)))
+	return buf.Bytes()
+}
+
+func TestBuildSizeBudget(t *testing.T) {
+	currTmpDir := t.TempDir()
+	gittest.InitGitDir(t, currTmpDir)
+
+	err := os.WriteFile(path.Join(currTmpDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(path.Join(currTmpDir, "main.go"), []byte(testMain), 0644)
+	require.NoError(t, err)
+
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: currTmpDir,
+		Version:    testVersionValue,
+	}
+	outputPath := path.Join(currTmpDir, "out", "build", "testProduct", testVersionValue, osarch.Current().String(), "testProduct")
+
+	for i, tc := range []struct {
+		name       string
+		sizeBudget distgo.BuildSizeBudgetParam
+		wantError  string
+	}{
+		{
+			name: "output within budget",
+			sizeBudget: distgo.BuildSizeBudgetParam{
+				MaxSize: 1 << 30,
+			},
+		},
+		{
+			name: "output exceeds budget",
+			sizeBudget: distgo.BuildSizeBudgetParam{
+				MaxSize: 1024,
+			},
+			wantError: `failed to build testProduct for ` + osarch.Current().String() + `: size of output .+/testProduct is [0-9]+ bytes, which exceeds its size budget of 1024 bytes by [0-9]+ bytes`,
+		},
+		{
+			name: "OS/Arch budget overrides default budget",
+			sizeBudget: distgo.BuildSizeBudgetParam{
+				MaxSize: 1 << 30,
+				OSArchsMaxSize: map[string]int64{
+					osarch.Current().String(): 1024,
+				},
+			},
+			wantError: `failed to build testProduct for ` + osarch.Current().String() + `: size of output .+/testProduct is [0-9]+ bytes, which exceeds its size budget of 1024 bytes by [0-9]+ bytes`,
+		},
+	} {
+		productParam := createBuildProductParam(func(param *distgo.ProductParam) {
+			param.Build.SizeBudget = tc.sizeBudget
+		})
+		err := build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{}, io.Discard)
+		if tc.wantError == "" {
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			assert.FileExists(t, outputPath, "Case %d: %s", i, tc.name)
+			continue
+		}
+		require.Error(t, err, "Case %d: %s", i, tc.name)
+		assert.Regexp(t, tc.wantError, err.Error(), "Case %d: %s", i, tc.name)
+		// the output that exceeds its budget is removed
+		assert.NoFileExists(t, outputPath, "Case %d: %s", i, tc.name)
+	}
+}
+
 func TestBuildOnlySpecifiedOSArchs(t *testing.T) {
 	tmp, cleanup, err := dirs.TempDir("", "")
 	defer cleanup()
@@ -15,8 +15,11 @@
 package build
 
 import (
+	"debug/buildinfo"
 	"os"
 	"path"
+	"slices"
+	"sync"
 
 	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/distgo/distgo/build/imports"
@@ -24,10 +27,11 @@ import (
 	"github.com/pkg/errors"
 )
 
-// RequiresBuild returns a pointer to a distgo.ProductParam that contains only the OS/arch parameters for the outputs
-// that require building. A product is considered to require building if its output executable does not exist or if the
-// output executable's modification date is older than any of the files (Go source, embedded, or other non-Go source)
-// required to build the product. Returns nil if all of the outputs exist and are up-to-date.
+// RequiresBuild returns a pointer to a distgo.ProductParam that contains only the OS/arch parameters (of the default
+// build and of the build variants) for the outputs that require building. A product is considered to require building
+// if its output executable does not exist or if the output executable's modification date is older than any of the
+// files (Go source, embedded, or other non-Go source) required to build the product. Returns nil if all of the outputs
+// exist and are up-to-date.
 func RequiresBuild(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam) (*distgo.ProductParam, error) {
 	if productParam.Build == nil {
 		return nil, nil
@@ -42,24 +46,116 @@ func RequiresBuild(projectInfo distgo.ProjectInfo, productParam distgo.ProductPa
 		return nil, errors.Wrapf(err, "failed to compute output information for %s", productParam.ID)
 	}
 
-	pathsMap := productTaskOutputInfo.ProductBuildArtifactPaths()
-	var requiresBuildOSArchs []osarch.OSArch
-	for _, currOSArch := range productParam.Build.OSArchs {
-		if fi, err := os.Stat(pathsMap[currOSArch]); err == nil {
-			if buildFiles, err := imports.AllFiles(path.Join(projectInfo.ProjectDir, productParam.Build.MainPkg), currOSArch.OS, currOSArch.Arch); err == nil {
-				if newerThan, err := buildFiles.NewerThan(fi); err == nil && !newerThan {
-					// if the build artifact for the product already exists and none of the input files for the
-					// product are newer than the build artifact, consider spec up-to-date
-					continue
-				}
-			}
+	goVersion := toolchainGoVersion(projectInfo.ProjectDir, productParam.Build.GoToolchain)
+
+	requiresBuildOSArchs := outdatedOSArchs(projectInfo, productParam.Build, goVersion, productParam.Build.OSArchs, productTaskOutputInfo.ProductBuildArtifactPaths())
+	var requiresBuildVariants map[distgo.BuildVariantID]distgo.BuildVariantParam
+	for variant, variantParam := range productParam.Build.Variants {
+		variantParam.OSArchs = outdatedOSArchs(projectInfo, productParam.Build, goVersion, variantParam.OSArchs, productTaskOutputInfo.ProductBuildVariantArtifactPaths(variant))
+		if len(variantParam.OSArchs) == 0 {
+			continue
 		}
-		requiresBuildOSArchs = append(requiresBuildOSArchs, currOSArch)
+		if requiresBuildVariants == nil {
+			requiresBuildVariants = make(map[distgo.BuildVariantID]distgo.BuildVariantParam)
+		}
+		requiresBuildVariants[variant] = variantParam
 	}
 
-	if len(requiresBuildOSArchs) == 0 {
+	if len(requiresBuildOSArchs) == 0 && len(requiresBuildVariants) == 0 {
 		return nil, nil
 	}
 	productParam.Build.OSArchs = requiresBuildOSArchs
+	productParam.Build.Variants = requiresBuildVariants
 	return &productParam, nil
 }
+
+type goToolchainKey struct {
+	projectDir  string
+	goToolchain distgo.GoToolchainParam
+}
+
+// goVersions caches the version of each Go toolchain determined by toolchainGoVersion so that the toolchain is run at
+// most once per process rather than once per product.
+var goVersions sync.Map
+
+// toolchainGoVersion returns the version of the provided Go toolchain for the project in the provided directory.
+// Returns an empty string if the version cannot be determined, in which case outputs are not compared against it.
+func toolchainGoVersion(projectDir string, goToolchain distgo.GoToolchainParam) string {
+	key := goToolchainKey{projectDir: projectDir, goToolchain: goToolchain}
+	if goVersion, ok := goVersions.Load(key); ok {
+		return goVersion.(string)
+	}
+	goVersion, _ := goToolchain.Version(projectDir)
+	goVersions.Store(key, goVersion)
+	return goVersion
+}
+
+// outdatedOSArchs returns the OS/Archs in osArchs whose output executable in pathsMap does not exist, is older than
+// any of the files required to build the main package, the PGO profile of the build or (for Windows) the files of the
+// Windows resources of the build, exceeds the size budget of the build, or was built by a Go toolchain whose version
+// differs from goVersion. The toolchain version is only compared if goVersion is non-empty and the output records the
+// version of the toolchain that built it.
+func outdatedOSArchs(projectInfo distgo.ProjectInfo, buildParam *distgo.BuildParam, goVersion string, osArchs []osarch.OSArch, pathsMap map[osarch.OSArch]string) []osarch.OSArch {
+	pgoProfile := buildParam.PGOProfilePath(projectInfo.ProjectDir)
+	var requiresBuildOSArchs []osarch.OSArch
+	for _, currOSArch := range osArchs {
+		inputFiles := []string{pgoProfile}
+		if currOSArch.OS == "windows" {
+			inputFiles = append(inputFiles, buildParam.WindowsResources.FilePaths(projectInfo.ProjectDir)...)
+		}
+		if fi, err := os.Stat(pathsMap[currOSArch]); err == nil && !builtByOtherGoVersion(pathsMap[currOSArch], goVersion) && !anyModifiedAfter(inputFiles, fi) && buildParam.SizeBudget.CheckSize(currOSArch, pathsMap[currOSArch], fi.Size()) == nil {
+			if !sourcesNewerThan(projectInfo, buildParam.MainPkg, currOSArch, fi) {
+				// if the build artifact for the product already exists and none of the input files for the
+				// product are newer than the build artifact, consider spec up-to-date
+				continue
+			}
+		}
+		requiresBuildOSArchs = append(requiresBuildOSArchs, currOSArch)
+	}
+	return requiresBuildOSArchs
+}
+
+// sourcesNewerThan returns true if any of the files required to build the main package for the provided OS/Arch (for
+// every architecture of a universal binary) is newer than the provided file or if the files cannot be determined.
+func sourcesNewerThan(projectInfo distgo.ProjectInfo, mainPkg string, osArch osarch.OSArch, fi os.FileInfo) bool {
+	for _, targetOSArch := range distgo.TargetOSArchs(osArch) {
+		buildFiles, err := imports.AllFiles(path.Join(projectInfo.ProjectDir, mainPkg), targetOSArch.OS, targetOSArch.Arch)
+		if err != nil {
+			return true
+		}
+		if newerThan, err := buildFiles.NewerThan(fi); err != nil || newerThan {
+			return true
+		}
+	}
+	return false
+}
+
+// modifiedAfter returns true if the file at the provided path exists and was modified after the provided file.
+func modifiedAfter(filePath string, fi os.FileInfo) bool {
+	if filePath == "" {
+		return false
+	}
+	currFi, err := os.Stat(filePath)
+	return err == nil && currFi.ModTime().After(fi.ModTime())
+}
+
+// anyModifiedAfter returns true if any of the files at the provided paths exists and was modified after the provided
+// file.
+func anyModifiedAfter(filePaths []string, fi os.FileInfo) bool {
+	return slices.ContainsFunc(filePaths, func(filePath string) bool {
+		return modifiedAfter(filePath, fi)
+	})
+}
+
+// builtByOtherGoVersion returns true if the Go build information of the file at the provided path records a Go version
+// that differs from goVersion.
+func builtByOtherGoVersion(artifactPath, goVersion string) bool {
+	if goVersion == "" {
+		return false
+	}
+	info, err := buildinfo.ReadFile(artifactPath)
+	if err != nil {
+		return false
+	}
+	return distgo.GoVersion(info.GoVersion) != goVersion
+}
@@ -0,0 +1,188 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sizereport records the sizes of the build outputs of products and compares them with the sizes recorded by a
+// previous report.
+package sizereport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/pkg/errors"
+)
+
+// Report records the sizes of the build outputs of products.
+type Report struct {
+	// Version is the version of the project when the report was created.
+	Version string `json:"version"`
+
+	// Artifacts are the build outputs. The key is the expanded ProductBuildID of the output.
+	Artifacts map[distgo.ProductBuildID]Artifact `json:"artifacts"`
+}
+
+// Artifact records the size of a build output.
+type Artifact struct {
+	// Path is the path of the output relative to the project directory.
+	Path string `json:"path"`
+
+	// Size is the size of the output in bytes.
+	Size int64 `json:"size"`
+
+	// Packages is the size in bytes of the symbols of every Go package in the output. The symbols that do not belong to
+	// a Go package are recorded under OtherPackage. Only set if package attribution was requested and the output has a
+	// symbol table.
+	Packages map[string]int64 `json:"packages,omitempty"`
+}
+
+// New returns the report for the build outputs of the provided products, which must exist. Returns an error if an
+// output exceeds the size budget of its product. If packages is true, the sizes of the outputs are also attributed to
+// the Go packages of their symbols.
+func New(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam, packages bool) (Report, error) {
+	report := Report{
+		Version:   projectInfo.Version,
+		Artifacts: make(map[distgo.ProductBuildID]Artifact),
+	}
+	for _, currProductParam := range productParams {
+		if currProductParam.Build == nil {
+			continue
+		}
+		productTaskOutputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, currProductParam)
+		if err != nil {
+			return Report{}, errors.Wrapf(err, "failed to compute output information for %s", currProductParam.ID)
+		}
+		variants := append([]distgo.BuildVariantID{""}, slices.Sorted(maps.Keys(currProductParam.Build.Variants))...)
+		for _, variant := range variants {
+			osArchs := currProductParam.Build.OSArchs
+			if variant != "" {
+				osArchs = currProductParam.Build.Variants[variant].OSArchs
+			}
+			artifactPaths := productTaskOutputInfo.ProductBuildVariantArtifactPaths(variant)
+			for _, currOSArch := range osArchs {
+				productBuildID := distgo.NewProductBuildVariantID(currProductParam.ID, currOSArch, variant)
+				artifact, err := newArtifact(projectInfo.ProjectDir, artifactPaths[currOSArch], packages)
+				if err != nil {
+					return Report{}, errors.Wrapf(err, "failed to record size of %s", productBuildID)
+				}
+				if err := currProductParam.Build.SizeBudget.CheckSize(currOSArch, artifactPaths[currOSArch], artifact.Size); err != nil {
+					return Report{}, errors.Wrapf(err, "%s", productBuildID)
+				}
+				report.Artifacts[productBuildID] = artifact
+			}
+		}
+	}
+	return report, nil
+}
+
+func newArtifact(projectDir, artifactPath string, packages bool) (Artifact, error) {
+	fi, err := os.Stat(artifactPath)
+	if err != nil {
+		return Artifact{}, errors.Wrapf(err, "build output does not exist: run the build task first")
+	}
+	artifact := Artifact{
+		Path: artifactPath,
+		Size: fi.Size(),
+	}
+	if relPath, err := filepath.Rel(projectDir, artifactPath); err == nil {
+		artifact.Path = relPath
+	}
+	if packages {
+		if artifact.Packages, err = packageSizes(artifactPath); err != nil {
+			return Artifact{}, err
+		}
+	}
+	return artifact, nil
+}
+
+// Read reads the report in the JSON file at the provided path.
+func Read(reportPath string) (Report, error) {
+	reportBytes, err := os.ReadFile(reportPath)
+	if err != nil {
+		return Report{}, errors.Wrapf(err, "failed to read size report")
+	}
+	var report Report
+	if err := json.Unmarshal(reportBytes, &report); err != nil {
+		return Report{}, errors.Wrapf(err, "failed to unmarshal size report %s", reportPath)
+	}
+	return report, nil
+}
+
+// Write writes the report as JSON to the file at the provided path.
+func (r Report) Write(reportPath string) error {
+	reportBytes, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal size report")
+	}
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory for size report")
+	}
+	if err := os.WriteFile(reportPath, append(reportBytes, '\n'), 0644); err != nil {
+		return errors.Wrapf(err, "failed to write size report")
+	}
+	return nil
+}
+
+// PrintComparison prints the size of every output of the current report and, if previous is non-nil, the previous size
+// of the output and the difference between the sizes. Outputs that only exist in the previous report are printed as
+// removed.
+func PrintComparison(w io.Writer, previous *Report, current Report) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	if previous == nil {
+		_, _ = fmt.Fprintln(tw, "PRODUCT BUILD ID\tSIZE")
+		for _, id := range slices.Sorted(maps.Keys(current.Artifacts)) {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\n", id, current.Artifacts[id].Size)
+		}
+		return tw.Flush()
+	}
+
+	ids := slices.Collect(maps.Keys(current.Artifacts))
+	for id := range previous.Artifacts {
+		if _, ok := current.Artifacts[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	_, _ = fmt.Fprintln(tw, "PRODUCT BUILD ID\tPREVIOUS\tCURRENT\tDELTA")
+	for _, id := range ids {
+		previousArtifact, inPrevious := previous.Artifacts[id]
+		currentArtifact, inCurrent := current.Artifacts[id]
+		switch {
+		case !inPrevious:
+			_, _ = fmt.Fprintf(tw, "%s\t-\t%d\tadded\n", id, currentArtifact.Size)
+		case !inCurrent:
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t-\tremoved\n", id, previousArtifact.Size)
+		default:
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n", id, previousArtifact.Size, currentArtifact.Size, formatDelta(previousArtifact.Size, currentArtifact.Size))
+		}
+	}
+	return tw.Flush()
+}
+
+// formatDelta returns the signed difference between the provided sizes and the difference as a percentage of the
+// previous size (for example, "+1024 (+2.00%)").
+func formatDelta(previous, current int64) string {
+	delta := current - previous
+	if previous == 0 {
+		return fmt.Sprintf("%+d", delta)
+	}
+	return fmt.Sprintf("%+d (%+.2f%%)", delta, float64(delta)*100/float64(previous))
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sizereport_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/build"
+	"github.com/palantir/distgo/distgo/build/sizereport"
+	"github.com/palantir/godel/v2/pkg/osarch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMain = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+}
+`
+
+func TestBuildSizeReport(t *testing.T) {
+	projectDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(projectDir, "main.go"), []byte(testMain), 0644)
+	require.NoError(t, err)
+
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: projectDir,
+		Version:    "1.0.0",
+	}
+	productParam := distgo.ProductParam{
+		ID:   "foo",
+		Name: "foo",
+		Build: &distgo.BuildParam{
+			NameTemplate: "{{Product}}",
+			MainPkg:      ".",
+			OutputDir:    "out/build",
+			OSArchs:      []osarch.OSArch{osarch.Current()},
+			Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+				"stripped": {
+					Flags: distgo.BuildFlagsParam{
+						Strip: new(true),
+					},
+					OSArchs: []osarch.OSArch{osarch.Current()},
+				},
+			},
+		},
+	}
+
+	// the report cannot be created before the outputs are built
+	_, err = sizereport.New(projectInfo, []distgo.ProductParam{productParam}, false)
+	assert.ErrorContains(t, err, "build output does not exist: run the build task first")
+
+	reportPath := filepath.Join(projectDir, "out", "size-report.json")
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{
+		SizeReport:         reportPath,
+		SizeReportPackages: true,
+	}, io.Discard)
+	require.NoError(t, err)
+
+	report, err := sizereport.Read(reportPath)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", report.Version)
+
+	defaultID := distgo.NewProductBuildID("foo", osarch.Current())
+	strippedID := distgo.NewProductBuildVariantID("foo", osarch.Current(), "stripped")
+	require.Len(t, report.Artifacts, 2)
+
+	defaultArtifact := report.Artifacts[defaultID]
+	assert.Equal(t, filepath.Join("out", "build", "foo", "1.0.0", osarch.Current().String(), "foo"), defaultArtifact.Path)
+	fi, err := os.Stat(filepath.Join(projectDir, defaultArtifact.Path))
+	require.NoError(t, err)
+	assert.Equal(t, fi.Size(), defaultArtifact.Size)
+	// the symbols are attributed to their packages and the attributed sizes never exceed the size of the file
+	assert.Greater(t, defaultArtifact.Packages["main"], int64(0))
+	assert.Greater(t, defaultArtifact.Packages["runtime"], int64(0))
+	assert.Greater(t, defaultArtifact.Packages["fmt"], int64(0))
+	var packagesSize int64
+	for _, size := range defaultArtifact.Packages {
+		packagesSize += size
+	}
+	assert.LessOrEqual(t, packagesSize, defaultArtifact.Size)
+
+	// the stripped output has no symbol table, so its size is not attributed to packages
+	strippedArtifact := report.Artifacts[strippedID]
+	assert.Less(t, strippedArtifact.Size, defaultArtifact.Size)
+	assert.Nil(t, strippedArtifact.Packages)
+
+	// the report created from the existing outputs matches the one written by the build
+	current, err := sizereport.New(projectInfo, []distgo.ProductParam{productParam}, true)
+	require.NoError(t, err)
+	assert.Equal(t, report, current)
+
+	// the size budget is checked for outputs that are up-to-date as well as for the outputs that are reported
+	productParam.Build.SizeBudget = distgo.BuildSizeBudgetParam{
+		MaxSize: 1024,
+	}
+	wantError := `size of output .+/foo is [0-9]+ bytes, which exceeds its size budget of 1024 bytes by [0-9]+ bytes`
+	_, err = sizereport.New(projectInfo, []distgo.ProductParam{productParam}, false)
+	require.Error(t, err)
+	assert.Regexp(t, wantError, err.Error())
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{
+		SizeReport: reportPath,
+	}, io.Discard)
+	require.Error(t, err)
+	assert.Regexp(t, wantError, err.Error())
+}
+
+func TestPrintComparison(t *testing.T) {
+	current := sizereport.Report{
+		Version: "1.1.0",
+		Artifacts: map[distgo.ProductBuildID]sizereport.Artifact{
+			"foo.linux-amd64":  {Size: 2100},
+			"foo.darwin-arm64": {Size: 1900},
+			"bar.linux-amd64":  {Size: 500},
+		},
+	}
+	for i, tc := range []struct {
+		previous *sizereport.Report
+		want     string
+	}{
+		{
+			previous: nil,
+			want: `PRODUCT BUILD ID  SIZE
+bar.linux-amd64   500
+foo.darwin-arm64  1900
+foo.linux-amd64   2100
+`,
+		},
+		{
+			previous: &sizereport.Report{
+				Version: "1.0.0",
+				Artifacts: map[distgo.ProductBuildID]sizereport.Artifact{
+					"foo.linux-amd64":  {Size: 2000},
+					"foo.darwin-arm64": {Size: 2000},
+					"baz.linux-amd64":  {Size: 300},
+				},
+			},
+			want: `PRODUCT BUILD ID  PREVIOUS  CURRENT  DELTA
+bar.linux-amd64   -         500      added
+baz.linux-amd64   300       -        removed
+foo.darwin-arm64  2000      1900     -100 (-5.00%)
+foo.linux-amd64   2000      2100     +100 (+5.00%)
+`,
+		},
+	} {
+		buf := &bytes.Buffer{}
+		err := sizereport.PrintComparison(buf, tc.previous, current)
+		require.NoError(t, err, "Case %d", i)
+		assert.Equal(t, tc.want, buf.String(), "Case %d", i)
+	}
+}
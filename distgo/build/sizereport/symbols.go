@@ -0,0 +1,213 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sizereport
+
+import (
+	"cmp"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// OtherPackage is the key of the package sizes of an artifact under which the sizes of the symbols that do not belong
+// to a Go package (such as type descriptors, string data and C symbols) are recorded.
+const OtherPackage = "(other)"
+
+type symbol struct {
+	name    string
+	section int
+	addr    uint64
+	size    uint64
+}
+
+// packageSizes returns the size in bytes of the symbols of every Go package in the ELF, Mach-O or PE file at the
+// provided path. The sizes of the symbols are taken from the symbol table if it records them (ELF) and are otherwise
+// approximated by the distance between the addresses of consecutive symbols. Symbols in sections that occupy no space
+// in the file are ignored. Returns nil if the file is not in one of the supported formats or has no symbol table (for
+// example, because it was built with the "-s" ldflag).
+func packageSizes(filePath string) (map[string]int64, error) {
+	syms, err := fileSymbols(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read symbols of %s", filePath)
+	}
+	if len(syms) == 0 {
+		return nil, nil
+	}
+	sizes := make(map[string]int64)
+	for _, sym := range syms {
+		sizes[symbolPackage(sym.name)] += int64(sym.size)
+	}
+	return sizes, nil
+}
+
+func fileSymbols(filePath string) ([]symbol, error) {
+	if f, err := elf.Open(filePath); err == nil {
+		defer func() {
+			_ = f.Close()
+		}()
+		return elfSymbols(f)
+	}
+	if f, err := macho.OpenFat(filePath); err == nil {
+		defer func() {
+			_ = f.Close()
+		}()
+		var syms []symbol
+		for _, arch := range f.Arches {
+			syms = append(syms, machoSymbols(arch.File)...)
+		}
+		return syms, nil
+	}
+	if f, err := macho.Open(filePath); err == nil {
+		defer func() {
+			_ = f.Close()
+		}()
+		return machoSymbols(f), nil
+	}
+	if f, err := pe.Open(filePath); err == nil {
+		defer func() {
+			_ = f.Close()
+		}()
+		return peSymbols(f), nil
+	}
+	return nil, nil
+}
+
+func elfSymbols(f *elf.File) ([]symbol, error) {
+	elfSyms, err := f.Symbols()
+	if err == elf.ErrNoSymbols {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var syms []symbol
+	sectionEnds := make(map[int]uint64)
+	for _, elfSym := range elfSyms {
+		if symType := elf.ST_TYPE(elfSym.Info); symType != elf.STT_FUNC && symType != elf.STT_OBJECT {
+			continue
+		}
+		if elfSym.Section <= elf.SHN_UNDEF || int(elfSym.Section) >= len(f.Sections) || f.Sections[elfSym.Section].Type == elf.SHT_NOBITS {
+			continue
+		}
+		sect := f.Sections[elfSym.Section]
+		sectionEnds[int(elfSym.Section)] = sect.Addr + sect.Size
+		syms = append(syms, symbol{name: elfSym.Name, section: int(elfSym.Section), addr: elfSym.Value, size: elfSym.Size})
+	}
+	return sizesFromAddresses(syms, sectionEnds), nil
+}
+
+func machoSymbols(f *macho.File) []symbol {
+	if f.Symtab == nil {
+		return nil
+	}
+	const (
+		nStab = 0xe0
+		nType = 0x0e
+		nSect = 0x0e
+
+		sectionType          = 0xff
+		sZerofill            = 0x1
+		sGBZerofill          = 0xc
+		sThreadLocalZerofill = 0x12
+	)
+	var syms []symbol
+	sectionEnds := make(map[int]uint64)
+	for _, machoSym := range f.Symtab.Syms {
+		if machoSym.Type&nStab != 0 || machoSym.Type&nType != nSect || machoSym.Sect == 0 || int(machoSym.Sect) > len(f.Sections) {
+			continue
+		}
+		sect := f.Sections[machoSym.Sect-1]
+		switch sect.Flags & sectionType {
+		case sZerofill, sGBZerofill, sThreadLocalZerofill:
+			continue
+		}
+		sectionEnds[int(machoSym.Sect)] = sect.Addr + sect.Size
+		syms = append(syms, symbol{name: machoSym.Name, section: int(machoSym.Sect), addr: machoSym.Value})
+	}
+	return sizesFromAddresses(syms, sectionEnds)
+}
+
+func peSymbols(f *pe.File) []symbol {
+	const (
+		imageSymClassExternal = 2
+		imageSymClassStatic   = 3
+	)
+	var syms []symbol
+	sectionEnds := make(map[int]uint64)
+	for _, peSym := range f.Symbols {
+		if peSym.SectionNumber <= 0 || int(peSym.SectionNumber) > len(f.Sections) {
+			continue
+		}
+		if peSym.StorageClass != imageSymClassExternal && peSym.StorageClass != imageSymClassStatic {
+			continue
+		}
+		// the part of the section beyond its size in the file (such as uninitialized data) occupies no space in the file
+		sect := f.Sections[peSym.SectionNumber-1]
+		sectionEnds[int(peSym.SectionNumber)] = uint64(min(sect.VirtualSize, sect.Size))
+		syms = append(syms, symbol{name: peSym.Name, section: int(peSym.SectionNumber), addr: uint64(peSym.Value)})
+	}
+	return sizesFromAddresses(syms, sectionEnds)
+}
+
+// sizesFromAddresses sets the size of every symbol whose size is not known to the distance between its address and the
+// address of the next symbol in its section (or the end of its section for the last symbol). Symbols that start beyond
+// the end of their section are removed.
+func sizesFromAddresses(syms []symbol, sectionEnds map[int]uint64) []symbol {
+	slices.SortStableFunc(syms, func(a, b symbol) int {
+		return cmp.Or(cmp.Compare(a.section, b.section), cmp.Compare(a.addr, b.addr))
+	})
+	var out []symbol
+	for i, sym := range syms {
+		end := sectionEnds[sym.section]
+		if sym.addr >= end {
+			continue
+		}
+		if sym.size != 0 {
+			out = append(out, sym)
+			continue
+		}
+		if i+1 < len(syms) && syms[i+1].section == sym.section {
+			end = min(end, syms[i+1].addr)
+		}
+		sym.size = end - sym.addr
+		out = append(out, sym)
+	}
+	return out
+}
+
+// symbolPackage returns the Go package of the symbol with the provided name. The package of a symbol is its name up to
+// the first "." after the last "/" (the linker escapes any "." in the last element of a package path), ignoring type
+// arguments. Returns OtherPackage if the symbol does not belong to a package.
+func symbolPackage(name string) string {
+	if strings.HasPrefix(name, "type:") || strings.HasPrefix(name, "go:") {
+		return OtherPackage
+	}
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	if name == "" || !unicode.IsLetter(rune(name[0])) {
+		return OtherPackage
+	}
+	pkgStart := strings.LastIndexByte(name, '/') + 1
+	dot := strings.IndexByte(name[pkgStart:], '.')
+	if dot <= 0 {
+		return OtherPackage
+	}
+	return name[:pkgStart+dot]
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"debug/macho"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// fatArchAlign is the alignment (as a power of 2) of the executables in a universal binary. 2^14 is the page size
+	// of arm64 macOS and is a multiple of the amd64 page size.
+	fatArchAlign = 14
+	// fatHeaderSize is the size of the "fat_header" struct and fatArchSize is the size of a "fat_arch" struct.
+	fatHeaderSize = 8
+	fatArchSize   = 20
+)
+
+// writeUniversalBinary writes a Mach-O universal ("fat") binary that contains the Mach-O executables at the provided
+// source paths to dst.
+func writeUniversalBinary(dst string, srcs ...string) (rErr error) {
+	type fatArch struct {
+		cpu    macho.Cpu
+		subCpu uint32
+		offset uint32
+		size   uint32
+		path   string
+	}
+
+	var archs []fatArch
+	offset := alignUp(fatHeaderSize+fatArchSize*uint32(len(srcs)), 1<<fatArchAlign)
+	for _, src := range srcs {
+		f, err := macho.Open(src)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read Mach-O file %s", src)
+		}
+		cpu, subCpu := f.Cpu, f.SubCpu
+		_ = f.Close()
+
+		fi, err := os.Stat(src)
+		if err != nil {
+			return errors.Wrapf(err, "failed to stat %s", src)
+		}
+		archs = append(archs, fatArch{
+			cpu:    cpu,
+			subCpu: subCpu,
+			offset: offset,
+			size:   uint32(fi.Size()),
+			path:   src,
+		})
+		offset = alignUp(offset+uint32(fi.Size()), 1<<fatArchAlign)
+	}
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create universal binary")
+	}
+	defer func() {
+		if err := out.Close(); err != nil && rErr == nil {
+			rErr = errors.Wrapf(err, "failed to close universal binary")
+		}
+	}()
+
+	header := []uint32{macho.MagicFat, uint32(len(archs))}
+	for _, arch := range archs {
+		header = append(header, uint32(arch.cpu), arch.subCpu, arch.offset, arch.size, fatArchAlign)
+	}
+	if err := binary.Write(out, binary.BigEndian, header); err != nil {
+		return errors.Wrapf(err, "failed to write universal binary header")
+	}
+	for _, arch := range archs {
+		if _, err := out.Seek(int64(arch.offset), io.SeekStart); err != nil {
+			return errors.Wrapf(err, "failed to seek in universal binary")
+		}
+		if err := copyFileContent(out, arch.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFileContent(w io.Writer, src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", src)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	if _, err := io.Copy(w, f); err != nil {
+		return errors.Wrapf(err, "failed to copy %s to universal binary", src)
+	}
+	return nil
+}
+
+func alignUp(n, align uint32) uint32 {
+	return (n + align - 1) &^ (align - 1)
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"sync"
+	"syscall"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/build/winres"
+	"github.com/pkg/errors"
+)
+
+// sysoLocks contains a *sync.Mutex for every path of a Windows resources file. A build with Windows resources holds
+// the lock for the path of the resources file for its OS/Arch in the directory of its main package for the duration of
+// the build so that concurrent builds of the same main package (for example, the variants of a product) never include
+// the resources of another build.
+var sysoLocks sync.Map
+
+// windowsResourcesSysoPath returns the path of the file that contains the Windows resources for the provided unit. The
+// name of the file has the OS/Arch suffix so that it is only included in the build for that OS/Arch.
+func windowsResourcesSysoPath(unit buildUnit) string {
+	return path.Join(unit.productTaskOutputInfo.Project.ProjectDir, unit.buildParam.MainPkg, fmt.Sprintf("distgo_resources_%s_%s.syso", unit.osArch.OS, unit.osArch.Arch))
+}
+
+// writeWindowsResources writes the Windows resources of the provided unit to the directory of its main package if the
+// unit is built for Windows and configures Windows resources. The Go tool only links the resources files that are in
+// the directory of the package, so the file cannot be provided using "-overlay". Returns a function that must be
+// called once the build is complete, which removes the resources file and releases the lock for its path. The file is
+// also removed if the process receives an interrupt or termination signal while the build is running.
+func writeWindowsResources(unit buildUnit, outputArtifactPath string, dryRun bool, stdout io.Writer) (func(), error) {
+	resourcesParam := unit.buildParam.WindowsResources
+	if unit.osArch.OS != "windows" || resourcesParam == nil {
+		return func() {}, nil
+	}
+	if !winres.SupportsArch(unit.osArch.Arch) {
+		return nil, errors.Errorf("Windows resources are not supported for %s", unit.osArch.String())
+	}
+	sysoPath := windowsResourcesSysoPath(unit)
+	if dryRun {
+		distgo.DryRunPrintln(stdout, fmt.Sprintf("Write Windows resources to %s", sysoPath))
+		return func() {}, nil
+	}
+
+	sysoBytes, err := windowsResourcesSyso(unit, resourcesParam, outputArtifactPath)
+	if err != nil {
+		return nil, err
+	}
+	lock, _ := sysoLocks.LoadOrStore(sysoPath, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	unlock := lock.(*sync.Mutex).Unlock
+	if err := os.WriteFile(sysoPath, sysoBytes, 0644); err != nil {
+		unlock()
+		return nil, errors.Wrapf(err, "failed to write Windows resources to %s", sysoPath)
+	}
+
+	signalCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	removed := make(chan struct{})
+	go func() {
+		<-signalCtx.Done()
+		_ = os.Remove(sysoPath)
+		close(removed)
+	}()
+	return func() {
+		stop()
+		<-removed
+		unlock()
+	}, nil
+}
+
+// windowsResourcesSyso returns the content of the Windows resources file for the provided unit.
+func windowsResourcesSyso(unit buildUnit, resourcesParam *distgo.WindowsResourcesParam, outputArtifactPath string) ([]byte, error) {
+	projectDir := unit.productTaskOutputInfo.Project.ProjectDir
+	productName := unit.productTaskOutputInfo.Product.Name
+	version := unit.productTaskOutputInfo.Project.Version
+
+	strs, err := resourcesParam.VersionStrings(productName, version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render Windows version information")
+	}
+	strs["OriginalFilename"] = filepath.Base(outputArtifactPath)
+	strs["InternalName"] = productName
+
+	res := winres.Resources{
+		Version: version,
+		Strings: strs,
+	}
+	if iconPath := resourcesParam.IconPath(projectDir); iconPath != "" {
+		if res.Icon, err = os.ReadFile(iconPath); err != nil {
+			return nil, errors.Wrapf(err, "failed to read icon")
+		}
+	}
+	if manifestPath := resourcesParam.ManifestPath(projectDir); manifestPath != "" {
+		if res.Manifest, err = os.ReadFile(manifestPath); err != nil {
+			return nil, errors.Wrapf(err, "failed to read manifest")
+		}
+	}
+	buf := &bytes.Buffer{}
+	if err := winres.WriteSyso(buf, unit.osArch.Arch, res); err != nil {
+		return nil, errors.Wrapf(err, "failed to compile Windows resources")
+	}
+	return buf.Bytes(), nil
+}
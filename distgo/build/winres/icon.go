@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package winres
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	iconDirSize      = 6
+	iconDirEntrySize = 16
+)
+
+// iconResources returns the resources for the provided ICO file: an icon resource for each image and the icon group
+// resource that refers to them.
+func iconResources(ico []byte) ([]resourceEntry, error) {
+	if len(ico) < iconDirSize {
+		return nil, errors.Errorf("invalid icon: file is too short")
+	}
+	reserved := binary.LittleEndian.Uint16(ico[0:])
+	iconType := binary.LittleEndian.Uint16(ico[2:])
+	count := int(binary.LittleEndian.Uint16(ico[4:]))
+	if reserved != 0 || iconType != 1 || count == 0 {
+		return nil, errors.Errorf("invalid icon: not an ICO file")
+	}
+	if len(ico) < iconDirSize+iconDirEntrySize*count {
+		return nil, errors.Errorf("invalid icon: file is too short for %d images", count)
+	}
+
+	// the group icon has the same header as the ICO file and an entry for each image that refers to the ID of its icon
+	// resource in place of its offset
+	group := &bytes.Buffer{}
+	group.Write(ico[:iconDirSize])
+
+	var entries []resourceEntry
+	for i := 0; i < count; i++ {
+		dirEntry := ico[iconDirSize+iconDirEntrySize*i:][:iconDirEntrySize]
+		size := binary.LittleEndian.Uint32(dirEntry[8:])
+		offset := binary.LittleEndian.Uint32(dirEntry[12:])
+		if uint64(offset)+uint64(size) > uint64(len(ico)) {
+			return nil, errors.Errorf("invalid icon: image %d exceeds the size of the file", i)
+		}
+		id := uint16(i + 1)
+		entries = append(entries, resourceEntry{typeID: rtIcon, id: id, data: ico[offset : offset+size]})
+
+		group.Write(dirEntry[:12])
+		_ = binary.Write(group, binary.LittleEndian, id)
+	}
+	entries = append(entries, resourceEntry{typeID: rtGroupIcon, id: 1, data: group.Bytes()})
+	return entries, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package winres
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// stringTableKey is the language (English, United States) and the code page (Unicode) of the strings.
+	stringTableKey  = "040904B0"
+	codePageUnicode = 0x04B0
+)
+
+// versionInfo returns the VS_VERSIONINFO resource for the provided version and strings.
+func versionInfo(version string, strs map[string]string) []byte {
+	allStrs := map[string]string{
+		"FileVersion":    version,
+		"ProductVersion": version,
+	}
+	for k, v := range strs {
+		allStrs[k] = v
+	}
+	var keys []string
+	for k, v := range allStrs {
+		if v != "" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var strNodes []versionNode
+	for _, k := range keys {
+		strNodes = append(strNodes, versionNode{key: k, text: true, value: utf16Bytes(allStrs[k])})
+	}
+
+	translation := &bytes.Buffer{}
+	_ = binary.Write(translation, binary.LittleEndian, []uint16{langEnUS, codePageUnicode})
+
+	return versionNode{
+		key:   "VS_VERSION_INFO",
+		value: fixedFileInfo(version),
+		children: []versionNode{
+			{
+				key:  "StringFileInfo",
+				text: true,
+				children: []versionNode{
+					{key: stringTableKey, text: true, children: strNodes},
+				},
+			},
+			{
+				key:  "VarFileInfo",
+				text: true,
+				children: []versionNode{
+					{key: "Translation", value: translation.Bytes()},
+				},
+			},
+		},
+	}.encode()
+}
+
+// versionNode is a structure of a version resource, which consists of a header, a key, an optional value and children.
+type versionNode struct {
+	key string
+	// text is true if the value of the node is text, in which case its length is specified in words rather than bytes
+	text     bool
+	value    []byte
+	children []versionNode
+}
+
+func (n versionNode) encode() []byte {
+	buf := &bytes.Buffer{}
+	valueLength := len(n.value)
+	if n.text {
+		valueLength /= 2
+	}
+	nodeType := uint16(0)
+	if n.text {
+		nodeType = 1
+	}
+	// the length is written once the node has been encoded
+	_ = binary.Write(buf, binary.LittleEndian, []uint16{0, uint16(valueLength), nodeType})
+	buf.Write(utf16Bytes(n.key))
+	pad32(buf)
+	buf.Write(n.value)
+	for _, child := range n.children {
+		pad32(buf)
+		buf.Write(child.encode())
+	}
+	out := buf.Bytes()
+	binary.LittleEndian.PutUint16(out, uint16(len(out)))
+	return out
+}
+
+// fixedFileInfo returns the VS_FIXEDFILEINFO structure for the provided version.
+func fixedFileInfo(version string) []byte {
+	const (
+		signature      = 0xFEEF04BD
+		structVersion  = 0x00010000
+		fileFlagsMask  = 0x3F
+		vosNTWindows32 = 0x00040004
+		vftApp         = 0x1
+	)
+	v := numericVersion(version)
+	versionMS := uint32(v[0])<<16 | uint32(v[1])
+	versionLS := uint32(v[2])<<16 | uint32(v[3])
+	buf := &bytes.Buffer{}
+	_ = binary.Write(buf, binary.LittleEndian, []uint32{
+		signature, structVersion,
+		versionMS, versionLS, // file version
+		versionMS, versionLS, // product version
+		fileFlagsMask, 0, vosNTWindows32, vftApp, 0,
+		0, 0, // file date
+	})
+	return buf.Bytes()
+}
+
+// numericVersion returns the leading numeric components (up to 4) of the provided version. For example, the numeric
+// version of "1.2.3-rc1" is [1 2 3 0].
+func numericVersion(version string) [4]uint16 {
+	var v [4]uint16
+	version = strings.TrimPrefix(version, "v")
+	for i, part := range strings.SplitN(version, ".", 4) {
+		end := 0
+		for end < len(part) && part[end] >= '0' && part[end] <= '9' {
+			end++
+		}
+		n, err := strconv.ParseUint(part[:end], 10, 16)
+		if err != nil {
+			break
+		}
+		v[i] = uint16(n)
+		if end != len(part) {
+			break
+		}
+	}
+	return v
+}
+
+// utf16Bytes returns the null-terminated UTF-16 encoding of the provided string.
+func utf16Bytes(s string) []byte {
+	buf := &bytes.Buffer{}
+	_ = binary.Write(buf, binary.LittleEndian, append(utf16.Encode([]rune(s)), 0))
+	return buf.Bytes()
+}
+
+func pad32(buf *bytes.Buffer) {
+	for buf.Len()%4 != 0 {
+		buf.WriteByte(0)
+	}
+}
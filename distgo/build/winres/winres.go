@@ -0,0 +1,240 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package winres compiles Windows resources (version information, icon and application manifest) into a COFF object
+// file (".syso") that the Go linker includes in Windows executables.
+package winres
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Resources are the resources that are compiled into a Windows executable.
+type Resources struct {
+	// Version is the version of the file and of the product. Its leading numeric components (up to 4) are used as the
+	// numeric version and the full value is used as the "FileVersion" and "ProductVersion" strings.
+	Version string
+
+	// Strings are the values of the other strings of the version information, such as "CompanyName" or
+	// "FileDescription". Empty values are omitted.
+	Strings map[string]string
+
+	// Icon is the content of an ICO file that is used as the icon of the executable. No icon is included if empty.
+	Icon []byte
+
+	// Manifest is the content of the application manifest of the executable. No manifest is included if empty.
+	Manifest []byte
+}
+
+// resource types and IDs
+const (
+	rtIcon      = 3
+	rtGroupIcon = 14
+	rtVersion   = 16
+	rtManifest  = 24
+
+	// langEnUS is the language of all of the resources (English, United States).
+	langEnUS = 0x0409
+)
+
+// COFF constants
+const (
+	imageFileMachineI386  = 0x14c
+	imageFileMachineAMD64 = 0x8664
+	imageFileMachineARM64 = 0xaa64
+
+	imageRelI386Dir32NB   = 0x7
+	imageRelAMD64Addr32NB = 0x3
+	imageRelARM64Addr32NB = 0x2
+
+	imageScnCntInitializedData = 0x40
+	imageScnMemRead            = 0x40000000
+
+	imageSymClassStatic = 3
+
+	fileHeaderSize    = 20
+	sectionHeaderSize = 40
+)
+
+type machine struct {
+	id        uint16
+	relocType uint16
+}
+
+var machines = map[string]machine{
+	"386":   {id: imageFileMachineI386, relocType: imageRelI386Dir32NB},
+	"amd64": {id: imageFileMachineAMD64, relocType: imageRelAMD64Addr32NB},
+	"arm64": {id: imageFileMachineARM64, relocType: imageRelARM64Addr32NB},
+}
+
+// SupportsArch returns true if resources can be compiled for the provided GOARCH.
+func SupportsArch(goarch string) bool {
+	_, ok := machines[goarch]
+	return ok
+}
+
+// WriteSyso writes a COFF object file that contains the provided resources for the provided GOARCH to w.
+func WriteSyso(w io.Writer, goarch string, res Resources) error {
+	m, ok := machines[goarch]
+	if !ok {
+		return errors.Errorf("Windows resources are not supported for architecture %s", goarch)
+	}
+
+	var entries []resourceEntry
+	if len(res.Icon) > 0 {
+		iconEntries, err := iconResources(res.Icon)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, iconEntries...)
+	}
+	entries = append(entries, resourceEntry{typeID: rtVersion, id: 1, data: versionInfo(res.Version, res.Strings)})
+	if len(res.Manifest) > 0 {
+		entries = append(entries, resourceEntry{typeID: rtManifest, id: 1, data: res.Manifest})
+	}
+	section, relocOffsets := resourceSection(entries)
+
+	// the section data is followed by the relocations, the symbol table (which consists of the symbol of the section)
+	// and the (empty) string table
+	sectionDataOffset := uint32(fileHeaderSize + sectionHeaderSize)
+	relocsOffset := sectionDataOffset + uint32(len(section))
+	symbolTableOffset := relocsOffset + uint32(10*len(relocOffsets))
+
+	buf := &bytes.Buffer{}
+	write := func(vals ...any) {
+		for _, val := range vals {
+			_ = binary.Write(buf, binary.LittleEndian, val)
+		}
+	}
+	// file header
+	write(m.id, uint16(1), uint32(0), symbolTableOffset, uint32(1), uint16(0), uint16(0))
+	// section header
+	write([8]byte{'.', 'r', 's', 'r', 'c'}, uint32(0), uint32(0), uint32(len(section)), sectionDataOffset, relocsOffset,
+		uint32(0), uint16(len(relocOffsets)), uint16(0), uint32(imageScnCntInitializedData|imageScnMemRead))
+	buf.Write(section)
+	// relocations of the data entries, which contain the offsets of the data relative to the section
+	for _, offset := range relocOffsets {
+		write(offset, uint32(0), m.relocType)
+	}
+	// symbol of the section
+	write([8]byte{'.', 'r', 's', 'r', 'c'}, uint32(0), int16(1), uint16(0), uint8(imageSymClassStatic), uint8(0))
+	// string table, which only consists of its size
+	write(uint32(4))
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return errors.Wrapf(err, "failed to write COFF object")
+	}
+	return nil
+}
+
+type resourceEntry struct {
+	typeID uint16
+	id     uint16
+	data   []byte
+}
+
+// resourceSection returns the content of the ".rsrc" section for the provided resources and the offsets of the fields
+// of the data entries that must be relocated. The section consists of the three levels (type, ID and language) of
+// resource directories, followed by the data entries and the data of the resources.
+func resourceSection(entries []resourceEntry) ([]byte, []uint32) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].typeID != entries[j].typeID {
+			return entries[i].typeID < entries[j].typeID
+		}
+		return entries[i].id < entries[j].id
+	})
+	var typeIDs []uint16
+	entriesByType := make(map[uint16][]int)
+	for i, entry := range entries {
+		if _, ok := entriesByType[entry.typeID]; !ok {
+			typeIDs = append(typeIDs, entry.typeID)
+		}
+		entriesByType[entry.typeID] = append(entriesByType[entry.typeID], i)
+	}
+
+	const (
+		dirSize       = 16
+		dirEntrySize  = 8
+		dataEntrySize = 16
+		subdirFlag    = 0x80000000
+	)
+	// compute the offsets of all of the directories, the data entries and the data
+	typeDirSize := uint32(dirSize + dirEntrySize*len(typeIDs))
+	idDirOffsets := make(map[uint16]uint32)
+	offset := typeDirSize
+	for _, typeID := range typeIDs {
+		idDirOffsets[typeID] = offset
+		offset += uint32(dirSize + dirEntrySize*len(entriesByType[typeID]))
+	}
+	langDirOffsets := make([]uint32, len(entries))
+	for i := range entries {
+		langDirOffsets[i] = offset
+		offset += dirSize + dirEntrySize
+	}
+	dataEntryOffsets := make([]uint32, len(entries))
+	for i := range entries {
+		dataEntryOffsets[i] = offset
+		offset += dataEntrySize
+	}
+	dataOffsets := make([]uint32, len(entries))
+	for i, entry := range entries {
+		offset = alignUp(offset, 8)
+		dataOffsets[i] = offset
+		offset += uint32(len(entry.data))
+	}
+
+	buf := &bytes.Buffer{}
+	write := func(vals ...any) {
+		for _, val := range vals {
+			_ = binary.Write(buf, binary.LittleEndian, val)
+		}
+	}
+	writeDir := func(numIDEntries int) {
+		write(uint32(0), uint32(0), uint16(0), uint16(0), uint16(0), uint16(numIDEntries))
+	}
+
+	writeDir(len(typeIDs))
+	for _, typeID := range typeIDs {
+		write(uint32(typeID), idDirOffsets[typeID]|subdirFlag)
+	}
+	for _, typeID := range typeIDs {
+		writeDir(len(entriesByType[typeID]))
+		for _, i := range entriesByType[typeID] {
+			write(uint32(entries[i].id), langDirOffsets[i]|subdirFlag)
+		}
+	}
+	for i := range entries {
+		writeDir(1)
+		write(uint32(langEnUS), dataEntryOffsets[i])
+	}
+	var relocOffsets []uint32
+	for i, entry := range entries {
+		relocOffsets = append(relocOffsets, uint32(buf.Len()))
+		write(dataOffsets[i], uint32(len(entry.data)), uint32(0), uint32(0))
+	}
+	for i, entry := range entries {
+		buf.Write(make([]byte, int(dataOffsets[i])-buf.Len()))
+		buf.Write(entry.data)
+	}
+	return buf.Bytes(), relocOffsets
+}
+
+func alignUp(n, align uint32) uint32 {
+	return (n + align - 1) &^ (align - 1)
+}
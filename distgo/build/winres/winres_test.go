@@ -0,0 +1,138 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package winres_test
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"testing"
+
+	"github.com/palantir/distgo/distgo/build/winres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testIcon is an ICO file with a single 16x16 image whose data is "ABCD".
+var testIcon = []byte{0, 0, 1, 0, 1, 0, 16, 16, 0, 0, 1, 0, 32, 0, 4, 0, 0, 0, 22, 0, 0, 0, 'A', 'B', 'C', 'D'}
+
+func TestWriteSyso(t *testing.T) {
+	for i, tc := range []struct {
+		goarch      string
+		wantMachine uint16
+		res         winres.Resources
+		wantRelocs  int
+	}{
+		{
+			goarch:      "amd64",
+			wantMachine: pe.IMAGE_FILE_MACHINE_AMD64,
+			res:         winres.Resources{Version: "1.2.3"},
+			wantRelocs:  1,
+		},
+		{
+			goarch:      "386",
+			wantMachine: pe.IMAGE_FILE_MACHINE_I386,
+			res:         winres.Resources{Version: "1.2.3", Manifest: []byte("<assembly/>")},
+			wantRelocs:  2,
+		},
+		{
+			goarch:      "arm64",
+			wantMachine: pe.IMAGE_FILE_MACHINE_ARM64,
+			res:         winres.Resources{Version: "1.2.3", Icon: testIcon, Manifest: []byte("<assembly/>")},
+			wantRelocs:  4,
+		},
+	} {
+		buf := &bytes.Buffer{}
+		err := winres.WriteSyso(buf, tc.goarch, tc.res)
+		require.NoError(t, err, "Case %d", i)
+
+		f, err := pe.NewFile(bytes.NewReader(buf.Bytes()))
+		require.NoError(t, err, "Case %d", i)
+		assert.Equal(t, tc.wantMachine, f.Machine, "Case %d", i)
+		require.Len(t, f.Sections, 1, "Case %d", i)
+		assert.Equal(t, ".rsrc", f.Sections[0].Name, "Case %d", i)
+		assert.Len(t, f.Sections[0].Relocs, tc.wantRelocs, "Case %d", i)
+		require.Len(t, f.Symbols, 1, "Case %d", i)
+		assert.Equal(t, ".rsrc", f.Symbols[0].Name, "Case %d", i)
+
+		data, err := f.Sections[0].Data()
+		require.NoError(t, err, "Case %d", i)
+		if tc.res.Icon != nil {
+			assert.True(t, bytes.Contains(data, []byte("ABCD")), "Case %d: section does not contain the icon", i)
+		}
+		if tc.res.Manifest != nil {
+			assert.True(t, bytes.Contains(data, tc.res.Manifest), "Case %d: section does not contain the manifest", i)
+		}
+	}
+}
+
+func TestWriteSysoFixedFileVersion(t *testing.T) {
+	for i, tc := range []struct {
+		version string
+		want    [4]uint16
+	}{
+		{"1.2.3", [4]uint16{1, 2, 3, 0}},
+		{"v1.2.3", [4]uint16{1, 2, 3, 0}},
+		{"1.2.3-rc1", [4]uint16{1, 2, 3, 0}},
+		{"1.2.3-4-gabcdef0.dirty", [4]uint16{1, 2, 3, 0}},
+		{"2.10.0.7.8", [4]uint16{2, 10, 0, 7}},
+		{"unspecified", [4]uint16{0, 0, 0, 0}},
+	} {
+		buf := &bytes.Buffer{}
+		err := winres.WriteSyso(buf, "amd64", winres.Resources{Version: tc.version})
+		require.NoError(t, err, "Case %d", i)
+
+		// the fixed file information starts with its signature, which is followed by the structure version and the file
+		// version
+		sigIdx := bytes.Index(buf.Bytes(), []byte{0xBD, 0x04, 0xEF, 0xFE})
+		require.NotEqual(t, -1, sigIdx, "Case %d", i)
+		fixedFileInfo := buf.Bytes()[sigIdx:]
+		versionMS := binary.LittleEndian.Uint32(fixedFileInfo[8:])
+		versionLS := binary.LittleEndian.Uint32(fixedFileInfo[12:])
+		got := [4]uint16{uint16(versionMS >> 16), uint16(versionMS), uint16(versionLS >> 16), uint16(versionLS)}
+		assert.Equal(t, tc.want, got, "Case %d", i)
+	}
+}
+
+func TestWriteSysoErrors(t *testing.T) {
+	for i, tc := range []struct {
+		goarch  string
+		res     winres.Resources
+		wantErr string
+	}{
+		{
+			goarch:  "arm",
+			wantErr: "Windows resources are not supported for architecture arm",
+		},
+		{
+			goarch:  "amd64",
+			res:     winres.Resources{Icon: []byte("not an icon")},
+			wantErr: "invalid icon: not an ICO file",
+		},
+		{
+			goarch:  "amd64",
+			res:     winres.Resources{Icon: testIcon[:20]},
+			wantErr: "invalid icon: file is too short for 1 images",
+		},
+		{
+			goarch:  "amd64",
+			res:     winres.Resources{Icon: testIcon[:24]},
+			wantErr: "invalid icon: image 0 exceeds the size of the file",
+		},
+	} {
+		err := winres.WriteSyso(&bytes.Buffer{}, tc.goarch, tc.res)
+		assert.EqualError(t, err, tc.wantErr, "Case %d", i)
+	}
+}
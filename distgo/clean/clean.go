@@ -56,12 +56,24 @@ func Run(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, dryRu
 			return err
 		}
 
-		// add build directory for product for removal
+		// add build and coverage-instrumented build directories for product for removal
 		if currProductParam.Build != nil {
 			removePaths[path.Dir(outputInfo.ProductBuildOutputDir())] = pathInfo{
 				rootDir: projectInfo.ProjectDir,
 				isDir:   true,
 			}
+
+			coverProductParam := currProductParam
+			coverBuild := currProductParam.Build.CoverBuild()
+			coverProductParam.Build = &coverBuild
+			coverOutputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, coverProductParam)
+			if err != nil {
+				return err
+			}
+			removePaths[path.Dir(coverOutputInfo.ProductBuildOutputDir())] = pathInfo{
+				rootDir: projectInfo.ProjectDir,
+				isDir:   true,
+			}
 		}
 
 		// add dist directory for product for removal
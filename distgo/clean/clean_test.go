@@ -119,6 +119,49 @@ func TestClean(t *testing.T) {
 				assert.True(t, os.IsNotExist(err))
 			},
 		},
+		{
+			"cleans coverage-instrumented build output",
+			distgoconfig.ProjectConfig{
+				Products: distgoconfig.ToProductsMap(map[distgo.ProductID]distgoconfig.ProductConfig{
+					"foo": {
+						Build: distgoconfig.ToBuildConfig(&distgoconfig.BuildConfig{
+							MainPkg: new("foo"),
+						}),
+					},
+				}),
+			},
+			func(t *testing.T, projectDir string) {
+				err := files.WriteGoFiles(projectDir, []gofiles.GoFileSpec{
+					{
+						RelPath: "go.mod",
+						Src:     `module foo`,
+					},
+					{
+						RelPath: "foo/main.go",
+						Src:     "package main; func main(){}",
+					},
+				})
+				require.NoError(t, err)
+				gittest.CommitAllFiles(t, projectDir, "Add foo")
+
+				gittest.CreateGitTag(t, projectDir, "0.1.0")
+			},
+			func(t *testing.T, projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam) {
+				err := build.Products(projectInfo, projectParam, nil, build.Options{
+					Cover: true,
+				}, io.Discard)
+				require.NoError(t, err)
+
+				coverOutput := path.Join(projectInfo.ProjectDir, "out", "cover", "foo", "0.1.0", osarch.Current().String(), "foo")
+				_, err = os.Stat(coverOutput)
+				require.NoError(t, err, "expected coverage-instrumented build output to exist at %s", coverOutput)
+			},
+			func(t *testing.T, caseNum int, name string, projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam) {
+				outputDir := path.Join(projectInfo.ProjectDir, "out")
+				_, err := os.Stat(outputDir)
+				assert.True(t, os.IsNotExist(err))
+			},
+		},
 		{
 			"cleans build output for multiple versions",
 			distgoconfig.ProjectConfig{
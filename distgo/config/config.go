@@ -17,11 +17,13 @@ package config
 import (
 	"fmt"
 	"maps"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/palantir/distgo/distgo"
 	v0 "github.com/palantir/distgo/distgo/config/internal/v0"
+	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/palantir/pkg/matcher"
 	"github.com/pkg/errors"
 )
@@ -46,6 +48,37 @@ func (cfg *ProjectConfig) ToParam(
 	defaultDisterCfg DisterConfig,
 	dockerBuilderFactory distgo.DockerBuilderFactory,
 	publisherFactory distgo.PublisherFactory) (distgo.ProjectParam, error) {
+	return cfg.toParam(projectDir, projectVersionerFactory, disterFactory, defaultDisterCfg, dockerBuilderFactory, publisherFactory, true)
+}
+
+// ToGraphParam returns the parameter for the project without resolving the dependencies of its products: the
+// AllDependencies of the products are not set, the dependencies are not checked for cycles and the Docker and dist
+// inputs that refer to other products are not verified or expanded. Used to inspect the product graph of projects
+// whose dependencies are not valid.
+func (cfg *ProjectConfig) ToGraphParam(
+	projectDir string,
+	projectVersionerFactory distgo.ProjectVersionerFactory,
+	disterFactory distgo.DisterFactory,
+	defaultDisterCfg DisterConfig,
+	dockerBuilderFactory distgo.DockerBuilderFactory,
+	publisherFactory distgo.PublisherFactory) (distgo.ProjectParam, error) {
+	return cfg.toParam(projectDir, projectVersionerFactory, disterFactory, defaultDisterCfg, dockerBuilderFactory, publisherFactory, false)
+}
+
+func (cfg *ProjectConfig) toParam(
+	projectDir string,
+	projectVersionerFactory distgo.ProjectVersionerFactory,
+	disterFactory distgo.DisterFactory,
+	defaultDisterCfg DisterConfig,
+	dockerBuilderFactory distgo.DockerBuilderFactory,
+	publisherFactory distgo.PublisherFactory,
+	resolveDependencies bool) (distgo.ProjectParam, error) {
+
+	renderedCfg, err := cfg.renderConfigTemplates(projectDir)
+	if err != nil {
+		return distgo.ProjectParam{}, err
+	}
+	cfg = &renderedCfg
 
 	var exclude matcher.Matcher
 	if !cfg.Exclude.Empty() {
@@ -73,8 +106,11 @@ func (cfg *ProjectConfig) ToParam(
 			return distgo.ProjectParam{}, errors.Errorf("ProductID cannot contain a '.': %s", productID)
 		}
 
-		productCfg := productCfg
-		productParam, err := (*ProductConfig)(&productCfg).ToParam(productID, cfg.ScriptIncludes, ProductConfig(cfg.ProductDefaults), disterFactory, dockerBuilderFactory)
+		mergedCfg, defaultCfg, _, err := cfg.mergeProductDefaults(productID, productCfg)
+		if err != nil {
+			return distgo.ProjectParam{}, err
+		}
+		productParam, err := mergedCfg.ToParam(productID, cfg.ScriptIncludes, defaultCfg, disterFactory, dockerBuilderFactory)
 		if err != nil {
 			return distgo.ProjectParam{}, err
 		}
@@ -83,6 +119,31 @@ func (cfg *ProjectConfig) ToParam(
 	}
 	sort.Sort(distgo.ByProductID(productIDs))
 
+	if resolveDependencies {
+		if err := resolveProductDependencies(products, productIDs); err != nil {
+			return distgo.ProjectParam{}, err
+		}
+	}
+
+	projectVersionerCfg := (*ProjectVersionConfig)(cfg.ProjectVersioner)
+	projectVersionerParam, err := projectVersionerCfg.ToParam(projectVersionerFactory)
+	if err != nil {
+		return distgo.ProjectParam{}, err
+	}
+
+	projectParam := distgo.ProjectParam{
+		Products:              products,
+		ScriptIncludes:        cfg.ScriptIncludes,
+		ProjectVersionerParam: projectVersionerParam,
+		Exclude:               exclude,
+	}
+	return projectParam, nil
+}
+
+// resolveProductDependencies sets the AllDependencies of the provided products, verifies that the dependencies do not
+// contain cycles and verifies and expands the Docker and dist inputs that refer to other products. The products are
+// updated in place.
+func resolveProductDependencies(products map[distgo.ProductID]distgo.ProductParam, productIDs []distgo.ProductID) error {
 	// compute full dependencies for all products (and error if cycles exist)
 	cycleErrors := make(map[distgo.ProductID]error)
 	for _, currProduct := range productIDs {
@@ -109,7 +170,7 @@ func (cfg *ProjectConfig) ToParam(
 		for _, currKey := range sortedKeys {
 			errOutputParts = append(errOutputParts, fmt.Sprintf("%s: %v", currKey, cycleErrors[currKey]))
 		}
-		return distgo.ProjectParam{}, errors.Errorf("%s", strings.Join(errOutputParts, "\n  "))
+		return errors.Errorf("%s", strings.Join(errOutputParts, "\n  "))
 	}
 
 	// perform verification of ProductBuildID and ProductDistID dependencies in Docker outputs. Must be performed after
@@ -130,16 +191,52 @@ func (cfg *ProjectConfig) ToParam(
 			// verify that input builds for product are syntactically valid and specify legal products
 			inputBuildProducts, err := distgo.ProductParamsForBuildProductArgs(productSubmap, nil, dockerBuilderParam.InputBuilds...)
 			if err != nil {
-				return distgo.ProjectParam{}, errors.Errorf("invalid Docker input build(s) specified for DockerBuilderParam %q for product %q", dockerID, productID)
+				return errors.Errorf("invalid Docker input build(s) specified for DockerBuilderParam %q for product %q", dockerID, productID)
+			}
+			// build variants are only used as inputs if they are specified explicitly
+			inputBuildVariants := make(map[distgo.ProductID]map[distgo.BuildVariantID]struct{})
+			for _, productBuildID := range dockerBuilderParam.InputBuilds {
+				variant := productBuildID.Variant()
+				if variant == "" {
+					continue
+				}
+				inputBuildProductID, _, _ := productBuildID.Parse()
+				if inputBuildVariants[inputBuildProductID] == nil {
+					inputBuildVariants[inputBuildProductID] = make(map[distgo.BuildVariantID]struct{})
+				}
+				inputBuildVariants[inputBuildProductID][variant] = struct{}{}
 			}
-			// input parameters are valid, but there may be product-level specifications. Expand all to "ProductID.OSArch" form.
+			// input parameters are valid, but there may be product-level specifications. Expand all to
+			// "ProductID.OSArch" or "ProductID.OSArch@Variant" form.
 			var expandedProductBuildIDs []distgo.ProductBuildID
 			for _, productParam := range inputBuildProducts {
 				if productParam.Build == nil {
 					continue
 				}
+				inputOSArchs := make(map[osarch.OSArch]distgo.ProductBuildID)
+				addInputBuild := func(osArch osarch.OSArch, variant distgo.BuildVariantID) error {
+					id := distgo.NewProductBuildVariantID(productParam.ID, osArch, variant)
+					if prevID, ok := inputOSArchs[osArch]; ok {
+						return errors.Errorf("invalid Docker input builds specified for DockerBuilderParam %q for product %q: %s and %s are both builds of %s for %s", dockerID, productID, prevID, id, productParam.ID, osArch)
+					}
+					inputOSArchs[osArch] = id
+					expandedProductBuildIDs = append(expandedProductBuildIDs, id)
+					return nil
+				}
 				for _, osArch := range productParam.Build.OSArchs {
-					expandedProductBuildIDs = append(expandedProductBuildIDs, distgo.NewProductBuildID(productParam.ID, osArch))
+					if err := addInputBuild(osArch, ""); err != nil {
+						return err
+					}
+				}
+				for _, variant := range slices.Sorted(maps.Keys(productParam.Build.Variants)) {
+					if _, ok := inputBuildVariants[productParam.ID][variant]; !ok {
+						continue
+					}
+					for _, osArch := range productParam.Build.Variants[variant].OSArchs {
+						if err := addInputBuild(osArch, variant); err != nil {
+							return err
+						}
+					}
 				}
 			}
 			// assign updated slice to DockerBuilderParam and update in DockerBuilderParams map so that update is persistent
@@ -149,7 +246,7 @@ func (cfg *ProjectConfig) ToParam(
 			// verify that input dists for product are syntactically valid and specify legal products
 			inputDistProducts, err := distgo.ProductParamsForDistProductArgs(productSubmap, dockerBuilderParam.InputDists...)
 			if err != nil {
-				return distgo.ProjectParam{}, errors.Errorf("invalid Docker input dist(s) specified for DockerBuilderParam %q for product %q", dockerID, productID)
+				return errors.Errorf("invalid Docker input dist(s) specified for DockerBuilderParam %q for product %q", dockerID, productID)
 			}
 			// input parameters are valid, but there may be product-level specifications. Expand all to "ProductID.DistID" form.
 			var expandedProductDistIDs []distgo.ProductDistID
@@ -181,7 +278,7 @@ func (cfg *ProjectConfig) ToParam(
 			}
 			if len(invalidInputDistsOutputPathsKeys) > 0 {
 				sort.Strings(invalidInputDistsOutputPathsKeys)
-				return distgo.ProjectParam{}, errors.Errorf("invalid InputDistsOutputPaths ProductDistIDs %v for DockerBuilderParam %q for product %q -- valid values are %v", invalidInputDistsOutputPathsKeys, dockerID, productID, expandedProductDistIDs)
+				return errors.Errorf("invalid InputDistsOutputPaths ProductDistIDs %v for DockerBuilderParam %q for product %q -- valid values are %v", invalidInputDistsOutputPathsKeys, dockerID, productID, expandedProductDistIDs)
 			}
 
 			// assign updated slice to DockerBuilderParam and update in DockerBuilderParams map so that update is persistent
@@ -190,19 +287,34 @@ func (cfg *ProjectConfig) ToParam(
 		}
 	}
 
-	projectVersionerCfg := (*ProjectVersionConfig)(cfg.ProjectVersioner)
-	projectVersionerParam, err := projectVersionerCfg.ToParam(projectVersionerFactory)
-	if err != nil {
-		return distgo.ProjectParam{}, err
-	}
-
-	projectParam := distgo.ProjectParam{
-		Products:              products,
-		ScriptIncludes:        cfg.ScriptIncludes,
-		ProjectVersionerParam: projectVersionerParam,
-		Exclude:               exclude,
+	// verify that the Docker exports used as dist inputs specify legal products and expand them to
+	// "ProductID.DockerID" form. Must be performed after products are checked for cycles.
+	for _, productID := range productIDs {
+		productParam := products[productID]
+		if productParam.Dist == nil {
+			continue
+		}
+		var distIDs []distgo.DistID
+		for k := range productParam.Dist.DistParams {
+			distIDs = append(distIDs, k)
+		}
+		sort.Sort(distgo.ByDistID(distIDs))
+		productSubmap := newProductSubmap(productParam)
+		for _, distID := range distIDs {
+			disterParam := productParam.Dist.DistParams[distID]
+			if len(disterParam.InputDockerExports) == 0 {
+				continue
+			}
+			expandedProductDockerIDs, err := expandInputDockerExports(productSubmap, disterParam.InputDockerExports)
+			if err != nil {
+				return errors.Wrapf(err, "invalid input Docker export(s) specified for DisterParam %q for product %q", distID, productID)
+			}
+			// assign updated slice to DisterParam and update in DistParams map so that update is persistent
+			disterParam.InputDockerExports = expandedProductDockerIDs
+			productParam.Dist.DistParams[distID] = disterParam
+		}
 	}
-	return projectParam, nil
+	return nil
 }
 
 // newProductSubmap returns a newly allocated map that contains only the provided product and all of its dependencies.
@@ -213,6 +325,59 @@ func newProductSubmap(productParam distgo.ProductParam) map[distgo.ProductID]dis
 	return out
 }
 
+// expandInputDockerExports returns the provided ProductDockerIDs in "ProductID.DockerID" form. A product-level ID
+// expands to all of the Docker configurations of the product that export archives. Returns an error if an ID does not
+// refer to a product in productSubmap or to a Docker configuration that exports archives.
+func expandInputDockerExports(productSubmap map[distgo.ProductID]distgo.ProductParam, productDockerIDs []distgo.ProductDockerID) ([]distgo.ProductDockerID, error) {
+	var expanded []distgo.ProductDockerID
+	seen := make(map[distgo.ProductDockerID]struct{})
+	add := func(id distgo.ProductDockerID) {
+		if _, ok := seen[id]; ok {
+			return
+		}
+		seen[id] = struct{}{}
+		expanded = append(expanded, id)
+	}
+	for _, productDockerID := range productDockerIDs {
+		productID, dockerID, tagID := productDockerID.Parse()
+		if tagID != "" {
+			return nil, errors.Errorf("%s: a Docker tag cannot be specified", productDockerID)
+		}
+		productParam, ok := productSubmap[productID]
+		if !ok {
+			return nil, errors.Errorf("%s: product %s is not the product or one of its dependencies", productDockerID, productID)
+		}
+		if productParam.Docker == nil {
+			return nil, errors.Errorf("%s: product %s does not declare a Docker configuration", productDockerID, productID)
+		}
+		if dockerID != "" {
+			dockerBuilderParam, ok := productParam.Docker.DockerBuilderParams[dockerID]
+			if !ok {
+				return nil, errors.Errorf("%s: product %s does not declare Docker configuration %s", productDockerID, productID, dockerID)
+			}
+			if dockerBuilderParam.Export == nil {
+				return nil, errors.Errorf("%s: Docker configuration %s of product %s does not export any archives", productDockerID, dockerID, productID)
+			}
+			add(productDockerID)
+			continue
+		}
+		var dockerIDs []distgo.DockerID
+		for k, v := range productParam.Docker.DockerBuilderParams {
+			if v.Export != nil {
+				dockerIDs = append(dockerIDs, k)
+			}
+		}
+		if len(dockerIDs) == 0 {
+			return nil, errors.Errorf("%s: no Docker configuration of product %s exports any archives", productDockerID, productID)
+		}
+		sort.Sort(distgo.ByDockerID(dockerIDs))
+		for _, currDockerID := range dockerIDs {
+			add(distgo.NewProductDockerID(productID, currDockerID, ""))
+		}
+	}
+	return expanded, nil
+}
+
 func computeAllDependencies(currProduct distgo.ProductID, allProducts map[distgo.ProductID]distgo.ProductParam, pathSoFar []distgo.ProductID) (map[distgo.ProductID]distgo.ProductParam, error) {
 	for _, seen := range pathSoFar {
 		if currProduct != seen {
@@ -20,19 +20,25 @@ import (
 	"path"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nmiyake/pkg/dirs"
 	"github.com/nmiyake/pkg/gofiles"
+	"github.com/palantir/distgo/dister/bin"
 	"github.com/palantir/distgo/dister/disterfactory"
 	"github.com/palantir/distgo/dister/manual"
 	"github.com/palantir/distgo/dister/osarchbin"
 	"github.com/palantir/distgo/distgo"
 	distgoconfig "github.com/palantir/distgo/distgo/config"
+	v0 "github.com/palantir/distgo/distgo/config/internal/v0"
 	"github.com/palantir/distgo/distgo/testfuncs"
 	"github.com/palantir/distgo/dockerbuilder/defaultdockerbuilder"
+	"github.com/palantir/distgo/dockerbuilder/dockerbuilderfactory"
 	"github.com/palantir/distgo/internal/files"
 	"github.com/palantir/distgo/projectversioner/git"
+	"github.com/palantir/distgo/publisher/publisherfactory"
 	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/palantir/pkg/gittest"
 	"github.com/palantir/pkg/matcher"
@@ -917,6 +923,107 @@ products:
 	}
 }
 
+func TestProjectConfig_ToParamAppliesProductDefaults(t *testing.T) {
+	const scriptIncludes = "set -e\n"
+	projectParam, err := projectParamFromYAML(t, `script-includes: |
+  set -e
+product-defaults:
+  build:
+    output-dir: default-build
+    environment:
+      CGO_ENABLED: "0"
+  run:
+    args:
+      - --default
+  dist:
+    disters:
+      os-arch-bin:
+        type: os-arch-bin
+        name-template: "{{Product}}-dist"
+      bin:
+        type: bin
+  publish:
+    group-id: com.palantir.default
+    info:
+      maven:
+        config:
+          url: https://default.example.com
+      artifactory:
+        config:
+          url: https://artifactory.example.com
+  docker:
+    repository: registry.example.com
+    docker-builders:
+      default-image:
+        type: default
+        context-dir: docker
+        tag-templates:
+          - default:latest
+products:
+  foo:
+    build:
+      main-pkg: foo
+      build-args-script: echo build args
+    run: {}
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+          script: echo dist
+    publish:
+      info:
+        maven:
+          config:
+            url: https://foo.example.com
+    docker:
+      docker-builders:
+        image:
+          type: default
+          context-dir: docker
+          tag-templates:
+            - foo:latest
+`)
+	require.NoError(t, err)
+	product := projectParam.Products["foo"]
+
+	require.NotNil(t, product.Build)
+	assert.Equal(t, "default-build", product.Build.OutputDir)
+	assert.Equal(t, "{{Product}}", product.Build.NameTemplate)
+	assert.Equal(t, "./foo", product.Build.MainPkg)
+	assert.Equal(t, distgo.CreateScriptContent("echo build args", scriptIncludes), product.Build.BuildArgsScript)
+	assert.Equal(t, map[string]string{"CGO_ENABLED": "0"}, product.Build.Environment)
+	assert.Equal(t, []osarch.OSArch{osarch.Current()}, product.Build.OSArchs)
+
+	require.NotNil(t, product.Run)
+	assert.Equal(t, []string{"--default"}, product.Run.Args)
+
+	// disters of the product and of the product defaults are combined, and a dister specified in both is merged
+	require.NotNil(t, product.Dist)
+	assert.Equal(t, "out/dist", product.Dist.OutputDir)
+	assert.ElementsMatch(t, []distgo.DistID{"bin", "os-arch-bin"}, maps.Keys(product.Dist.DistParams))
+	assert.Equal(t, "{{Product}}-dist", product.Dist.DistParams["os-arch-bin"].NameTemplate)
+	assert.Equal(t, distgo.CreateScriptContent("echo dist", scriptIncludes), product.Dist.DistParams["os-arch-bin"].Script)
+	assert.Equal(t, "{{Product}}-{{Version}}", product.Dist.DistParams["bin"].NameTemplate)
+
+	// publishers of the product and of the product defaults are combined, and the product wins for a publisher
+	// specified in both
+	require.NotNil(t, product.Publish)
+	assert.Equal(t, distgo.PublishParam{
+		GroupID: "com.palantir.default",
+		PublishInfo: map[distgo.PublisherTypeID]distgo.PublisherParam{
+			"artifactory": {ConfigBytes: []byte("url: https://artifactory.example.com\n")},
+			"maven":       {ConfigBytes: []byte("url: https://foo.example.com\n")},
+		},
+	}, *product.Publish)
+
+	// Docker builders are not inherited from the product defaults
+	require.NotNil(t, product.Docker)
+	assert.Equal(t, "registry.example.com", product.Docker.Repository)
+	assert.Equal(t, "out/docker", product.Docker.OutputDir)
+	assert.ElementsMatch(t, []distgo.DockerID{"image"}, maps.Keys(product.Docker.DockerBuilderParams))
+	assert.Equal(t, "Dockerfile", product.Docker.DockerBuilderParams["image"].DockerfilePath)
+}
+
 // "clean" removes "{{OutputDir}}/{{ProductID}}" wholesale, so anything wider takes content distgo never created
 func TestDockerConfigRejectsOutputDirOutsideProject(t *testing.T) {
 	for _, tc := range []struct {
@@ -937,6 +1044,112 @@ func TestDockerConfigRejectsOutputDirOutsideProject(t *testing.T) {
 	}
 }
 
+func TestDockerImageTestsConfigToParam(t *testing.T) {
+	param, err := (&distgoconfig.DockerImageTestsConfig{
+		FileExistence: []v0.DockerImageFileExistenceTestConfig{
+			{Path: "/usr/bin/foo", Mode: new("4755")},
+			{Path: "/root/.ssh", ShouldExist: new(false)},
+		},
+	}).ToParam()
+	require.NoError(t, err)
+	assert.Equal(t, []distgo.DockerImageFileExistenceTest{
+		{Path: "/usr/bin/foo", ShouldExist: true, Mode: new(int64(04755))},
+		{Path: "/root/.ssh", ShouldExist: false},
+	}, param.FileExistenceTests)
+
+	for _, tc := range []struct {
+		name    string
+		cfg     distgoconfig.DockerImageTestsConfig
+		wantErr string
+	}{
+		{
+			name:    "invalid platform",
+			cfg:     distgoconfig.DockerImageTestsConfig{Platforms: []string{"linux/amd64/v8/extra"}},
+			wantErr: `invalid platform "linux/amd64/v8/extra"`,
+		},
+		{
+			name:    "relative path",
+			cfg:     distgoconfig.DockerImageTestsConfig{FileExistence: []v0.DockerImageFileExistenceTestConfig{{Path: "usr/bin/foo"}}},
+			wantErr: `invalid file-existence test: path must be an absolute path in the image, was "usr/bin/foo"`,
+		},
+		{
+			name:    "non-octal mode",
+			cfg:     distgoconfig.DockerImageTestsConfig{FileExistence: []v0.DockerImageFileExistenceTestConfig{{Path: "/foo", Mode: new("0855")}}},
+			wantErr: `invalid file-existence test for /foo: mode "0855" is not an octal permission value such as "0755"`,
+		},
+		{
+			name:    "invalid forbidden path",
+			cfg:     distgoconfig.DockerImageTestsConfig{Layers: &v0.DockerImageLayersTestConfig{ForbiddenPaths: []string{"[a-"}}},
+			wantErr: `invalid forbidden-paths pattern "[a-"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.cfg.ToParam()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestDockerConfigRegistriesToParam(t *testing.T) {
+	param, err := (&distgoconfig.DockerConfig{
+		Registries: distgoconfig.ToDockerRegistriesConfig(&distgoconfig.DockerRegistriesConfig{
+			"docker.io": {
+				Credentials: &v0.DockerRegistryCredentialsConfig{TokenEnv: new("DOCKERHUB_TOKEN")},
+			},
+			"registry.internal.example.com:5000": {
+				Insecure: new(true),
+				Retry:    &v0.DockerRegistryRetryConfig{Attempts: new(5), InitialBackoff: new("500ms")},
+			},
+		}),
+	}).ToParam("", distgoconfig.DockerConfig{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]distgo.DockerRegistryParam{
+		"index.docker.io": {
+			Credentials: &distgo.DockerRegistryCredentialsParam{TokenEnvVar: "DOCKERHUB_TOKEN"},
+		},
+		"registry.internal.example.com:5000": {
+			Insecure: true,
+			Retry:    &distgo.DockerRegistryRetryParam{Attempts: 5, InitialBackoff: 500 * time.Millisecond},
+		},
+	}, param.Registries)
+
+	for _, tc := range []struct {
+		name    string
+		cfg     distgoconfig.DockerRegistryConfig
+		wantErr string
+	}{
+		{
+			name:    "username without password",
+			cfg:     distgoconfig.DockerRegistryConfig{Credentials: &v0.DockerRegistryCredentialsConfig{UsernameEnv: new("USER")}},
+			wantErr: "credentials must specify both username-env and password-env",
+		},
+		{
+			name: "token and username",
+			cfg: distgoconfig.DockerRegistryConfig{Credentials: &v0.DockerRegistryCredentialsConfig{
+				UsernameEnv: new("USER"), PasswordEnv: new("PASSWORD"), TokenEnv: new("TOKEN"),
+			}},
+			wantErr: "credentials cannot specify both token-env and username-env/password-env",
+		},
+		{
+			name:    "zero attempts",
+			cfg:     distgoconfig.DockerRegistryConfig{Retry: &v0.DockerRegistryRetryConfig{Attempts: new(0)}},
+			wantErr: "retry attempts must be at least 1, was 0",
+		},
+		{
+			name:    "invalid backoff",
+			cfg:     distgoconfig.DockerRegistryConfig{Retry: &v0.DockerRegistryRetryConfig{InitialBackoff: new("soon")}},
+			wantErr: `invalid retry initial-backoff "soon"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.cfg.ToParam()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
 func TestProjectConfig_DefaultProducts(t *testing.T) {
 	tmpDir, cleanup, err := dirs.TempDir("", "")
 	require.NoError(t, err)
@@ -1096,6 +1309,52 @@ product-defaults:
 	}
 }
 
+func TestProjectConfig_LabelsAndGroups(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		yml        string
+		wantLabels map[string]string
+		wantGroups []string
+		wantErr    string
+	}{
+		{
+			name: "labels and groups are inherited from product defaults",
+			yml: `product-defaults:
+  labels:
+    team: platform
+  groups:
+    - services
+products:
+  foo:
+    groups:
+      - cli
+`,
+			wantLabels: map[string]string{"team": "platform"},
+			wantGroups: []string{"cli"},
+		},
+		{
+			name: "invalid label key is an error",
+			yml: `products:
+  foo:
+    labels:
+      "team=x": payments
+`,
+			wantErr: `invalid label key "team=x" for product foo: must be non-empty and consist only of alphanumeric characters, '-', '_', '.' and '/'`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectParam, err := projectParamFromYAML(t, tc.yml)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantLabels, projectParam.Products["foo"].Labels)
+			assert.Equal(t, tc.wantGroups, projectParam.Products["foo"].Groups)
+		})
+	}
+}
+
 func TestProjectConfig_DockerBuildDepForNonDependentProduct(t *testing.T) {
 	for i, tc := range []struct {
 		name      string
@@ -1155,6 +1414,87 @@ products:
 	}
 }
 
+func TestProjectConfig_InputDockerExports(t *testing.T) {
+	const exportingProductsYML = `
+  test-2:
+    docker:
+      docker-builders:
+        exported:
+          type: default
+          context-dir: docker
+          tag-templates:
+            - test-2:latest
+          export:
+            docker-archive: true
+        not-exported:
+          type: default
+          context-dir: docker
+          tag-templates:
+            - test-2-internal:latest
+  test-3:
+`
+
+	var gotCfg distgoconfig.ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+products:
+  test-1:
+    dist:
+      disters:
+        type: bin
+        input-docker-exports:
+          - test-2
+    dependencies:
+      - test-2
+`+exportingProductsYML), &gotCfg))
+	projectParam := testfuncs.NewProjectParam(t, gotCfg, "", "")
+	assert.Equal(t, []distgo.ProductDockerID{"test-2.exported"}, projectParam.Products["test-1"].Dist.DistParams["bin"].InputDockerExports)
+
+	for i, tc := range []struct {
+		name      string
+		yml       string
+		wantError string
+	}{
+		{
+			"Docker export input must be from a dependent product",
+			`
+products:
+  test-1:
+    dist:
+      disters:
+        type: bin
+        input-docker-exports:
+          - test-2
+    dependencies:
+      - test-3
+` + exportingProductsYML,
+			`invalid input Docker export(s) specified for DisterParam "bin" for product "test-1": test-2: product test-2 is not the product or one of its dependencies`,
+		},
+		{
+			"Docker export input must export archives",
+			`
+products:
+  test-1:
+    dist:
+      disters:
+        type: bin
+        input-docker-exports:
+          - test-2.not-exported
+    dependencies:
+      - test-2
+` + exportingProductsYML,
+			`invalid input Docker export(s) specified for DisterParam "bin" for product "test-1": test-2.not-exported: Docker configuration not-exported of product test-2 does not export any archives`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotCfg distgoconfig.ProjectConfig
+			err := yaml.Unmarshal([]byte(tc.yml), &gotCfg)
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			_, err = testfuncs.NewProjectParamReturnError(t, gotCfg, "", fmt.Sprintf("Case %d: %s", i, tc.name))
+			assert.EqualError(t, err, tc.wantError, "Case %d: %s", i, tc.name)
+		})
+	}
+}
+
 func TestProjectConfig_InvalidDependencies(t *testing.T) {
 	for i, tc := range []struct {
 		name      string
@@ -1398,10 +1738,1140 @@ import _ "github.com/palantir/witchcraft-go-logging/wlog-zap"
 	assert.Equal(t, wantKeys, gotKeys)
 }
 
-func mustOSArch(in string) osarch.OSArch {
-	osArch, err := osarch.New(in)
-	if err != nil {
-		panic(err)
+func TestProjectConfig_BuildFlags(t *testing.T) {
+	projectParam, err := projectParamFromYAML(t, `product-defaults:
+  build:
+    flags:
+      trimpath: true
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+      os-flags:
+        linux:
+          tags:
+            - netgo
+      arch-flags:
+        arm64:
+          gcflags: all=-N -l
+      os-archs-flags:
+        linux-arm64:
+          strip: false
+          ldflags-vars:
+            main.target: '{{OSArch}}'
+  bar:
+    build:
+      main-pkg: ./bar
+      flags:
+        buildmode: pie
+        strip: true
+        ldflags-vars:
+          main.product: '{{Product}}'
+`)
+	require.NoError(t, err)
+
+	fooBuild := projectParam.Products["foo"].Build
+	assert.Equal(t, distgo.BuildFlagsParam{Trimpath: new(true)}, fooBuild.Flags)
+	assert.Equal(t, map[string]distgo.BuildFlagsParam{"linux": {Tags: []string{"netgo"}}}, fooBuild.OSFlags)
+	assert.Equal(t, map[string]distgo.BuildFlagsParam{"arm64": {Gcflags: new("all=-N -l")}}, fooBuild.ArchFlags)
+	assert.Equal(t, distgo.BuildFlagsParam{
+		Tags:        []string{"netgo"},
+		LdflagsVars: map[string]string{"main.target": "{{OSArch}}"},
+		Gcflags:     new("all=-N -l"),
+		Trimpath:    new(true),
+		Strip:       new(false),
+	}, fooBuild.FlagsForOSArch(osarch.OSArch{OS: "linux", Arch: "arm64"}))
+
+	// flags specified for a product replace the flags specified in the product defaults
+	assert.Equal(t, distgo.BuildFlagsParam{
+		LdflagsVars: map[string]string{"main.product": "{{Product}}"},
+		Buildmode:   new("pie"),
+		Strip:       new(true),
+	}, projectParam.Products["bar"].Build.Flags)
+}
+
+func TestProjectConfig_BuildVariants(t *testing.T) {
+	darwinAMD64 := osarch.OSArch{OS: "darwin", Arch: "amd64"}
+	linuxAMD64 := osarch.OSArch{OS: "linux", Arch: "amd64"}
+
+	projectParam, err := projectParamFromYAML(t, `products:
+  foo:
+    build:
+      main-pkg: ./foo
+      os-archs:
+        - os: darwin
+          arch: amd64
+        - os: linux
+          arch: amd64
+      variants:
+        boringcrypto:
+          environment:
+            GOEXPERIMENT: boringcrypto
+          flags:
+            tags:
+              - fips
+          os-archs:
+            - os: linux
+              arch: amd64
+        v3:
+          environment:
+            GOAMD64: v3
+    dist:
+      disters:
+        fips:
+          type: os-arch-bin
+          build-variant: boringcrypto
+          config:
+            os-archs:
+              - os: linux
+                arch: amd64
+  bar:
+    docker:
+      docker-builders:
+        default:
+          type: default
+          context-dir: docker
+          input-builds:
+            - foo.linux-amd64@boringcrypto
+          tag-templates:
+            latest: bar:latest
+    dependencies:
+      - foo
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[distgo.BuildVariantID]distgo.BuildVariantParam{
+		"boringcrypto": {
+			Environment: map[string]string{"GOEXPERIMENT": "boringcrypto"},
+			Flags:       distgo.BuildFlagsParam{Tags: []string{"fips"}},
+			OSArchs:     []osarch.OSArch{linuxAMD64},
+		},
+		// variants are built for the OS/Archs of the build by default
+		"v3": {
+			Environment: map[string]string{"GOAMD64": "v3"},
+			OSArchs:     []osarch.OSArch{darwinAMD64, linuxAMD64},
+		},
+	}, projectParam.Products["foo"].Build.Variants)
+	assert.Equal(t, distgo.BuildVariantID("boringcrypto"), projectParam.Products["foo"].Dist.DistParams["fips"].BuildVariant)
+	assert.Equal(t, []distgo.ProductBuildID{"foo.linux-amd64@boringcrypto"}, projectParam.Products["bar"].Docker.DockerBuilderParams["default"].InputBuilds)
+
+	for _, tc := range []struct {
+		name      string
+		yml       string
+		wantError string
+	}{
+		{
+			"variant name cannot contain a '.'",
+			`products:
+  foo:
+    build:
+      variants:
+        v1.2: {}
+`,
+			`invalid build variant name "v1.2": must consist of alphanumeric characters, '-' and '_'`,
+		},
+		{
+			"dist cannot use a variant that does not exist",
+			`products:
+  foo:
+    build:
+      variants:
+        race: {}
+    dist:
+      disters:
+        bin:
+          type: bin
+          build-variant: debug
+`,
+			`dist bin of product foo specifies build variant debug, which is not a build variant of the product -- valid values are [race]`,
+		},
+		{
+			"Docker builder cannot use two builds of a product for the same OS/Arch",
+			`products:
+  foo:
+    build:
+      os-archs:
+        - os: linux
+          arch: amd64
+      variants:
+        race: {}
+    docker:
+      docker-builders:
+        default:
+          type: default
+          context-dir: docker
+          input-builds:
+            - foo
+            - foo@race
+          tag-templates:
+            latest: foo:latest
+`,
+			`invalid Docker input builds specified for DockerBuilderParam "default" for product "foo": foo.linux-amd64 and foo.linux-amd64@race are both builds of foo for linux-amd64`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := projectParamFromYAML(t, tc.yml)
+			assert.EqualError(t, err, tc.wantError)
+		})
+	}
+}
+
+func TestProjectConfig_BuildMode(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		yml       string
+		wantMode  distgo.BuildMode
+		wantError string
+	}{
+		{
+			"build mode is not set by default",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+`,
+			"",
+			"",
+		},
+		{
+			"library build mode",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+      mode: c-shared
+      flags:
+        trimpath: true
+`,
+			distgo.BuildModeCShared,
+			"",
+		},
+		{
+			"invalid build mode",
+			`products:
+  foo:
+    build:
+      mode: shared
+`,
+			"",
+			`invalid build mode "shared": must be one of [exe c-shared c-archive plugin]`,
+		},
+		{
+			"library build mode cannot be combined with the buildmode flag",
+			`products:
+  foo:
+    build:
+      mode: plugin
+      os-flags:
+        linux:
+          buildmode: pie
+`,
+			"",
+			`the buildmode flag cannot be specified for a product with build mode plugin`,
+		},
+		{
+			"library build mode cannot be combined with the darwin-universal OS/Arch",
+			`products:
+  foo:
+    build:
+      mode: c-archive
+      os-archs:
+        - os: darwin
+          arch: universal
+`,
+			"",
+			`the darwin-universal OS/Arch cannot be specified for a product with build mode c-archive`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectParam, err := projectParamFromYAML(t, tc.yml)
+			if tc.wantError != "" {
+				assert.EqualError(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantMode, projectParam.Products["foo"].Build.Mode)
+		})
+	}
+}
+
+func TestProjectConfig_WebAssembly(t *testing.T) {
+	jsWasm := osarch.OSArch{OS: "js", Arch: "wasm"}
+
+	projectParam, err := projectParamFromYAML(t, `products:
+  foo:
+    build:
+      main-pkg: ./foo
+      os-archs:
+        - os: js
+          arch: wasm
+    run:
+      wasm-runtime: wasmtime run
+    dist:
+      disters:
+        bin:
+          type: bin
+          config:
+            include-wasm-exec: true
+        os-arch-bin:
+          type: os-arch-bin
+          config:
+            os-archs:
+              - os: js
+                arch: wasm
+            include-wasm-exec: true
+`)
+	require.NoError(t, err)
+
+	product := projectParam.Products["foo"]
+	assert.Equal(t, "wasmtime run", product.Run.WasmRuntime)
+	assert.Equal(t, distgo.NewDisterWithConfig(&bin.Dister{
+		IncludeWasmExec: true,
+	}, []byte("include-wasm-exec: true\n")), product.Dist.DistParams["bin"].Dister)
+	assert.Equal(t, distgo.NewDisterWithConfig(&osarchbin.Dister{
+		OSArchs:         []osarch.OSArch{jsWasm},
+		IncludeWasmExec: true,
+	}, []byte("os-archs:\n- os: js\n  arch: wasm\ninclude-wasm-exec: true\n")), product.Dist.DistParams["os-arch-bin"].Dister)
+}
+
+func TestProjectConfig_GoToolchain(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		yml       string
+		want      distgo.GoToolchainParam
+		wantError string
+	}{
+		{
+			"Go toolchain is not set by default",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+`,
+			distgo.GoToolchainParam{},
+			"",
+		},
+		{
+			"Go toolchain from product defaults is used",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+  bar:
+    build:
+      main-pkg: ./bar
+      go-toolchain:
+        binary: /usr/local/go-fips/bin/go
+product-defaults:
+  build:
+    go-toolchain:
+      toolchain: go1.22.3+path
+`,
+			distgo.GoToolchainParam{
+				Toolchain: "go1.22.3+path",
+			},
+			"",
+		},
+		{
+			"Go toolchain cannot specify both binary and toolchain",
+			`products:
+  foo:
+    build:
+      go-toolchain:
+        binary: go1.22.3
+        toolchain: go1.22.3+path
+`,
+			distgo.GoToolchainParam{},
+			`go-toolchain cannot specify both binary and toolchain`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectParam, err := projectParamFromYAML(t, tc.yml)
+			if tc.wantError != "" {
+				assert.EqualError(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, projectParam.Products["foo"].Build.GoToolchain)
+		})
+	}
+}
+
+func TestProjectConfig_Cover(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		yml       string
+		want      distgo.BuildCoverParam
+		wantError string
+	}{
+		{
+			"cover configuration",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+      cover:
+        output-dir: out/integration
+        coverpkg:
+          - ./...
+`,
+			distgo.BuildCoverParam{
+				OutputDir: "out/integration",
+				Coverpkg:  []string{"./..."},
+			},
+			"",
+		},
+		{
+			"cover output directory cannot be the build output directory",
+			`products:
+  foo:
+    build:
+      output-dir: out/cover
+`,
+			distgo.BuildCoverParam{},
+			`cover output-dir must differ from the build output-dir out/cover`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectParam, err := projectParamFromYAML(t, tc.yml)
+			if tc.wantError != "" {
+				assert.EqualError(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, projectParam.Products["foo"].Build.Cover)
+		})
+	}
+}
+
+func TestProjectConfig_WindowsResources(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		yml  string
+		want *distgo.WindowsResourcesParam
+	}{
+		{
+			"Windows resources are not set by default",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+`,
+			nil,
+		},
+		{
+			"Windows resources are set",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+      windows-resources:
+        company-name: Palantir Technologies, Inc.
+        file-description: "{{Product}} server"
+        product-name: Foo
+        copyright: Copyright 2026 Palantir Technologies, Inc.
+        icon: resources/foo.ico
+        manifest: resources/foo.manifest
+`,
+			&distgo.WindowsResourcesParam{
+				CompanyName:     "Palantir Technologies, Inc.",
+				FileDescription: "{{Product}} server",
+				ProductName:     "Foo",
+				Copyright:       "Copyright 2026 Palantir Technologies, Inc.",
+				Icon:            "resources/foo.ico",
+				Manifest:        "resources/foo.manifest",
+			},
+		},
+		{
+			"Windows resources from product defaults are used",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+product-defaults:
+  build:
+    windows-resources:
+      company-name: Palantir Technologies, Inc.
+`,
+			&distgo.WindowsResourcesParam{
+				CompanyName: "Palantir Technologies, Inc.",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectParam, err := projectParamFromYAML(t, tc.yml)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, projectParam.Products["foo"].Build.WindowsResources)
+		})
+	}
+}
+
+func TestProjectConfig_SizeBudget(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		yml       string
+		want      distgo.BuildSizeBudgetParam
+		wantError string
+	}{
+		{
+			"size budget is not set by default",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+`,
+			distgo.BuildSizeBudgetParam{},
+			"",
+		},
+		{
+			"size budget with units",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+      size-budget:
+        max-size: 20MB
+        os-archs:
+          windows-amd64: 1.5MiB
+          linux-arm64: 4096
+          darwin-arm64: 512 KiB
+`,
+			distgo.BuildSizeBudgetParam{
+				MaxSize: 20000000,
+				OSArchsMaxSize: map[string]int64{
+					"windows-amd64": 1572864,
+					"linux-arm64":   4096,
+					"darwin-arm64":  524288,
+				},
+			},
+			"",
+		},
+		{
+			"size budget from product defaults is used",
+			`products:
+  foo:
+    build:
+      main-pkg: ./foo
+product-defaults:
+  build:
+    size-budget:
+      max-size: 1GB
+`,
+			distgo.BuildSizeBudgetParam{
+				MaxSize: 1000000000,
+			},
+			"",
+		},
+		{
+			"size budget with invalid unit",
+			`products:
+  foo:
+    build:
+      size-budget:
+        max-size: 20mb
+`,
+			distgo.BuildSizeBudgetParam{},
+			`invalid size-budget max-size: invalid unit "mb" in size "20mb": must be one of B, KB, MB, GB, KiB, MiB or GiB`,
+		},
+		{
+			"size budget with invalid size",
+			`products:
+  foo:
+    build:
+      size-budget:
+        os-archs:
+          linux-amd64: large
+`,
+			distgo.BuildSizeBudgetParam{},
+			`invalid size-budget for os-arch linux-amd64: invalid size "large"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectParam, err := projectParamFromYAML(t, tc.yml)
+			if tc.wantError != "" {
+				assert.EqualError(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, projectParam.Products["foo"].Build.SizeBudget)
+		})
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	disterFactory, err := disterfactory.New(nil, nil)
+	require.NoError(t, err)
+	dockerBuilderFactory, err := dockerbuilderfactory.New(nil, nil)
+	require.NoError(t, err)
+	publisherFactory, err := publisherfactory.New(nil, nil)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		yml  string
+		want []string
+	}{
+		{
+			name: "valid configuration",
+			yml: `products:
+  foo:
+    build:
+      main-pkg: ./foo
+      os-archs:
+        - os: linux
+          arch: amd64
+    dist:
+      disters:
+        type: os-arch-bin
+        config:
+          os-archs:
+            - os: linux
+              arch: amd64
+    docker:
+      docker-builders:
+        foo:
+          type: default
+          context-dir: docker
+          tag-templates: "{{Repository}}foo:{{Version}}"
+    publish:
+      group-id: com.palantir.foo
+      info:
+        github:
+          config:
+            api-url: https://api.github.com
+product-defaults:
+  dist:
+    disters:
+      bar:
+        type: bin
+`,
+		},
+		{
+			name: "structural errors are reported with their positions",
+			yml: `products:
+  foo:
+    build:
+      main-pkgs: ./foo
+      os-archs:
+        - os: linux
+          arch: amd64
+    dist:
+      disters:
+        type: os-arch-bin
+        config:
+          os-arch:
+            - os: linux
+              arch: amd64
+    docker:
+      docker-builders:
+        foo:
+          type: dfault
+          tag-templates: "{{Repository}}foo:{{Version}}"
+    publish:
+      group-id: com.palantir.foo
+      group-id: com.palantir.bar
+      info:
+        githb: {}
+`,
+			want: []string{
+				`4:7: products.foo.build.main-pkgs: unknown field "main-pkgs" (did you mean "main-pkg"?)`,
+				`12:11: products.foo.dist.disters.config.os-arch: unknown field "os-arch" (did you mean "os-archs"?)`,
+				`18:17: products.foo.docker.docker-builders.foo.type: invalid value "dfault": must be one of "default"`,
+				`22:7: products.foo.publish.group-id: duplicate key "group-id"`,
+				`24:9: products.foo.publish.info.githb: unknown field "githb" (did you mean "github"?)`,
+			},
+		},
+		{
+			name: "YAML syntax error",
+			yml:  "products:\n  foo: [\n",
+			want: []string{`2: invalid YAML: did not find expected node content`},
+		},
+		{
+			name: "legacy configuration is not validated",
+			yml:  "legacy-config: true\nproducts:\n  foo:\n    unknown: value\n",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs, err := distgoconfig.ValidateConfig([]byte(tc.yml), disterFactory, dockerBuilderFactory, publisherFactory)
+			require.NoError(t, err)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func mustOSArch(in string) osarch.OSArch {
+	osArch, err := osarch.New(in)
+	if err != nil {
+		panic(err)
+	}
+	return osArch
+}
+
+// projectParamFromYAML returns the project parameter for the provided configuration using the built-in disters and
+// Docker builders.
+func projectParamFromYAML(t *testing.T, yml string) (distgo.ProjectParam, error) {
+	t.Helper()
+	disterFactory, err := disterfactory.New(nil, nil)
+	require.NoError(t, err)
+	dockerBuilderFactory, err := dockerbuilderfactory.New(nil, nil)
+	require.NoError(t, err)
+
+	var cfg distgoconfig.ProjectConfig
+	require.NoError(t, yaml.UnmarshalStrict([]byte(yml), &cfg))
+	return cfg.ToParam(t.TempDir(), nil, disterFactory, distgoconfig.DisterConfig{}, dockerBuilderFactory, nil)
+}
+
+func TestProjectConfig_ProductDefaultsDeepMerge(t *testing.T) {
+	disterFactory, err := disterfactory.New(nil, nil)
+	require.NoError(t, err)
+	dockerBuilderFactory, err := dockerbuilderfactory.New(nil, nil)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name        string
+		yml         string
+		wantErr     string
+		wantSources map[string]distgoconfig.ValueSource
+		verify      func(t *testing.T, product distgo.ProductParam)
+	}{
+		{
+			name: "maps are merged key by key, disters are merged by ID and lists are replaced",
+			yml: `product-defaults-merge:
+  strategy: deep
+product-defaults:
+  build:
+    environment:
+      CGO_ENABLED: "0"
+      GOFLAGS: -mod=vendor
+    os-archs:
+      - os: linux
+        arch: amd64
+  dist:
+    disters:
+      bin:
+        type: bin
+      os-arch-bin:
+        type: os-arch-bin
+        name-template: "{{Product}}-bin"
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+      environment:
+        CGO_ENABLED: "1"
+      os-archs:
+        - os: darwin
+          arch: arm64
+    dist:
+      disters:
+        os-arch-bin:
+          script: echo hello
+`,
+			wantSources: map[string]distgoconfig.ValueSource{
+				"build.environment":                      distgoconfig.ValueSourceMerged,
+				"build.main-pkg":                         distgoconfig.ValueSourceExplicit,
+				"dist.disters.bin.type":                  distgoconfig.ValueSourceProductDefaults,
+				"dist.disters.os-arch-bin.name-template": distgoconfig.ValueSourceProductDefaults,
+				"dist.disters.os-arch-bin.script":        distgoconfig.ValueSourceExplicit,
+				"dist.output-dir":                        distgoconfig.ValueSourceDefault,
+			},
+			verify: func(t *testing.T, product distgo.ProductParam) {
+				assert.Equal(t, map[string]string{"CGO_ENABLED": "1", "GOFLAGS": "-mod=vendor"}, product.Build.Environment)
+				assert.Equal(t, []osarch.OSArch{{OS: "darwin", Arch: "arm64"}}, product.Build.OSArchs)
+				assert.ElementsMatch(t, []distgo.DistID{"bin", "os-arch-bin"}, maps.Keys(product.Dist.DistParams))
+				assert.Equal(t, "{{Product}}-bin", product.Dist.DistParams["os-arch-bin"].NameTemplate)
+				assert.Contains(t, product.Dist.DistParams["os-arch-bin"].Script, "echo hello")
+			},
+		},
+		{
+			name: "lists configured as append lists are appended to inherited lists",
+			yml: `product-defaults-merge:
+  strategy: deep
+  append-lists:
+    - build.os-archs
+product-defaults:
+  build:
+    os-archs:
+      - os: linux
+        arch: amd64
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+      os-archs:
+        - os: darwin
+          arch: arm64
+`,
+			wantSources: map[string]distgoconfig.ValueSource{
+				"build.os-archs": distgoconfig.ValueSourceMerged,
+			},
+			verify: func(t *testing.T, product distgo.ProductParam) {
+				assert.Equal(t, []osarch.OSArch{{OS: "linux", Arch: "amd64"}, {OS: "darwin", Arch: "arm64"}}, product.Build.OSArchs)
+			},
+		},
+		{
+			name: "merged maps keep the key order of the product",
+			yml: `product-defaults-merge:
+  strategy: deep
+product-defaults:
+  docker:
+    docker-builders:
+      foo-image:
+        type: default
+        context-dir: docker
+        tag-templates:
+          release: "{{Repository}}foo:{{Version}}"
+          latest: "{{Repository}}foo:latest"
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+    docker:
+      docker-builders:
+        foo-image:
+          tag-templates:
+            snapshot: "{{Repository}}foo:snapshot"
+            latest: "{{Repository}}foo:latest-snapshot"
+`,
+			verify: func(t *testing.T, product distgo.ProductParam) {
+				tagTemplates := product.Docker.DockerBuilderParams["foo-image"].TagTemplates
+				assert.Equal(t, []distgo.DockerTagID{"snapshot", "latest", "release"}, tagTemplates.OrderedKeys)
+				assert.Equal(t, "{{Repository}}foo:latest-snapshot", tagTemplates.Templates["latest"])
+			},
+		},
+		{
+			name: "unset removes inherited values",
+			yml: `product-defaults-merge:
+  strategy: deep
+product-defaults:
+  build:
+    environment:
+      CGO_ENABLED: "0"
+      GOFLAGS: -mod=vendor
+  dist:
+    disters:
+      bin:
+        type: bin
+      os-arch-bin:
+        type: os-arch-bin
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+    dist: {}
+    unset:
+      - build.environment.GOFLAGS
+      - dist.disters.bin
+`,
+			verify: func(t *testing.T, product distgo.ProductParam) {
+				assert.Equal(t, map[string]string{"CGO_ENABLED": "0"}, product.Build.Environment)
+				assert.ElementsMatch(t, []distgo.DistID{"os-arch-bin"}, maps.Keys(product.Dist.DistParams))
+			},
+		},
+		{
+			name: "unset path that does not match a value is an error",
+			yml: `product-defaults-merge:
+  strategy: deep
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+    unset:
+      - build.environment.GOFLAGS
+`,
+			wantErr: `failed to merge product-defaults for product foo: unset path "build.environment.GOFLAGS" does not match any configuration value`,
+		},
+		{
+			name: "unset requires deep strategy",
+			yml: `products:
+  foo:
+    build:
+      main-pkg: ./foo
+    unset:
+      - build.environment
+`,
+			wantErr: `unset specified for product foo requires the "deep" product-defaults-merge strategy`,
+		},
+		{
+			name: "invalid strategy is an error",
+			yml: `product-defaults-merge:
+  strategy: deepest
+products:
+  foo: {}
+`,
+			wantErr: `invalid product-defaults-merge strategy "deepest": must be "shallow" or "deep"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var cfg distgoconfig.ProjectConfig
+			require.NoError(t, yaml.UnmarshalStrict([]byte(tc.yml), &cfg))
+
+			projectParam, err := cfg.ToParam("", nil, disterFactory, distgoconfig.DisterConfig{}, dockerBuilderFactory, nil)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tc.verify(t, projectParam.Products["foo"])
+
+			resolved, err := cfg.ResolveProductConfigs("", distgoconfig.DisterConfig{}, false)
+			require.NoError(t, err)
+			for path, want := range tc.wantSources {
+				assert.Equal(t, want, resolved["foo"].Sources[path], path)
+			}
+		})
+	}
+}
+
+func TestResolveIncludesAndProfiles(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		files    map[string]string
+		profiles []string
+		want     string
+		wantErr  string
+	}{
+		{
+			name: "included products and profiles are combined",
+			files: map[string]string{
+				"dist-plugin.yml": `includes:
+  - products/*.yml
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+profiles:
+  release:
+    products:
+      foo:
+        build:
+          environment:
+            CGO_ENABLED: "0"
+`,
+				"products/bar.yml": `products:
+  bar:
+    build:
+      main-pkg: ./bar
+script-includes: echo included
+`,
+			},
+			want: `products:
+  foo:
+    build:
+      main-pkg: ./foo
+  bar:
+    build:
+      main-pkg: ./bar
+script-includes: echo included
+`,
+		},
+		{
+			name: "profiles are applied in order",
+			files: map[string]string{
+				"dist-plugin.yml": `products:
+  foo:
+    build:
+      main-pkg: ./foo
+      environment:
+        GOFLAGS: -mod=vendor
+    docker:
+      repository: registry.example.com/
+profiles:
+  staging:
+    products:
+      foo:
+        build:
+          environment:
+            CGO_ENABLED: "0"
+        docker:
+          repository: staging.example.com/
+  local:
+    products:
+      foo:
+        docker:
+          repository: localhost:5000/
+`,
+			},
+			profiles: []string{"staging", "local"},
+			want: `products:
+  foo:
+    build:
+      main-pkg: ./foo
+      environment:
+        GOFLAGS: -mod=vendor
+        CGO_ENABLED: "0"
+    docker:
+      repository: localhost:5000/
+`,
+		},
+		{
+			name: "product defined in multiple files is an error",
+			files: map[string]string{
+				"dist-plugin.yml": `includes:
+  - foo.yml
+products:
+  foo: {}
+`,
+				"foo.yml": `products:
+  foo: {}
+`,
+			},
+			wantErr: "failed to include configuration file {{dir}}/foo.yml: products entry foo is already specified",
+		},
+		{
+			name: "missing included file is an error",
+			files: map[string]string{
+				"dist-plugin.yml": `includes:
+  - foo.yml
+`,
+			},
+			wantErr: "included configuration file {{dir}}/foo.yml does not exist",
+		},
+		{
+			name: "unknown profile is an error",
+			files: map[string]string{
+				"dist-plugin.yml": `profiles:
+  release: {}
+  staging: {}
+`,
+			},
+			profiles: []string{"prod"},
+			wantErr:  `invalid profile "prod": valid values are [release staging]`,
+		},
+		{
+			name: "profile that configures an undefined product is an error",
+			files: map[string]string{
+				"dist-plugin.yml": `products:
+  foo: {}
+profiles:
+  release:
+    products:
+      bar: {}
+`,
+			},
+			profiles: []string{"release"},
+			wantErr:  `failed to apply profile "release": product bar is not defined`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for relPath, content := range tc.files {
+				require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, relPath)), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(dir, relPath), []byte(content), 0644))
+			}
+			cfgFile := filepath.Join(dir, "dist-plugin.yml")
+			cfgBytes, err := os.ReadFile(cfgFile)
+			require.NoError(t, err)
+
+			got, err := distgoconfig.ResolveIncludesAndProfiles(cfgFile, cfgBytes, tc.profiles, func(cfgBytes []byte) ([]byte, error) {
+				return cfgBytes, nil
+			})
+			if tc.wantErr != "" {
+				require.EqualError(t, err, strings.ReplaceAll(tc.wantErr, "{{dir}}", dir))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestProjectConfig_Vars(t *testing.T) {
+	t.Setenv("DISTGO_TEST_REGISTRY", "registry.example.com")
+
+	for _, tc := range []struct {
+		name    string
+		yml     string
+		wantErr string
+		verify  func(t *testing.T, product distgo.ProductParam)
+	}{
+		{
+			name: "vars and environment variables are rendered in string values",
+			yml: `vars:
+  team: infra
+  registry:
+    env: DISTGO_TEST_REGISTRY
+  channel:
+    env: DISTGO_TEST_UNSET
+    default: stable
+  suffix:
+    script: echo "-$((1 + 1))"
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+      output-dir: 'out/{{Var "team"}}'
+      environment:
+        CHANNEL: '{{Var "channel"}}{{Var "suffix"}}'
+        TARGET: '{{OSArch}}'
+    docker:
+      repository: '{{Env "DISTGO_TEST_REGISTRY"}}/{{Var "team"}}'
+`,
+			verify: func(t *testing.T, product distgo.ProductParam) {
+				assert.Equal(t, "out/infra", product.Build.OutputDir)
+				assert.Equal(t, map[string]string{"CHANNEL": "stable-2", "TARGET": "{{OSArch}}"}, product.Build.Environment)
+				assert.Equal(t, "registry.example.com/infra", product.Docker.Repository)
+			},
+		},
+		{
+			name: "scripts with literal braces are not rendered",
+			yml: `vars:
+  team: infra
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+      script: |
+        echo '{{'
+        echo "{{print}}"
+      build-args-script: echo '{{Var "team"}}'
+      environment:
+        TEAM: '{{Var "team"}}'
+    dist:
+      disters:
+        type: os-arch-bin
+        script: echo "}}{{"
+`,
+			verify: func(t *testing.T, product distgo.ProductParam) {
+				assert.Contains(t, product.Build.Script, "echo '{{'\necho \"{{print}}\"\n")
+				assert.Contains(t, product.Build.BuildArgsScript, `echo '{{Var "team"}}'`)
+				assert.Equal(t, map[string]string{"TEAM": "infra"}, product.Build.Environment)
+				assert.Contains(t, product.Dist.DistParams["os-arch-bin"].Script, `echo "}}{{"`)
+			},
+		},
+		{
+			name: "undefined variable is an error",
+			yml: `products:
+  foo:
+    build:
+      output-dir: '{{Var "missing"}}'
+`,
+			wantErr: `failed to render products.foo.build.output-dir: failed to execute template: template: distgoTemplate:1:2: executing "distgoTemplate" at <Var "missing">: error calling Var: variable "missing" is not defined`,
+		},
+		{
+			name: "variable must specify exactly one source",
+			yml: `vars:
+  team:
+    value: infra
+    env: TEAM
+products:
+  foo: {}
+`,
+			wantErr: `invalid variable "team": exactly one of value, env or script must be specified`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectParam, err := projectParamFromYAML(t, tc.yml)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tc.verify(t, projectParam.Products["foo"])
+		})
 	}
-	return osArch
 }
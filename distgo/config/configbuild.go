@@ -15,7 +15,11 @@
 package config
 
 import (
+	"maps"
 	"path"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/palantir/distgo/distgo"
@@ -24,8 +28,17 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	defaultBuildOutputDir    = "out/build"
+	defaultBuildNameTemplate = "{{Product}}"
+)
+
 type BuildConfig v0.BuildConfig
 
+type BuildFlagsConfig v0.BuildFlagsConfig
+
+type BuildVariantConfig v0.BuildVariantConfig
+
 func ToBuildConfig(in *BuildConfig) *v0.BuildConfig {
 	return (*v0.BuildConfig)(in)
 }
@@ -35,25 +48,284 @@ func ToBuildConfig(in *BuildConfig) *v0.BuildConfig {
 // receiver config but is specified in the default config, the default config value is used. If a value is not specified
 // in either configuration, the program-specified default value (if any) is used.
 func (cfg *BuildConfig) ToParam(scriptIncludes string, defaultCfg BuildConfig) (distgo.BuildParam, error) {
-	outputDir := getConfigStringValue(cfg.OutputDir, defaultCfg.OutputDir, "out/build")
+	resolvedCfg := cfg.resolve(nil, "", scriptIncludes, defaultCfg)
+	return resolvedCfg.toParam()
+}
+
+// resolve returns the BuildConfig in which every value is the value that ToParam uses: the value of the receiver
+// config, of the default config or the program-specified default value. The script includes are prepended to the build
+// args script. The source of each value is recorded in sources using paths relative to the provided path.
+func (cfg *BuildConfig) resolve(sources *configSources, path, scriptIncludes string, defaultCfg BuildConfig) BuildConfig {
+	key := func(key string) string {
+		return joinConfigPath(path, key)
+	}
+	resolvedCfg := BuildConfig{
+		NameTemplate:       resolveStringValue(sources, key("name-template"), cfg.NameTemplate, defaultCfg.NameTemplate, defaultBuildNameTemplate),
+		OutputDir:          resolveStringValue(sources, key("output-dir"), cfg.OutputDir, defaultCfg.OutputDir, defaultBuildOutputDir),
+		MainPkg:            resolveValue(sources, key("main-pkg"), cfg.MainPkg, defaultCfg.MainPkg, nil),
+		Mode:               resolveValue(sources, key("mode"), cfg.Mode, defaultCfg.Mode, nil),
+		GoToolchain:        resolveValue(sources, key("go-toolchain"), cfg.GoToolchain, defaultCfg.GoToolchain, nil),
+		PGOProfile:         resolveValue(sources, key("pgo-profile"), cfg.PGOProfile, defaultCfg.PGOProfile, nil),
+		Cover:              resolveValue(sources, key("cover"), cfg.Cover, defaultCfg.Cover, nil),
+		SizeBudget:         resolveValue(sources, key("size-budget"), cfg.SizeBudget, defaultCfg.SizeBudget, nil),
+		WindowsResources:   resolveValue(sources, key("windows-resources"), cfg.WindowsResources, defaultCfg.WindowsResources, nil),
+		BuildArgsScript:    resolveScriptValue(resolveValue(sources, key("build-args-script"), cfg.BuildArgsScript, defaultCfg.BuildArgsScript, nil), scriptIncludes),
+		VersionVar:         resolveValue(sources, key("version-var"), cfg.VersionVar, defaultCfg.VersionVar, nil),
+		Flags:              resolveValue(sources, key("flags"), cfg.Flags, defaultCfg.Flags, nil),
+		OSFlags:            resolveValue(sources, key("os-flags"), cfg.OSFlags, defaultCfg.OSFlags, nil),
+		ArchFlags:          resolveValue(sources, key("arch-flags"), cfg.ArchFlags, defaultCfg.ArchFlags, nil),
+		OSArchsFlags:       resolveValue(sources, key("os-archs-flags"), cfg.OSArchsFlags, defaultCfg.OSArchsFlags, nil),
+		Environment:        resolveValue(sources, key("environment"), cfg.Environment, defaultCfg.Environment, nil),
+		OSEnvironment:      resolveValue(sources, key("os-environment"), cfg.OSEnvironment, defaultCfg.OSEnvironment, nil),
+		OSArchsEnvironment: resolveValue(sources, key("os-archs-environment"), cfg.OSArchsEnvironment, defaultCfg.OSArchsEnvironment, nil),
+		Script:             resolveValue(sources, key("script"), cfg.Script, defaultCfg.Script, nil),
+		OSArchs:            resolveValue(sources, key("os-archs"), cfg.OSArchs, defaultCfg.OSArchs, &[]osarch.OSArch{osarch.Current()}),
+		Variants:           resolveValue(sources, key("variants"), cfg.Variants, defaultCfg.Variants, nil),
+	}
+	if resolvedCfg.MainPkg != nil && *resolvedCfg.MainPkg != "" && !strings.HasPrefix(*resolvedCfg.MainPkg, "./") {
+		resolvedCfg.MainPkg = new("./" + *resolvedCfg.MainPkg)
+	}
+	return resolvedCfg
+}
+
+// toParam returns the BuildParam represented by the receiver *BuildConfig, which must be a resolved configuration.
+func (cfg *BuildConfig) toParam() (distgo.BuildParam, error) {
+	outputDir := configValue(cfg.OutputDir)
 	if path.IsAbs(outputDir) {
 		return distgo.BuildParam{}, errors.Errorf("output-dir cannot be specified as an absolute path")
 	}
-	mainPkg := getConfigStringValue(cfg.MainPkg, defaultCfg.MainPkg, "")
-	if mainPkg != "" && !strings.HasPrefix(mainPkg, "./") {
-		mainPkg = "./" + mainPkg
+	mode := configValue(cfg.Mode)
+	if mode != "" && !slices.Contains(distgo.BuildModes(), mode) {
+		return distgo.BuildParam{}, errors.Errorf("invalid build mode %q: must be one of %v", mode, distgo.BuildModes())
+	}
+	goToolchain, err := goToolchainParam(configValue(cfg.GoToolchain))
+	if err != nil {
+		return distgo.BuildParam{}, err
+	}
+	cover, err := buildCoverParam(configValue(cfg.Cover), outputDir)
+	if err != nil {
+		return distgo.BuildParam{}, err
+	}
+	sizeBudget, err := buildSizeBudgetParam(configValue(cfg.SizeBudget))
+	if err != nil {
+		return distgo.BuildParam{}, err
+	}
+	var windowsResources *distgo.WindowsResourcesParam
+	if cfg.WindowsResources != nil {
+		windowsResources = windowsResourcesParam(*cfg.WindowsResources)
+	}
+	osArchs := configValue(cfg.OSArchs)
+	variants, err := buildVariantParams(configValue(cfg.Variants), osArchs)
+	if err != nil {
+		return distgo.BuildParam{}, err
 	}
 
-	return distgo.BuildParam{
-		NameTemplate:       getConfigStringValue(cfg.NameTemplate, defaultCfg.NameTemplate, "{{Product}}"),
+	buildParam := distgo.BuildParam{
+		NameTemplate:       configValue(cfg.NameTemplate),
 		OutputDir:          outputDir,
-		MainPkg:            mainPkg,
-		BuildArgsScript:    distgo.CreateScriptContent(getConfigStringValue(cfg.BuildArgsScript, defaultCfg.BuildArgsScript, ""), scriptIncludes),
-		VersionVar:         getConfigStringValue(cfg.VersionVar, defaultCfg.VersionVar, ""),
-		Script:             getConfigStringValue(cfg.Script, defaultCfg.Script, ""),
-		Environment:        getConfigValue(cfg.Environment, defaultCfg.Environment, nil).(map[string]string),
-		OSEnvironment:      getConfigValue(cfg.OSEnvironment, defaultCfg.OSEnvironment, nil).(map[string]map[string]string),
-		OSArchsEnvironment: getConfigValue(cfg.OSArchsEnvironment, defaultCfg.OSArchsEnvironment, nil).(map[string]map[string]string),
-		OSArchs:            getConfigValue(cfg.OSArchs, defaultCfg.OSArchs, []osarch.OSArch{osarch.Current()}).([]osarch.OSArch),
-	}, nil
+		MainPkg:            configValue(cfg.MainPkg),
+		Mode:               mode,
+		GoToolchain:        goToolchain,
+		PGOProfile:         configValue(cfg.PGOProfile),
+		Cover:              cover,
+		SizeBudget:         sizeBudget,
+		WindowsResources:   windowsResources,
+		BuildArgsScript:    configValue(cfg.BuildArgsScript),
+		VersionVar:         configValue(cfg.VersionVar),
+		Script:             configValue(cfg.Script),
+		Environment:        configValue(cfg.Environment),
+		OSEnvironment:      configValue(cfg.OSEnvironment),
+		OSArchsEnvironment: configValue(cfg.OSArchsEnvironment),
+		Flags:              buildFlagsParam(configValue(cfg.Flags)),
+		OSFlags:            buildFlagsParams(configValue(cfg.OSFlags)),
+		ArchFlags:          buildFlagsParams(configValue(cfg.ArchFlags)),
+		OSArchsFlags:       buildFlagsParams(configValue(cfg.OSArchsFlags)),
+		OSArchs:            osArchs,
+		Variants:           variants,
+	}
+	if mode.IsLibrary() && specifiesBuildmodeFlag(buildParam) {
+		return distgo.BuildParam{}, errors.Errorf("the buildmode flag cannot be specified for a product with build mode %s", mode)
+	}
+	if mode.IsLibrary() && specifiesOSArch(buildParam, distgo.DarwinUniversal) {
+		return distgo.BuildParam{}, errors.Errorf("the %s OS/Arch cannot be specified for a product with build mode %s", distgo.DarwinUniversal, mode)
+	}
+	return buildParam, nil
+}
+
+// specifiesBuildmodeFlag returns true if any of the flags of the provided build parameter specify the buildmode flag.
+func specifiesBuildmodeFlag(param distgo.BuildParam) bool {
+	flags := []distgo.BuildFlagsParam{param.Flags}
+	for _, flagsMap := range []map[string]distgo.BuildFlagsParam{param.OSFlags, param.ArchFlags, param.OSArchsFlags} {
+		flags = append(flags, slices.Collect(maps.Values(flagsMap))...)
+	}
+	for _, variantParam := range param.Variants {
+		flags = append(flags, variantParam.Flags)
+	}
+	return slices.ContainsFunc(flags, func(f distgo.BuildFlagsParam) bool {
+		return f.Buildmode != nil
+	})
+}
+
+func buildCoverParam(cfg v0.BuildCoverConfig, buildOutputDir string) (distgo.BuildCoverParam, error) {
+	outputDir := getConfigStringValue(cfg.OutputDir, nil, "")
+	if path.IsAbs(outputDir) {
+		return distgo.BuildCoverParam{}, errors.Errorf("cover output-dir cannot be specified as an absolute path")
+	}
+	if path.Clean(distgo.BuildCoverParam{OutputDir: outputDir}.OutputDirOrDefault()) == path.Clean(buildOutputDir) {
+		return distgo.BuildCoverParam{}, errors.Errorf("cover output-dir must differ from the build output-dir %s", buildOutputDir)
+	}
+	param := distgo.BuildCoverParam{
+		OutputDir: outputDir,
+	}
+	if cfg.Coverpkg != nil {
+		param.Coverpkg = *cfg.Coverpkg
+	}
+	return param, nil
+}
+
+// specifiesOSArch returns true if the provided OS/Arch is an OS/Arch of the default build or of any of the variants of
+// the provided build parameter.
+func specifiesOSArch(param distgo.BuildParam, osArch osarch.OSArch) bool {
+	if slices.Contains(param.OSArchs, osArch) {
+		return true
+	}
+	for _, variantParam := range param.Variants {
+		if slices.Contains(variantParam.OSArchs, osArch) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildSizeBudgetParam(cfg v0.BuildSizeBudgetConfig) (distgo.BuildSizeBudgetParam, error) {
+	var param distgo.BuildSizeBudgetParam
+	if cfg.MaxSize != nil {
+		maxSize, err := parseByteSize(*cfg.MaxSize)
+		if err != nil {
+			return distgo.BuildSizeBudgetParam{}, errors.Wrapf(err, "invalid size-budget max-size")
+		}
+		param.MaxSize = maxSize
+	}
+	if cfg.OSArchs != nil {
+		param.OSArchsMaxSize = make(map[string]int64)
+		for osArchStr, sizeStr := range *cfg.OSArchs {
+			if _, err := osarch.New(osArchStr); err != nil {
+				return distgo.BuildSizeBudgetParam{}, errors.Wrapf(err, "invalid size-budget os-arch %s", osArchStr)
+			}
+			maxSize, err := parseByteSize(sizeStr)
+			if err != nil {
+				return distgo.BuildSizeBudgetParam{}, errors.Wrapf(err, "invalid size-budget for os-arch %s", osArchStr)
+			}
+			param.OSArchsMaxSize[osArchStr] = maxSize
+		}
+	}
+	return param, nil
+}
+
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"B":   1,
+	"KB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+}
+
+var byteSizeRegexp = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([A-Za-z]*)$`)
+
+// parseByteSize returns the number of bytes represented by the provided size, which is a number optionally followed by
+// one of the units in byteSizeUnits (for example, "512", "20MB" or "1.5GiB").
+func parseByteSize(size string) (int64, error) {
+	match := byteSizeRegexp.FindStringSubmatch(strings.TrimSpace(size))
+	if match == nil {
+		return 0, errors.Errorf("invalid size %q", size)
+	}
+	unit, ok := byteSizeUnits[match[2]]
+	if !ok {
+		return 0, errors.Errorf("invalid unit %q in size %q: must be one of B, KB, MB, GB, KiB, MiB or GiB", match[2], size)
+	}
+	val, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid size %q", size)
+	}
+	return int64(val * unit), nil
+}
+
+func windowsResourcesParam(cfg v0.WindowsResourcesConfig) *distgo.WindowsResourcesParam {
+	return &distgo.WindowsResourcesParam{
+		CompanyName:     getConfigStringValue(cfg.CompanyName, nil, ""),
+		FileDescription: getConfigStringValue(cfg.FileDescription, nil, ""),
+		ProductName:     getConfigStringValue(cfg.ProductName, nil, ""),
+		Copyright:       getConfigStringValue(cfg.Copyright, nil, ""),
+		Icon:            getConfigStringValue(cfg.Icon, nil, ""),
+		Manifest:        getConfigStringValue(cfg.Manifest, nil, ""),
+	}
+}
+
+func goToolchainParam(cfg v0.GoToolchainConfig) (distgo.GoToolchainParam, error) {
+	param := distgo.GoToolchainParam{
+		Binary:    getConfigStringValue(cfg.Binary, nil, ""),
+		Toolchain: getConfigStringValue(cfg.Toolchain, nil, ""),
+	}
+	if param.Binary != "" && param.Toolchain != "" {
+		return distgo.GoToolchainParam{}, errors.Errorf("go-toolchain cannot specify both binary and toolchain")
+	}
+	return param, nil
+}
+
+func buildFlagsParam(cfg v0.BuildFlagsConfig) distgo.BuildFlagsParam {
+	param := distgo.BuildFlagsParam{
+		Gcflags:   cfg.Gcflags,
+		Asmflags:  cfg.Asmflags,
+		Trimpath:  cfg.Trimpath,
+		Buildmode: cfg.Buildmode,
+		Race:      cfg.Race,
+		Strip:     cfg.Strip,
+	}
+	if cfg.Tags != nil {
+		param.Tags = *cfg.Tags
+	}
+	if cfg.LdflagsVars != nil {
+		param.LdflagsVars = *cfg.LdflagsVars
+	}
+	return param
+}
+
+func buildFlagsParams(cfgs map[string]v0.BuildFlagsConfig) map[string]distgo.BuildFlagsParam {
+	if cfgs == nil {
+		return nil
+	}
+	params := make(map[string]distgo.BuildFlagsParam, len(cfgs))
+	for k, cfg := range cfgs {
+		params[k] = buildFlagsParam(cfg)
+	}
+	return params
+}
+
+func buildVariantParams(cfgs map[distgo.BuildVariantID]v0.BuildVariantConfig, defaultOSArchs []osarch.OSArch) (map[distgo.BuildVariantID]distgo.BuildVariantParam, error) {
+	if cfgs == nil {
+		return nil, nil
+	}
+	params := make(map[distgo.BuildVariantID]distgo.BuildVariantParam, len(cfgs))
+	for variantID, cfg := range cfgs {
+		if !distgo.IsValidSelectorName(string(variantID)) || strings.ContainsAny(string(variantID), "./") {
+			return nil, errors.Errorf("invalid build variant name %q: must consist of alphanumeric characters, '-' and '_'", variantID)
+		}
+		param := distgo.BuildVariantParam{
+			OSArchs: defaultOSArchs,
+		}
+		if cfg.Environment != nil {
+			param.Environment = *cfg.Environment
+		}
+		if cfg.Flags != nil {
+			param.Flags = buildFlagsParam(*cfg.Flags)
+		}
+		if cfg.OSArchs != nil {
+			param.OSArchs = *cfg.OSArchs
+		}
+		params[variantID] = param
+	}
+	return params, nil
 }
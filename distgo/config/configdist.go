@@ -24,6 +24,11 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+const (
+	defaultDistOutputDir      = "out/dist"
+	defaultDisterNameTemplate = "{{Product}}-{{Version}}"
+)
+
 type DistConfig v0.DistConfig
 
 func ToDistConfig(in *DistConfig) *v0.DistConfig {
@@ -35,11 +40,26 @@ func ToDistConfig(in *DistConfig) *v0.DistConfig {
 // receiver config but is specified in the default config, the default config value is used. If a value is not specified
 // in either configuration, the program-specified default value (if any) is used.
 func (cfg *DistConfig) ToParam(scriptIncludes string, defaultCfg DistConfig, disterFactory distgo.DisterFactory) (distgo.DistParam, error) {
-	outputDir := getConfigStringValue(cfg.OutputDir, defaultCfg.OutputDir, "out/dist")
+	resolvedCfg := cfg.resolve(nil, "", scriptIncludes, defaultCfg)
+	return resolvedCfg.toParam(disterFactory)
+}
+
+// resolve returns the DistConfig in which every value is the value that ToParam uses. The source of each value is
+// recorded in sources using paths relative to the provided path.
+func (cfg *DistConfig) resolve(sources *configSources, path, scriptIncludes string, defaultCfg DistConfig) DistConfig {
+	return DistConfig{
+		OutputDir: resolveStringValue(sources, joinConfigPath(path, "output-dir"), cfg.OutputDir, defaultCfg.OutputDir, defaultDistOutputDir),
+		Disters:   ToDistersConfig((*DistersConfig)(cfg.Disters).resolve(sources, joinConfigPath(path, "disters"), (*DistersConfig)(defaultCfg.Disters), scriptIncludes)),
+	}
+}
+
+// toParam returns the DistParam represented by the receiver *DistConfig, which must be a resolved configuration.
+func (cfg *DistConfig) toParam(disterFactory distgo.DisterFactory) (distgo.DistParam, error) {
+	outputDir := configValue(cfg.OutputDir)
 	if path.IsAbs(outputDir) {
 		return distgo.DistParam{}, errors.Errorf("output-dir cannot be specified as an absolute path")
 	}
-	disters, err := (*DistersConfig)(cfg.Disters).ToParam((*DistersConfig)(defaultCfg.Disters), scriptIncludes, disterFactory)
+	disters, err := (*DistersConfig)(cfg.Disters).toParam(disterFactory)
 	if err != nil {
 		return distgo.DistParam{}, err
 	}
@@ -56,21 +76,46 @@ func ToDisterConfig(in DisterConfig) v0.DisterConfig {
 }
 
 func (cfg *DisterConfig) ToParam(defaultCfg DisterConfig, scriptIncludes string, disterFactory distgo.DisterFactory) (distgo.DisterParam, error) {
-	disterType := getConfigStringValue(cfg.Type, defaultCfg.Type, "")
+	resolvedCfg := cfg.resolve(nil, "", scriptIncludes, defaultCfg)
+	return resolvedCfg.toParam(disterFactory)
+}
+
+// resolve returns the DisterConfig in which every value is the value that ToParam uses. The script includes are
+// prepended to the script. The source of each value is recorded in sources using paths relative to the provided path.
+func (cfg *DisterConfig) resolve(sources *configSources, path, scriptIncludes string, defaultCfg DisterConfig) DisterConfig {
+	key := func(key string) string {
+		return joinConfigPath(path, key)
+	}
+	return DisterConfig{
+		Type:               resolveValue(sources, key("type"), cfg.Type, defaultCfg.Type, nil),
+		Config:             resolveValue(sources, key("config"), cfg.Config, defaultCfg.Config, nil),
+		NameTemplate:       resolveStringValue(sources, key("name-template"), cfg.NameTemplate, defaultCfg.NameTemplate, defaultDisterNameTemplate),
+		InputDir:           resolveValue(sources, key("input-dir"), cfg.InputDir, defaultCfg.InputDir, nil),
+		Script:             resolveScriptValue(resolveValue(sources, key("script"), cfg.Script, defaultCfg.Script, nil), scriptIncludes),
+		InputDockerExports: resolveValue(sources, key("input-docker-exports"), cfg.InputDockerExports, defaultCfg.InputDockerExports, nil),
+		BuildVariant:       resolveValue(sources, key("build-variant"), cfg.BuildVariant, defaultCfg.BuildVariant, nil),
+	}
+}
+
+// toParam returns the DisterParam represented by the receiver *DisterConfig, which must be a resolved configuration.
+func (cfg *DisterConfig) toParam(disterFactory distgo.DisterFactory) (distgo.DisterParam, error) {
+	disterType := configValue(cfg.Type)
 	if disterType == "" {
 		return distgo.DisterParam{}, errors.Errorf("dister type must be specified for DisterConfig")
 	}
-	dister, err := newDister(disterType, getConfigValue(cfg.Config, defaultCfg.Config, nil).(yaml.MapSlice), disterFactory)
+	dister, err := newDister(disterType, configValue(cfg.Config), disterFactory)
 	if err != nil {
 		return distgo.DisterParam{}, err
 	}
 
-	inputDirCfg := getConfigValue((*InputDirConfig)(cfg.InputDir), (*InputDirConfig)(defaultCfg.InputDir), InputDirConfig{}).(InputDirConfig)
+	inputDirCfg := configValue((*InputDirConfig)(cfg.InputDir))
 	return distgo.DisterParam{
-		NameTemplate: getConfigStringValue(cfg.NameTemplate, defaultCfg.NameTemplate, "{{Product}}-{{Version}}"),
-		InputDir:     inputDirCfg.ToParam(),
-		Script:       distgo.CreateScriptContent(getConfigStringValue(cfg.Script, defaultCfg.Script, ""), scriptIncludes),
-		Dister:       dister,
+		NameTemplate:       configValue(cfg.NameTemplate),
+		InputDir:           inputDirCfg.ToParam(),
+		Script:             configValue(cfg.Script),
+		Dister:             dister,
+		InputDockerExports: configValue(cfg.InputDockerExports),
+		BuildVariant:       configValue(cfg.BuildVariant),
 	}, nil
 }
 
@@ -116,47 +161,49 @@ func ToDistersConfig(in *DistersConfig) *v0.DistersConfig {
 }
 
 func (cfgs *DistersConfig) ToParam(defaultCfg *DistersConfig, scriptIncludes string, disterFactory distgo.DisterFactory) (map[distgo.DistID]distgo.DisterParam, error) {
-	// keys that exist either only in cfgs or only in defaultCfg
-	distinctCfgs := make(map[distgo.DistID]DisterConfig)
-	// keys that appear in both cfgs and defaultCfg
-	commonCfgIDs := make(map[distgo.DistID]struct{})
+	return cfgs.resolve(nil, "", defaultCfg, scriptIncludes).toParam(disterFactory)
+}
 
-	if cfgs != nil {
-		for distID, distCfg := range *cfgs {
-			if defaultCfg != nil {
-				if _, ok := (*defaultCfg)[distID]; ok {
-					commonCfgIDs[distID] = struct{}{}
-					continue
-				}
-			}
-			distinctCfgs[distID] = DisterConfig(distCfg)
-		}
+// resolve returns the DistersConfig that contains the disters of the receiver and of the default config: a dister that
+// is specified in both is resolved against the dister of the default config, and every other dister is resolved on its
+// own. Returns nil if both are nil. The source of each value is recorded in sources using paths relative to the
+// provided path.
+func (cfgs *DistersConfig) resolve(sources *configSources, path string, defaultCfg *DistersConfig, scriptIncludes string) *DistersConfig {
+	if cfgs == nil && defaultCfg == nil {
+		return nil
 	}
+	resolvedCfgs := make(DistersConfig)
 	if defaultCfg != nil {
 		for distID, distCfg := range *defaultCfg {
 			if cfgs != nil {
 				if _, ok := (*cfgs)[distID]; ok {
-					commonCfgIDs[distID] = struct{}{}
 					continue
 				}
 			}
-			distinctCfgs[distID] = DisterConfig(distCfg)
+			// a dister that only exists in the default config is inherited from it
+			resolvedCfgs[distID] = v0.DisterConfig((&DisterConfig{}).resolve(sources, joinConfigPath(path, string(distID)), scriptIncludes, DisterConfig(distCfg)))
+		}
+	}
+	if cfgs != nil {
+		for distID, distCfg := range *cfgs {
+			var currDefaultCfg DisterConfig
+			if defaultCfg != nil {
+				currDefaultCfg = DisterConfig((*defaultCfg)[distID])
+			}
+			resolvedCfgs[distID] = v0.DisterConfig((*DisterConfig)(&distCfg).resolve(sources, joinConfigPath(path, string(distID)), scriptIncludes, currDefaultCfg))
 		}
 	}
+	return &resolvedCfgs
+}
 
+// toParam returns the DisterParams represented by the receiver *DistersConfig, which must be a resolved configuration.
+func (cfgs *DistersConfig) toParam(disterFactory distgo.DisterFactory) (map[distgo.DistID]distgo.DisterParam, error) {
 	distParamsMap := make(map[distgo.DistID]distgo.DisterParam)
-	// generate parameters for all of the distinct elements
-	for distID, distCfg := range distinctCfgs {
-		currParam, err := distCfg.ToParam(DisterConfig{}, scriptIncludes, disterFactory)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to generate parameter for dist configuration %s", distID)
-		}
-		distParamsMap[distID] = currParam
+	if cfgs == nil {
+		return distParamsMap, nil
 	}
-	// merge keys that appear in both maps
-	for distID := range commonCfgIDs {
-		currCfg := (*cfgs)[distID]
-		currParam, err := (*DisterConfig)(&currCfg).ToParam(DisterConfig((*defaultCfg)[distID]), scriptIncludes, disterFactory)
+	for distID, distCfg := range *cfgs {
+		currParam, err := (*DisterConfig)(&distCfg).toParam(disterFactory)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to generate parameter for dist configuration %s", distID)
 		}
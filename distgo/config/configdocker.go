@@ -17,13 +17,21 @@ package config
 import (
 	"path"
 	"strings"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/palantir/distgo/distgo"
 	v0 "github.com/palantir/distgo/distgo/config/internal/v0"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
 
+const (
+	defaultDockerOutputDir = "out/docker"
+	defaultDockerfilePath  = "Dockerfile"
+)
+
 type DockerConfig v0.DockerConfig
 
 func ToDockerConfig(in *DockerConfig) *v0.DockerConfig {
@@ -31,19 +39,129 @@ func ToDockerConfig(in *DockerConfig) *v0.DockerConfig {
 }
 
 func (cfg *DockerConfig) ToParam(scriptIncludes string, defaultCfg DockerConfig, dockerBuilderFactory distgo.DockerBuilderFactory) (distgo.DockerParam, error) {
-	outputDir := getConfigStringValue(cfg.OutputDir, defaultCfg.OutputDir, "out/docker")
+	resolvedCfg := cfg.resolve(nil, "", scriptIncludes, defaultCfg)
+	return resolvedCfg.toParam(dockerBuilderFactory)
+}
+
+// resolve returns the DockerConfig in which every value is the value that ToParam uses. The Docker builders are not
+// inherited from the default config. The source of each value is recorded in sources using paths relative to the
+// provided path.
+func (cfg *DockerConfig) resolve(sources *configSources, path, scriptIncludes string, defaultCfg DockerConfig) DockerConfig {
+	return DockerConfig{
+		Repository:           resolveValue(sources, joinConfigPath(path, "repository"), cfg.Repository, defaultCfg.Repository, nil),
+		OutputDir:            resolveStringValue(sources, joinConfigPath(path, "output-dir"), cfg.OutputDir, defaultCfg.OutputDir, defaultDockerOutputDir),
+		DockerBuildersConfig: ToDockerBuildersConfig((*DockerBuildersConfig)(cfg.DockerBuildersConfig).resolve(sources, joinConfigPath(path, "docker-builders"), scriptIncludes, nil)),
+		Registries:           resolveValue(sources, joinConfigPath(path, "registries"), cfg.Registries, defaultCfg.Registries, nil),
+	}
+}
+
+// toParam returns the DockerParam represented by the receiver *DockerConfig, which must be a resolved configuration.
+func (cfg *DockerConfig) toParam(dockerBuilderFactory distgo.DockerBuilderFactory) (distgo.DockerParam, error) {
+	outputDir := configValue(cfg.OutputDir)
 	if err := validateDockerOutputDir(outputDir); err != nil {
 		return distgo.DockerParam{}, err
 	}
 
-	dockerBuilderParams, err := (*DockerBuildersConfig)(cfg.DockerBuildersConfig).ToParam(scriptIncludes, (*DockerBuildersConfig)(cfg.DockerBuildersConfig), dockerBuilderFactory)
+	dockerBuilderParams, err := (*DockerBuildersConfig)(cfg.DockerBuildersConfig).toParam(dockerBuilderFactory)
 	if err != nil {
 		return distgo.DockerParam{}, err
 	}
+	var registries map[string]distgo.DockerRegistryParam
+	if registriesCfg := configValue(cfg.Registries); len(registriesCfg) > 0 {
+		registries = make(map[string]distgo.DockerRegistryParam)
+		for host, registryCfg := range registriesCfg {
+			registry, err := name.NewRegistry(host)
+			if err != nil {
+				return distgo.DockerParam{}, errors.Wrapf(err, "invalid registry %q", host)
+			}
+			// registries are keyed by their canonical form so that "docker.io" and "index.docker.io" refer to the same
+			// registry
+			key := registry.RegistryStr()
+			if _, ok := registries[key]; ok {
+				return distgo.DockerParam{}, errors.Errorf("registry %q specified more than once", key)
+			}
+			registryParam, err := (*DockerRegistryConfig)(&registryCfg).ToParam()
+			if err != nil {
+				return distgo.DockerParam{}, errors.Wrapf(err, "invalid configuration for registry %q", host)
+			}
+			registries[key] = registryParam
+		}
+	}
 	return distgo.DockerParam{
 		OutputDir:           outputDir,
-		Repository:          getConfigStringValue(cfg.Repository, defaultCfg.Repository, ""),
+		Repository:          configValue(cfg.Repository),
 		DockerBuilderParams: dockerBuilderParams,
+		Registries:          registries,
+	}, nil
+}
+
+type DockerRegistriesConfig v0.DockerRegistriesConfig
+
+func ToDockerRegistriesConfig(in *DockerRegistriesConfig) *v0.DockerRegistriesConfig {
+	return (*v0.DockerRegistriesConfig)(in)
+}
+
+type DockerRegistryConfig v0.DockerRegistryConfig
+
+func ToDockerRegistryConfig(in DockerRegistryConfig) v0.DockerRegistryConfig {
+	return (v0.DockerRegistryConfig)(in)
+}
+
+type DockerRegistryCredentialsConfig v0.DockerRegistryCredentialsConfig
+
+func ToDockerRegistryCredentialsConfig(in *DockerRegistryCredentialsConfig) *v0.DockerRegistryCredentialsConfig {
+	return (*v0.DockerRegistryCredentialsConfig)(in)
+}
+
+type DockerRegistryRetryConfig v0.DockerRegistryRetryConfig
+
+func ToDockerRegistryRetryConfig(in *DockerRegistryRetryConfig) *v0.DockerRegistryRetryConfig {
+	return (*v0.DockerRegistryRetryConfig)(in)
+}
+
+func (cfg *DockerRegistryConfig) ToParam() (distgo.DockerRegistryParam, error) {
+	var credentials *distgo.DockerRegistryCredentialsParam
+	if cfg.Credentials != nil {
+		credentials = &distgo.DockerRegistryCredentialsParam{
+			UsernameEnvVar: getConfigStringValue(cfg.Credentials.UsernameEnv, nil, ""),
+			PasswordEnvVar: getConfigStringValue(cfg.Credentials.PasswordEnv, nil, ""),
+			TokenEnvVar:    getConfigStringValue(cfg.Credentials.TokenEnv, nil, ""),
+		}
+		hasBasic := credentials.UsernameEnvVar != "" || credentials.PasswordEnvVar != ""
+		switch {
+		case hasBasic && credentials.TokenEnvVar != "":
+			return distgo.DockerRegistryParam{}, errors.Errorf("credentials cannot specify both token-env and username-env/password-env")
+		case hasBasic && (credentials.UsernameEnvVar == "" || credentials.PasswordEnvVar == ""):
+			return distgo.DockerRegistryParam{}, errors.Errorf("credentials must specify both username-env and password-env")
+		case !hasBasic && credentials.TokenEnvVar == "":
+			return distgo.DockerRegistryParam{}, errors.Errorf("credentials must specify either username-env and password-env or token-env")
+		}
+	}
+
+	var retry *distgo.DockerRegistryRetryParam
+	if cfg.Retry != nil {
+		attempts := getConfigValue(cfg.Retry.Attempts, (*int)(nil), 1).(int)
+		if attempts < 1 {
+			return distgo.DockerRegistryParam{}, errors.Errorf("retry attempts must be at least 1, was %d", attempts)
+		}
+		initialBackoffStr := getConfigStringValue(cfg.Retry.InitialBackoff, nil, "1s")
+		initialBackoff, err := time.ParseDuration(initialBackoffStr)
+		if err != nil {
+			return distgo.DockerRegistryParam{}, errors.Wrapf(err, "invalid retry initial-backoff %q", initialBackoffStr)
+		}
+		if initialBackoff < 0 {
+			return distgo.DockerRegistryParam{}, errors.Errorf("retry initial-backoff cannot be negative, was %q", initialBackoffStr)
+		}
+		retry = &distgo.DockerRegistryRetryParam{
+			Attempts:       attempts,
+			InitialBackoff: initialBackoff,
+		}
+	}
+
+	return distgo.DockerRegistryParam{
+		Insecure:    getConfigValue(cfg.Insecure, (*bool)(nil), false).(bool),
+		Credentials: credentials,
+		Retry:       retry,
 	}, nil
 }
 
@@ -69,49 +187,52 @@ func ToDockerBuildersConfig(in *DockerBuildersConfig) *v0.DockerBuildersConfig {
 }
 
 func (cfgs *DockerBuildersConfig) ToParam(scriptIncludes string, defaultCfg *DockerBuildersConfig, dockerBuilderFactory distgo.DockerBuilderFactory) (map[distgo.DockerID]distgo.DockerBuilderParam, error) {
-	// keys that exist either only in cfgs or only in defaultCfg
-	distinctCfgs := make(map[distgo.DockerID]DockerBuilderConfig)
-	// keys that appear in both cfgs and defaultCfg
-	commonCfgIDs := make(map[distgo.DockerID]struct{})
+	return cfgs.resolve(nil, "", scriptIncludes, defaultCfg).toParam(dockerBuilderFactory)
+}
 
-	if cfgs != nil {
-		for dockerID, dockerCfg := range *cfgs {
-			if defaultCfg != nil {
-				if _, ok := (*defaultCfg)[dockerID]; ok {
-					commonCfgIDs[dockerID] = struct{}{}
-					continue
-				}
-			}
-			distinctCfgs[dockerID] = DockerBuilderConfig(dockerCfg)
-		}
+// resolve returns the DockerBuildersConfig that contains the Docker builders of the receiver and of the default config:
+// a Docker builder that is specified in both is resolved against the Docker builder of the default config, and every
+// other Docker builder is resolved on its own. Returns nil if both are nil. The source of each value is recorded in
+// sources using paths relative to the provided path.
+func (cfgs *DockerBuildersConfig) resolve(sources *configSources, path, scriptIncludes string, defaultCfg *DockerBuildersConfig) *DockerBuildersConfig {
+	if cfgs == nil && defaultCfg == nil {
+		return nil
 	}
+	resolvedCfgs := make(DockerBuildersConfig)
 	if defaultCfg != nil {
-		for distID, distCfg := range *defaultCfg {
+		for dockerID, dockerCfg := range *defaultCfg {
 			if cfgs != nil {
-				if _, ok := (*cfgs)[distID]; ok {
-					commonCfgIDs[distID] = struct{}{}
+				if _, ok := (*cfgs)[dockerID]; ok {
 					continue
 				}
 			}
-			distinctCfgs[distID] = DockerBuilderConfig(distCfg)
+			// a Docker builder that only exists in the default config is inherited from it
+			resolvedCfgs[dockerID] = v0.DockerBuilderConfig((&DockerBuilderConfig{}).resolve(sources, joinConfigPath(path, string(dockerID)), scriptIncludes, DockerBuilderConfig(dockerCfg)))
 		}
 	}
+	if cfgs != nil {
+		for dockerID, dockerCfg := range *cfgs {
+			var currDefaultCfg DockerBuilderConfig
+			if defaultCfg != nil {
+				currDefaultCfg = DockerBuilderConfig((*defaultCfg)[dockerID])
+			}
+			resolvedCfgs[dockerID] = v0.DockerBuilderConfig((*DockerBuilderConfig)(&dockerCfg).resolve(sources, joinConfigPath(path, string(dockerID)), scriptIncludes, currDefaultCfg))
+		}
+	}
+	return &resolvedCfgs
+}
 
+// toParam returns the DockerBuilderParams represented by the receiver *DockerBuildersConfig, which must be a resolved
+// configuration.
+func (cfgs *DockerBuildersConfig) toParam(dockerBuilderFactory distgo.DockerBuilderFactory) (map[distgo.DockerID]distgo.DockerBuilderParam, error) {
 	dockerBuilderParamsMap := make(map[distgo.DockerID]distgo.DockerBuilderParam)
-	// generate parameters for all of the distinct elements
-	for dockerID, dockerBuilderCfg := range distinctCfgs {
-		currParam, err := dockerBuilderCfg.ToParam(scriptIncludes, DockerBuilderConfig{}, dockerBuilderFactory)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to generate parameter for Docker configuration %s", dockerID)
-		}
-		dockerBuilderParamsMap[dockerID] = currParam
+	if cfgs == nil {
+		return dockerBuilderParamsMap, nil
 	}
-	// merge keys that appear in both maps
-	for dockerID := range commonCfgIDs {
-		currCfg := (*cfgs)[dockerID]
-		currParam, err := (*DockerBuilderConfig)(&currCfg).ToParam(scriptIncludes, DockerBuilderConfig((*defaultCfg)[dockerID]), dockerBuilderFactory)
+	for dockerID, dockerBuilderCfg := range *cfgs {
+		currParam, err := (*DockerBuilderConfig)(&dockerBuilderCfg).toParam(dockerBuilderFactory)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to generate parameter for dist configuration %s", dockerID)
+			return nil, errors.Wrapf(err, "failed to generate parameter for Docker configuration %s", dockerID)
 		}
 		dockerBuilderParamsMap[dockerID] = currParam
 	}
@@ -125,36 +246,155 @@ func ToDockerBuilderConfig(in DockerBuilderConfig) v0.DockerBuilderConfig {
 }
 
 func (cfg *DockerBuilderConfig) ToParam(scriptIncludes string, defaultCfg DockerBuilderConfig, dockerBuilderFactory distgo.DockerBuilderFactory) (distgo.DockerBuilderParam, error) {
-	dockerBuilderType := getConfigStringValue(cfg.Type, defaultCfg.Type, "")
+	resolvedCfg := cfg.resolve(nil, "", scriptIncludes, defaultCfg)
+	return resolvedCfg.toParam(dockerBuilderFactory)
+}
+
+// resolve returns the DockerBuilderConfig in which every value is the value that ToParam uses. The script includes are
+// prepended to the script. The source of each value is recorded in sources using paths relative to the provided path.
+func (cfg *DockerBuilderConfig) resolve(sources *configSources, path, scriptIncludes string, defaultCfg DockerBuilderConfig) DockerBuilderConfig {
+	key := func(key string) string {
+		return joinConfigPath(path, key)
+	}
+	return DockerBuilderConfig{
+		Type:                     resolveValue(sources, key("type"), cfg.Type, defaultCfg.Type, nil),
+		Script:                   resolveScriptValue(resolveValue(sources, key("script"), cfg.Script, defaultCfg.Script, nil), scriptIncludes),
+		Config:                   resolveValue(sources, key("config"), cfg.Config, defaultCfg.Config, nil),
+		DockerfilePath:           resolveStringValue(sources, key("dockerfile-path"), cfg.DockerfilePath, defaultCfg.DockerfilePath, defaultDockerfilePath),
+		DisableTemplateRendering: resolveValue(sources, key("disable-template-rendering"), cfg.DisableTemplateRendering, defaultCfg.DisableTemplateRendering, nil),
+		SkipPush:                 resolveValue(sources, key("skip-push"), cfg.SkipPush, defaultCfg.SkipPush, nil),
+		ContextDir:               resolveValue(sources, key("context-dir"), cfg.ContextDir, defaultCfg.ContextDir, nil),
+		InputProductsDir:         resolveValue(sources, key("input-products-dir"), cfg.InputProductsDir, defaultCfg.InputProductsDir, nil),
+		InputBuilds:              resolveValue(sources, key("input-builds"), cfg.InputBuilds, defaultCfg.InputBuilds, nil),
+		InputDists:               resolveValue(sources, key("input-dists"), cfg.InputDists, defaultCfg.InputDists, nil),
+		InputDistsOutputPaths:    resolveValue(sources, key("input-dist-output-paths"), cfg.InputDistsOutputPaths, defaultCfg.InputDistsOutputPaths, nil),
+		TagTemplates:             resolveValue(sources, key("tag-templates"), cfg.TagTemplates, defaultCfg.TagTemplates, nil),
+		Tests:                    resolveValue(sources, key("tests"), cfg.Tests, defaultCfg.Tests, nil),
+		Export:                   resolveValue(sources, key("export"), cfg.Export, defaultCfg.Export, nil),
+		Mirrors:                  resolveValue(sources, key("mirrors"), cfg.Mirrors, defaultCfg.Mirrors, nil),
+	}
+}
+
+// toParam returns the DockerBuilderParam represented by the receiver *DockerBuilderConfig, which must be a resolved
+// configuration.
+func (cfg *DockerBuilderConfig) toParam(dockerBuilderFactory distgo.DockerBuilderFactory) (distgo.DockerBuilderParam, error) {
+	dockerBuilderType := configValue(cfg.Type)
 	if dockerBuilderType == "" {
 		return distgo.DockerBuilderParam{}, errors.Errorf("type must be non-empty")
 	}
-	dockerBuilder, err := newDockerBuilder(dockerBuilderType, getConfigValue(cfg.Config, defaultCfg.Config, nil).(yaml.MapSlice), dockerBuilderFactory)
+	dockerBuilder, err := newDockerBuilder(dockerBuilderType, configValue(cfg.Config), dockerBuilderFactory)
 	if err != nil {
 		return distgo.DockerBuilderParam{}, err
 	}
 
-	contextDir := getConfigStringValue(cfg.ContextDir, defaultCfg.ContextDir, "")
+	contextDir := configValue(cfg.ContextDir)
 	if contextDir == "" {
 		return distgo.DockerBuilderParam{}, errors.Errorf("context-dir must be non-empty")
 	}
-	tagTemplates := TagTemplatesMap(getConfigValue(cfg.TagTemplates, defaultCfg.TagTemplates, nil).(v0.TagTemplatesMap))
+	tagTemplates := TagTemplatesMap(configValue(cfg.TagTemplates))
 	if len(tagTemplates.Templates) == 0 {
 		return distgo.DockerBuilderParam{}, errors.Errorf("tag-templates must be non-empty")
 	}
 
+	var tests *distgo.DockerImageTestsParam
+	if cfg.Tests != nil {
+		testsParam, err := (*DockerImageTestsConfig)(cfg.Tests).ToParam()
+		if err != nil {
+			return distgo.DockerBuilderParam{}, errors.Wrapf(err, "invalid tests")
+		}
+		tests = &testsParam
+	}
+
+	var export *distgo.DockerExportParam
+	if cfg.Export != nil {
+		exportParam, err := (*DockerExportConfig)(cfg.Export).ToParam()
+		if err != nil {
+			return distgo.DockerBuilderParam{}, errors.Wrapf(err, "invalid export")
+		}
+		if exportParam.DockerArchive || exportParam.OCIArchive {
+			export = &exportParam
+		}
+	}
+
+	var mirrors map[distgo.DockerMirrorID]distgo.DockerMirrorParam
+	if mirrorsCfg := configValue(cfg.Mirrors); len(mirrorsCfg) > 0 {
+		mirrors = make(map[distgo.DockerMirrorID]distgo.DockerMirrorParam)
+		for mirrorID, mirrorCfg := range mirrorsCfg {
+			mirrorParam, err := (*DockerMirrorConfig)(&mirrorCfg).ToParam(tagTemplates)
+			if err != nil {
+				return distgo.DockerBuilderParam{}, errors.Wrapf(err, "invalid mirror %s", mirrorID)
+			}
+			mirrors[mirrorID] = mirrorParam
+		}
+	}
+
 	return distgo.DockerBuilderParam{
 		DockerBuilder:            dockerBuilder,
-		Script:                   distgo.CreateScriptContent(getConfigStringValue(cfg.Script, defaultCfg.Script, ""), scriptIncludes),
-		DockerfilePath:           getConfigStringValue(cfg.DockerfilePath, defaultCfg.DockerfilePath, "Dockerfile"),
-		DisableTemplateRendering: getConfigValue(cfg.DisableTemplateRendering, defaultCfg.DisableTemplateRendering, false).(bool),
-		SkipPush:                 getConfigValue(cfg.SkipPush, defaultCfg.SkipPush, false).(bool),
+		Script:                   configValue(cfg.Script),
+		DockerfilePath:           configValue(cfg.DockerfilePath),
+		DisableTemplateRendering: configValue(cfg.DisableTemplateRendering),
+		SkipPush:                 configValue(cfg.SkipPush),
 		ContextDir:               contextDir,
-		InputProductsDir:         getConfigStringValue(cfg.InputProductsDir, defaultCfg.InputProductsDir, ""),
-		InputBuilds:              getConfigValue(cfg.InputBuilds, defaultCfg.InputBuilds, nil).([]distgo.ProductBuildID),
-		InputDists:               getConfigValue(cfg.InputDists, defaultCfg.InputDists, nil).([]distgo.ProductDistID),
-		InputDistsOutputPaths:    getConfigValue(cfg.InputDistsOutputPaths, defaultCfg.InputDistsOutputPaths, nil).(map[distgo.ProductDistID][]string),
+		InputProductsDir:         configValue(cfg.InputProductsDir),
+		InputBuilds:              configValue(cfg.InputBuilds),
+		InputDists:               configValue(cfg.InputDists),
+		InputDistsOutputPaths:    configValue(cfg.InputDistsOutputPaths),
 		TagTemplates:             tagTemplates.ToParam(),
+		Tests:                    tests,
+		Export:                   export,
+		Mirrors:                  mirrors,
+	}, nil
+}
+
+type DockerMirrorsConfig v0.DockerMirrorsConfig
+
+func ToDockerMirrorsConfig(in *DockerMirrorsConfig) *v0.DockerMirrorsConfig {
+	return (*v0.DockerMirrorsConfig)(in)
+}
+
+type DockerMirrorConfig v0.DockerMirrorConfig
+
+func ToDockerMirrorConfig(in DockerMirrorConfig) v0.DockerMirrorConfig {
+	return (v0.DockerMirrorConfig)(in)
+}
+
+// ToParam returns the parameter for the mirror. builderTagTemplates are the tag templates of the Docker builder, which
+// are used if the mirror does not specify its own.
+func (cfg *DockerMirrorConfig) ToParam(builderTagTemplates TagTemplatesMap) (distgo.DockerMirrorParam, error) {
+	repository := getConfigStringValue(cfg.Repository, nil, "")
+	if repository == "" {
+		return distgo.DockerMirrorParam{}, errors.Errorf("repository must be non-empty")
+	}
+	tagTemplates := builderTagTemplates
+	if cfg.TagTemplates != nil {
+		tagTemplates = TagTemplatesMap(*cfg.TagTemplates)
+		if len(tagTemplates.Templates) == 0 {
+			return distgo.DockerMirrorParam{}, errors.Errorf("tag-templates must be non-empty if specified")
+		}
+	}
+	return distgo.DockerMirrorParam{
+		Repository:   repository,
+		TagTemplates: tagTemplates.ToParam(),
+	}, nil
+}
+
+type DockerExportConfig v0.DockerExportConfig
+
+func ToDockerExportConfig(in *DockerExportConfig) *v0.DockerExportConfig {
+	return (*v0.DockerExportConfig)(in)
+}
+
+func (cfg *DockerExportConfig) ToParam() (distgo.DockerExportParam, error) {
+	platform := getConfigStringValue(cfg.DockerArchivePlatform, nil, "")
+	if platform != "" {
+		if _, err := v1.ParsePlatform(platform); err != nil {
+			return distgo.DockerExportParam{}, errors.Wrapf(err, "invalid docker-archive-platform %q", platform)
+		}
+	}
+	return distgo.DockerExportParam{
+		DockerArchive:         getConfigValue(cfg.DockerArchive, (*bool)(nil), false).(bool),
+		DockerArchivePlatform: platform,
+		OCIArchive:            getConfigValue(cfg.OCIArchive, (*bool)(nil), false).(bool),
 	}, nil
 }
 
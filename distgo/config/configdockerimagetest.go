@@ -0,0 +1,141 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/palantir/distgo/distgo"
+	v0 "github.com/palantir/distgo/distgo/config/internal/v0"
+	"github.com/pkg/errors"
+)
+
+type DockerImageTestsConfig v0.DockerImageTestsConfig
+
+func ToDockerImageTestsConfig(in *DockerImageTestsConfig) *v0.DockerImageTestsConfig {
+	return (*v0.DockerImageTestsConfig)(in)
+}
+
+func (cfg *DockerImageTestsConfig) ToParam() (distgo.DockerImageTestsParam, error) {
+	for _, platform := range cfg.Platforms {
+		if _, err := v1.ParsePlatform(platform); err != nil {
+			return distgo.DockerImageTestsParam{}, errors.Wrapf(err, "invalid platform %q", platform)
+		}
+	}
+
+	var fileExistenceTests []distgo.DockerImageFileExistenceTest
+	for _, testCfg := range cfg.FileExistence {
+		if err := validateImageTestPath(testCfg.Path); err != nil {
+			return distgo.DockerImageTestsParam{}, errors.Wrapf(err, "invalid file-existence test")
+		}
+		var mode *int64
+		if testCfg.Mode != nil {
+			modeVal, err := strconv.ParseUint(*testCfg.Mode, 8, 32)
+			if err != nil || modeVal > 07777 {
+				return distgo.DockerImageTestsParam{}, errors.Errorf("invalid file-existence test for %s: mode %q is not an octal permission value such as \"0755\"", testCfg.Path, *testCfg.Mode)
+			}
+			mode = new(int64(modeVal))
+		}
+		fileExistenceTests = append(fileExistenceTests, distgo.DockerImageFileExistenceTest{
+			Path:        testCfg.Path,
+			ShouldExist: getConfigValue(testCfg.ShouldExist, (*bool)(nil), true).(bool),
+			Mode:        mode,
+			UID:         testCfg.UID,
+			GID:         testCfg.GID,
+		})
+	}
+
+	var fileContentTests []distgo.DockerImageFileContentTest
+	for _, testCfg := range cfg.FileContent {
+		if err := validateImageTestPath(testCfg.Path); err != nil {
+			return distgo.DockerImageTestsParam{}, errors.Wrapf(err, "invalid file-content test")
+		}
+		expectedContents, err := compileRegexps(testCfg.ExpectedContents)
+		if err != nil {
+			return distgo.DockerImageTestsParam{}, errors.Wrapf(err, "invalid expected-contents for file-content test for %s", testCfg.Path)
+		}
+		excludedContents, err := compileRegexps(testCfg.ExcludedContents)
+		if err != nil {
+			return distgo.DockerImageTestsParam{}, errors.Wrapf(err, "invalid excluded-contents for file-content test for %s", testCfg.Path)
+		}
+		fileContentTests = append(fileContentTests, distgo.DockerImageFileContentTest{
+			Path:             testCfg.Path,
+			ExpectedContents: expectedContents,
+			ExcludedContents: excludedContents,
+		})
+	}
+
+	var metadataTest *distgo.DockerImageMetadataTest
+	if cfg.Metadata != nil {
+		metadataTest = &distgo.DockerImageMetadataTest{
+			Entrypoint:     cfg.Metadata.Entrypoint,
+			Cmd:            cfg.Metadata.Cmd,
+			User:           cfg.Metadata.User,
+			WorkingDir:     cfg.Metadata.WorkingDir,
+			Env:            cfg.Metadata.Env,
+			Labels:         cfg.Metadata.Labels,
+			RequiredLabels: cfg.Metadata.RequiredLabels,
+			ExposedPorts:   cfg.Metadata.ExposedPorts,
+			Volumes:        cfg.Metadata.Volumes,
+		}
+	}
+
+	var layersTest *distgo.DockerImageLayersTest
+	if cfg.Layers != nil {
+		for _, pattern := range cfg.Layers.ForbiddenPaths {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return distgo.DockerImageTestsParam{}, errors.Wrapf(err, "invalid forbidden-paths pattern %q", pattern)
+			}
+		}
+		forbiddenContents, err := compileRegexps(cfg.Layers.ForbiddenContents)
+		if err != nil {
+			return distgo.DockerImageTestsParam{}, errors.Wrapf(err, "invalid forbidden-contents")
+		}
+		layersTest = &distgo.DockerImageLayersTest{
+			ForbiddenPaths:    cfg.Layers.ForbiddenPaths,
+			ForbiddenContents: forbiddenContents,
+		}
+	}
+
+	return distgo.DockerImageTestsParam{
+		Platforms:          cfg.Platforms,
+		FileExistenceTests: fileExistenceTests,
+		FileContentTests:   fileContentTests,
+		MetadataTest:       metadataTest,
+		LayersTest:         layersTest,
+	}, nil
+}
+
+func validateImageTestPath(testPath string) error {
+	if !path.IsAbs(testPath) {
+		return errors.Errorf("path must be an absolute path in the image, was %q", testPath)
+	}
+	return nil
+}
+
+func compileRegexps(exprs []string) ([]*regexp.Regexp, error) {
+	var out []*regexp.Regexp
+	for _, expr := range exprs {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile regular expression %q", expr)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
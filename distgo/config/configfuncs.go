@@ -19,6 +19,15 @@ import (
 	"reflect"
 )
 
+// configValue returns the value that valPtr points to, or the zero value of its type if valPtr is nil.
+func configValue[T any](valPtr *T) T {
+	if valPtr == nil {
+		var zero T
+		return zero
+	}
+	return *valPtr
+}
+
 // getConfigStringValue returns a value based on the inputs. Is similar to "getConfigValue", but if the result of
 // "getConfigValue" is an empty string, the provided "defaultVal" is returned (rather than returning the the empty
 // string). This has the effect of ensuring that the provided "defaultVal" is used as the string value if the value
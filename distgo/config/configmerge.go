@@ -0,0 +1,275 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/palantir/distgo/distgo"
+	v0 "github.com/palantir/distgo/distgo/config/internal/v0"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ProductDefaultsMergeShallow = "shallow"
+	ProductDefaultsMergeDeep    = "deep"
+)
+
+// productDefaultsSections are the top-level keys of the product configuration that are only inherited from the product
+// defaults if the product specifies them.
+var productDefaultsSections = []string{"build", "run", "dist", "publish", "docker"}
+
+// productDefaultsMergeStrategy returns the product defaults merge strategy of the configuration and verifies that the
+// "unset" configuration is only used where it is supported.
+func (cfg *ProjectConfig) productDefaultsMergeStrategy() (string, error) {
+	strategy := ProductDefaultsMergeShallow
+	if cfg.ProductDefaultsMerge != nil {
+		strategy = getConfigStringValue(cfg.ProductDefaultsMerge.Strategy, nil, ProductDefaultsMergeShallow)
+	}
+	if strategy != ProductDefaultsMergeShallow && strategy != ProductDefaultsMergeDeep {
+		return "", errors.Errorf("invalid product-defaults-merge strategy %q: must be %q or %q", strategy, ProductDefaultsMergeShallow, ProductDefaultsMergeDeep)
+	}
+	if cfg.ProductDefaults.Unset != nil {
+		return "", errors.Errorf("unset cannot be specified in product-defaults")
+	}
+	if strategy == ProductDefaultsMergeShallow && cfg.ProductDefaultsMerge != nil && cfg.ProductDefaultsMerge.AppendLists != nil {
+		return "", errors.Errorf("product-defaults-merge append-lists can only be specified with the %q strategy", ProductDefaultsMergeDeep)
+	}
+	return strategy, nil
+}
+
+// mergeProductDefaults returns the configuration of the product and the product defaults that should be used to
+// create its parameter. With the "shallow" strategy, these are the provided product configuration and the product
+// defaults of the project. With the "deep" strategy, the returned product configuration is the result of deep merging
+// the product configuration with the product defaults and the returned product defaults are empty: in that case, the
+// returned map records the source of every merged value keyed by its path.
+func (cfg *ProjectConfig) mergeProductDefaults(productID distgo.ProductID, productCfg v0.ProductConfig) (ProductConfig, ProductConfig, map[string]ValueSource, error) {
+	strategy, err := cfg.productDefaultsMergeStrategy()
+	if err != nil {
+		return ProductConfig{}, ProductConfig{}, nil, err
+	}
+	if strategy == ProductDefaultsMergeShallow {
+		if productCfg.Unset != nil {
+			return ProductConfig{}, ProductConfig{}, nil, errors.Errorf("unset specified for product %s requires the %q product-defaults-merge strategy", productID, ProductDefaultsMergeDeep)
+		}
+		return ProductConfig(productCfg), ProductConfig(cfg.ProductDefaults), nil, nil
+	}
+
+	var appendLists []string
+	if cfg.ProductDefaultsMerge.AppendLists != nil {
+		appendLists = *cfg.ProductDefaultsMerge.AppendLists
+	}
+	merged, sources, err := deepMergeProductConfig(productCfg, cfg.ProductDefaults, appendLists)
+	if err != nil {
+		return ProductConfig{}, ProductConfig{}, nil, errors.Wrapf(err, "failed to merge product-defaults for product %s", productID)
+	}
+	return ProductConfig(merged), ProductConfig{}, sources, nil
+}
+
+// deepMergeProductConfig merges the product configuration with the product defaults. Maps are merged key by key, a
+// list at a path that matches one of appendLists is appended to the inherited list and any other value of the product
+// replaces the inherited value. The paths specified by the "unset" configuration of the product are then removed.
+func deepMergeProductConfig(productCfg, defaultCfg v0.ProductConfig, appendLists []string) (v0.ProductConfig, map[string]ValueSource, error) {
+	var unset []string
+	if productCfg.Unset != nil {
+		unset = *productCfg.Unset
+		productCfg.Unset = nil
+	}
+	// dependencies are not inherited from the product defaults
+	defaultCfg.Dependencies = nil
+
+	productYAML, err := toMapSlice(productCfg)
+	if err != nil {
+		return v0.ProductConfig{}, nil, err
+	}
+	defaultYAML, err := toMapSlice(defaultCfg)
+	if err != nil {
+		return v0.ProductConfig{}, nil, err
+	}
+	// sections are only inherited if the product specifies them
+	defaultYAML = slices.DeleteFunc(defaultYAML, func(item yaml.MapItem) bool {
+		return slices.Contains(productDefaultsSections, fmt.Sprint(item.Key)) && !hasMapKey(productYAML, item.Key)
+	})
+
+	m := &deepMerger{
+		appendLists: appendLists,
+		sources:     make(map[string]ValueSource),
+	}
+	merged := m.mergeMaps("", productYAML, defaultYAML)
+	for _, path := range unset {
+		var ok bool
+		if merged, ok = unsetPath(merged, path); !ok {
+			return v0.ProductConfig{}, nil, errors.Errorf("unset path %q does not match any configuration value", path)
+		}
+		for k := range m.sources {
+			if k == path || strings.HasPrefix(k, path+".") {
+				delete(m.sources, k)
+			}
+		}
+	}
+
+	mergedBytes, err := yaml.Marshal(merged)
+	if err != nil {
+		return v0.ProductConfig{}, nil, errors.Wrapf(err, "failed to marshal merged configuration")
+	}
+	var out v0.ProductConfig
+	if err := yaml.UnmarshalStrict(mergedBytes, &out); err != nil {
+		return v0.ProductConfig{}, nil, errors.Wrapf(err, "failed to unmarshal merged configuration")
+	}
+	return out, m.sources, nil
+}
+
+type deepMerger struct {
+	appendLists []string
+	sources     map[string]ValueSource
+	// secondaryKeyOrder specifies that merged maps keep the key order of the secondary map rather than the key order
+	// of the primary map.
+	secondaryKeyOrder bool
+}
+
+// mergeMaps merges the primary map into the secondary map. Keys keep the order of the primary map and keys that only
+// exist in the secondary map are added after them, or the reverse if secondaryKeyOrder is true.
+func (m *deepMerger) mergeMaps(path string, primary, secondary yaml.MapSlice) yaml.MapSlice {
+	first, firstSource, second, secondSource := primary, ValueSourceExplicit, secondary, ValueSourceProductDefaults
+	if m.secondaryKeyOrder {
+		first, firstSource, second, secondSource = secondary, ValueSourceProductDefaults, primary, ValueSourceExplicit
+	}
+	out := make(yaml.MapSlice, 0, len(primary)+len(secondary))
+	for _, item := range first {
+		itemPath := joinConfigPath(path, fmt.Sprint(item.Key))
+		secondVal, ok := mapValue(second, item.Key)
+		if !ok {
+			out = append(out, item)
+			m.record(itemPath, item.Value, firstSource)
+			continue
+		}
+		if m.secondaryKeyOrder {
+			out = append(out, yaml.MapItem{Key: item.Key, Value: m.merge(itemPath, secondVal, item.Value)})
+		} else {
+			out = append(out, yaml.MapItem{Key: item.Key, Value: m.merge(itemPath, item.Value, secondVal)})
+		}
+	}
+	for _, item := range second {
+		if hasMapKey(first, item.Key) {
+			continue
+		}
+		out = append(out, item)
+		m.record(joinConfigPath(path, fmt.Sprint(item.Key)), item.Value, secondSource)
+	}
+	return out
+}
+
+func (m *deepMerger) merge(path string, primary, secondary any) any {
+	switch primaryVal := primary.(type) {
+	case yaml.MapSlice:
+		if secondaryVal, ok := secondary.(yaml.MapSlice); ok {
+			m.sources[path] = ValueSourceMerged
+			return m.mergeMaps(path, primaryVal, secondaryVal)
+		}
+	case []any:
+		if secondaryVal, ok := secondary.([]any); ok && m.isAppendList(path) {
+			m.sources[path] = ValueSourceMerged
+			return append(slices.Clone(secondaryVal), primaryVal...)
+		}
+	}
+	m.record(path, primary, ValueSourceExplicit)
+	return primary
+}
+
+// record records the source of the value at the provided path and of all of the values nested in it.
+func (m *deepMerger) record(path string, val any, source ValueSource) {
+	m.sources[path] = source
+	if mapVal, ok := val.(yaml.MapSlice); ok {
+		for _, item := range mapVal {
+			m.record(joinConfigPath(path, fmt.Sprint(item.Key)), item.Value, source)
+		}
+	}
+}
+
+func (m *deepMerger) isAppendList(path string) bool {
+	pathParts := strings.Split(path, ".")
+	for _, pattern := range m.appendLists {
+		patternParts := strings.Split(pattern, ".")
+		if len(patternParts) != len(pathParts) {
+			continue
+		}
+		matches := true
+		for i := range patternParts {
+			if patternParts[i] != "*" && patternParts[i] != pathParts[i] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// unsetPath removes the value at the provided dot-separated path from the map and returns the result and whether a
+// value was removed. Because keys such as dist IDs may contain dots, the longest key that matches a prefix of the path
+// is used at every level.
+func unsetPath(in yaml.MapSlice, path string) (yaml.MapSlice, bool) {
+	matchIdx, rest := -1, ""
+	for i, item := range in {
+		key := fmt.Sprint(item.Key)
+		if key == path {
+			return slices.Delete(slices.Clone(in), i, i+1), true
+		}
+		if strings.HasPrefix(path, key+".") && (matchIdx == -1 || len(key) > len(fmt.Sprint(in[matchIdx].Key))) {
+			matchIdx, rest = i, strings.TrimPrefix(path, key+".")
+		}
+	}
+	if matchIdx == -1 {
+		return in, false
+	}
+	nested, ok := in[matchIdx].Value.(yaml.MapSlice)
+	if !ok {
+		return in, false
+	}
+	updated, ok := unsetPath(nested, rest)
+	if !ok {
+		return in, false
+	}
+	out := slices.Clone(in)
+	out[matchIdx].Value = updated
+	return out, true
+}
+
+func toMapSlice(in any) (yaml.MapSlice, error) {
+	cfgBytes, err := yaml.Marshal(in)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal configuration")
+	}
+	return unmarshalMapSlice(cfgBytes)
+}
+
+func hasMapKey(in yaml.MapSlice, key any) bool {
+	_, ok := mapValue(in, key)
+	return ok
+}
+
+func mapValue(in yaml.MapSlice, key any) (any, bool) {
+	for _, item := range in {
+		if fmt.Sprint(item.Key) == fmt.Sprint(key) {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
@@ -15,8 +15,12 @@
 package config
 
 import (
+	"maps"
+	"slices"
+
 	"github.com/palantir/distgo/distgo"
 	v0 "github.com/palantir/distgo/distgo/config/internal/v0"
+	"github.com/pkg/errors"
 )
 
 type ProductConfig v0.ProductConfig
@@ -26,13 +30,69 @@ func ToProductConfig(in *ProductConfig) *v0.ProductConfig {
 }
 
 func (cfg *ProductConfig) ToParam(productID distgo.ProductID, scriptIncludes string, defaultCfg ProductConfig, disterFactory distgo.DisterFactory, dockerBuilderFactory distgo.DockerBuilderFactory) (distgo.ProductParam, error) {
-	var buildParam *distgo.BuildParam
+	resolvedCfg := cfg.resolve(nil, productID, scriptIncludes, defaultCfg)
+	return resolvedCfg.toParam(productID, disterFactory, dockerBuilderFactory)
+}
+
+// resolve returns the ProductConfig in which every value is the value that ToParam uses. A section such as "build" is
+// only present if it is specified in the receiver config, and within a section each value that the receiver does not
+// specify is taken from the default config or, if it is not specified there either, from the program-specified default
+// value. Dependencies are not inherited from the default config. The source of each value is recorded in sources.
+func (cfg *ProductConfig) resolve(sources *configSources, productID distgo.ProductID, scriptIncludes string, defaultCfg ProductConfig) ProductConfig {
+	resolvedCfg := ProductConfig{
+		Name:         resolveStringValue(sources, "name", cfg.Name, defaultCfg.Name, string(productID)),
+		Labels:       resolveValue(sources, "labels", cfg.Labels, defaultCfg.Labels, nil),
+		Groups:       resolveValue(sources, "groups", cfg.Groups, defaultCfg.Groups, nil),
+		Dependencies: resolveValue(sources, "dependencies", cfg.Dependencies, nil, nil),
+	}
 	if cfg.Build != nil {
-		defaultBuildCfg := BuildConfig{}
+		var defaultBuildCfg BuildConfig
 		if defaultCfg.Build != nil {
 			defaultBuildCfg = BuildConfig(*defaultCfg.Build)
 		}
-		buildParamVar, err := (*BuildConfig)(cfg.Build).ToParam(scriptIncludes, defaultBuildCfg)
+		buildCfg := (*BuildConfig)(cfg.Build).resolve(sources, "build", scriptIncludes, defaultBuildCfg)
+		resolvedCfg.Build = ToBuildConfig(&buildCfg)
+	}
+	if cfg.Run != nil {
+		var defaultRunCfg RunConfig
+		if defaultCfg.Run != nil {
+			defaultRunCfg = RunConfig(*defaultCfg.Run)
+		}
+		runCfg := (*RunConfig)(cfg.Run).resolve(sources, "run", defaultRunCfg)
+		resolvedCfg.Run = ToRunConfig(&runCfg)
+	}
+	if cfg.Dist != nil {
+		var defaultDistCfg DistConfig
+		if defaultCfg.Dist != nil {
+			defaultDistCfg = DistConfig(*defaultCfg.Dist)
+		}
+		distCfg := (*DistConfig)(cfg.Dist).resolve(sources, "dist", scriptIncludes, defaultDistCfg)
+		resolvedCfg.Dist = ToDistConfig(&distCfg)
+	}
+	if cfg.Publish != nil {
+		var defaultPublishCfg PublishConfig
+		if defaultCfg.Publish != nil {
+			defaultPublishCfg = PublishConfig(*defaultCfg.Publish)
+		}
+		publishCfg := (*PublishConfig)(cfg.Publish).resolve(sources, "publish", defaultPublishCfg)
+		resolvedCfg.Publish = ToPublishConfig(&publishCfg)
+	}
+	if cfg.Docker != nil {
+		var defaultDockerCfg DockerConfig
+		if defaultCfg.Docker != nil {
+			defaultDockerCfg = DockerConfig(*defaultCfg.Docker)
+		}
+		dockerCfg := (*DockerConfig)(cfg.Docker).resolve(sources, "docker", scriptIncludes, defaultDockerCfg)
+		resolvedCfg.Docker = ToDockerConfig(&dockerCfg)
+	}
+	return resolvedCfg
+}
+
+// toParam returns the ProductParam represented by the receiver *ProductConfig, which must be a resolved configuration.
+func (cfg *ProductConfig) toParam(productID distgo.ProductID, disterFactory distgo.DisterFactory, dockerBuilderFactory distgo.DockerBuilderFactory) (distgo.ProductParam, error) {
+	var buildParam *distgo.BuildParam
+	if cfg.Build != nil {
+		buildParamVar, err := (*BuildConfig)(cfg.Build).toParam()
 		if err != nil {
 			return distgo.ProductParam{}, err
 		}
@@ -41,34 +101,36 @@ func (cfg *ProductConfig) ToParam(productID distgo.ProductID, scriptIncludes str
 
 	var runParam *distgo.RunParam
 	if cfg.Run != nil {
-		defaultRunCfg := RunConfig{}
-		if defaultCfg.Run != nil {
-			defaultRunCfg = RunConfig(*defaultCfg.Run)
-		}
-		runParamVar := (*RunConfig)(cfg.Run).ToParam(defaultRunCfg)
+		runParamVar := (*RunConfig)(cfg.Run).toParam()
 		runParam = &runParamVar
 	}
 
 	var distParam *distgo.DistParam
 	if cfg.Dist != nil {
-		var defaultDistConfig DistConfig
-		if defaultCfg.Dist != nil {
-			defaultDistConfig = DistConfig(*defaultCfg.Dist)
-		}
-		distParamsVar, err := (*DistConfig)(cfg.Dist).ToParam(scriptIncludes, defaultDistConfig, disterFactory)
+		distParamsVar, err := (*DistConfig)(cfg.Dist).toParam(disterFactory)
 		if err != nil {
 			return distgo.ProductParam{}, err
 		}
 		distParam = &distParamsVar
 	}
+	if distParam != nil {
+		for _, distID := range slices.Sorted(maps.Keys(distParam.DistParams)) {
+			variant := distParam.DistParams[distID].BuildVariant
+			if variant == "" {
+				continue
+			}
+			if buildParam == nil {
+				return distgo.ProductParam{}, errors.Errorf("dist %s of product %s specifies build variant %s, but the product does not have a build configuration", distID, productID, variant)
+			}
+			if _, ok := buildParam.Variants[variant]; !ok {
+				return distgo.ProductParam{}, errors.Errorf("dist %s of product %s specifies build variant %s, which is not a build variant of the product -- valid values are %v", distID, productID, variant, slices.Sorted(maps.Keys(buildParam.Variants)))
+			}
+		}
+	}
 
 	var publishParam *distgo.PublishParam
 	if cfg.Publish != nil {
-		defaultPublishCfg := PublishConfig{}
-		if defaultCfg.Publish != nil {
-			defaultPublishCfg = PublishConfig(*defaultCfg.Publish)
-		}
-		publishParamVar, err := (*PublishConfig)(cfg.Publish).ToParam(defaultPublishCfg)
+		publishParamVar, err := (*PublishConfig)(cfg.Publish).toParam()
 		if err != nil {
 			return distgo.ProductParam{}, err
 		}
@@ -77,11 +139,7 @@ func (cfg *ProductConfig) ToParam(productID distgo.ProductID, scriptIncludes str
 
 	var dockerParam *distgo.DockerParam
 	if cfg.Docker != nil {
-		defaultDockerCfg := DockerConfig{}
-		if defaultCfg.Docker != nil {
-			defaultDockerCfg = DockerConfig(*defaultCfg.Docker)
-		}
-		dockerParamVar, err := (*DockerConfig)(cfg.Docker).ToParam(scriptIncludes, defaultDockerCfg, dockerBuilderFactory)
+		dockerParamVar, err := (*DockerConfig)(cfg.Docker).toParam(dockerBuilderFactory)
 		if err != nil {
 			return distgo.ProductParam{}, err
 		}
@@ -100,9 +158,24 @@ func (cfg *ProductConfig) ToParam(productID distgo.ProductID, scriptIncludes str
 			firstLevelDeps = append(firstLevelDeps, currDep)
 		}
 	}
+	labels := configValue(cfg.Labels)
+	for key := range labels {
+		if !distgo.IsValidSelectorName(key) {
+			return distgo.ProductParam{}, errors.Errorf("invalid label key %q for product %s: %s", key, productID, distgo.SelectorNameRequirements)
+		}
+	}
+	groups := configValue(cfg.Groups)
+	for _, group := range groups {
+		if !distgo.IsValidSelectorName(group) {
+			return distgo.ProductParam{}, errors.Errorf("invalid group %q for product %s: %s", group, productID, distgo.SelectorNameRequirements)
+		}
+	}
+
 	return distgo.ProductParam{
 		ID:                     productID,
-		Name:                   getConfigStringValue(cfg.Name, defaultCfg.Name, string(productID)),
+		Name:                   configValue(cfg.Name),
+		Labels:                 labels,
+		Groups:                 groups,
 		Build:                  buildParam,
 		Run:                    runParam,
 		Dist:                   distParam,
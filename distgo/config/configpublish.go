@@ -28,12 +28,53 @@ func ToPublishConfig(in *PublishConfig) *v0.PublishConfig {
 }
 
 func (cfg *PublishConfig) ToParam(defaultCfg PublishConfig) (distgo.PublishParam, error) {
-	publishInfo, err := mergePublishInfos(fromPublishIDPublisherConfig(cfg.PublishInfo), fromPublishIDPublisherConfig(defaultCfg.PublishInfo))
-	if err != nil {
-		return distgo.PublishParam{}, err
+	resolvedCfg := cfg.resolve(nil, "", defaultCfg)
+	return resolvedCfg.toParam()
+}
+
+// resolve returns the PublishConfig in which every value is the value that ToParam uses. The publish info contains the
+// publishers of the receiver and of the default config, and the configuration of the receiver is used for a publisher
+// that is specified in both. The source of each value is recorded in sources using paths relative to the provided path.
+func (cfg *PublishConfig) resolve(sources *configSources, path string, defaultCfg PublishConfig) PublishConfig {
+	resolvedCfg := PublishConfig{
+		GroupID: resolveValue(sources, joinConfigPath(path, "group-id"), cfg.GroupID, defaultCfg.GroupID, nil),
+	}
+	if cfg.PublishInfo == nil && defaultCfg.PublishInfo == nil {
+		return resolvedCfg
+	}
+	infoPath := joinConfigPath(path, "info")
+	publishInfo := make(map[distgo.PublisherTypeID]v0.PublisherConfig)
+	if defaultCfg.PublishInfo != nil {
+		for publishID, publisherCfg := range *defaultCfg.PublishInfo {
+			publishInfo[publishID] = publisherCfg
+			sources.recordDefaults(joinConfigPath(infoPath, string(publishID)))
+		}
+	}
+	if cfg.PublishInfo != nil {
+		for publishID, publisherCfg := range *cfg.PublishInfo {
+			publishInfo[publishID] = publisherCfg
+			sources.recordPrimary(joinConfigPath(infoPath, string(publishID)))
+		}
+	}
+	resolvedCfg.PublishInfo = &publishInfo
+	return resolvedCfg
+}
+
+// toParam returns the PublishParam represented by the receiver *PublishConfig, which must be a resolved configuration.
+func (cfg *PublishConfig) toParam() (distgo.PublishParam, error) {
+	var publishInfo map[distgo.PublisherTypeID]distgo.PublisherParam
+	if cfg.PublishInfo != nil && len(*cfg.PublishInfo) > 0 {
+		publishInfo = make(map[distgo.PublisherTypeID]distgo.PublisherParam)
+		for publishID, publisherCfg := range *cfg.PublishInfo {
+			publisherParam, err := (*PublisherConfig)(&publisherCfg).ToParam()
+			if err != nil {
+				return distgo.PublishParam{}, errors.Wrapf(err, "failed to create publisher param for %s", publishID)
+			}
+			publishInfo[publishID] = publisherParam
+		}
 	}
 	return distgo.PublishParam{
-		GroupID:     getConfigStringValue(cfg.GroupID, defaultCfg.GroupID, ""),
+		GroupID:     configValue(cfg.GroupID),
 		PublishInfo: publishInfo,
 	}, nil
 }
@@ -59,56 +100,6 @@ func (cfg *PublisherConfig) ToParam() (distgo.PublisherParam, error) {
 	}, nil
 }
 
-func mergePublishInfos(cfgPublishInfo, defaultCfgPublishInfo *map[distgo.PublisherTypeID]PublisherConfig) (map[distgo.PublisherTypeID]distgo.PublisherParam, error) {
-	distinctVals := make(map[distgo.PublisherTypeID]PublisherConfig)
-	commonKeys := make(map[distgo.PublisherTypeID]struct{})
-
-	if cfgPublishInfo != nil {
-		for publishID, val := range *cfgPublishInfo {
-			if defaultCfgPublishInfo != nil {
-				if _, ok := (*defaultCfgPublishInfo)[publishID]; ok {
-					commonKeys[publishID] = struct{}{}
-					continue
-				}
-			}
-			distinctVals[publishID] = val
-		}
-	}
-
-	if defaultCfgPublishInfo != nil {
-		for publishID, val := range *defaultCfgPublishInfo {
-			if cfgPublishInfo != nil {
-				if _, ok := (*cfgPublishInfo)[publishID]; ok {
-					commonKeys[publishID] = struct{}{}
-					continue
-				}
-			}
-			distinctVals[publishID] = val
-		}
-	}
-
-	var publishInfo map[distgo.PublisherTypeID]distgo.PublisherParam
-	if len(distinctVals) > 0 || len(commonKeys) > 0 {
-		publishInfo = make(map[distgo.PublisherTypeID]distgo.PublisherParam)
-		for publishID, publisherConfig := range distinctVals {
-			publisherParam, err := publisherConfig.ToParam()
-			if err != nil {
-				return nil, errors.Wrapf(err, "failed to create publisher param for %s", publishID)
-			}
-			publishInfo[publishID] = publisherParam
-		}
-		for publishID := range commonKeys {
-			publisherCfg := (*cfgPublishInfo)[publishID]
-			publisherParam, err := publisherCfg.ToParam()
-			if err != nil {
-				return nil, errors.Wrapf(err, "failed to create publisher param for %s", publishID)
-			}
-			publishInfo[publishID] = publisherParam
-		}
-	}
-	return publishInfo, nil
-}
-
 func ToPublishInfo(in *map[distgo.PublisherTypeID]PublisherConfig) *map[distgo.PublisherTypeID]v0.PublisherConfig {
 	if in == nil {
 		return nil
@@ -119,14 +110,3 @@ func ToPublishInfo(in *map[distgo.PublisherTypeID]PublisherConfig) *map[distgo.P
 	}
 	return &out
 }
-
-func fromPublishIDPublisherConfig(in *map[distgo.PublisherTypeID]v0.PublisherConfig) *map[distgo.PublisherTypeID]PublisherConfig {
-	if in == nil {
-		return nil
-	}
-	out := make(map[distgo.PublisherTypeID]PublisherConfig, len(*in))
-	for k, v := range *in {
-		out[k] = PublisherConfig(v)
-	}
-	return &out
-}
@@ -30,7 +30,23 @@ func ToRunConfig(in *RunConfig) *v0.RunConfig {
 // config but is specified in the default config, the default config value is used. If a value is not specified in
 // either configuration, the program-specified default value (if any) is used.
 func (cfg *RunConfig) ToParam(defaultCfg RunConfig) distgo.RunParam {
+	resolvedCfg := cfg.resolve(nil, "", defaultCfg)
+	return resolvedCfg.toParam()
+}
+
+// resolve returns the RunConfig in which every value is the value that ToParam uses. The source of each value is
+// recorded in sources using paths relative to the provided path.
+func (cfg *RunConfig) resolve(sources *configSources, path string, defaultCfg RunConfig) RunConfig {
+	return RunConfig{
+		Args:        resolveValue(sources, joinConfigPath(path, "args"), cfg.Args, defaultCfg.Args, nil),
+		WasmRuntime: resolveValue(sources, joinConfigPath(path, "wasm-runtime"), cfg.WasmRuntime, defaultCfg.WasmRuntime, nil),
+	}
+}
+
+// toParam returns the RunParam represented by the receiver *RunConfig, which must be a resolved configuration.
+func (cfg *RunConfig) toParam() distgo.RunParam {
 	return distgo.RunParam{
-		Args: getConfigValue(cfg.Args, defaultCfg.Args, nil).([]string),
+		Args:        configValue(cfg.Args),
+		WasmRuntime: configValue(cfg.WasmRuntime),
 	}
 }
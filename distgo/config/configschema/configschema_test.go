@@ -0,0 +1,201 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/palantir/distgo/distgo/config/configschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+type testConfig struct {
+	Name         *string              `yaml:"name,omitempty"`
+	Enabled      *bool                `yaml:"enabled,omitempty"`
+	Count        *int                 `yaml:"count,omitempty"`
+	Tags         []string             `yaml:"tags,omitempty"`
+	Children     map[string]testChild `yaml:"children,omitempty"`
+	Raw          *yaml.MapSlice       `yaml:"raw,omitempty"`
+	Shorthand    *testShorthand       `yaml:"shorthand,omitempty"`
+	testEmbedded `yaml:",inline"`
+}
+
+type testEmbedded struct {
+	URL string `yaml:"url,omitempty"`
+}
+
+type testChild struct {
+	Path string `yaml:"path,omitempty"`
+}
+
+type testShorthand struct {
+	Value string `yaml:"value,omitempty"`
+}
+
+func (s *testShorthand) ConfigSchema(g *configschema.Generator) *configschema.Schema {
+	return &configschema.Schema{
+		AnyOf: []*configschema.Schema{
+			{Type: "string"},
+			{Type: "object", Properties: map[string]*configschema.Schema{"value": {Type: "string"}}, NoAdditionalProperties: true},
+		},
+	}
+}
+
+func TestSchemaJSONRoundTrip(t *testing.T) {
+	schema := configschema.For(testConfig{})
+	schemaJSON, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	parsed, err := configschema.Parse(schemaJSON)
+	require.NoError(t, err)
+	assert.Equal(t, schema, parsed)
+
+	def := parsed.Defs["testConfig"]
+	require.NotNil(t, def)
+	assert.True(t, def.NoAdditionalProperties)
+	assert.Contains(t, def.Properties, "url", "inline fields should be merged")
+	assert.Equal(t, &configschema.Schema{Type: "object", AdditionalProperties: &configschema.Schema{Ref: "#/$defs/testChild"}}, def.Properties["children"])
+
+	nilSchema, err := configschema.Parse([]byte("null"))
+	require.NoError(t, err)
+	assert.Nil(t, nilSchema)
+}
+
+func TestEmbed(t *testing.T) {
+	root := configschema.For(testConfig{})
+	ref := configschema.Embed(root, configschema.For(testConfig{}), "asset.foo")
+	assert.Equal(t, "#/$defs/asset.foo", ref)
+	assert.Equal(t, "#/$defs/asset.foo.testConfig", root.Defs["asset.foo"].Ref)
+	assert.Equal(t, "#/$defs/asset.foo.testChild", root.Defs["asset.foo.testConfig"].Properties["children"].AdditionalProperties.Ref)
+}
+
+func TestValidateYAML(t *testing.T) {
+	schema := configschema.For(testConfig{})
+	for _, tc := range []struct {
+		name string
+		yml  string
+		want []string
+	}{
+		{
+			name: "valid configuration",
+			yml: `name: foo
+enabled: yes
+count: 3
+tags: [a, 1]
+children:
+  a:
+    path: ./a
+raw:
+  anything: [goes]
+shorthand: value
+url: http://example.com
+`,
+		},
+		{
+			name: "empty document",
+			yml:  "",
+		},
+		{
+			name: "null values are valid",
+			yml:  "name:\nchildren:\n",
+		},
+		{
+			name: "unknown field with suggestion",
+			yml:  "nmae: foo\n",
+			want: []string{`1:1: nmae: unknown field "nmae" (did you mean "name"?)`},
+		},
+		{
+			name: "unknown field without suggestion",
+			yml:  "children:\n  a:\n    unrelated: foo\n",
+			want: []string{`3:5: children.a.unrelated: unknown field "unrelated"`},
+		},
+		{
+			name: "duplicate key",
+			yml:  "name: foo\nname: bar\n",
+			want: []string{`2:1: name: duplicate key "name"`},
+		},
+		{
+			name: "type mismatches",
+			yml:  "enabled: maybe\ncount: 1.5\ntags: foo\nchildren: [a]\n",
+			want: []string{
+				`1:10: enabled: expected a boolean, got "maybe"`,
+				`2:8: count: expected an integer, got "1.5"`,
+				`3:7: tags: expected a sequence, got "foo"`,
+				`4:11: children: expected a mapping, got a sequence`,
+			},
+		},
+		{
+			name: "errors in sequence elements",
+			yml:  "tags:\n  - a\n  - {b: c}\n",
+			want: []string{`3:5: tags[1]: expected a string, got a mapping`},
+		},
+		{
+			name: "closest anyOf branch is reported",
+			yml:  "shorthand:\n  valeu: foo\n",
+			want: []string{`2:3: shorthand.valeu: unknown field "valeu" (did you mean "value"?)`},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			errs, err := configschema.ValidateYAML(schema, []byte(tc.yml))
+			require.NoError(t, err)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestValidateYAMLEnumAndRequired(t *testing.T) {
+	schema := &configschema.Schema{
+		Type: "object",
+		Properties: map[string]*configschema.Schema{
+			"type":   {Type: "string", Enum: []any{"a", "b"}},
+			"config": {},
+		},
+		Required:               []string{"type"},
+		NoAdditionalProperties: true,
+		AllOf: []*configschema.Schema{
+			{
+				If: &configschema.Schema{Type: "object", Properties: map[string]*configschema.Schema{"type": {Const: "a"}}, Required: []string{"type"}},
+				Then: &configschema.Schema{Type: "object", Properties: map[string]*configschema.Schema{
+					"config": {Type: "object", Properties: map[string]*configschema.Schema{"key": {Type: "string"}}, NoAdditionalProperties: true},
+				}},
+			},
+		},
+	}
+	for _, tc := range []struct {
+		yml  string
+		want []string
+	}{
+		{yml: "type: a\nconfig:\n  key: foo\n"},
+		{yml: "type: b\nconfig:\n  other: foo\n"},
+		{yml: "type: a\nconfig:\n  other: foo\n", want: []string{`3:3: config.other: unknown field "other"`}},
+		{yml: "type: c\n", want: []string{`1:7: type: invalid value "c": must be one of "a", "b"`}},
+		{yml: "config: {}\n", want: []string{`1:1: missing required field "type"`}},
+	} {
+		errs, err := configschema.ValidateYAML(schema, []byte(tc.yml))
+		require.NoError(t, err)
+		var got []string
+		for _, e := range errs {
+			got = append(got, e.Error())
+		}
+		assert.Equal(t, tc.want, got, "YAML:\n%s", tc.yml)
+	}
+}
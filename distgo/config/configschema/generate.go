@@ -0,0 +1,178 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Provider is implemented by configuration types whose YAML form is not derived from their fields, such as types that
+// implement yaml.Unmarshaler to accept a shorthand form. The returned schema describes every form the type accepts.
+type Provider interface {
+	ConfigSchema(g *Generator) *Schema
+}
+
+// Generator generates schemas for Go types based on the way that gopkg.in/yaml.v2 strictly unmarshals them. Named
+// struct types are added to the definitions of the generated schema and referenced using "$ref".
+type Generator struct {
+	defs     map[string]*Schema
+	defNames map[reflect.Type]string
+}
+
+// For returns the schema for the type of the provided value. The returned schema is a complete JSON Schema document
+// that contains the definitions for all of the named types that it references.
+func For(v any) *Schema {
+	g := &Generator{
+		defs:     make(map[string]*Schema),
+		defNames: make(map[reflect.Type]string),
+	}
+	root := g.Type(reflect.TypeOf(v))
+	root.SchemaURI = Draft
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+// JSON returns the JSON form of the schema for the type of the provided value.
+func JSON(v any) ([]byte, error) {
+	out, err := json.Marshal(For(v))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal JSON Schema")
+	}
+	return out, nil
+}
+
+var (
+	providerType = reflect.TypeFor[Provider]()
+	mapSliceType = reflect.TypeFor[yaml.MapSlice]()
+)
+
+// Type returns the schema for the provided type.
+func (g *Generator) Type(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == mapSliceType {
+		return &Schema{Type: "object"}
+	}
+	implementsProvider := reflect.PointerTo(t).Implements(providerType)
+	if t.Name() != "" && (t.Kind() == reflect.Struct || implementsProvider) {
+		if defName, ok := g.defNames[t]; ok {
+			return &Schema{Ref: DefRef(defName)}
+		}
+		defName := g.defName(t)
+		g.defNames[t] = defName
+		// register a placeholder before generating the definition so that recursive types terminate
+		g.defs[defName] = &Schema{}
+		*g.defs[defName] = *g.unnamed(t, implementsProvider)
+		return &Schema{Ref: DefRef(defName)}
+	}
+	return g.unnamed(t, implementsProvider)
+}
+
+func (g *Generator) unnamed(t reflect.Type, implementsProvider bool) *Schema {
+	if implementsProvider {
+		return reflect.New(t).Interface().(Provider).ConfigSchema(g)
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.Struct(t)
+	case reflect.Map:
+		return &Schema{
+			Type:                 "object",
+			AdditionalProperties: g.Type(t.Elem()),
+		}
+	case reflect.Slice, reflect.Array:
+		return &Schema{
+			Type:  "array",
+			Items: g.Type(t.Elem()),
+		}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		// interfaces accept any value
+		return &Schema{}
+	}
+}
+
+// Struct returns the object schema for the fields of the provided struct type. Unlike Type, the returned schema is
+// never a reference and does not use the Provider implementation of the type, which allows a Provider to describe the
+// full form of a type that also supports a shorthand form.
+func (g *Generator) Struct(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	schema := &Schema{
+		Type:                   "object",
+		Properties:             make(map[string]*Schema),
+		NoAdditionalProperties: true,
+	}
+	g.addFields(schema, t)
+	return schema
+}
+
+// addFields adds the properties for the fields of the provided struct type to schema.
+func (g *Generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if inline := strings.Contains(","+opts+",", ",inline,"); inline {
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Map {
+				schema.AdditionalProperties = g.Type(fieldType.Elem())
+				schema.NoAdditionalProperties = false
+				continue
+			}
+			g.addFields(schema, fieldType)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		schema.Properties[name] = g.Type(field.Type)
+	}
+}
+
+// defName returns the name of the definition for the provided type: its name, qualified by its package path if another
+// type already uses the name.
+func (g *Generator) defName(t reflect.Type) string {
+	name := t.Name()
+	if _, ok := g.defs[name]; !ok {
+		return name
+	}
+	return strings.ReplaceAll(t.PkgPath(), "/", ".") + "." + name
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package configschema generates JSON Schemas for YAML configuration types and validates YAML documents against them.
+// Only the subset of JSON Schema needed to describe distgo configuration is supported.
+package configschema
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema.
+type Schema struct {
+	SchemaURI   string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	Const       any                `json:"const,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	// AdditionalProperties is the schema of the properties of an object that are not in Properties. If nil, additional
+	// properties are allowed unless NoAdditionalProperties is true.
+	AdditionalProperties *Schema `json:"-"`
+	// NoAdditionalProperties specifies that an object cannot have properties that are not in Properties. Serialized
+	// as "additionalProperties: false".
+	NoAdditionalProperties bool      `json:"-"`
+	Items                  *Schema   `json:"items,omitempty"`
+	AnyOf                  []*Schema `json:"anyOf,omitempty"`
+	AllOf                  []*Schema `json:"allOf,omitempty"`
+	If                     *Schema   `json:"if,omitempty"`
+	Then                   *Schema   `json:"then,omitempty"`
+}
+
+type schemaAlias Schema
+
+type schemaJSON struct {
+	*schemaAlias
+	AdditionalProperties json.RawMessage `json:"additionalProperties,omitempty"`
+}
+
+func (s Schema) MarshalJSON() ([]byte, error) {
+	out := schemaJSON{schemaAlias: (*schemaAlias)(&s)}
+	switch {
+	case s.AdditionalProperties != nil:
+		additionalProperties, err := json.Marshal(s.AdditionalProperties)
+		if err != nil {
+			return nil, err
+		}
+		out.AdditionalProperties = additionalProperties
+	case s.NoAdditionalProperties:
+		out.AdditionalProperties = json.RawMessage("false")
+	}
+	return json.Marshal(out)
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	in := schemaJSON{schemaAlias: (*schemaAlias)(s)}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	switch strings.TrimSpace(string(in.AdditionalProperties)) {
+	case "", "true":
+	case "false":
+		s.NoAdditionalProperties = true
+	default:
+		var additionalProperties Schema
+		if err := json.Unmarshal(in.AdditionalProperties, &additionalProperties); err != nil {
+			return errors.Wrapf(err, "failed to unmarshal additionalProperties")
+		}
+		s.AdditionalProperties = &additionalProperties
+	}
+	return nil
+}
+
+// Parse returns the schema for the provided JSON. Returns nil if the JSON is empty or null.
+func Parse(schemaJSON []byte) (*Schema, error) {
+	if trimmed := strings.TrimSpace(string(schemaJSON)); trimmed == "" || trimmed == "null" {
+		return nil, nil
+	}
+	var schema Schema
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal JSON Schema")
+	}
+	return &schema, nil
+}
+
+const defsRefPrefix = "#/$defs/"
+
+// DefRef returns the "$ref" value that refers to the definition with the provided name.
+func DefRef(name string) string {
+	return defsRefPrefix + name
+}
+
+// Embed adds the provided schema, which is a schema in its own right with its own definitions, to the definitions of
+// root under the provided name. The definitions of the embedded schema are moved to root and prefixed with name so that
+// they cannot conflict with existing definitions. Returns the "$ref" value that refers to the embedded schema.
+func Embed(root, schema *Schema, name string) string {
+	if root.Defs == nil {
+		root.Defs = make(map[string]*Schema)
+	}
+	rename := func(ref string) string {
+		if defName, ok := strings.CutPrefix(ref, defsRefPrefix); ok {
+			return DefRef(name + "." + defName)
+		}
+		if ref == "#" {
+			return DefRef(name)
+		}
+		return ref
+	}
+	for defName, def := range schema.Defs {
+		root.Defs[name+"."+defName] = rewriteRefs(def, rename)
+	}
+	embedded := rewriteRefs(schema, rename)
+	embedded.SchemaURI = ""
+	embedded.Defs = nil
+	root.Defs[name] = embedded
+	return DefRef(name)
+}
+
+// rewriteRefs returns a copy of the provided schema in which every "$ref" value is replaced by the result of rename.
+func rewriteRefs(s *Schema, rename func(string) string) *Schema {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	if out.Ref != "" {
+		out.Ref = rename(out.Ref)
+	}
+	if s.Properties != nil {
+		out.Properties = make(map[string]*Schema, len(s.Properties))
+		for k, v := range s.Properties {
+			out.Properties[k] = rewriteRefs(v, rename)
+		}
+	}
+	if s.Defs != nil {
+		out.Defs = make(map[string]*Schema, len(s.Defs))
+		for k, v := range s.Defs {
+			out.Defs[k] = rewriteRefs(v, rename)
+		}
+	}
+	out.AdditionalProperties = rewriteRefs(s.AdditionalProperties, rename)
+	out.Items = rewriteRefs(s.Items, rename)
+	out.If = rewriteRefs(s.If, rename)
+	out.Then = rewriteRefs(s.Then, rename)
+	out.AnyOf = rewriteRefsSlice(s.AnyOf, rename)
+	out.AllOf = rewriteRefsSlice(s.AllOf, rename)
+	return &out
+}
+
+func rewriteRefsSlice(in []*Schema, rename func(string) string) []*Schema {
+	if in == nil {
+		return nil
+	}
+	out := make([]*Schema, len(in))
+	for i, v := range in {
+		out[i] = rewriteRefs(v, rename)
+	}
+	return out
+}
@@ -0,0 +1,361 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configschema
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.yaml.in/yaml/v3"
+)
+
+// Error is a validation error at a position in a YAML document.
+type Error struct {
+	// Line and Column are the 1-based position of the node that caused the error. Column is 0 if only the line is
+	// known.
+	Line   int
+	Column int
+	// Path is the dot-separated path of the node that caused the error. Sequence elements are written as "[i]".
+	Path    string
+	Message string
+}
+
+func (e Error) Error() string {
+	msg := e.Message
+	if e.Path != "" {
+		msg = e.Path + ": " + msg
+	}
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, msg)
+	case e.Line > 0:
+		return fmt.Sprintf("%d: %s", e.Line, msg)
+	default:
+		return msg
+	}
+}
+
+// ValidateYAML validates the provided YAML document against the provided schema. Returns an error if the document is
+// not valid YAML. The returned validation errors are sorted by position.
+func ValidateYAML(schema *Schema, data []byte) ([]Error, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse YAML")
+	}
+	return Validate(schema, &node), nil
+}
+
+// Validate validates the provided YAML node against the provided schema. Validation is strict: object keys that are not
+// defined by the schema and duplicate keys are errors. Scalars are checked against the types that gopkg.in/yaml.v2
+// accepts when unmarshalling into the corresponding Go type. Null values are always valid. The returned errors are
+// sorted by position.
+func Validate(schema *Schema, node *yaml.Node) []Error {
+	v := validator{root: schema}
+	errs := v.validate(schema, node, "")
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs
+}
+
+type validator struct {
+	root *Schema
+}
+
+func (v validator) validate(schema *Schema, node *yaml.Node, path string) []Error {
+	if schema == nil || node == nil {
+		return nil
+	}
+	switch node.Kind {
+	case 0:
+		// empty document
+		return nil
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return v.validate(schema, node.Content[0], path)
+	case yaml.AliasNode:
+		return v.validate(schema, node.Alias, path)
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil
+	}
+	if schema.Ref != "" {
+		resolved, err := v.resolve(schema.Ref)
+		if err != nil {
+			return []Error{newError(node, path, err.Error())}
+		}
+		return v.validate(resolved, node, path)
+	}
+
+	var errs []Error
+	if len(schema.AnyOf) > 0 {
+		errs = append(errs, v.validateAnyOf(schema.AnyOf, node, path)...)
+	}
+	for _, sub := range schema.AllOf {
+		errs = append(errs, v.validate(sub, node, path)...)
+	}
+	if schema.If != nil && len(v.validate(schema.If, node, path)) == 0 {
+		errs = append(errs, v.validate(schema.Then, node, path)...)
+	}
+
+	switch schema.Type {
+	case "object":
+		errs = append(errs, v.validateObject(schema, node, path)...)
+	case "array":
+		if node.Kind != yaml.SequenceNode {
+			return append(errs, newError(node, path, "expected a sequence, got "+describe(node)))
+		}
+		for i, elem := range node.Content {
+			errs = append(errs, v.validate(schema.Items, elem, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case "string":
+		if node.Kind != yaml.ScalarNode {
+			errs = append(errs, newError(node, path, "expected a string, got "+describe(node)))
+		}
+	case "boolean":
+		if node.Kind != yaml.ScalarNode || node.Style&(yaml.SingleQuotedStyle|yaml.DoubleQuotedStyle) != 0 || !isBool(node.Value) {
+			errs = append(errs, newError(node, path, "expected a boolean, got "+describe(node)))
+		}
+	case "integer":
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			errs = append(errs, newError(node, path, "expected an integer, got "+describe(node)))
+		}
+	case "number":
+		if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+			errs = append(errs, newError(node, path, "expected a number, got "+describe(node)))
+		}
+	}
+
+	if node.Kind == yaml.MappingNode {
+		for _, required := range schema.Required {
+			if !hasKey(node, required) {
+				errs = append(errs, newError(node, path, fmt.Sprintf("missing required field %q", required)))
+			}
+		}
+	}
+	if node.Kind == yaml.ScalarNode {
+		if schema.Const != nil && fmt.Sprint(schema.Const) != node.Value {
+			errs = append(errs, newError(node, path, fmt.Sprintf("expected %q, got %q", fmt.Sprint(schema.Const), node.Value)))
+		}
+		if len(schema.Enum) > 0 && !containsValue(schema.Enum, node.Value) {
+			errs = append(errs, newError(node, path, fmt.Sprintf("invalid value %q: must be one of %s", node.Value, quoteValues(schema.Enum))))
+		}
+	}
+	return errs
+}
+
+// validateAnyOf validates the node against each of the provided schemas. If the node is not valid for any of them, the
+// errors for the schema that is most likely the intended form are returned: the schema that produced the fewest errors,
+// preferring schemas whose type matches the kind of the node.
+func (v validator) validateAnyOf(schemas []*Schema, node *yaml.Node, path string) []Error {
+	var closest []Error
+	closestMatchesKind := false
+	for i, sub := range schemas {
+		errs := v.validate(sub, node, path)
+		if len(errs) == 0 {
+			return nil
+		}
+		matchesKind := v.matchesKind(sub, node)
+		if i == 0 || (matchesKind && !closestMatchesKind) || (matchesKind == closestMatchesKind && len(errs) < len(closest)) {
+			closest, closestMatchesKind = errs, matchesKind
+		}
+	}
+	return closest
+}
+
+// matchesKind returns true if the type of the provided schema is compatible with the kind of the provided node.
+func (v validator) matchesKind(schema *Schema, node *yaml.Node) bool {
+	if schema.Ref != "" {
+		resolved, err := v.resolve(schema.Ref)
+		if err != nil {
+			return false
+		}
+		return v.matchesKind(resolved, node)
+	}
+	for _, sub := range schema.AllOf {
+		if !v.matchesKind(sub, node) {
+			return false
+		}
+	}
+	switch schema.Type {
+	case "":
+		return true
+	case "object":
+		return node.Kind == yaml.MappingNode
+	case "array":
+		return node.Kind == yaml.SequenceNode
+	default:
+		return node.Kind == yaml.ScalarNode
+	}
+}
+
+func (v validator) validateObject(schema *Schema, node *yaml.Node, path string) []Error {
+	if node.Kind != yaml.MappingNode {
+		return []Error{newError(node, path, "expected a mapping, got "+describe(node))}
+	}
+	var errs []Error
+	seen := make(map[string]bool)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		key := keyNode.Value
+		keyPath := joinPath(path, key)
+		if seen[key] {
+			errs = append(errs, newError(keyNode, keyPath, fmt.Sprintf("duplicate key %q", key)))
+			continue
+		}
+		seen[key] = true
+
+		if propSchema, ok := schema.Properties[key]; ok {
+			errs = append(errs, v.validate(propSchema, valueNode, keyPath)...)
+			continue
+		}
+		switch {
+		case schema.AdditionalProperties != nil:
+			errs = append(errs, v.validate(schema.AdditionalProperties, valueNode, keyPath)...)
+		case schema.NoAdditionalProperties:
+			msg := fmt.Sprintf("unknown field %q", key)
+			if suggestion := closestName(key, schema.Properties); suggestion != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			errs = append(errs, newError(keyNode, keyPath, msg))
+		}
+	}
+	return errs
+}
+
+func (v validator) resolve(ref string) (*Schema, error) {
+	if ref == "#" {
+		return v.root, nil
+	}
+	defName, ok := strings.CutPrefix(ref, defsRefPrefix)
+	if !ok {
+		return nil, errors.Errorf("unsupported schema reference %q", ref)
+	}
+	def, ok := v.root.Defs[defName]
+	if !ok {
+		return nil, errors.Errorf("schema definition %q does not exist", defName)
+	}
+	return def, nil
+}
+
+func hasKey(node *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+func newError(node *yaml.Node, path, msg string) Error {
+	return Error{
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: msg,
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func describe(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a sequence"
+	default:
+		return strconv.Quote(node.Value)
+	}
+}
+
+// isBool returns true if the provided scalar is a boolean value for gopkg.in/yaml.v2, which supports YAML 1.1 booleans.
+func isBool(value string) bool {
+	switch value {
+	case "y", "Y", "yes", "Yes", "YES", "on", "On", "ON", "true", "True", "TRUE",
+		"n", "N", "no", "No", "NO", "off", "Off", "OFF", "false", "False", "FALSE":
+		return true
+	}
+	return false
+}
+
+func containsValue(values []any, value string) bool {
+	for _, v := range values {
+		if fmt.Sprint(v) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteValues(values []any) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(fmt.Sprint(v))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// closestName returns the property name that is most similar to the provided key, or an empty string if no property
+// name is similar enough to be a plausible intended value.
+func closestName(key string, properties map[string]*Schema) string {
+	var names []string
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	closest, closestDist := "", len(key)/3+2
+	for _, name := range names {
+		if dist := editDistance(key, name); dist < closestDist {
+			closest, closestDist = name, dist
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
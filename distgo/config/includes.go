@@ -0,0 +1,222 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/palantir/godel/v2/pkg/versionedconfig"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// ProfileEnvVar is the environment variable that specifies the configuration profiles to apply if none are specified
+// using the "--profile" flag. Multiple profiles are separated by commas.
+const ProfileEnvVar = "DISTGO_PROFILE"
+
+const (
+	includesKey = "includes"
+	profilesKey = "profiles"
+	productsKey = "products"
+)
+
+// IncludedConfigFiles returns the paths of the configuration files included by the provided configuration, which was
+// read from cfgFile. The patterns are resolved relative to the directory of cfgFile, and the files matched by each
+// pattern are returned in lexical order.
+func IncludedConfigFiles(cfgFile string, cfgBytes []byte) ([]string, error) {
+	var cfg struct {
+		Includes []string `yaml:"includes"`
+	}
+	if err := yaml.Unmarshal(cfgBytes, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal configuration")
+	}
+	cfgDir := filepath.Dir(cfgFile)
+	var includedFiles []string
+	for _, pattern := range cfg.Includes {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(cfgDir, pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid include pattern %q", pattern)
+		}
+		if len(matches) == 0 && !strings.ContainsAny(pattern, "*?[") {
+			return nil, errors.Errorf("included configuration file %s does not exist", pattern)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if filepath.Clean(match) == filepath.Clean(cfgFile) || slices.Contains(includedFiles, match) {
+				continue
+			}
+			includedFiles = append(includedFiles, match)
+		}
+	}
+	return includedFiles, nil
+}
+
+// ResolveIncludesAndProfiles returns the configuration that results from combining the provided configuration, which
+// was read from cfgFile, with the configuration files that it includes and then applying the provided profiles in
+// order. The provided configuration must already be upgraded, and upgradeConfig is used to upgrade the content of each
+// included file. The returned configuration does not specify includes or profiles.
+func ResolveIncludesAndProfiles(cfgFile string, cfgBytes []byte, profiles []string, upgradeConfig func(cfgBytes []byte) ([]byte, error)) ([]byte, error) {
+	cfgYAML, err := unmarshalMapSlice(cfgBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !hasMapKey(cfgYAML, includesKey) && !hasMapKey(cfgYAML, profilesKey) && len(profiles) == 0 {
+		return cfgBytes, nil
+	}
+
+	includedFiles, err := IncludedConfigFiles(cfgFile, cfgBytes)
+	if err != nil {
+		return nil, err
+	}
+	cfgYAML = deleteMapKey(cfgYAML, includesKey)
+	for _, includedFile := range includedFiles {
+		includedYAML, err := readIncludedConfig(includedFile, upgradeConfig)
+		if err != nil {
+			return nil, err
+		}
+		if cfgYAML, err = combineConfigs(cfgYAML, includedYAML); err != nil {
+			return nil, errors.Wrapf(err, "failed to include configuration file %s", includedFile)
+		}
+	}
+
+	profilesVal, _ := mapValue(cfgYAML, profilesKey)
+	profilesYAML, _ := profilesVal.(yaml.MapSlice)
+	cfgYAML = deleteMapKey(cfgYAML, profilesKey)
+	for _, profile := range profiles {
+		profileVal, ok := mapValue(profilesYAML, profile)
+		if !ok {
+			var validProfiles []string
+			for _, item := range profilesYAML {
+				validProfiles = append(validProfiles, fmt.Sprint(item.Key))
+			}
+			sort.Strings(validProfiles)
+			return nil, errors.Errorf("invalid profile %q: valid values are %v", profile, validProfiles)
+		}
+		profileYAML, _ := profileVal.(yaml.MapSlice)
+		if cfgYAML, err = applyProfile(cfgYAML, profileYAML); err != nil {
+			return nil, errors.Wrapf(err, "failed to apply profile %q", profile)
+		}
+	}
+
+	resolvedBytes, err := yaml.Marshal(cfgYAML)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal configuration")
+	}
+	return resolvedBytes, nil
+}
+
+func readIncludedConfig(includedFile string, upgradeConfig func(cfgBytes []byte) ([]byte, error)) (yaml.MapSlice, error) {
+	cfgBytes, err := os.ReadFile(includedFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read included configuration file")
+	}
+	if versionedconfig.IsLegacyConfig(cfgBytes) {
+		return nil, errors.Errorf("included configuration file %s cannot be legacy configuration", includedFile)
+	}
+	upgradedCfgBytes, err := upgradeConfig(cfgBytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to upgrade included configuration file %s", includedFile)
+	}
+	cfgYAML, err := unmarshalMapSlice(upgradedCfgBytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid included configuration file %s", includedFile)
+	}
+	if hasMapKey(cfgYAML, includesKey) {
+		return nil, errors.Errorf("included configuration file %s cannot specify %s", includedFile, includesKey)
+	}
+	return cfgYAML, nil
+}
+
+// combineConfigs returns the result of adding the included configuration to the provided configuration. The entries of
+// "products" and "profiles" are combined, and any other top-level key may only be specified in one of them.
+func combineConfigs(cfgYAML, includedYAML yaml.MapSlice) (yaml.MapSlice, error) {
+	out := slices.Clone(cfgYAML)
+	for _, item := range includedYAML {
+		key := fmt.Sprint(item.Key)
+		existingVal, ok := mapValue(out, key)
+		if !ok {
+			out = append(out, item)
+			continue
+		}
+		if key != productsKey && key != profilesKey {
+			return nil, errors.Errorf("%s is already specified", key)
+		}
+		existingEntries, _ := existingVal.(yaml.MapSlice)
+		includedEntries, _ := item.Value.(yaml.MapSlice)
+		combinedEntries := slices.Clone(existingEntries)
+		for _, entry := range includedEntries {
+			if hasMapKey(existingEntries, entry.Key) {
+				return nil, errors.Errorf("%s entry %v is already specified", key, entry.Key)
+			}
+			combinedEntries = append(combinedEntries, entry)
+		}
+		out = setMapValue(out, key, combinedEntries)
+	}
+	return out, nil
+}
+
+func applyProfile(cfgYAML, profileYAML yaml.MapSlice) (yaml.MapSlice, error) {
+	for _, key := range []string{includesKey, profilesKey} {
+		if hasMapKey(profileYAML, key) {
+			return nil, errors.Errorf("a profile cannot specify %s", key)
+		}
+	}
+	productsVal, _ := mapValue(cfgYAML, productsKey)
+	products, _ := productsVal.(yaml.MapSlice)
+	profileProductsVal, _ := mapValue(profileYAML, productsKey)
+	profileProducts, _ := profileProductsVal.(yaml.MapSlice)
+	for _, item := range profileProducts {
+		if !hasMapKey(products, item.Key) {
+			return nil, errors.Errorf("product %v is not defined", item.Key)
+		}
+	}
+	m := &deepMerger{
+		sources:           make(map[string]ValueSource),
+		secondaryKeyOrder: true,
+	}
+	return m.mergeMaps("", profileYAML, cfgYAML), nil
+}
+
+func unmarshalMapSlice(cfgBytes []byte) (yaml.MapSlice, error) {
+	var out yaml.MapSlice
+	if err := yaml.Unmarshal(cfgBytes, &out); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal configuration")
+	}
+	return out, nil
+}
+
+func deleteMapKey(in yaml.MapSlice, key any) yaml.MapSlice {
+	return slices.DeleteFunc(slices.Clone(in), func(item yaml.MapItem) bool {
+		return fmt.Sprint(item.Key) == fmt.Sprint(key)
+	})
+}
+
+func setMapValue(in yaml.MapSlice, key, val any) yaml.MapSlice {
+	for i, item := range in {
+		if fmt.Sprint(item.Key) == fmt.Sprint(key) {
+			in[i].Value = val
+			return in
+		}
+	}
+	return append(in, yaml.MapItem{Key: key, Value: val})
+}
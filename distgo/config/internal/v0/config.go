@@ -16,9 +16,11 @@ package v0
 
 import (
 	"bytes"
+	"reflect"
 	"sort"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config/configschema"
 	"github.com/palantir/pkg/matcher"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
@@ -33,6 +35,10 @@ type ProjectConfig struct {
 	// ProductDefaults is non-nil, the value in ProductDefaults is used.
 	ProductDefaults ProductConfig `yaml:"product-defaults,omitempty"`
 
+	// ProductDefaultsMerge specifies how ProductDefaults is merged with the configuration of each product. If
+	// unspecified, the "shallow" strategy described for ProductDefaults is used.
+	ProductDefaultsMerge *ProductDefaultsMergeConfig `yaml:"product-defaults-merge,omitempty"`
+
 	// ScriptIncludes specifies a string that is appended to every script that is written out. Can be used to define
 	// functions or constants for all scripts.
 	ScriptIncludes string `yaml:"script-includes,omitempty"`
@@ -44,6 +50,83 @@ type ProjectConfig struct {
 
 	// Exclude matches the paths to exclude when determining the projects to build.
 	Exclude matcher.NamesPathsCfg `yaml:"exclude,omitempty"`
+
+	// Vars maps variable names to their definitions. The value of a variable can be used in any string value of the
+	// product configuration and of ProductDefaults using the {{Var "name"}} template function. The {{Env "NAME"}},
+	// {{Env "NAME" "default"}} and {{Commit}} template functions can also be used in these values. Scripts are not
+	// rendered: they are provided with environment variables when they run.
+	Vars map[string]VarConfig `yaml:"vars,omitempty"`
+
+	// Includes specifies other configuration files whose content is combined with this configuration. Each value is a
+	// path or glob pattern that is resolved relative to the directory of this configuration file. The products and
+	// profiles of the included files are added to those of this configuration, and any other top-level key can be
+	// specified by at most one of the files. Included files cannot specify includes.
+	Includes []string `yaml:"includes,omitempty"`
+
+	// Profiles maps profile names to configuration overlays. When a profile is selected, its configuration is merged
+	// into the configuration: maps are merged key by key and any other value of the profile replaces the existing one.
+	// A profile can only configure products that are defined by the configuration.
+	Profiles map[string]ProjectConfig `yaml:"profiles,omitempty"`
+}
+
+// VarConfig defines the value of a variable. Exactly one of Value, Env or Script must be specified. A variable may
+// also be specified as a string, which is equivalent to specifying Value.
+type VarConfig struct {
+	// Value is the static value of the variable.
+	Value *string `yaml:"value,omitempty"`
+
+	// Env is the name of the environment variable whose value is the value of the variable.
+	Env *string `yaml:"env,omitempty"`
+
+	// Default is the value of the variable if the environment variable specified by Env is unset or empty.
+	Default *string `yaml:"default,omitempty"`
+
+	// Script is the content of a script that is run in the project directory to compute the value of the variable.
+	// The output of the script with leading and trailing whitespace trimmed is the value of the variable. The
+	// ScriptIncludes of the configuration are prepended to the script.
+	Script *string `yaml:"script,omitempty"`
+}
+
+func (cfg *VarConfig) UnmarshalYAML(unmarshal func(any) error) error {
+	// if configuration is specified as string only, consider as the value
+	var val string
+	if err := unmarshal(&val); err == nil {
+		*cfg = VarConfig{
+			Value: &val,
+		}
+		return nil
+	}
+	type varConfigAlias VarConfig
+	var cfgVal varConfigAlias
+	if err := unmarshal(&cfgVal); err != nil {
+		return err
+	}
+	*cfg = VarConfig(cfgVal)
+	return nil
+}
+
+// ConfigSchema returns the schema for the forms that UnmarshalYAML accepts: a value or the full configuration.
+func (cfg *VarConfig) ConfigSchema(g *configschema.Generator) *configschema.Schema {
+	return &configschema.Schema{
+		AnyOf: []*configschema.Schema{
+			{Type: "string"},
+			g.Struct(reflect.TypeFor[VarConfig]()),
+		},
+	}
+}
+
+type ProductDefaultsMergeConfig struct {
+	// Strategy is the merge strategy: "shallow" or "deep". With the "shallow" strategy, a value specified by a product
+	// replaces the corresponding value in ProductDefaults wholesale. With the "deep" strategy, maps are merged key by
+	// key (which merges disters, publishers and Docker builders by their ID), structs are merged field by field and
+	// lists and scalars specified by a product replace the inherited value. With either strategy, a top-level section
+	// such as "build" only applies to a product that specifies it and dependencies are never inherited.
+	Strategy *string `yaml:"strategy,omitempty"`
+
+	// AppendLists specifies the lists that are appended to the inherited list rather than replacing it when the "deep"
+	// strategy is used. Each value is the dot-separated path of a list from the product configuration, in which "*"
+	// matches any single key, such as "build.os-archs" or "docker.docker-builders.*.input-dists".
+	AppendLists *[]string `yaml:"append-lists,omitempty"`
 }
 
 func UpgradeConfig(
@@ -15,6 +15,7 @@
 package v0
 
 import (
+	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/godel/v2/pkg/osarch"
 )
 
@@ -37,6 +38,69 @@ type BuildConfig struct {
 	// "./distgo/main".
 	MainPkg *string `yaml:"main-pkg,omitempty"`
 
+	// Mode is the build mode of the product, which is one of "exe", "c-shared", "c-archive" or "plugin". The "c-shared",
+	// "c-archive" and "plugin" modes build the main package with the corresponding "-buildmode" flag and create the
+	// following files in place of the executable:
+	//   * c-shared: "{{Name}}.so" ("{{Name}}.dylib" for darwin and "{{Name}}.dll" for windows) and "{{Name}}.h"
+	//   * c-archive: "{{Name}}.a" ("{{Name}}.lib" for windows) and "{{Name}}.h"
+	//   * plugin: "{{Name}}.so"
+	//
+	// The "buildmode" flag cannot be specified for products that use one of these modes. If not specified, "exe" is used
+	// as the default value.
+	Mode *distgo.BuildMode `yaml:"mode,omitempty"`
+
+	// GoToolchain specifies the Go toolchain that is used to build the product. At most one of "binary" and "toolchain"
+	// can be specified. For example, the following builds the product using the "go1.22.3" toolchain installed on the
+	// PATH:
+	//
+	//   go-toolchain:
+	//     toolchain: go1.22.3+path
+	//
+	// If not specified, the "go" binary on the PATH is used.
+	GoToolchain *GoToolchainConfig `yaml:"go-toolchain,omitempty"`
+
+	// PGOProfile is the path to the profile used for profile-guided optimization relative to the project directory. If
+	// not specified, the "default.pgo" file in the directory of the main package is used if it exists (which matches the
+	// behavior of "go build"). If "off", profile-guided optimization is disabled. The profile is an input of the build:
+	// outputs that are older than the profile are rebuilt.
+	PGOProfile *string `yaml:"pgo-profile,omitempty"`
+
+	// Cover specifies the coverage-instrumented builds of the product, which are created instead of the regular builds
+	// when the "build" task is run with the "--cover" flag. For example, the following instruments all of the packages
+	// of the project:
+	//
+	//   cover:
+	//     coverpkg:
+	//       - ./...
+	Cover *BuildCoverConfig `yaml:"cover,omitempty"`
+
+	// SizeBudget specifies the maximum sizes of the outputs of the build. A build whose output exceeds its budget fails
+	// and its output is removed. Sizes are specified in bytes or with one of the units "B", "KB", "MB", "GB" (powers of
+	// 1000) or "KiB", "MiB", "GiB" (powers of 1024). For example, the following limits the outputs to 20 MB except for
+	// the "windows-amd64" output, which is limited to 25 MB:
+	//
+	//   size-budget:
+	//     max-size: 20MB
+	//     os-archs:
+	//       windows-amd64: 25MB
+	//
+	// If not specified, the sizes of the outputs are not limited.
+	SizeBudget *BuildSizeBudgetConfig `yaml:"size-budget,omitempty"`
+
+	// WindowsResources specifies the version information, icon and application manifest that are compiled into the
+	// executables built for Windows. For example:
+	//
+	//   windows-resources:
+	//     company-name: Palantir Technologies, Inc.
+	//     file-description: "{{Product}} server"
+	//     copyright: Copyright 2026 Palantir Technologies, Inc.
+	//     icon: resources/app.ico
+	//     manifest: resources/app.manifest
+	//
+	// The file and product version of the version information are set to the version of the project. If not specified,
+	// no resources are compiled into the executables.
+	WindowsResources *WindowsResourcesConfig `yaml:"windows-resources,omitempty"`
+
 	// BuildArgsScript is the content of a script that is written to a file and run before this product is built
 	// to provide supplemental build arguments for the product. The content of this value is written to a file and
 	// executed. The script process uses the project directory as its working directory and inherits the environment
@@ -56,11 +120,46 @@ type BuildConfig struct {
 	// ldflag.
 	VersionVar *string `yaml:"version-var,omitempty"`
 
+	// Flags specifies the flags provided to the "build" command for every build target. For example, the following
+	// builds with "-trimpath" and sets the "main.commit" variable to the commit of the project:
+	//
+	//   flags:
+	//     trimpath: true
+	//     ldflags-vars:
+	//       main.commit: '{{Commit}}'
+	//
+	// The flags are provided after the arguments generated by BuildArgsScript, and the ldflags variables are combined
+	// with the ldflag for VersionVar.
+	Flags *BuildFlagsConfig `yaml:"flags,omitempty"`
+
+	// OSFlags specifies the flags provided to the "build" command that are specific to an OS. The key is the OS portion
+	// of the "{OS}-{Arch}" target. The flags that are specified override the ones specified for all targets, except for
+	// the ldflags variables, which are merged. For example, the following builds with the "netgo" tag for the "linux"
+	// OS:
+	//
+	//   os-flags:
+	//     linux:
+	//       tags:
+	//         - netgo
+	OSFlags *map[string]BuildFlagsConfig `yaml:"os-flags,omitempty"`
+
+	// ArchFlags specifies the flags provided to the "build" command that are specific to an architecture. The key is the
+	// Arch portion of the "{OS}-{Arch}" target. These flags are applied after the "OSFlags" flags.
+	ArchFlags *map[string]BuildFlagsConfig `yaml:"arch-flags,omitempty"`
+
+	// OSArchsFlags specifies the flags provided to the "build" command that are specific to an OS/Architecture. The key
+	// is the OS/Arch formatted in the form "{OS}-{Arch}". These flags are applied after the "OSFlags" and "ArchFlags"
+	// flags.
+	OSArchsFlags *map[string]BuildFlagsConfig `yaml:"os-archs-flags,omitempty"`
+
 	// Environment specifies values for the environment variables that should be set for the build. For example,
 	// the following sets CGO to false:
 	//
 	//   environment:
 	//     CGO_ENABLED: "0"
+	//
+	// The values of this map and of the OSEnvironment and OSArchsEnvironment maps are rendered for every build target
+	// with the {{OSArch}}, {{Product}} and {{Version}} template functions.
 	Environment *map[string]string `yaml:"environment,omitempty"`
 
 	// OSEnvironment specifies values for the environment variables that should be set for the build that are specific
@@ -83,6 +182,124 @@ type BuildConfig struct {
 	Script *string `yaml:"script,omitempty"`
 
 	// OSArchs specifies the GOOS and GOARCH pairs for which the product is built. If blank, defaults to the GOOS
-	// and GOARCH of the host system at runtime.
+	// and GOARCH of the host system at runtime. The synthetic "darwin-universal" pair ("os: darwin", "arch: universal")
+	// builds the product for "darwin-amd64" and "darwin-arm64" and merges the executables into a single macOS universal
+	// binary. The flags and environment variables of "darwin-amd64" and "darwin-arm64" are used for their builds.
+	OSArchs *[]osarch.OSArch `yaml:"os-archs,omitempty"`
+
+	// Variants specifies named variants of the build. Each variant is built in addition to the default build using the
+	// configuration of the default build with the environment variables and flags of the variant applied last. The
+	// output of a variant is written to the "{OS}-{Arch}-{Variant}" directory of the build output directory and a
+	// variant can be built on its own using the "{Product}@{Variant}" or "{Product}.{OS}-{Arch}@{Variant}" build IDs.
+	// For example, the following declares a variant that builds with the race detector:
+	//
+	//   variants:
+	//     race:
+	//       environment:
+	//         CGO_ENABLED: "1"
+	//       flags:
+	//         race: true
+	Variants *map[distgo.BuildVariantID]BuildVariantConfig `yaml:"variants,omitempty"`
+}
+
+type BuildVariantConfig struct {
+	// Environment specifies values for the environment variables that are set for the builds of the variant. They are
+	// set after all of the environment variables of the default build.
+	Environment *map[string]string `yaml:"environment,omitempty"`
+
+	// Flags specifies the flags of the variant. They are applied after all of the flags of the default build.
+	Flags *BuildFlagsConfig `yaml:"flags,omitempty"`
+
+	// OSArchs specifies the GOOS and GOARCH pairs for which the variant is built. If blank, defaults to the OSArchs of
+	// the build.
 	OSArchs *[]osarch.OSArch `yaml:"os-archs,omitempty"`
 }
+
+type BuildCoverConfig struct {
+	// OutputDir specifies the output directory for the coverage-instrumented builds. It must differ from the output
+	// directory of the build so that the coverage-instrumented executables never replace the regular ones. If not
+	// specified, "out/cover" is used as the default value.
+	OutputDir *string `yaml:"output-dir,omitempty"`
+
+	// Coverpkg are the patterns of the packages that are instrumented, which are provided to the "build" command using
+	// the "-coverpkg" flag. If not specified, only the main package is instrumented.
+	Coverpkg *[]string `yaml:"coverpkg,omitempty"`
+}
+
+type BuildSizeBudgetConfig struct {
+	// MaxSize is the maximum size of the output for every OS/Arch that does not have an entry in "os-archs".
+	MaxSize *string `yaml:"max-size,omitempty"`
+
+	// OSArchs specifies the maximum sizes that are specific to an OS/Arch. The key is the OS/Arch formatted in the form
+	// "{OS}-{Arch}".
+	OSArchs *map[string]string `yaml:"os-archs,omitempty"`
+}
+
+type WindowsResourcesConfig struct {
+	// CompanyName is the value of the "CompanyName" string of the version information. The "{{Product}}" and
+	// "{{Version}}" template functions can be used in this and the other strings of the version information.
+	CompanyName *string `yaml:"company-name,omitempty"`
+
+	// FileDescription is the value of the "FileDescription" string of the version information.
+	FileDescription *string `yaml:"file-description,omitempty"`
+
+	// ProductName is the value of the "ProductName" string of the version information. If not specified, the name of
+	// the product is used.
+	ProductName *string `yaml:"product-name,omitempty"`
+
+	// Copyright is the value of the "LegalCopyright" string of the version information.
+	Copyright *string `yaml:"copyright,omitempty"`
+
+	// Icon is the path to the ICO file that is used as the icon of the executables relative to the project directory.
+	Icon *string `yaml:"icon,omitempty"`
+
+	// Manifest is the path to the application manifest of the executables relative to the project directory.
+	Manifest *string `yaml:"manifest,omitempty"`
+}
+
+type GoToolchainConfig struct {
+	// Binary is the path to the "go" binary that is used to build the product. A relative path that contains a path
+	// separator is resolved against the project directory and a name without a path separator is looked up on the
+	// PATH (for example, "go1.22.3" for a toolchain installed using "golang.org/dl").
+	Binary *string `yaml:"binary,omitempty"`
+
+	// Toolchain is the value of the GOTOOLCHAIN environment variable that is set when the "go" binary is invoked (for
+	// example, "go1.22.3+path" or "local").
+	Toolchain *string `yaml:"toolchain,omitempty"`
+}
+
+type BuildFlagsConfig struct {
+	// Tags are the build tags provided to the "build" command using the "-tags" flag.
+	Tags *[]string `yaml:"tags,omitempty"`
+
+	// LdflagsVars specifies the values of string variables that are set using "-X" ldflags. The key is the full path to
+	// the variable (for example, "main.commit") and the value is its value. The values are rendered for every build
+	// target with the following template functions:
+	//   * {{Product}}: the name of the product
+	//   * {{Version}}: the version of the project
+	//   * {{OSArch}}: the OS/architecture of the build target
+	//   * {{BuildDate}}: the time at which the build started in RFC 3339 format (UTC)
+	//   * {{Dirty}}: "true" if the version of the project has the ".dirty" suffix, "false" otherwise
+	//
+	// The "Env", "Var" and "Commit" configuration template functions can also be used.
+	LdflagsVars *map[string]string `yaml:"ldflags-vars,omitempty"`
+
+	// Gcflags is the value of the "-gcflags" flag.
+	Gcflags *string `yaml:"gcflags,omitempty"`
+
+	// Asmflags is the value of the "-asmflags" flag.
+	Asmflags *string `yaml:"asmflags,omitempty"`
+
+	// Trimpath specifies whether the "-trimpath" flag is provided.
+	Trimpath *bool `yaml:"trimpath,omitempty"`
+
+	// Buildmode is the value of the "-buildmode" flag.
+	Buildmode *string `yaml:"buildmode,omitempty"`
+
+	// Race specifies whether the "-race" flag is provided.
+	Race *bool `yaml:"race,omitempty"`
+
+	// Strip specifies whether the symbol table and DWARF information are omitted from the executable using the "-s -w"
+	// ldflags.
+	Strip *bool `yaml:"strip,omitempty"`
+}
@@ -15,7 +15,10 @@
 package v0
 
 import (
+	"reflect"
+
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config/configschema"
 	"github.com/palantir/pkg/matcher"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
@@ -61,6 +64,18 @@ type DisterConfig struct {
 	// process and also has dist-related environment variables. Refer to the documentation for the
 	// distgo.DistScriptEnvVariables function for the extra environment variables.
 	Script *string `yaml:"script,omitempty"`
+
+	// InputDockerExports specifies the Docker configurations whose exported archives (see DockerBuilderConfig.Export)
+	// are copied to the dist work directory before the distribution operation is run. The values are of the form
+	// "{{ProductID}}" (all of the Docker configurations of the product that export archives) or
+	// "{{ProductID}}.{{DockerID}}", and the referenced products must be this product or one of its declared
+	// dependencies. The archives must have been created by "docker build" before the dist is run.
+	InputDockerExports *[]distgo.ProductDockerID `yaml:"input-docker-exports,omitempty"`
+
+	// BuildVariant specifies the build variant (see BuildConfig.Variants) of the product whose executables are used by
+	// the dist. If blank, the executables of the default build are used. The executables of the dependencies of the
+	// product are always those of their default builds.
+	BuildVariant *distgo.BuildVariantID `yaml:"build-variant,omitempty"`
 }
 
 type InputDirConfig struct {
@@ -100,6 +115,16 @@ func (cfg *InputDirConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	return nil
 }
 
+// ConfigSchema returns the schema for the forms that UnmarshalYAML accepts: a path or the full configuration.
+func (cfg *InputDirConfig) ConfigSchema(g *configschema.Generator) *configschema.Schema {
+	return &configschema.Schema{
+		AnyOf: []*configschema.Schema{
+			{Type: "string"},
+			g.Struct(reflect.TypeFor[InputDirConfig]()),
+		},
+	}
+}
+
 type DistersConfig map[distgo.DistID]DisterConfig
 
 func (cfgs *DistersConfig) UnmarshalYAML(unmarshal func(any) error) error {
@@ -122,3 +147,18 @@ func (cfgs *DistersConfig) UnmarshalYAML(unmarshal func(any) error) error {
 	*cfgs = multiple
 	return nil
 }
+
+// ConfigSchema returns the schema for the forms that UnmarshalYAML accepts: a single DisterConfig with an explicit
+// "type" or a map[DistID]DisterConfig.
+func (cfgs *DistersConfig) ConfigSchema(g *configschema.Generator) *configschema.Schema {
+	disterConfig := g.Type(reflect.TypeFor[DisterConfig]())
+	return &configschema.Schema{
+		AnyOf: []*configschema.Schema{
+			{
+				AllOf:    []*configschema.Schema{disterConfig},
+				Required: []string{"type"},
+			},
+			{Type: "object", AdditionalProperties: disterConfig},
+		},
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"strconv"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config/configschema"
 	"gopkg.in/yaml.v2"
 )
 
@@ -36,6 +37,46 @@ type DockerConfig struct {
 
 	// DockerBuilderParams contains the Docker params for this distribution.
 	DockerBuildersConfig *DockerBuildersConfig `yaml:"docker-builders,omitempty"`
+
+	// Registries specifies settings for the registries that images are pushed to. The key is the registry host as it
+	// appears in the image tags (for example, "registry.example.com:5000" or "docker.io"). Registries that are not
+	// specified use secure connections, the credentials from the Docker configuration of the user and the default
+	// retry policy. Images pushed using the Docker daemon rather than from an OCI layout use the registry settings of
+	// the daemon, so pushing such an image fails if its registry specifies insecure or credentials. The retry policy
+	// applies to both.
+	Registries *DockerRegistriesConfig `yaml:"registries,omitempty"`
+}
+
+type DockerRegistriesConfig map[string]DockerRegistryConfig
+
+type DockerRegistryConfig struct {
+	// Insecure allows pushing to the registry over plain HTTP or with an untrusted TLS certificate.
+	Insecure *bool `yaml:"insecure,omitempty"`
+	// Credentials specifies where the credentials for the registry are read from. If unspecified, the credentials
+	// from the Docker configuration of the user are used.
+	Credentials *DockerRegistryCredentialsConfig `yaml:"credentials,omitempty"`
+	// Retry specifies the retry policy for requests to the registry.
+	Retry *DockerRegistryRetryConfig `yaml:"retry,omitempty"`
+}
+
+// DockerRegistryCredentialsConfig references the environment variables that hold the credentials for a registry, so
+// that secrets never have to be written to the configuration. Either both UsernameEnv and PasswordEnv or TokenEnv must
+// be specified.
+type DockerRegistryCredentialsConfig struct {
+	// UsernameEnv is the name of the environment variable that holds the username for the registry.
+	UsernameEnv *string `yaml:"username-env,omitempty"`
+	// PasswordEnv is the name of the environment variable that holds the password for the registry.
+	PasswordEnv *string `yaml:"password-env,omitempty"`
+	// TokenEnv is the name of the environment variable that holds a bearer token for the registry.
+	TokenEnv *string `yaml:"token-env,omitempty"`
+}
+
+type DockerRegistryRetryConfig struct {
+	// Attempts is the maximum number of times a request is attempted. Must be at least 1.
+	Attempts *int `yaml:"attempts,omitempty"`
+	// InitialBackoff is the time waited before the first retry as a Go duration string (for example, "1s"). The wait
+	// doubles after each retry. If unspecified, "1s" is used.
+	InitialBackoff *string `yaml:"initial-backoff,omitempty"`
 }
 
 type DockerBuildersConfig map[distgo.DockerID]DockerBuilderConfig
@@ -100,6 +141,109 @@ type DockerBuilderConfig struct {
 	// TagTemplates specifies the templates that should be used to render the tag(s) for the Docker image. If multiple
 	// values are specified, the image will be tagged with all of them.
 	TagTemplates *TagTemplatesMap `yaml:"tag-templates,omitempty"`
+	// Tests specifies the assertions that the "docker test" task makes about the image built by this configuration.
+	// The tests inspect the OCI layout written by the Docker builder directly, so they require a builder that produces
+	// an OCI layout but do not require a Docker daemon.
+	Tests *DockerImageTestsConfig `yaml:"tests,omitempty"`
+	// Export specifies the offline archives of the image that the "docker build" task writes from the OCI layout
+	// written by the Docker builder, so it requires a builder that produces an OCI layout. The archives are written to
+	// "{{OutputDir}}/{{ID}}/{{Version}}" and are listed by "artifacts docker".
+	Export *DockerExportConfig `yaml:"export,omitempty"`
+	// Mirrors specifies additional repositories that the "docker push" task pushes the image to. The key is an
+	// identifier for the mirror that can be used to refer to it.
+	Mirrors *DockerMirrorsConfig `yaml:"mirrors,omitempty"`
+}
+
+type DockerMirrorsConfig map[distgo.DockerMirrorID]DockerMirrorConfig
+
+type DockerMirrorConfig struct {
+	// Repository is the repository of the mirror. This value is made available to the tag templates of the mirror as
+	// {{Repository}} and {{RepositoryLiteral}}.
+	Repository *string `yaml:"repository,omitempty"`
+	// TagTemplates specifies the templates that are used to render the tags the image is pushed to in the mirror. If
+	// unspecified, the tag templates of the Docker builder are used.
+	TagTemplates *TagTemplatesMap `yaml:"tag-templates,omitempty"`
+}
+
+type DockerExportConfig struct {
+	// DockerArchive specifies whether a tarball that can be loaded using "docker load" is written to
+	// "{{Product}}-{{Version}}-{{DockerID}}.docker.tar". The image is tagged with all of the rendered tags of the
+	// configuration.
+	DockerArchive *bool `yaml:"docker-archive,omitempty"`
+	// DockerArchivePlatform is the platform ("os/arch" or "os/arch/variant") of the image written to the Docker
+	// archive, which can only hold a single image per tag. Must be specified if the image is built for more than one
+	// platform.
+	DockerArchivePlatform *string `yaml:"docker-archive-platform,omitempty"`
+	// OCIArchive specifies whether a gzip-compressed tarball of the OCI layout is written to
+	// "{{Product}}-{{Version}}-{{DockerID}}.oci.tar.gz". The index of the archived layout names the image by each of the
+	// rendered tags of the configuration.
+	OCIArchive *bool `yaml:"oci-archive,omitempty"`
+}
+
+type DockerImageTestsConfig struct {
+	// Platforms restricts the tests to the images for the specified platforms, which are of the form "os/arch" or
+	// "os/arch/variant". If unspecified, the tests are run against the image for every platform in the image index.
+	Platforms []string `yaml:"platforms,omitempty"`
+	// FileExistence specifies tests that verify the presence, absence and attributes of files in the image.
+	FileExistence []DockerImageFileExistenceTestConfig `yaml:"file-existence,omitempty"`
+	// FileContent specifies tests that verify the content of files in the image.
+	FileContent []DockerImageFileContentTestConfig `yaml:"file-content,omitempty"`
+	// Metadata specifies a test that verifies the configuration of the image.
+	Metadata *DockerImageMetadataTestConfig `yaml:"metadata,omitempty"`
+	// Layers specifies a test that verifies the content of every layer of the image, including files that a later
+	// layer removes.
+	Layers *DockerImageLayersTestConfig `yaml:"layers,omitempty"`
+}
+
+type DockerImageFileExistenceTestConfig struct {
+	// Path is the absolute path of the file in the image.
+	Path string `yaml:"path,omitempty"`
+	// ShouldExist specifies whether the file is expected to exist. Defaults to true.
+	ShouldExist *bool `yaml:"should-exist,omitempty"`
+	// Mode is the expected permission bits of the file as an octal string (for example, "0755").
+	Mode *string `yaml:"mode,omitempty"`
+	// UID is the expected user ID of the owner of the file.
+	UID *int `yaml:"uid,omitempty"`
+	// GID is the expected group ID of the owner of the file.
+	GID *int `yaml:"gid,omitempty"`
+}
+
+type DockerImageFileContentTestConfig struct {
+	// Path is the absolute path of the file in the image.
+	Path string `yaml:"path,omitempty"`
+	// ExpectedContents are regular expressions that must each match the content of the file.
+	ExpectedContents []string `yaml:"expected-contents,omitempty"`
+	// ExcludedContents are regular expressions that must not match the content of the file.
+	ExcludedContents []string `yaml:"excluded-contents,omitempty"`
+}
+
+type DockerImageMetadataTestConfig struct {
+	// Entrypoint is the expected entrypoint of the image.
+	Entrypoint *[]string `yaml:"entrypoint,omitempty"`
+	// Cmd is the expected default arguments of the image.
+	Cmd *[]string `yaml:"cmd,omitempty"`
+	// User is the expected user of the image.
+	User *string `yaml:"user,omitempty"`
+	// WorkingDir is the expected working directory of the image.
+	WorkingDir *string `yaml:"workdir,omitempty"`
+	// Env is a map from environment variable name to its expected value.
+	Env map[string]string `yaml:"env,omitempty"`
+	// Labels is a map from label key to its expected value.
+	Labels map[string]string `yaml:"labels,omitempty"`
+	// RequiredLabels are label keys that must be present with any value.
+	RequiredLabels []string `yaml:"required-labels,omitempty"`
+	// ExposedPorts are ports (for example, "8080/tcp") that must be exposed by the image.
+	ExposedPorts []string `yaml:"exposed-ports,omitempty"`
+	// Volumes are paths that must be declared as volumes by the image.
+	Volumes []string `yaml:"volumes,omitempty"`
+}
+
+type DockerImageLayersTestConfig struct {
+	// ForbiddenPaths are glob patterns for files that must not appear in any layer. A pattern that contains a '/' is
+	// matched against the absolute path of the file, while any other pattern is matched against its base name.
+	ForbiddenPaths []string `yaml:"forbidden-paths,omitempty"`
+	// ForbiddenContents are regular expressions that must not match the content of any regular file in any layer.
+	ForbiddenContents []string `yaml:"forbidden-contents,omitempty"`
 }
 
 type TagTemplatesMap struct {
@@ -171,3 +315,15 @@ func (t *TagTemplatesMap) UnmarshalYAML(unmarshal func(any) error) error {
 	}
 	return nil
 }
+
+// ConfigSchema returns the schema for the forms that UnmarshalYAML accepts: a single template, a list of templates or a
+// map from tag ID to template.
+func (t *TagTemplatesMap) ConfigSchema(g *configschema.Generator) *configschema.Schema {
+	return &configschema.Schema{
+		AnyOf: []*configschema.Schema{
+			{Type: "string"},
+			{Type: "array", Items: &configschema.Schema{Type: "string"}},
+			{Type: "object", AdditionalProperties: &configschema.Schema{Type: "string"}},
+		},
+	}
+}
@@ -26,6 +26,14 @@ type ProductConfig struct {
 	// If a value is not specified, the value of ProductID is used as the default value.
 	Name *string `yaml:"name,omitempty"`
 
+	// Labels are arbitrary key/value pairs that describe the product. Products can be selected by label using
+	// selector expressions such as "team=payments" in commands that accept products.
+	Labels *map[string]string `yaml:"labels,omitempty"`
+
+	// Groups are the names of the groups that the product belongs to. Products can be selected by group using selector
+	// expressions such as "group:cli" in commands that accept products.
+	Groups *[]string `yaml:"groups,omitempty"`
+
 	// Build specifies the build configuration for the product.
 	Build *BuildConfig `yaml:"build,omitempty"`
 
@@ -43,4 +51,10 @@ type ProductConfig struct {
 
 	// Dependencies specifies the first-level dependencies of this product. Stores the IDs of the products.
 	Dependencies *[]distgo.ProductID `yaml:"dependencies,omitempty"`
+
+	// Unset specifies values inherited from ProductDefaults that are removed from the configuration of this product.
+	// Each value is the dot-separated path of the value from the product configuration, such as
+	// "build.environment.CGO_ENABLED" or "dist.disters.os-arch-bin". Only valid if ProductDefaultsMerge specifies the
+	// "deep" strategy, and cannot be specified in ProductDefaults.
+	Unset *[]string `yaml:"unset,omitempty"`
 }
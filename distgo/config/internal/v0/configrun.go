@@ -17,4 +17,8 @@ package v0
 type RunConfig struct {
 	// Args contain the arguments provided to the product when invoked using the "run" task.
 	Args *[]string `yaml:"args,omitempty"`
+
+	// WasmRuntime is the command used to run the product when invoked using the "run" task. If specified, the product
+	// is built for "wasip1-wasm" and the resulting module is executed using the command (for example, "wasmtime").
+	WasmRuntime *string `yaml:"wasm-runtime,omitempty"`
 }
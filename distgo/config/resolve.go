@@ -0,0 +1,188 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/pkg/matcher"
+)
+
+// ValueSource is the origin of a value in the resolved configuration of a product.
+type ValueSource string
+
+const (
+	// ValueSourceExplicit is a value that is specified in the configuration of the product.
+	ValueSourceExplicit ValueSource = "explicit"
+	// ValueSourceProductDefaults is a value that is inherited from "product-defaults".
+	ValueSourceProductDefaults ValueSource = "product-defaults"
+	// ValueSourceDefault is a value that is provided by distgo: either a built-in default value or a value of the
+	// configuration that is generated for the main packages of a project that does not configure any products.
+	ValueSourceDefault ValueSource = "default"
+	// ValueSourceLegacyUpgrade is a value that was produced by upgrading legacy configuration.
+	ValueSourceLegacyUpgrade ValueSource = "legacy-upgrade"
+	// ValueSourceMerged is a map or list that combines values of the product and of "product-defaults" using the "deep"
+	// product-defaults-merge strategy.
+	ValueSourceMerged ValueSource = "merged"
+)
+
+// ResolvedProductConfig is the effective configuration of a product: the configuration that is used to create its
+// distgo.ProductParam after "product-defaults", built-in default values and script includes are applied.
+type ResolvedProductConfig struct {
+	Config ProductConfig
+	// Sources maps the path of each value in Config to its source. A path is the dot-separated sequence of YAML keys
+	// from the product configuration to the value, such as "build.output-dir" or "dist.disters.os-arch-bin.script".
+	Sources map[string]ValueSource
+}
+
+// ResolveProductConfigs returns the effective configuration of every product in the project. The configuration is
+// resolved by the same functions that ToParam uses to create the parameters of the products: a top-level section such
+// as "build" is only present if the product specifies it, and within a section each value that the product does not
+// specify is taken from "product-defaults" or, if it is not specified there either, from the built-in default value. If
+// the project does not configure any products, the products are generated for the main packages in projectDir using
+// defaultDisterCfg. If legacy is true, the values that come from the configuration are attributed to
+// ValueSourceLegacyUpgrade.
+func (cfg *ProjectConfig) ResolveProductConfigs(projectDir string, defaultDisterCfg DisterConfig, legacy bool) (map[distgo.ProductID]ResolvedProductConfig, error) {
+	renderedCfg, err := cfg.renderConfigTemplates(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	cfg = &renderedCfg
+
+	var exclude matcher.Matcher
+	if !cfg.Exclude.Empty() {
+		exclude = cfg.Exclude.Matcher()
+	}
+
+	explicitSource, defaultsSource := ValueSourceExplicit, ValueSourceProductDefaults
+	if legacy {
+		explicitSource, defaultsSource = ValueSourceLegacyUpgrade, ValueSourceLegacyUpgrade
+	}
+	cfgProducts := cfg.Products
+	if cfgProducts == nil && projectDir != "" {
+		productCfgs, err := mainPkgsProductsConfig(projectDir, defaultDisterCfg, exclude)
+		if err != nil {
+			return nil, err
+		}
+		cfgProducts = toProductIDV0ProductConfigMap(productCfgs)
+		explicitSource = ValueSourceDefault
+	}
+
+	resolved := make(map[distgo.ProductID]ResolvedProductConfig, len(cfgProducts))
+	for productID, productCfg := range cfgProducts {
+		mergedCfg, defaultCfg, mergedSources, err := cfg.mergeProductDefaults(productID, productCfg)
+		if err != nil {
+			return nil, err
+		}
+		sources := &configSources{
+			explicitSource: explicitSource,
+			defaultsSource: defaultsSource,
+			mergedSources:  mergedSources,
+			sources:        make(map[string]ValueSource),
+		}
+		resolved[productID] = ResolvedProductConfig{
+			Config:  mergedCfg.resolve(sources, productID, cfg.ScriptIncludes, defaultCfg),
+			Sources: sources.sources,
+		}
+	}
+	return resolved, nil
+}
+
+// configSources records the source of each value that is selected when a configuration is resolved. The resolve
+// functions that ToParam uses to apply the product defaults and the built-in default values accept a nil
+// *configSources, in which case nothing is recorded.
+type configSources struct {
+	explicitSource ValueSource
+	defaultsSource ValueSource
+	// mergedSources is the source of each value of a product configuration that was deep merged with the product
+	// defaults. Nil if the "shallow" strategy is used.
+	mergedSources map[string]ValueSource
+	sources       map[string]ValueSource
+}
+
+// recordPrimary records the source of the value at the provided path of the configuration of the product. If the
+// configuration was deep merged with the product defaults, this is the source recorded by the merge.
+func (s *configSources) recordPrimary(path string) {
+	if s == nil {
+		return
+	}
+	switch s.mergedSources[path] {
+	case ValueSourceProductDefaults:
+		s.sources[path] = s.defaultsSource
+	case ValueSourceMerged:
+		s.sources[path] = ValueSourceMerged
+	default:
+		s.sources[path] = s.explicitSource
+	}
+}
+
+// recordDefaults records that the value at the provided path is inherited from the product defaults.
+func (s *configSources) recordDefaults(path string) {
+	if s == nil {
+		return
+	}
+	s.sources[path] = s.defaultsSource
+}
+
+// recordBuiltin records that the value at the provided path is a built-in default value.
+func (s *configSources) recordBuiltin(path string) {
+	if s == nil {
+		return
+	}
+	s.sources[path] = ValueSourceDefault
+}
+
+// resolveValue returns primary if it is non-nil, secondary if it is non-nil and defaultVal otherwise (which may be
+// nil), and records the source of the returned value at the provided path.
+func resolveValue[T any](s *configSources, path string, primary, secondary, defaultVal *T) *T {
+	switch {
+	case primary != nil:
+		s.recordPrimary(path)
+		return primary
+	case secondary != nil:
+		s.recordDefaults(path)
+		return secondary
+	case defaultVal != nil:
+		s.recordBuiltin(path)
+		return defaultVal
+	}
+	return nil
+}
+
+// resolveStringValue is like resolveValue, but uses the provided default value (unless it is empty) if the selected
+// value is empty in the same manner as getConfigStringValue.
+func resolveStringValue(s *configSources, path string, primary, secondary *string, defaultVal string) *string {
+	val := resolveValue(s, path, primary, secondary, nil)
+	if defaultVal != "" && (val == nil || *val == "") {
+		s.recordBuiltin(path)
+		return &defaultVal
+	}
+	return val
+}
+
+// resolveScriptValue returns the provided script with the script includes of the project prepended in the same manner
+// as distgo.CreateScriptContent.
+func resolveScriptValue(script *string, scriptIncludes string) *string {
+	if script == nil {
+		return nil
+	}
+	return new(distgo.CreateScriptContent(*script, scriptIncludes))
+}
+
+func joinConfigPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
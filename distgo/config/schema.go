@@ -0,0 +1,175 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config/configschema"
+	v0 "github.com/palantir/distgo/distgo/config/internal/v0"
+	"github.com/palantir/godel/v2/pkg/versionedconfig"
+	"github.com/pkg/errors"
+	"go.yaml.in/yaml/v3"
+)
+
+// ProjectConfigSchema returns the JSON Schema for dist-plugin configuration. The "config" values of disters, Docker
+// builders and publishers are described by the schemas provided by the factories for their types: factories that
+// implement distgo.ConfigSchemaProvider, which includes the factories for asset-provided types, contribute a schema for
+// each of their types that has one, and the configuration of any other type is not constrained.
+func ProjectConfigSchema(
+	disterFactory distgo.DisterFactory,
+	dockerBuilderFactory distgo.DockerBuilderFactory,
+	publisherFactory distgo.PublisherFactory) (*configschema.Schema, error) {
+
+	root := configschema.For(v0.ProjectConfig{})
+	root.Title = "dist-plugin configuration"
+
+	disterSchemas, err := typeConfigSchemas(root, "dister", disterFactory.Types(), disterFactory)
+	if err != nil {
+		return nil, err
+	}
+	addTypedConfigSchemas(root.Defs["DisterConfig"], disterFactory.Types(), disterSchemas)
+
+	dockerBuilderSchemas, err := typeConfigSchemas(root, "docker-builder", dockerBuilderFactory.Types(), dockerBuilderFactory)
+	if err != nil {
+		return nil, err
+	}
+	addTypedConfigSchemas(root.Defs["DockerBuilderConfig"], dockerBuilderFactory.Types(), dockerBuilderSchemas)
+
+	publisherSchemas, err := typeConfigSchemas(root, "publisher", publisherFactory.Types(), publisherFactory)
+	if err != nil {
+		return nil, err
+	}
+	publishInfo := &configschema.Schema{
+		Type:                   "object",
+		Properties:             make(map[string]*configschema.Schema),
+		NoAdditionalProperties: true,
+	}
+	for _, typeName := range publisherFactory.Types() {
+		publisherConfig := &configschema.Schema{Ref: configschema.DefRef("PublisherConfig")}
+		if ref, ok := publisherSchemas[typeName]; ok {
+			publisherConfig = &configschema.Schema{
+				Type: "object",
+				Properties: map[string]*configschema.Schema{
+					"config": {Ref: ref},
+				},
+				NoAdditionalProperties: true,
+			}
+		}
+		publishInfo.Properties[typeName] = publisherConfig
+	}
+	root.Defs["PublishConfig"].Properties["info"] = publishInfo
+	return root, nil
+}
+
+// typeConfigSchemas embeds the configuration schemas for the provided types in root and returns a map from type name
+// to the reference to its schema. Types whose factory does not provide a schema are not included in the returned map.
+func typeConfigSchemas(root *configschema.Schema, kind string, typeNames []string, factory any) (map[string]string, error) {
+	provider, ok := factory.(distgo.ConfigSchemaProvider)
+	if !ok {
+		return nil, nil
+	}
+	refs := make(map[string]string)
+	for _, typeName := range typeNames {
+		schemaJSON, err := provider.ConfigSchema(typeName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get configuration schema for %s type %q", kind, typeName)
+		}
+		schema, err := configschema.Parse(schemaJSON)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid configuration schema for %s type %q", kind, typeName)
+		}
+		if schema == nil {
+			continue
+		}
+		refs[typeName] = configschema.Embed(root, schema, kind+"."+typeName)
+	}
+	return refs, nil
+}
+
+// addTypedConfigSchemas restricts the "type" property of the provided definition to the provided type names and
+// validates its "config" property using the schema for the type.
+func addTypedConfigSchemas(def *configschema.Schema, typeNames []string, refs map[string]string) {
+	sortedTypeNames := append([]string(nil), typeNames...)
+	sort.Strings(sortedTypeNames)
+	enum := make([]any, len(sortedTypeNames))
+	for i, typeName := range sortedTypeNames {
+		enum[i] = typeName
+	}
+	def.Properties["type"] = &configschema.Schema{
+		Type: "string",
+		Enum: enum,
+	}
+	for _, typeName := range sortedTypeNames {
+		ref, ok := refs[typeName]
+		if !ok {
+			continue
+		}
+		def.AllOf = append(def.AllOf, &configschema.Schema{
+			If: &configschema.Schema{
+				Type: "object",
+				Properties: map[string]*configschema.Schema{
+					"type": {Const: typeName},
+				},
+				Required: []string{"type"},
+			},
+			Then: &configschema.Schema{
+				Type: "object",
+				Properties: map[string]*configschema.Schema{
+					"config": {Ref: ref},
+				},
+			},
+		})
+	}
+}
+
+var yamlErrorLineRegexp = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// ValidateConfig strictly validates the structure of the provided dist-plugin configuration against the schema returned
+// by ProjectConfigSchema and returns the errors sorted by position. Unlike the unmarshalling performed when the
+// configuration is loaded, every error in the file is reported. YAML syntax errors are also returned as validation
+// errors. Legacy configuration is not validated because it is upgraded before it is used.
+//
+// Validation does not check the semantics of the configuration, such as whether the dependencies of a product exist:
+// such errors are reported when the configuration is converted to a distgo.ProjectParam.
+func ValidateConfig(
+	cfgBytes []byte,
+	disterFactory distgo.DisterFactory,
+	dockerBuilderFactory distgo.DockerBuilderFactory,
+	publisherFactory distgo.PublisherFactory) ([]configschema.Error, error) {
+
+	if versionedconfig.IsLegacyConfig(cfgBytes) {
+		return nil, nil
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(cfgBytes, &node); err != nil {
+		syntaxErr := configschema.Error{
+			Message: err.Error(),
+		}
+		if match := yamlErrorLineRegexp.FindStringSubmatch(err.Error()); match != nil {
+			syntaxErr.Line, _ = strconv.Atoi(match[1])
+			syntaxErr.Message = "invalid YAML: " + err.Error()[len(match[0]):]
+		}
+		return []configschema.Error{syntaxErr}, nil
+	}
+	schema, err := ProjectConfigSchema(disterFactory, dockerBuilderFactory, publisherFactory)
+	if err != nil {
+		return nil, err
+	}
+	return configschema.Validate(schema, &node), nil
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/palantir/distgo/distgo"
+	v0 "github.com/palantir/distgo/distgo/config/internal/v0"
+	"github.com/palantir/distgo/pkg/git"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+type VarConfig v0.VarConfig
+
+// templatedConfig is the part of the configuration whose string values are rendered using the configuration template
+// functions.
+type templatedConfig struct {
+	Products        map[distgo.ProductID]v0.ProductConfig `yaml:"products,omitempty"`
+	ProductDefaults v0.ProductConfig                      `yaml:"product-defaults,omitempty"`
+}
+
+// scriptKeys are the keys of the script values of the configuration. Scripts are not rendered: they may contain text
+// that resembles a template action (such as "{{" in a shell command) and are provided with environment variables when
+// they run.
+var scriptKeys = []string{"script", "build-args-script"}
+
+// renderConfigTemplates returns a copy of the configuration in which the "Env", "Var" and "Commit" template functions
+// in the string values of the products and product defaults are rendered. Only the template actions that call one of
+// these functions are rendered: the other parts of the values, such as the functions that are provided when a task
+// runs, and scripts are unchanged.
+func (cfg *ProjectConfig) renderConfigTemplates(projectDir string) (ProjectConfig, error) {
+	tmplYAML, err := toMapSlice(templatedConfig{
+		Products:        cfg.Products,
+		ProductDefaults: cfg.ProductDefaults,
+	})
+	if err != nil {
+		return ProjectConfig{}, err
+	}
+	templates := collectTemplates(tmplYAML, nil)
+	if len(templates) == 0 && len(cfg.Vars) == 0 {
+		return *cfg, nil
+	}
+
+	usesFn := func(name string) bool {
+		return slices.ContainsFunc(templates, func(tmpl string) bool {
+			return strings.Contains(tmpl, name)
+		})
+	}
+	vars, err := cfg.resolveVars(projectDir, usesFn("Var"))
+	if err != nil {
+		return ProjectConfig{}, err
+	}
+	fns := []distgo.TemplateFunction{
+		distgo.EnvTemplateFunction(),
+		distgo.VarTemplateFunction(vars),
+	}
+	if usesFn("Commit") {
+		commit, err := git.CmdOutput(projectDir, "rev-parse", "HEAD")
+		if err != nil {
+			return ProjectConfig{}, errors.Wrapf(err, "failed to determine commit for Commit template function")
+		}
+		fns = append(fns, distgo.CommitTemplateFunction(commit))
+	}
+
+	renderedYAML, err := renderTemplates("", tmplYAML, fns)
+	if err != nil {
+		return ProjectConfig{}, err
+	}
+	if reflect.DeepEqual(renderedYAML, any(tmplYAML)) {
+		return *cfg, nil
+	}
+	renderedBytes, err := yaml.Marshal(renderedYAML)
+	if err != nil {
+		return ProjectConfig{}, errors.Wrapf(err, "failed to marshal rendered configuration")
+	}
+	var rendered templatedConfig
+	if err := yaml.UnmarshalStrict(renderedBytes, &rendered); err != nil {
+		return ProjectConfig{}, errors.Wrapf(err, "failed to unmarshal rendered configuration")
+	}
+
+	out := *cfg
+	if cfg.Products != nil && rendered.Products == nil {
+		// preserve an empty products map, which disables the generation of products for main packages
+		rendered.Products = make(map[distgo.ProductID]v0.ProductConfig)
+	}
+	out.Products = rendered.Products
+	out.ProductDefaults = rendered.ProductDefaults
+	return out, nil
+}
+
+// resolveVars returns the values of the variables of the configuration. Scripts are only run if runScripts is true:
+// otherwise, the value of a variable that is computed by a script is empty.
+func (cfg *ProjectConfig) resolveVars(projectDir string, runScripts bool) (map[string]string, error) {
+	vars := make(map[string]string, len(cfg.Vars))
+	for name, varCfg := range cfg.Vars {
+		val, err := (*VarConfig)(&varCfg).value(projectDir, cfg.ScriptIncludes, runScripts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid variable %q", name)
+		}
+		vars[name] = val
+	}
+	return vars, nil
+}
+
+func (cfg *VarConfig) value(projectDir, scriptIncludes string, runScript bool) (string, error) {
+	numSources := 0
+	for _, source := range []*string{cfg.Value, cfg.Env, cfg.Script} {
+		if source != nil {
+			numSources++
+		}
+	}
+	if numSources != 1 {
+		return "", errors.Errorf("exactly one of value, env or script must be specified")
+	}
+	if cfg.Default != nil && cfg.Env == nil {
+		return "", errors.Errorf("default can only be specified with env")
+	}
+
+	switch {
+	case cfg.Value != nil:
+		return *cfg.Value, nil
+	case cfg.Env != nil:
+		if val := os.Getenv(*cfg.Env); val != "" {
+			return val, nil
+		}
+		return getConfigStringValue(cfg.Default, nil, ""), nil
+	case !runScript:
+		return "", nil
+	default:
+		outputBuf := &bytes.Buffer{}
+		if err := distgo.WriteAndExecuteScript(distgo.ProjectInfo{ProjectDir: projectDir}, distgo.CreateScriptContent(*cfg.Script, scriptIncludes), nil, outputBuf); err != nil {
+			return "", errors.Wrapf(err, "failed to execute script: %s", outputBuf.String())
+		}
+		return strings.TrimSpace(outputBuf.String()), nil
+	}
+}
+
+// collectTemplates appends all of the string values in the provided YAML value that contain template actions to out.
+// Scripts are ignored.
+func collectTemplates(in any, out []string) []string {
+	switch val := in.(type) {
+	case yaml.MapSlice:
+		for _, item := range val {
+			if isScriptKey(item.Key) {
+				continue
+			}
+			out = collectTemplates(item.Value, out)
+		}
+	case []any:
+		for _, elem := range val {
+			out = collectTemplates(elem, out)
+		}
+	case string:
+		if strings.Contains(val, "{{") {
+			out = append(out, val)
+		}
+	}
+	return out
+}
+
+func renderTemplates(path string, in any, fns []distgo.TemplateFunction) (any, error) {
+	switch val := in.(type) {
+	case yaml.MapSlice:
+		out := make(yaml.MapSlice, len(val))
+		for i, item := range val {
+			if isScriptKey(item.Key) {
+				out[i] = item
+				continue
+			}
+			rendered, err := renderTemplates(joinConfigPath(path, fmt.Sprint(item.Key)), item.Value, fns)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = yaml.MapItem{Key: item.Key, Value: rendered}
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(val))
+		for i, elem := range val {
+			rendered, err := renderTemplates(fmt.Sprintf("%s[%d]", path, i), elem, fns)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	case string:
+		rendered, err := distgo.PartiallyRenderTemplate(val, fns...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render %s", path)
+		}
+		return rendered, nil
+	default:
+		return in, nil
+	}
+}
+
+func isScriptKey(key any) bool {
+	keyStr, ok := key.(string)
+	return ok && slices.Contains(scriptKeys, keyStr)
+}
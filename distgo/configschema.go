@@ -0,0 +1,23 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo
+
+// ConfigSchemaProvider provides the JSON Schema for the YAML configuration of the types created by a factory. It is
+// implemented by the DisterFactory, DockerBuilderFactory and PublisherFactory implementations provided by distgo.
+type ConfigSchemaProvider interface {
+	// ConfigSchema returns the JSON Schema for the configuration of the provided type. Returns nil if the type is
+	// valid but does not provide a schema, in which case any configuration is accepted.
+	ConfigSchema(typeName string) ([]byte, error)
+}
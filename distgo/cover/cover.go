@@ -0,0 +1,65 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cover
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Merge merges the coverage data that coverage-instrumented executables wrote to the GOCOVERDIR directories provided
+// as inputDirs into outputDir and prints the coverage percentage of each package. If textProfile is non-empty, the
+// merged data is also written to it in the text format used by "go test -coverprofile" so that it can be used with
+// "go tool cover". The data is processed using "go tool covdata".
+func Merge(inputDirs []string, outputDir, textProfile string, stdout io.Writer) error {
+	if len(inputDirs) == 0 {
+		return errors.Errorf("at least one input directory must be specified")
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return errors.Wrapf(err, "failed to create output directory")
+	}
+	if _, err := runCovdata("merge", "-i="+strings.Join(inputDirs, ","), "-o="+outputDir); err != nil {
+		return errors.Wrapf(err, "failed to merge coverage data")
+	}
+	if textProfile != "" {
+		if _, err := runCovdata("textfmt", "-i="+outputDir, "-o="+textProfile); err != nil {
+			return errors.Wrapf(err, "failed to write text coverage profile")
+		}
+	}
+	percent, err := runCovdata("percent", "-i="+outputDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to summarize coverage data")
+	}
+	_, _ = fmt.Fprintf(stdout, "Merged coverage data from %s into %s\n", strings.Join(inputDirs, ", "), outputDir)
+	_, _ = fmt.Fprint(stdout, string(percent))
+	return nil
+}
+
+func runCovdata(args ...string) ([]byte, error) {
+	cmd := exec.Command("go", append([]string{"tool", "covdata"}, args...)...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
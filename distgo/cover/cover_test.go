@@ -0,0 +1,93 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cover_test
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/build"
+	"github.com/palantir/distgo/distgo/cover"
+	"github.com/palantir/godel/v2/pkg/osarch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMain = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+}
+`
+
+func TestCoverBuildAndMerge(t *testing.T) {
+	projectDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(projectDir, "main.go"), []byte(testMain), 0644)
+	require.NoError(t, err)
+
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: projectDir,
+		Version:    "1.0.0",
+	}
+	productParam := distgo.ProductParam{
+		ID:   "foo",
+		Name: "foo",
+		Build: &distgo.BuildParam{
+			NameTemplate: "{{Product}}",
+			MainPkg:      ".",
+			OutputDir:    "out/build",
+			OSArchs:      []osarch.OSArch{osarch.Current()},
+		},
+	}
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{
+		Cover: true,
+	}, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	// the coverage-instrumented executable is written to the cover output directory rather than the build one
+	assert.NoDirExists(t, filepath.Join(projectDir, "out", "build"))
+	executable := filepath.Join(projectDir, "out", "cover", "foo", "1.0.0", osarch.Current().String(), "foo")
+	require.FileExists(t, executable)
+
+	// run the executable twice to create coverage data in separate directories
+	var inputDirs []string
+	for range 2 {
+		coverDir := t.TempDir()
+		cmd := exec.Command(executable)
+		cmd.Env = append(os.Environ(), "GOCOVERDIR="+coverDir)
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, "Output: %s", string(output))
+		inputDirs = append(inputDirs, coverDir)
+	}
+
+	outputDir := filepath.Join(t.TempDir(), "merged")
+	textProfile := filepath.Join(t.TempDir(), "cover.out")
+	outBuf := &bytes.Buffer{}
+	err = cover.Merge(inputDirs, outputDir, textProfile, outBuf)
+	require.NoError(t, err)
+	assert.Regexp(t, `(?s)^Merged coverage data from .+ into .+/merged\n.*foo\s+coverage: 100\.0% of statements\n$`, outBuf.String())
+
+	textProfileContent, err := os.ReadFile(textProfile)
+	require.NoError(t, err)
+	assert.Contains(t, string(textProfileContent), "mode: set\n")
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo
+
+import (
+	"github.com/palantir/godel/v2/pkg/osarch"
+)
+
+// DarwinUniversal is the synthetic OS/Arch for macOS universal binaries. The build for it builds the executable for
+// every architecture returned by DarwinUniversalOSArchs and merges them into a single Mach-O universal binary.
+var DarwinUniversal = osarch.OSArch{OS: "darwin", Arch: "universal"}
+
+// DarwinUniversalOSArchs returns the OS/Archs whose executables are merged into a macOS universal binary.
+func DarwinUniversalOSArchs() []osarch.OSArch {
+	return []osarch.OSArch{
+		{OS: "darwin", Arch: "amd64"},
+		{OS: "darwin", Arch: "arm64"},
+	}
+}
+
+// TargetOSArchs returns the OS/Archs that the Go toolchain builds for the provided build OS/Arch: the architectures
+// returned by DarwinUniversalOSArchs for DarwinUniversal and the provided OS/Arch otherwise.
+func TargetOSArchs(osArch osarch.OSArch) []osarch.OSArch {
+	if osArch == DarwinUniversal {
+		return DarwinUniversalOSArchs()
+	}
+	return []osarch.OSArch{osArch}
+}
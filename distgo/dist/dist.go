@@ -153,6 +153,11 @@ func Run(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, dryRu
 				}
 			}
 
+			// copy input Docker exports
+			if err := copyInputDockerExports(productTaskOutputInfo, currDistParam.InputDockerExports, distWorkDir); err != nil {
+				return errors.Wrapf(err, "failed to copy input Docker exports")
+			}
+
 			// run dist task
 			runDistOutput, err := currDistParam.Dister.RunDist(currDistID, productTaskOutputInfo)
 			if err != nil {
@@ -225,6 +230,32 @@ func copyInputDir(inputDir string, exclude matcher.Matcher, dstDir string) error
 	return nil
 }
 
+// copyInputDockerExports copies the archives exported for the Docker configurations specified by productDockerIDs,
+// which must be in expanded form, into dstDir.
+func copyInputDockerExports(productTaskOutputInfo distgo.ProductTaskOutputInfo, productDockerIDs []distgo.ProductDockerID, dstDir string) error {
+	for _, exportPath := range inputDockerExportPaths(productTaskOutputInfo, productDockerIDs) {
+		if _, err := os.Stat(exportPath); err != nil {
+			return errors.Wrapf(err, "Docker export %s does not exist: run \"docker build\" for the image before creating the distribution", exportPath)
+		}
+		if _, err := shutil.Copy(exportPath, path.Join(dstDir, path.Base(exportPath)), false); err != nil {
+			return errors.Wrapf(err, "failed to copy Docker export %s", exportPath)
+		}
+	}
+	return nil
+}
+
+// inputDockerExportPaths returns the paths of the archives exported for the Docker configurations specified by
+// productDockerIDs, which must be in expanded form.
+func inputDockerExportPaths(productTaskOutputInfo distgo.ProductTaskOutputInfo, productDockerIDs []distgo.ProductDockerID) []string {
+	var exportPaths []string
+	allOutputInfos := productTaskOutputInfo.AllProductOutputInfosMap()
+	for _, productDockerID := range productDockerIDs {
+		productID, dockerID, _ := productDockerID.Parse()
+		exportPaths = append(exportPaths, distgo.ProductDockerExportArtifactPaths(productTaskOutputInfo.Project, allOutputInfos[productID])[dockerID]...)
+	}
+	return exportPaths
+}
+
 func outputArtifactDisplayPaths(in []string) []string {
 	if in == nil {
 		return nil
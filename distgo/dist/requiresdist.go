@@ -29,6 +29,7 @@ import (
 //   - The product's dist configuration (as specified by configModTime) is more recent than any of its dist artifacts
 //   - The product has dependencies and any of the dependent build or dist artifacts are newer (have a later
 //     modification date) than any of the dist artifacts for the provided product
+//   - Any of the Docker exports that are inputs to the dist are newer than any of the dist artifacts
 //   - The product does not define a dist configuration
 //
 // Returns nil if all of the outputs exist and are up-to-date.
@@ -82,7 +83,12 @@ func disterRequiresDist(distID distgo.DistID, productTaskOutputInfo distgo.Produ
 	}
 
 	// if the newest build artifact is more recent than the oldest dist, consider dist out-of-date
-	if newestBuildArtifactForDist := newestArtifactModTime(buildArtifactPaths(productTaskOutputInfo.Project, productTaskOutputInfo.Product)); newestBuildArtifactForDist != nil && newestBuildArtifactForDist.Truncate(time.Second).After(oldestDistTime.Truncate(time.Second)) {
+	if newestBuildArtifactForDist := newestArtifactModTime(buildArtifactPaths(productTaskOutputInfo.Project, productTaskOutputInfo.Product, productTaskOutputInfo.Product.DistOutputInfos.DistInfos[distID].BuildVariant)); newestBuildArtifactForDist != nil && newestBuildArtifactForDist.Truncate(time.Second).After(oldestDistTime.Truncate(time.Second)) {
+		return true
+	}
+
+	// if any input Docker export is more recent than the oldest dist, consider dist out-of-date
+	if newestDockerExportForDist := newestArtifactModTime(inputDockerExportPaths(productTaskOutputInfo, productTaskOutputInfo.Product.DistOutputInfos.DistInfos[distID].InputDockerExports)); newestDockerExportForDist != nil && newestDockerExportForDist.Truncate(time.Second).After(oldestDistTime.Truncate(time.Second)) {
 		return true
 	}
 
@@ -96,7 +102,7 @@ func disterRequiresDist(distID distgo.DistID, productTaskOutputInfo distgo.Produ
 	// if any dependent artifact (build or dist) is newer than the oldest dist artifact, consider dist artifact out-of-date
 	for _, depProductOutputInfo := range productTaskOutputInfo.Deps {
 		newestDependencyTime := newestArtifactModTime(append(
-			buildArtifactPaths(productTaskOutputInfo.Project, depProductOutputInfo),
+			buildArtifactPaths(productTaskOutputInfo.Project, depProductOutputInfo, ""),
 			distArtifactPaths(productTaskOutputInfo.Project, distID, depProductOutputInfo)...,
 		))
 		if newestDependencyTime != nil && newestDependencyTime.After(oldestDistTime) {
@@ -106,12 +112,12 @@ func disterRequiresDist(distID distgo.DistID, productTaskOutputInfo distgo.Produ
 	return false
 }
 
-func buildArtifactPaths(projectInfo distgo.ProjectInfo, productInfo distgo.ProductOutputInfo) []string {
+func buildArtifactPaths(projectInfo distgo.ProjectInfo, productInfo distgo.ProductOutputInfo, variant distgo.BuildVariantID) []string {
 	if productInfo.BuildOutputInfo == nil {
 		return nil
 	}
 	var artifacts []string
-	for _, v := range distgo.ProductBuildArtifactPaths(projectInfo, productInfo) {
+	for _, v := range distgo.ProductBuildVariantArtifactPaths(projectInfo, productInfo, variant) {
 		artifacts = append(artifacts, v)
 	}
 	return artifacts
@@ -15,26 +15,33 @@
 package docker
 
 import (
-	"context"
 	"fmt"
 	"io"
 	"maps"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"sort"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/distgo/distgo/build"
 	"github.com/palantir/distgo/distgo/dist"
+	"github.com/palantir/distgo/internal/syncwriter"
 	"github.com/palantir/godel/v2/pkg/osarch"
-	"github.com/palantir/pkg/signals"
 	"github.com/pkg/errors"
 )
 
-func BuildProducts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, configModTime *time.Time, productDockerIDs []distgo.ProductDockerID, tagKeys []string, verbose, dryRun bool, stdout io.Writer) error {
+// BuildProducts builds the Docker images for the products specified by productDockerIDs (or for all products if none
+// are specified), first running the build and dist tasks for any products whose outputs are required and out-of-date.
+// Images are built in the topological order of the product dependency graph. If parallel is true, Docker images that
+// do not depend on each other are built concurrently: an image starts building as soon as the images of all of the
+// products its product depends on have been built. At most concurrency images are built at the same time; if
+// concurrency is not positive, the number of logical processors reported by Go is used.
+func BuildProducts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, configModTime *time.Time, productDockerIDs []distgo.ProductDockerID, tagKeys []string, verbose, dryRun, parallel bool, concurrency int, stdout io.Writer) error {
 	// determine products that match specified productDockerIDs
 	productParams, err := distgo.ProductParamsForDockerProductArgs(projectParam.Products, productDockerIDs...)
 	if err != nil {
@@ -69,8 +76,9 @@ func BuildProducts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectPa
 	for _, currProductParam := range productParams {
 		productDistIDs = append(productDistIDs, distgo.ProductDistID(currProductParam.ID))
 	}
-	// run dist for products that require dist artifact generation
-	if err := dist.Products(projectInfo, projectParam, configModTime, productDistIDs, dryRun, true, stdout); err != nil {
+	// run dist for products that require dist artifact generation. Dists that take Docker exports as input consume the
+	// output of this task rather than providing input to it, so they are left for a subsequent "dist".
+	if err := dist.Products(projectInfo, withoutDockerExportInputDists(projectParam), configModTime, productDistIDs, dryRun, true, stdout); err != nil {
 		return err
 	}
 
@@ -90,6 +98,9 @@ func BuildProducts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectPa
 	if err != nil {
 		return err
 	}
+	if parallel {
+		return runBuildsInParallel(projectInfo, targetProducts, topoOrderedIDs, concurrency, verbose, dryRun, stdout)
+	}
 	for _, currID := range topoOrderedIDs {
 		currProduct := targetProducts[currID]
 		if err := RunBuild(projectInfo, currProduct, verbose, dryRun, stdout); err != nil {
@@ -103,9 +114,51 @@ func BuildProducts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectPa
 // dependent products for the provided product must already exist, and the dist outputs for the current product and all
 // of its dependent products must also exist in the proper locations.
 func RunBuild(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, verbose, dryRun bool, stdout io.Writer) error {
+	units, err := dockerBuildUnitsForProduct(projectInfo, productParam, dryRun, stdout)
+	if err != nil {
+		return err
+	}
+	for _, unit := range units {
+		if err := unit.run(projectInfo, verbose, dryRun, stdout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dockerBuildUnit is a single Docker image build: the build for one DockerID of one product.
+type dockerBuildUnit struct {
+	productID             distgo.ProductID
+	productName           string
+	dockerID              distgo.DockerID
+	dockerBuilderParam    distgo.DockerBuilderParam
+	productTaskOutputInfo distgo.ProductTaskOutputInfo
+	buildArtifactPaths    map[distgo.ProductID]map[osarch.OSArch]string
+	distArtifactPaths     map[distgo.ProductID]map[distgo.DistID][]string
+}
+
+func (u dockerBuildUnit) run(projectInfo distgo.ProjectInfo, verbose, dryRun bool, stdout io.Writer) error {
+	return runSingleDockerBuild(
+		projectInfo,
+		u.productID,
+		u.productName,
+		u.dockerID,
+		u.dockerBuilderParam,
+		u.productTaskOutputInfo,
+		u.buildArtifactPaths,
+		u.distArtifactPaths,
+		verbose,
+		dryRun,
+		stdout,
+	)
+}
+
+// dockerBuildUnitsForProduct returns the Docker build units for the provided product sorted by DockerID. Returns an
+// empty slice (after printing that the product is skipped) if the product does not declare Docker outputs.
+func dockerBuildUnitsForProduct(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, dryRun bool, stdout io.Writer) ([]dockerBuildUnit, error) {
 	if productParam.Docker == nil {
 		distgo.PrintlnOrDryRunPrintln(stdout, fmt.Sprintf("%s does not have Docker outputs; skipping build", productParam.ID), dryRun)
-		return nil
+		return nil, nil
 	}
 
 	var dockerIDs []distgo.DockerID
@@ -116,30 +169,109 @@ func RunBuild(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam,
 
 	productTaskOutputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, productParam)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	allBuildArtifactPaths := productTaskOutputInfo.ProductDockerBuildArtifactPaths()
 	allDistArtifactPaths := productTaskOutputInfo.ProductDockerDistArtifactPaths()
 
+	var units []dockerBuildUnit
 	for _, dockerID := range dockerIDs {
-		if err := runSingleDockerBuild(
-			projectInfo,
-			productParam.ID,
-			productParam.Name,
-			dockerID,
-			productParam.Docker.DockerBuilderParams[dockerID],
-			productTaskOutputInfo,
-			allBuildArtifactPaths[dockerID],
-			allDistArtifactPaths[dockerID],
-			verbose,
-			dryRun,
-			stdout,
-		); err != nil {
-			return err
+		units = append(units, dockerBuildUnit{
+			productID:             productParam.ID,
+			productName:           productParam.Name,
+			dockerID:              dockerID,
+			dockerBuilderParam:    productParam.Docker.DockerBuilderParams[dockerID],
+			productTaskOutputInfo: productTaskOutputInfo,
+			buildArtifactPaths:    allBuildArtifactPaths[dockerID],
+			distArtifactPaths:     allDistArtifactPaths[dockerID],
+		})
+	}
+	return units, nil
+}
+
+// runBuildsInParallel runs the Docker builds for the products in targetProducts concurrently with concurrency workers
+// (or with one worker per logical processor reported by Go if concurrency is not positive). Each (Product, DockerID)
+// pair is an individual unit of work, and a unit is not started until all of the units of the products its product
+// depends on have completed. If any build returns an error, the first error is returned once the builds that are
+// already running finish (and any builds that have not started will not be started).
+func runBuildsInParallel(projectInfo distgo.ProjectInfo, targetProducts map[distgo.ProductID]distgo.ProductParam, topoOrderedIDs []distgo.ProductID, concurrency int, verbose, dryRun bool, stdout io.Writer) error {
+	stdout = syncwriter.New(stdout)
+
+	productDone := make(map[distgo.ProductID]chan struct{})
+	for _, productID := range topoOrderedIDs {
+		productDone[productID] = make(chan struct{})
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	failed := make(chan struct{})
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			close(failed)
+		})
+	}
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	workers := make(chan struct{}, concurrency)
+
+	for _, productID := range topoOrderedIDs {
+		productParam := targetProducts[productID]
+		units, err := dockerBuildUnitsForProduct(projectInfo, productParam, dryRun, stdout)
+		if err != nil {
+			fail(err)
+			break
+		}
+
+		var productWG sync.WaitGroup
+		for _, unit := range units {
+			wg.Add(1)
+			productWG.Add(1)
+			go func() {
+				defer wg.Done()
+				defer productWG.Done()
+				for _, depID := range productParam.FirstLevelDependencies {
+					depDone, ok := productDone[depID]
+					if !ok {
+						continue
+					}
+					select {
+					case <-depDone:
+					case <-failed:
+						return
+					}
+				}
+				select {
+				case workers <- struct{}{}:
+				case <-failed:
+					return
+				}
+				defer func() {
+					<-workers
+				}()
+				// a build may have failed while this unit was waiting for a worker
+				select {
+				case <-failed:
+					return
+				default:
+				}
+				if err := unit.run(projectInfo, verbose, dryRun, stdout); err != nil {
+					fail(err)
+				}
+			}()
 		}
+		go func() {
+			productWG.Wait()
+			close(productDone[productID])
+		}()
 	}
-	return nil
+	wg.Wait()
+	return firstErr
 }
 
 func runSingleDockerBuild(
@@ -158,13 +290,18 @@ func runSingleDockerBuild(
 		// link build artifacts into context directory
 		for productID, valMap := range buildArtifactPaths {
 			currOutputInfo := productTaskOutputInfo.AllProductOutputInfosMap()[productID]
-			buildArtifactSrcPaths := distgo.ProductBuildArtifactPaths(projectInfo, currOutputInfo)
+			inputBuildVariants := productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID].InputBuildVariants[productID]
 			for osArch, buildArtifactDstPath := range valMap {
 				if err := os.MkdirAll(path.Dir(buildArtifactDstPath), 0755); err != nil {
 					return errors.Wrapf(err, "failed to create directories")
 				}
-				if err := createNewHardLink(buildArtifactSrcPaths[osArch], buildArtifactDstPath); err != nil {
-					return errors.Wrapf(err, "failed to link build artifact into context directory")
+				// the builds of library build modes create multiple files, which are all linked next to the primary
+				// artifact
+				buildArtifactSrcPaths := distgo.ProductBuildArtifactFilePaths(projectInfo, currOutputInfo, inputBuildVariants[distgo.OSArchID(osArch.String())])[osArch]
+				for _, buildArtifactSrcPath := range buildArtifactSrcPaths {
+					if err := createNewHardLink(buildArtifactSrcPath, path.Join(path.Dir(buildArtifactDstPath), path.Base(buildArtifactSrcPath))); err != nil {
+						return errors.Wrapf(err, "failed to link build artifact into context directory")
+					}
 				}
 			}
 		}
@@ -213,28 +350,33 @@ func runSingleDockerBuild(
 			}
 		}
 		if renderedDockerfile != string(originalDockerfileBytes) {
-			// Dockerfile contained templates and rendering them changes file: overwrite file with rendered version
-			// and restore afterwards
-			if err := os.WriteFile(dockerfilePath, []byte(renderedDockerfile), 0644); err != nil {
-				return errors.Wrapf(err, "failed to write rendered Dockerfile")
+			// Dockerfile contained templates and rendering them changes file: the builder must build from the rendered
+			// file in the staging directory since the original file is never modified
+			stagedDockerfile, err := supportsStagedDockerfile(dockerBuilderParam.DockerBuilder)
+			if err != nil {
+				return errors.Wrapf(err, "failed to determine whether the Docker builder for configuration %s of product %s supports staged Dockerfiles", dockerID, productID)
 			}
+			if !stagedDockerfile {
+				return errors.Errorf("rendering the templates in Dockerfile %s for configuration %s of product %s changes its content, but its Docker builder does not support building from a rendered Dockerfile: update the Docker builder or set disable-template-rendering", dockerBuilderParam.DockerfilePath, dockerID, productID)
+			}
+		}
 
-			cleanupCtx, cancel := signals.ContextWithShutdown(context.Background())
-			cleanupDone := make(chan struct{})
-			defer func() {
-				cancel()
-				<-cleanupDone
-			}()
-			go func() {
-				select {
-				case <-cleanupCtx.Done():
-					if err := os.WriteFile(dockerfilePath, originalDockerfileBytes, 0644); err != nil && rErr == nil {
-						rErr = errors.Wrapf(err, "failed to restore original Dockerfile content")
-					}
-				}
-				cleanupDone <- struct{}{}
-			}()
+		// write the rendered Dockerfile to a staging directory for this build and provide its path to the builder,
+		// leaving the original file untouched
+		stagingDir, err := os.MkdirTemp("", fmt.Sprintf("distgo-docker-%s-%s-", productID, dockerID))
+		if err != nil {
+			return errors.Wrapf(err, "failed to create staging directory for rendered Dockerfile")
+		}
+		defer func() {
+			if err := os.RemoveAll(stagingDir); err != nil && rErr == nil {
+				rErr = errors.Wrapf(err, "failed to remove staging directory %s", stagingDir)
+			}
+		}()
+		renderedDockerfilePath, err := writeRenderedDockerfile(stagingDir, dockerfilePath, renderedDockerfile)
+		if err != nil {
+			return err
 		}
+		productTaskOutputInfo = withRenderedDockerfilePath(productTaskOutputInfo, dockerID, renderedDockerfilePath)
 	}
 
 	if !dryRun {
@@ -261,9 +403,59 @@ func runSingleDockerBuild(
 			}
 		}
 	}
+
+	if dockerBuilderParam.Export != nil {
+		if err := runDockerExport(productID, dockerID, *dockerBuilderParam.Export, productTaskOutputInfo, dryRun, stdout); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// supportsStagedDockerfile returns true if dockerBuilder builds from DockerBuilderOutputInfo.RenderedDockerfilePath.
+func supportsStagedDockerfile(dockerBuilder distgo.DockerBuilder) (bool, error) {
+	stagedBuilder, ok := dockerBuilder.(distgo.StagedDockerfileDockerBuilder)
+	if !ok {
+		return false, nil
+	}
+	return stagedBuilder.SupportsStagedDockerfile()
+}
+
+// writeRenderedDockerfile writes the rendered content of the Dockerfile at dockerfilePath into stagingDir and returns
+// the path of the written file. The file keeps the base name of the original, and a Dockerfile-specific ignore file
+// ("<Dockerfile>.dockerignore") next to the original is copied alongside it, since builders look for that file next to
+// the Dockerfile they are given.
+func writeRenderedDockerfile(stagingDir, dockerfilePath, renderedDockerfile string) (string, error) {
+	renderedDockerfilePath := filepath.Join(stagingDir, filepath.Base(dockerfilePath))
+	if err := os.WriteFile(renderedDockerfilePath, []byte(renderedDockerfile), 0644); err != nil {
+		return "", errors.Wrapf(err, "failed to write rendered Dockerfile")
+	}
+	dockerignoreBytes, err := os.ReadFile(dockerfilePath + ".dockerignore")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return renderedDockerfilePath, nil
+		}
+		return "", errors.Wrapf(err, "failed to read Dockerfile-specific ignore file")
+	}
+	if err := os.WriteFile(renderedDockerfilePath+".dockerignore", dockerignoreBytes, 0644); err != nil {
+		return "", errors.Wrapf(err, "failed to write Dockerfile-specific ignore file")
+	}
+	return renderedDockerfilePath, nil
+}
+
+// withRenderedDockerfilePath returns a copy of productTaskOutputInfo in which the output info for the given DockerID
+// records renderedDockerfilePath. The Docker output infos are copied rather than modified in place because they are
+// shared by all of the DockerIDs of the product, which may be built concurrently.
+func withRenderedDockerfilePath(productTaskOutputInfo distgo.ProductTaskOutputInfo, dockerID distgo.DockerID, renderedDockerfilePath string) distgo.ProductTaskOutputInfo {
+	dockerOutputInfos := *productTaskOutputInfo.Product.DockerOutputInfos
+	dockerOutputInfos.DockerBuilderOutputInfos = maps.Clone(dockerOutputInfos.DockerBuilderOutputInfos)
+	dockerBuilderOutputInfo := dockerOutputInfos.DockerBuilderOutputInfos[dockerID]
+	dockerBuilderOutputInfo.RenderedDockerfilePath = renderedDockerfilePath
+	dockerOutputInfos.DockerBuilderOutputInfos[dockerID] = dockerBuilderOutputInfo
+	productTaskOutputInfo.Product.DockerOutputInfos = &dockerOutputInfos
+	return productTaskOutputInfo
+}
+
 // removeLegacyOCIOutput removes any OCI layout left in an output location this build will not write to. Nothing
 // migrated those layouts when the Docker output directory was introduced, so leaving one in place lets "docker push"
 // publish an image from an earlier build at the same version. Only a directory holding an OCI layout is removed, so a
@@ -377,14 +569,26 @@ func getDockerBuilderOutputInfo(primaryProductID distgo.ProductID, allOutputInfo
 	return dockerBuilderOutput, nil
 }
 
+// createNewHardLink creates a hard link to src at dst, replacing any file that exists at dst. The link is created at a
+// temporary path and renamed into place so that builds sharing a context directory can link the same input
+// concurrently.
 func createNewHardLink(src, dst string) error {
-	if _, err := os.Stat(dst); err == nil {
-		// ensure the target does not exists before creating a new one
-		if err := os.Remove(dst); err != nil {
-			return errors.Wrapf(err, "failed to remove existing file")
-		}
+	tmpFile, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+"-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary file")
+	}
+	tmpPath := tmpFile.Name()
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close temporary file")
+	}
+	if err := os.Remove(tmpPath); err != nil {
+		return errors.Wrapf(err, "failed to remove temporary file")
+	}
+	if err := os.Link(src, tmpPath); err != nil {
+		return errors.Wrapf(err, "failed to create hard link %s from %s", dst, src)
 	}
-	if err := os.Link(src, dst); err != nil {
+	if err := os.Rename(tmpPath, dst); err != nil {
+		_ = os.Remove(tmpPath)
 		return errors.Wrapf(err, "failed to create hard link %s from %s", dst, src)
 	}
 	return nil
@@ -63,8 +63,7 @@ func (b *printDockerfileDockerBuilder) TypeName() (string, error) {
 }
 
 func (b *printDockerfileDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo, verbose, dryRun bool, stdout io.Writer) error {
-	dockerBuilderOutputInfo := productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID]
-	fullDockerfilePath := path.Join(productTaskOutputInfo.Project.ProjectDir, dockerBuilderOutputInfo.ContextDir, dockerBuilderOutputInfo.DockerfilePath)
+	fullDockerfilePath := productTaskOutputInfo.ProductDockerfilePath(dockerID)
 	bytes, err := os.ReadFile(fullDockerfilePath)
 	if err != nil {
 		return errors.Wrapf(err, "failed to read Dockerfile at %s", fullDockerfilePath)
@@ -73,6 +72,62 @@ func (b *printDockerfileDockerBuilder) RunDockerBuild(dockerID distgo.DockerID,
 	return nil
 }
 
+func (b *printDockerfileDockerBuilder) SupportsStagedDockerfile() (bool, error) {
+	return true, nil
+}
+
+const stagedDockerfileDockerBuilderTypeName = "staged-dockerfile"
+
+func newStagedDockerfileBuilder(cfgYML []byte) (distgo.DockerBuilder, error) {
+	return &stagedDockerfileDockerBuilder{}, nil
+}
+
+// stagedDockerfileDockerBuilder prints the Dockerfile it builds from and the Dockerfile in the context directory.
+type stagedDockerfileDockerBuilder struct {
+	printDockerfileDockerBuilder
+}
+
+func (b *stagedDockerfileDockerBuilder) TypeName() (string, error) {
+	return stagedDockerfileDockerBuilderTypeName, nil
+}
+
+func (b *stagedDockerfileDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo, verbose, dryRun bool, stdout io.Writer) error {
+	if err := b.printDockerfileDockerBuilder.RunDockerBuild(dockerID, productTaskOutputInfo, verbose, dryRun, stdout); err != nil {
+		return err
+	}
+	dockerOutputInfo := productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID]
+	bytes, err := os.ReadFile(path.Join(productTaskOutputInfo.Project.ProjectDir, dockerOutputInfo.ContextDir, dockerOutputInfo.DockerfilePath))
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(stdout, string(bytes))
+	return nil
+}
+
+const unstagedDockerfileDockerBuilderTypeName = "unstaged-dockerfile"
+
+func newUnstagedDockerfileBuilder(cfgYML []byte) (distgo.DockerBuilder, error) {
+	return &unstagedDockerfileDockerBuilder{}, nil
+}
+
+// unstagedDockerfileDockerBuilder prints the Dockerfile in the context directory and does not implement
+// distgo.StagedDockerfileDockerBuilder.
+type unstagedDockerfileDockerBuilder struct{}
+
+func (b *unstagedDockerfileDockerBuilder) TypeName() (string, error) {
+	return unstagedDockerfileDockerBuilderTypeName, nil
+}
+
+func (b *unstagedDockerfileDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo, verbose, dryRun bool, stdout io.Writer) error {
+	dockerOutputInfo := productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID]
+	bytes, err := os.ReadFile(path.Join(productTaskOutputInfo.Project.ProjectDir, dockerOutputInfo.ContextDir, dockerOutputInfo.DockerfilePath))
+	if err != nil {
+		return err
+	}
+	_, _ = fmt.Fprint(stdout, string(bytes))
+	return nil
+}
+
 func TestDockerBuild(t *testing.T) {
 	tmp, cleanup, err := dirs.TempDir("", "")
 	defer cleanup()
@@ -792,7 +847,7 @@ RUN echo 'Tags for foo: {{Tags "foo" "print-dockerfile"}}'
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 
 			buffer := &bytes.Buffer{}
-			err = docker.BuildProducts(projectInfo, projectParam, &preDistTime, nil, tc.tagKeys, false, false, buffer)
+			err = docker.BuildProducts(projectInfo, projectParam, &preDistTime, nil, tc.tagKeys, false, false, false, 0, buffer)
 			if tc.wantErrorRegexp == "" {
 				require.NoError(t, err, "Case %d: %s", i, tc.name)
 			} else {
@@ -812,6 +867,188 @@ RUN echo 'Tags for foo: {{Tags "foo" "print-dockerfile"}}'
 	}
 }
 
+func TestDockerBuildParallel(t *testing.T) {
+	projectDir, cleanup, err := dirs.TempDir("", "")
+	defer cleanup()
+	require.NoError(t, err)
+
+	gittest.InitGitDir(t, projectDir)
+	for _, currProduct := range []string{"foo", "bar"} {
+		contextDir := path.Join(projectDir, currProduct+"-docker")
+		err = os.Mkdir(contextDir, 0755)
+		require.NoError(t, err)
+		err = os.WriteFile(path.Join(contextDir, "Dockerfile"), []byte(`FROM {{if eq Product "bar"}}{{Tag "foo" "print-dockerfile" "default"}}{{else}}alpine:3.5{{end}}
+`), 0644)
+		require.NoError(t, err)
+	}
+	err = os.WriteFile(path.Join(projectDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	gittest.CommitAllFiles(t, projectDir, "Commit files")
+	gittest.CreateGitTag(t, projectDir, "0.1.0")
+
+	dockerConfig := func(product string) *distgoconfig.DockerConfig {
+		return &distgoconfig.DockerConfig{
+			DockerBuildersConfig: distgoconfig.ToDockerBuildersConfig(&distgoconfig.DockerBuildersConfig{
+				printDockerfileDockerBuilderTypeName: distgoconfig.ToDockerBuilderConfig(distgoconfig.DockerBuilderConfig{
+					Type:       new(printDockerfileDockerBuilderTypeName),
+					ContextDir: new(product + "-docker"),
+					TagTemplates: distgoconfig.ToTagTemplatesMap(mustTagTemplatesMap(
+						"default", product+":latest",
+					)),
+				}),
+			}),
+		}
+	}
+	projectCfg := distgoconfig.ProjectConfig{
+		Products: distgoconfig.ToProductsMap(map[distgo.ProductID]distgoconfig.ProductConfig{
+			"bar": {
+				Docker: distgoconfig.ToDockerConfig(dockerConfig("bar")),
+				Dependencies: &[]distgo.ProductID{
+					"foo",
+				},
+			},
+			"foo": {
+				Docker: distgoconfig.ToDockerConfig(dockerConfig("foo")),
+			},
+		}),
+	}
+
+	projectVersionerFactory, err := projectversionerfactory.New(nil, nil)
+	require.NoError(t, err)
+	disterFactory, err := disterfactory.New(nil, nil)
+	require.NoError(t, err)
+	defaultDisterCfg, err := disterfactory.DefaultConfig()
+	require.NoError(t, err)
+	dockerBuilderFactory, err := dockerbuilderfactory.New([]dockerbuilder.Creator{dockerbuilder.NewCreator(printDockerfileDockerBuilderTypeName, newPrintDockerfileBuilder)}, nil)
+	require.NoError(t, err)
+	publisherFactory, err := publisherfactory.New(nil, nil)
+	require.NoError(t, err)
+
+	projectParam, err := projectCfg.ToParam(projectDir, projectVersionerFactory, disterFactory, defaultDisterCfg, dockerBuilderFactory, publisherFactory)
+	require.NoError(t, err)
+	projectInfo, err := projectParam.ProjectInfo(projectDir)
+	require.NoError(t, err)
+
+	buffer := &bytes.Buffer{}
+	err = docker.BuildProducts(projectInfo, projectParam, nil, nil, nil, false, false, true, 0, buffer)
+	require.NoError(t, err)
+
+	// bar depends on foo, so its build does not start until the build for foo has finished
+	assert.Equal(t, `Running Docker build for configuration print-dockerfile of product foo...
+FROM alpine:3.5
+Running Docker build for configuration print-dockerfile of product bar...
+FROM foo:latest
+`, buffer.String())
+
+	bytes, err := os.ReadFile(path.Join(projectDir, "bar-docker", "Dockerfile"))
+	require.NoError(t, err)
+	assert.Equal(t, `FROM {{if eq Product "bar"}}{{Tag "foo" "print-dockerfile" "default"}}{{else}}alpine:3.5{{end}}
+`, string(bytes))
+}
+
+func TestDockerBuildRenderedDockerfile(t *testing.T) {
+	for _, tc := range []struct {
+		name            string
+		builderType     string
+		dockerfile      string
+		wantErrorRegexp string
+		wantStdout      string
+	}{
+		{
+			"Dockerfile is staged for a builder that supports staged Dockerfiles",
+			stagedDockerfileDockerBuilderTypeName,
+			"FROM {{Product}}:latest\n",
+			"",
+			`Running Docker build for configuration default of product foo...
+FROM foo:latest
+FROM {{Product}}:latest
+`,
+		},
+		{
+			"build fails if rendering changes the Dockerfile of a builder that does not support staged Dockerfiles",
+			unstagedDockerfileDockerBuilderTypeName,
+			"FROM {{Product}}:latest\n",
+			`^rendering the templates in Dockerfile Dockerfile for configuration default of product foo changes its content, but its Docker builder does not support building from a rendered Dockerfile: update the Docker builder or set disable-template-rendering$`,
+			"",
+		},
+		{
+			"Dockerfile without templates is built by a builder that does not support staged Dockerfiles",
+			unstagedDockerfileDockerBuilderTypeName,
+			"FROM alpine:3.5\n",
+			"",
+			`Running Docker build for configuration default of product foo...
+FROM alpine:3.5
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			gittest.InitGitDir(t, projectDir)
+			err := os.Mkdir(path.Join(projectDir, "docker"), 0755)
+			require.NoError(t, err)
+			err = os.WriteFile(path.Join(projectDir, "docker", "Dockerfile"), []byte(tc.dockerfile), 0644)
+			require.NoError(t, err)
+			err = os.WriteFile(path.Join(projectDir, "go.mod"), []byte("module foo"), 0644)
+			require.NoError(t, err)
+			gittest.CommitAllFiles(t, projectDir, "Commit files")
+			gittest.CreateGitTag(t, projectDir, "0.1.0")
+
+			projectCfg := distgoconfig.ProjectConfig{
+				Products: distgoconfig.ToProductsMap(map[distgo.ProductID]distgoconfig.ProductConfig{
+					"foo": {
+						Docker: distgoconfig.ToDockerConfig(&distgoconfig.DockerConfig{
+							DockerBuildersConfig: distgoconfig.ToDockerBuildersConfig(&distgoconfig.DockerBuildersConfig{
+								"default": distgoconfig.ToDockerBuilderConfig(distgoconfig.DockerBuilderConfig{
+									Type:       new(tc.builderType),
+									ContextDir: new("docker"),
+									TagTemplates: distgoconfig.ToTagTemplatesMap(mustTagTemplatesMap(
+										"default", "foo:latest",
+									)),
+								}),
+							}),
+						}),
+					},
+				}),
+			}
+
+			projectVersionerFactory, err := projectversionerfactory.New(nil, nil)
+			require.NoError(t, err)
+			disterFactory, err := disterfactory.New(nil, nil)
+			require.NoError(t, err)
+			defaultDisterCfg, err := disterfactory.DefaultConfig()
+			require.NoError(t, err)
+			dockerBuilderFactory, err := dockerbuilderfactory.New([]dockerbuilder.Creator{
+				dockerbuilder.NewCreator(printDockerfileDockerBuilderTypeName, newPrintDockerfileBuilder),
+				dockerbuilder.NewCreator(stagedDockerfileDockerBuilderTypeName, newStagedDockerfileBuilder),
+				dockerbuilder.NewCreator(unstagedDockerfileDockerBuilderTypeName, newUnstagedDockerfileBuilder),
+			}, nil)
+			require.NoError(t, err)
+			publisherFactory, err := publisherfactory.New(nil, nil)
+			require.NoError(t, err)
+
+			projectParam, err := projectCfg.ToParam(projectDir, projectVersionerFactory, disterFactory, defaultDisterCfg, dockerBuilderFactory, publisherFactory)
+			require.NoError(t, err)
+			projectInfo, err := projectParam.ProjectInfo(projectDir)
+			require.NoError(t, err)
+
+			buffer := &bytes.Buffer{}
+			err = docker.BuildProducts(projectInfo, projectParam, nil, nil, nil, false, false, false, 0, buffer)
+			if tc.wantErrorRegexp == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Regexp(t, regexp.MustCompile(tc.wantErrorRegexp), err.Error())
+			}
+			assert.Equal(t, tc.wantStdout, buffer.String())
+
+			// the Dockerfile is never modified
+			bytes, err := os.ReadFile(path.Join(projectDir, "docker", "Dockerfile"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.dockerfile, string(bytes))
+		})
+	}
+}
+
 func mustTagTemplatesMap(nameAndVal ...string) *distgoconfig.TagTemplatesMap {
 	out := &distgoconfig.TagTemplatesMap{
 		Templates: make(map[distgo.DockerTagID]string),
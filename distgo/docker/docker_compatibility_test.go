@@ -176,5 +176,5 @@ func TestLegacyBareManifestLayoutPush(t *testing.T) {
 	require.NoError(t, tarball.WriteToFile(filepath.Join(outputDir, "image.tar"), name.MustParseReference("product:1.0.0"), image))
 
 	require.Equal(t, outputDir, dockerOCIOutputDir(info, "legacy"))
-	require.NoError(t, runSingleDockerPush("product", "legacy", info, true, false, io.Discard))
+	require.NoError(t, runSingleDockerPush("product", "legacy", info, nil, true, false, io.Discard))
 }
@@ -0,0 +1,244 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/palantir/distgo/distgo"
+	"github.com/pkg/errors"
+)
+
+// runDockerExport writes the archives specified by the export parameter of the Docker configuration from the OCI layout
+// written by its DockerBuilder. The OCI layout must exist unless dryRun is true.
+func runDockerExport(productID distgo.ProductID, dockerID distgo.DockerID, export distgo.DockerExportParam, productTaskOutputInfo distgo.ProductTaskOutputInfo, dryRun bool, stdout io.Writer) error {
+	exportPaths := productTaskOutputInfo.ProductDockerExportArtifactPaths()[dockerID]
+	if len(exportPaths) == 0 {
+		return nil
+	}
+	distgo.PrintlnOrDryRunPrintln(stdout, fmt.Sprintf("Exporting image for configuration %s of product %s to %s", dockerID, productID, strings.Join(exportPaths, ", ")), dryRun)
+	if dryRun {
+		return nil
+	}
+
+	ociDir := dockerOCIOutputDir(productTaskOutputInfo, dockerID)
+	if ociDir == "" {
+		return errors.Errorf("configuration %s of product %s exports archives, but its DockerBuilder did not write an OCI layout", dockerID, productID)
+	}
+	renderedTags := productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID].RenderedTags
+	for _, exportPath := range exportPaths {
+		var writeFn func(w io.Writer) error
+		switch {
+		case strings.HasSuffix(exportPath, distgo.DockerArchiveExtension):
+			img, err := dockerArchiveImage(ociDir, export.DockerArchivePlatform)
+			if err != nil {
+				return errors.Wrapf(err, "failed to export Docker archive for configuration %s of product %s", dockerID, productID)
+			}
+			writeFn = func(w io.Writer) error {
+				return writeDockerArchive(w, img, renderedTags)
+			}
+		case strings.HasSuffix(exportPath, distgo.OCIArchiveExtension):
+			writeFn = func(w io.Writer) error {
+				return writeOCIArchive(w, ociDir)
+			}
+		default:
+			return errors.Errorf("unknown export archive type for %s", exportPath)
+		}
+		if err := writeFileAtomically(exportPath, writeFn); err != nil {
+			return errors.Wrapf(err, "failed to export image for configuration %s of product %s", dockerID, productID)
+		}
+	}
+	return nil
+}
+
+// dockerArchiveImage returns the image in the OCI layout at ociDir that is written to a Docker archive: the image for
+// the provided platform, or the only image in the layout if platform is empty.
+func dockerArchiveImage(ociDir, platform string) (v1.Image, error) {
+	images, err := imagesFromOCILayout(ociDir)
+	if err != nil {
+		return nil, err
+	}
+	if platform == "" {
+		if len(images) != 1 {
+			var platforms []string
+			for _, img := range images {
+				platforms = append(platforms, img.platform.String())
+			}
+			return nil, errors.Errorf("a Docker archive holds a single image per tag, but the OCI layout contains images for platforms %v: specify docker-archive-platform", platforms)
+		}
+		return images[0].image, nil
+	}
+	wantPlatform, err := v1.ParsePlatform(platform)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid platform %q", platform)
+	}
+	for _, img := range images {
+		if img.platform.Satisfies(*wantPlatform) {
+			return img.image, nil
+		}
+	}
+	return nil, errors.Errorf("the OCI layout does not contain an image for platform %s", platform)
+}
+
+// writeDockerArchive writes the provided image tagged with each of the provided tags in the format read by
+// "docker load".
+func writeDockerArchive(w io.Writer, img v1.Image, tags []string) error {
+	refToImage := make(map[name.Reference]v1.Image)
+	for _, tag := range tags {
+		ref, err := name.NewTag(tag)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse tag %s", tag)
+		}
+		refToImage[ref] = img
+	}
+	return tarball.MultiRefWrite(refToImage, w)
+}
+
+// writeOCIArchive writes a gzip-compressed tarball of the OCI layout at ociDir. If the layout contains the wrapper
+// written by WriteDockerBuildContextLayout, its index, which names the image by each of its rendered tags, is archived
+// as the index of the layout so that tools that import the archive can tag the image. The entries are written in a
+// fixed order without timestamps or ownership so that the same layout always produces the same archive.
+func writeOCIArchive(w io.Writer, ociDir string) (rErr error) {
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	defer func() {
+		if err := tw.Close(); err != nil && rErr == nil {
+			rErr = errors.Wrap(err, "failed to close tar writer")
+		}
+		if err := gzw.Close(); err != nil && rErr == nil {
+			rErr = errors.Wrap(err, "failed to close gzip writer")
+		}
+	}()
+
+	indexPath := filepath.Join(ociDir, distgo.DockerBuildContextLayoutSubdir, "index.json")
+	if _, err := os.Stat(indexPath); err != nil {
+		indexPath = filepath.Join(ociDir, "index.json")
+	}
+	if err := addFileToTar(tw, filepath.Join(ociDir, "oci-layout"), "oci-layout"); err != nil {
+		return err
+	}
+	if err := addFileToTar(tw, indexPath, "index.json"); err != nil {
+		return err
+	}
+	blobsDir := filepath.Join(ociDir, "blobs")
+	return filepath.WalkDir(blobsDir, func(currPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(ociDir, currPath)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     filepath.ToSlash(relPath) + "/",
+				Mode:     0755,
+			})
+		}
+		return addFileToTar(tw, currPath, filepath.ToSlash(relPath))
+	})
+}
+
+func addFileToTar(tw *tar.Writer, srcPath, name string) (rErr error) {
+	f, err := os.Open(srcPath)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open %s", srcPath)
+	}
+	defer func() {
+		if err := f.Close(); err != nil && rErr == nil {
+			rErr = errors.Wrapf(err, "failed to close %s", srcPath)
+		}
+	}()
+	fi, err := f.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "failed to stat %s", srcPath)
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0644,
+		Size:     fi.Size(),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to write tar header for %s", name)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return errors.Wrapf(err, "failed to write %s to archive", name)
+	}
+	return nil
+}
+
+// writeFileAtomically writes the file at dst using writeFn. The content is written to a temporary file in the same
+// directory that is renamed to dst once it is complete, so an interrupted export never leaves a truncated archive
+// behind.
+func writeFileAtomically(dst string, writeFn func(w io.Writer) error) (rErr error) {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory for %s", dst)
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+"-")
+	if err != nil {
+		return errors.Wrapf(err, "failed to create temporary file for %s", dst)
+	}
+	defer func() {
+		if rErr != nil {
+			_ = tmpFile.Close()
+			_ = os.Remove(tmpFile.Name())
+		}
+	}()
+	if err := writeFn(tmpFile); err != nil {
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close %s", tmpFile.Name())
+	}
+	if err := os.Chmod(tmpFile.Name(), 0644); err != nil {
+		return errors.Wrapf(err, "failed to set permissions of %s", tmpFile.Name())
+	}
+	if err := os.Rename(tmpFile.Name(), dst); err != nil {
+		return errors.Wrapf(err, "failed to write %s", dst)
+	}
+	return nil
+}
+
+// withoutDockerExportInputDists returns a copy of projectParam from which the dists that take Docker exports as input
+// are removed. The provided projectParam is not modified.
+func withoutDockerExportInputDists(projectParam distgo.ProjectParam) distgo.ProjectParam {
+	products := make(map[distgo.ProductID]distgo.ProductParam, len(projectParam.Products))
+	for productID, productParam := range projectParam.Products {
+		if productParam.Dist != nil {
+			distParam := *productParam.Dist
+			distParam.DistParams = make(map[distgo.DistID]distgo.DisterParam)
+			for distID, disterParam := range productParam.Dist.DistParams {
+				if len(disterParam.InputDockerExports) == 0 {
+					distParam.DistParams[distID] = disterParam
+				}
+			}
+			productParam.Dist = &distParam
+		}
+		products[productID] = productParam
+	}
+	projectParam.Products = products
+	return projectParam
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/palantir/distgo/distgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// imageLayoutDockerBuilder writes an OCI layout with an image for each of its platforms to the output directory
+type imageLayoutDockerBuilder struct {
+	platforms []v1.Platform
+}
+
+func (imageLayoutDockerBuilder) TypeName() (string, error) {
+	return "image-layout", nil
+}
+
+func (b imageLayoutDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, info distgo.ProductTaskOutputInfo, _, _ bool, _ io.Writer) error {
+	outputDir := filepath.Join(info.Project.ProjectDir, info.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID].OutputDir)
+	p, err := layout.Write(outputDir, empty.Index)
+	if err != nil {
+		return err
+	}
+	for _, platform := range b.platforms {
+		img, err := mutate.ConfigFile(empty.Image, &v1.ConfigFile{OS: platform.OS, Architecture: platform.Architecture})
+		if err != nil {
+			return err
+		}
+		if err := p.AppendImage(img, layout.WithPlatform(platform)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestDockerExport(t *testing.T) {
+	info := testOutputInfo(t, "exporter")
+	export := distgo.DockerExportParam{
+		DockerArchive: true,
+		OCIArchive:    true,
+	}
+	builderOutputInfo := info.Product.DockerOutputInfos.DockerBuilderOutputInfos["exporter"]
+	builderOutputInfo.ExportArtifactNames = export.ArtifactNames("product", "1.0.0", "exporter")
+	info.Product.DockerOutputInfos.DockerBuilderOutputInfos["exporter"] = builderOutputInfo
+	info.Product.DockerOutputInfos.DockerIDs = []distgo.DockerID{"exporter"}
+
+	param := distgo.DockerBuilderParam{
+		DockerBuilder:  imageLayoutDockerBuilder{platforms: []v1.Platform{{OS: "linux", Architecture: "amd64"}}},
+		ContextDir:     "context",
+		DockerfilePath: "Dockerfile",
+		Export:         &export,
+	}
+	buf := &bytes.Buffer{}
+	require.NoError(t, runSingleDockerBuild(info.Project, "product", "product", "exporter", param, info, nil, nil, false, false, buf))
+
+	exportDir := filepath.Join(info.Project.ProjectDir, "out", "docker", "product", "1.0.0")
+	assert.Equal(t, map[distgo.DockerID][]string{
+		"exporter": {
+			filepath.Join(exportDir, "product-1.0.0-exporter.docker.tar"),
+			filepath.Join(exportDir, "product-1.0.0-exporter.oci.tar.gz"),
+		},
+	}, info.ProductDockerExportArtifactPaths())
+	assert.Contains(t, buf.String(), "Exporting image for configuration exporter of product product to ")
+
+	// the Docker archive holds the image under its rendered tag
+	tag, err := name.NewTag("product:1.0.0")
+	require.NoError(t, err)
+	img, err := tarball.ImageFromPath(filepath.Join(exportDir, "product-1.0.0-exporter.docker.tar"), &tag)
+	require.NoError(t, err)
+	cfg, err := img.ConfigFile()
+	require.NoError(t, err)
+	assert.Equal(t, "amd64", cfg.Architecture)
+
+	// the index of the OCI archive names the image by its rendered tag
+	f, err := os.Open(filepath.Join(exportDir, "product-1.0.0-exporter.oci.tar.gz"))
+	require.NoError(t, err)
+	defer func() {
+		_ = f.Close()
+	}()
+	gzr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	tr := tar.NewReader(gzr)
+	var names []string
+	var index v1.IndexManifest
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		if hdr.Name == "index.json" {
+			require.NoError(t, json.NewDecoder(tr).Decode(&index))
+		}
+	}
+	assert.Equal(t, []string{"oci-layout", "index.json", "blobs/"}, names[:3])
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, "product:1.0.0", index.Manifests[0].Annotations[distgo.OCIRefNameAnnotation])
+}
+
+func TestDockerExportMultiPlatformRequiresPlatform(t *testing.T) {
+	info := testOutputInfo(t, "exporter")
+	export := distgo.DockerExportParam{
+		DockerArchive: true,
+	}
+	builderOutputInfo := info.Product.DockerOutputInfos.DockerBuilderOutputInfos["exporter"]
+	builderOutputInfo.ExportArtifactNames = export.ArtifactNames("product", "1.0.0", "exporter")
+	info.Product.DockerOutputInfos.DockerBuilderOutputInfos["exporter"] = builderOutputInfo
+	info.Product.DockerOutputInfos.DockerIDs = []distgo.DockerID{"exporter"}
+
+	param := distgo.DockerBuilderParam{
+		DockerBuilder: imageLayoutDockerBuilder{platforms: []v1.Platform{
+			{OS: "linux", Architecture: "amd64"},
+			{OS: "linux", Architecture: "arm64"},
+		}},
+		ContextDir:     "context",
+		DockerfilePath: "Dockerfile",
+		Export:         &export,
+	}
+	err := runSingleDockerBuild(info.Project, "product", "product", "exporter", param, info, nil, nil, false, false, io.Discard)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the OCI layout contains images for platforms [linux/amd64 linux/arm64]: specify docker-archive-platform")
+
+	export.DockerArchivePlatform = "linux/arm64"
+	require.NoError(t, runSingleDockerBuild(info.Project, "product", "product", "exporter", param, info, nil, nil, false, false, io.Discard))
+	tag, err := name.NewTag("product:1.0.0")
+	require.NoError(t, err)
+	img, err := tarball.ImageFromPath(info.ProductDockerExportArtifactPaths()["exporter"][0], &tag)
+	require.NoError(t, err)
+	cfg, err := img.ConfigFile()
+	require.NoError(t, err)
+	assert.Equal(t, "arm64", cfg.Architecture)
+}
@@ -0,0 +1,621 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/palantir/distgo/distgo"
+	"github.com/pkg/errors"
+)
+
+const (
+	// attestation manifests written by BuildKit are stored in the image index alongside the platform images
+	referenceTypeAnnotation   = "vnd.docker.reference.type"
+	attestationManifestType   = "attestation-manifest"
+	maxSymlinksResolvedInPath = 40
+)
+
+// TestProducts runs the image tests of the Docker images of the products specified by productDockerIDs (or of all of
+// the products with Docker images if none are specified). The tests are run against the OCI layouts written by the
+// "docker build" task, so the images must have been built by a DockerBuilder that writes an OCI layout. All of the
+// tests are run even if some of them fail, and an error is returned if any of them failed.
+func TestProducts(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productDockerIDs []distgo.ProductDockerID, stdout io.Writer) error {
+	// determine products that match specified productDockerIDs
+	productParams, err := distgo.ProductParamsForDockerProductArgs(projectParam.Products, productDockerIDs...)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+	for _, currParam := range productParams {
+		currFailed, err := RunTest(projectInfo, currParam, stdout)
+		if err != nil {
+			return err
+		}
+		failed = append(failed, currFailed...)
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("image tests failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// RunTest runs the image tests of the Docker images of the provided product and returns the ProductDockerIDs of the
+// images for which a test failed. The returned error is only non-nil if the tests could not be run.
+func RunTest(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, stdout io.Writer) ([]string, error) {
+	if productParam.Docker == nil {
+		_, _ = fmt.Fprintf(stdout, "%s does not have Docker outputs; skipping test\n", productParam.ID)
+		return nil, nil
+	}
+
+	var dockerIDs []distgo.DockerID
+	for k := range productParam.Docker.DockerBuilderParams {
+		dockerIDs = append(dockerIDs, k)
+	}
+	sort.Sort(distgo.ByDockerID(dockerIDs))
+
+	productTaskOutputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, productParam)
+	if err != nil {
+		return nil, err
+	}
+
+	var failed []string
+	for _, dockerID := range dockerIDs {
+		tests := productParam.Docker.DockerBuilderParams[dockerID].Tests
+		if tests == nil {
+			_, _ = fmt.Fprintf(stdout, "Docker configuration %s of product %s does not declare tests; skipping\n", dockerID, productParam.ID)
+			continue
+		}
+		outputDir := dockerOCIOutputDir(productTaskOutputInfo, dockerID)
+		if outputDir == "" {
+			return nil, errors.Errorf("no OCI layout found for configuration %s of product %s: run \"docker build\" for it using a builder that writes an OCI layout before running its tests", dockerID, productParam.ID)
+		}
+		passed, err := runSingleDockerTest(productParam.ID, dockerID, *tests, outputDir, stdout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to run tests for configuration %s of product %s", dockerID, productParam.ID)
+		}
+		if !passed {
+			failed = append(failed, string(distgo.NewProductDockerID(productParam.ID, dockerID, "")))
+		}
+	}
+	return failed, nil
+}
+
+// platformImage is a single image of an OCI layout along with the platform it was built for.
+type platformImage struct {
+	platform v1.Platform
+	image    v1.Image
+}
+
+func runSingleDockerTest(productID distgo.ProductID, dockerID distgo.DockerID, tests distgo.DockerImageTestsParam, outputDir string, stdout io.Writer) (bool, error) {
+	images, err := imagesFromOCILayout(outputDir)
+	if err != nil {
+		return false, err
+	}
+
+	var platformFilters []v1.Platform
+	for _, platform := range tests.Platforms {
+		filter, err := v1.ParsePlatform(platform)
+		if err != nil {
+			return false, errors.Wrapf(err, "invalid platform %q", platform)
+		}
+		platformFilters = append(platformFilters, *filter)
+	}
+
+	var testedImages []platformImage
+	for _, img := range images {
+		if len(platformFilters) == 0 || slices.ContainsFunc(platformFilters, img.platform.Satisfies) {
+			testedImages = append(testedImages, img)
+		}
+	}
+	if len(testedImages) == 0 {
+		_, _ = fmt.Fprintf(stdout, "FAIL: no image for configuration %s of product %s matches platforms %v\n", dockerID, productID, tests.Platforms)
+		return false, nil
+	}
+
+	allPassed := true
+	for _, img := range testedImages {
+		_, _ = fmt.Fprintf(stdout, "Testing image for platform %s of configuration %s of product %s...\n", img.platform.String(), dockerID, productID)
+		failures, numChecks, err := runImageTests(img.image, tests)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to test image for platform %s", img.platform.String())
+		}
+		for _, failure := range failures {
+			_, _ = fmt.Fprintf(stdout, "FAIL: %s\n", failure)
+		}
+		_, _ = fmt.Fprintf(stdout, "%d of %d checks passed\n", numChecks-len(failures), numChecks)
+		if len(failures) > 0 {
+			allPassed = false
+		}
+	}
+	return allPassed, nil
+}
+
+// imagesFromOCILayout returns all of the platform images in the OCI layout at the provided path, descending into nested
+// image indexes and skipping attestation manifests.
+func imagesFromOCILayout(outputDir string) ([]platformImage, error) {
+	index, err := layout.ImageIndexFromPath(outputDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to construct image index from OCI layout at path %s", outputDir)
+	}
+	idxManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read index manifest")
+	}
+	if !idxManifest.MediaType.IsIndex() && idxManifest.MediaType.IsImage() {
+		// layouts written by older DockerBuilders store a single image as a tarball next to a bare manifest
+		tarPath := filepath.Join(outputDir, "image.tar")
+		img, err := tarball.ImageFromPath(tarPath, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read image from path %s", tarPath)
+		}
+		platform, err := imagePlatform(img, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []platformImage{{platform: platform, image: img}}, nil
+	}
+	return imagesFromIndex(index)
+}
+
+func imagesFromIndex(index v1.ImageIndex) ([]platformImage, error) {
+	idxManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read index manifest")
+	}
+	var images []platformImage
+	for _, desc := range idxManifest.Manifests {
+		switch {
+		case desc.MediaType.IsIndex():
+			innerIndex, err := index.ImageIndex(desc.Digest)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read image index digest %s", desc.Digest)
+			}
+			innerImages, err := imagesFromIndex(innerIndex)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, innerImages...)
+		case desc.MediaType.IsImage():
+			if desc.Annotations[referenceTypeAnnotation] == attestationManifestType {
+				continue
+			}
+			img, err := index.Image(desc.Digest)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read image digest %s", desc.Digest)
+			}
+			platform, err := imagePlatform(img, desc.Platform)
+			if err != nil {
+				return nil, err
+			}
+			images = append(images, platformImage{platform: platform, image: img})
+		}
+	}
+	return images, nil
+}
+
+// imagePlatform returns the platform recorded for an image in its index descriptor or, if the descriptor does not
+// record one, in the configuration of the image.
+func imagePlatform(img v1.Image, descPlatform *v1.Platform) (v1.Platform, error) {
+	if descPlatform != nil {
+		return *descPlatform, nil
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return v1.Platform{}, errors.Wrap(err, "failed to read image configuration")
+	}
+	if platform := cfg.Platform(); platform != nil {
+		return *platform, nil
+	}
+	return v1.Platform{}, nil
+}
+
+// runImageTests runs the provided tests against the image and returns a description of every check that failed along
+// with the total number of checks that were made.
+func runImageTests(img v1.Image, tests distgo.DockerImageTestsParam) ([]string, int, error) {
+	var failures []string
+	numChecks := 0
+	check := func(failure string) {
+		numChecks++
+		if failure != "" {
+			failures = append(failures, failure)
+		}
+	}
+
+	if len(tests.FileExistenceTests) > 0 || len(tests.FileContentTests) > 0 {
+		fs, err := readImageFilesystem(img, tests.FileContentTests)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, test := range tests.FileExistenceTests {
+			check(fs.checkFileExistence(test))
+		}
+		for _, test := range tests.FileContentTests {
+			for _, failure := range fs.checkFileContent(test) {
+				check(failure)
+			}
+		}
+	}
+
+	if tests.MetadataTest != nil {
+		cfg, err := img.ConfigFile()
+		if err != nil {
+			return nil, 0, errors.Wrap(err, "failed to read image configuration")
+		}
+		for _, failure := range checkMetadata(cfg.Config, *tests.MetadataTest) {
+			check(failure)
+		}
+	}
+
+	if tests.LayersTest != nil {
+		layerFailures, err := checkLayers(img, *tests.LayersTest)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, failure := range layerFailures {
+			check(failure)
+		}
+		// a layers test that finds nothing forbidden counts as a single passing check
+		if len(layerFailures) == 0 {
+			check("")
+		}
+	}
+	return failures, numChecks, nil
+}
+
+// imageFilesystem is the flattened filesystem of an image: the headers of all of its files keyed by absolute path and
+// the content of the regular files that are the subject of content tests.
+type imageFilesystem struct {
+	headers  map[string]*tar.Header
+	contents map[string][]byte
+}
+
+func readImageFilesystem(img v1.Image, contentTests []distgo.DockerImageFileContentTest) (imageFilesystem, error) {
+	fs := imageFilesystem{
+		headers:  make(map[string]*tar.Header),
+		contents: make(map[string][]byte),
+	}
+	if err := fs.read(img, nil); err != nil {
+		return imageFilesystem{}, err
+	}
+
+	// the files whose content is needed are only known once links are resolved, which requires all of the headers
+	contentPaths := make(map[string]struct{})
+	for _, test := range contentTests {
+		if resolved, err := fs.resolve(test.Path); err == nil {
+			contentPaths[resolved] = struct{}{}
+		}
+	}
+	if len(contentPaths) > 0 {
+		if err := fs.read(img, contentPaths); err != nil {
+			return imageFilesystem{}, err
+		}
+	}
+	return fs, nil
+}
+
+// read reads the flattened filesystem of the image. If contentPaths is nil, the headers of all files are recorded;
+// otherwise, the content of the specified files is recorded.
+func (fs imageFilesystem) read(img v1.Image, contentPaths map[string]struct{}) (rErr error) {
+	rc := mutate.Extract(img)
+	defer func() {
+		if err := rc.Close(); err != nil && rErr == nil {
+			rErr = errors.Wrap(err, "failed to close image filesystem")
+		}
+	}()
+
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read image filesystem")
+		}
+		p := imagePath(hdr.Name)
+		if contentPaths == nil {
+			fs.headers[p] = hdr
+			continue
+		}
+		if _, ok := contentPaths[p]; !ok || hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read content of %s", p)
+		}
+		fs.contents[p] = content
+	}
+}
+
+// resolve resolves the symbolic links in every component of the provided path and any hard link at the end of it, and
+// returns the path of the file it refers to. Returns an error if the file does not exist.
+func (fs imageFilesystem) resolve(p string) (string, error) {
+	p = imagePath(p)
+	for range maxSymlinksResolvedInPath {
+		resolved, changed := fs.resolveFirstSymlink(p)
+		if changed {
+			p = resolved
+			continue
+		}
+		hdr, ok := fs.headers[p]
+		if !ok {
+			if p == "/" {
+				return p, nil
+			}
+			return "", errors.Errorf("%s does not exist", p)
+		}
+		if hdr.Typeflag == tar.TypeLink {
+			p = imagePath(hdr.Linkname)
+			continue
+		}
+		return p, nil
+	}
+	return "", errors.Errorf("too many levels of symbolic links in %s", p)
+}
+
+// resolveFirstSymlink replaces the first component of the provided path that is a symbolic link with its target.
+// Returns false if no component of the path is a symbolic link.
+func (fs imageFilesystem) resolveFirstSymlink(p string) (string, bool) {
+	parts := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	for i := range parts {
+		prefix := "/" + strings.Join(parts[:i+1], "/")
+		hdr, ok := fs.headers[prefix]
+		if !ok || hdr.Typeflag != tar.TypeSymlink {
+			continue
+		}
+		target := hdr.Linkname
+		if !path.IsAbs(target) {
+			target = path.Join(path.Dir(prefix), target)
+		}
+		return imagePath(path.Join(append([]string{target}, parts[i+1:]...)...)), true
+	}
+	return p, false
+}
+
+func (fs imageFilesystem) checkFileExistence(test distgo.DockerImageFileExistenceTest) string {
+	resolved, err := fs.resolve(test.Path)
+	if err != nil {
+		if test.ShouldExist {
+			return fmt.Sprintf("expected %s to exist: %v", test.Path, err)
+		}
+		return ""
+	}
+	if !test.ShouldExist {
+		return fmt.Sprintf("expected %s not to exist", test.Path)
+	}
+	hdr, ok := fs.headers[resolved]
+	if !ok {
+		// the root directory has no entry of its own
+		return ""
+	}
+	var mismatches []string
+	if actual := hdr.Mode & 07777; test.Mode != nil && actual != *test.Mode {
+		mismatches = append(mismatches, fmt.Sprintf("mode is %04o, expected %04o", actual, *test.Mode))
+	}
+	if test.UID != nil && hdr.Uid != *test.UID {
+		mismatches = append(mismatches, fmt.Sprintf("uid is %d, expected %d", hdr.Uid, *test.UID))
+	}
+	if test.GID != nil && hdr.Gid != *test.GID {
+		mismatches = append(mismatches, fmt.Sprintf("gid is %d, expected %d", hdr.Gid, *test.GID))
+	}
+	if len(mismatches) > 0 {
+		return fmt.Sprintf("%s: %s", test.Path, strings.Join(mismatches, ", "))
+	}
+	return ""
+}
+
+// checkFileContent returns the result of every expression of the provided test: an empty string for each expression
+// that is satisfied and a description of the failure for each expression that is not.
+func (fs imageFilesystem) checkFileContent(test distgo.DockerImageFileContentTest) []string {
+	numChecks := max(len(test.ExpectedContents)+len(test.ExcludedContents), 1)
+	resolved, err := fs.resolve(test.Path)
+	if err != nil {
+		return slices.Repeat([]string{fmt.Sprintf("cannot check content of %s: %v", test.Path, err)}, numChecks)
+	}
+	content, ok := fs.contents[resolved]
+	if !ok {
+		return slices.Repeat([]string{fmt.Sprintf("cannot check content of %s: not a regular file", test.Path)}, numChecks)
+	}
+
+	results := make([]string, 0, numChecks)
+	for _, re := range test.ExpectedContents {
+		if !re.Match(content) {
+			results = append(results, fmt.Sprintf("content of %s does not match %q", test.Path, re.String()))
+		} else {
+			results = append(results, "")
+		}
+	}
+	for _, re := range test.ExcludedContents {
+		if re.Match(content) {
+			results = append(results, fmt.Sprintf("content of %s matches excluded expression %q", test.Path, re.String()))
+		} else {
+			results = append(results, "")
+		}
+	}
+	if len(results) == 0 {
+		// a test without expressions only verifies that the file can be read
+		results = append(results, "")
+	}
+	return results
+}
+
+// checkMetadata returns the result of every assertion of the provided test: an empty string for each assertion that is
+// satisfied and a description of the failure for each assertion that is not.
+func checkMetadata(cfg v1.Config, test distgo.DockerImageMetadataTest) []string {
+	var results []string
+	addResult := func(ok bool, format string, args ...any) {
+		if ok {
+			results = append(results, "")
+			return
+		}
+		results = append(results, fmt.Sprintf(format, args...))
+	}
+
+	if test.Entrypoint != nil {
+		addResult(slices.Equal(cfg.Entrypoint, *test.Entrypoint), "entrypoint is %q, expected %q", cfg.Entrypoint, *test.Entrypoint)
+	}
+	if test.Cmd != nil {
+		addResult(slices.Equal(cfg.Cmd, *test.Cmd), "cmd is %q, expected %q", cfg.Cmd, *test.Cmd)
+	}
+	if test.User != nil {
+		addResult(cfg.User == *test.User, "user is %q, expected %q", cfg.User, *test.User)
+	}
+	if test.WorkingDir != nil {
+		addResult(cfg.WorkingDir == *test.WorkingDir, "working directory is %q, expected %q", cfg.WorkingDir, *test.WorkingDir)
+	}
+
+	env := make(map[string]string)
+	for _, kv := range cfg.Env {
+		k, v, _ := strings.Cut(kv, "=")
+		env[k] = v
+	}
+	for _, k := range sortedKeys(test.Env) {
+		actual, ok := env[k]
+		switch {
+		case !ok:
+			addResult(false, "environment variable %s is not set, expected %q", k, test.Env[k])
+		default:
+			addResult(actual == test.Env[k], "environment variable %s is %q, expected %q", k, actual, test.Env[k])
+		}
+	}
+	for _, k := range sortedKeys(test.Labels) {
+		actual, ok := cfg.Labels[k]
+		switch {
+		case !ok:
+			addResult(false, "label %s is not set, expected %q", k, test.Labels[k])
+		default:
+			addResult(actual == test.Labels[k], "label %s is %q, expected %q", k, actual, test.Labels[k])
+		}
+	}
+	for _, k := range test.RequiredLabels {
+		_, ok := cfg.Labels[k]
+		addResult(ok, "required label %s is not set", k)
+	}
+	for _, port := range test.ExposedPorts {
+		_, ok := cfg.ExposedPorts[port]
+		addResult(ok, "port %s is not exposed", port)
+	}
+	for _, volume := range test.Volumes {
+		_, ok := cfg.Volumes[volume]
+		addResult(ok, "volume %s is not declared", volume)
+	}
+	return results
+}
+
+// checkLayers returns a description of every forbidden file found in the layers of the image.
+func checkLayers(img v1.Image, test distgo.DockerImageLayersTest) ([]string, error) {
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read image layers")
+	}
+	var failures []string
+	for i, layer := range layers {
+		digest, err := layer.Digest()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compute digest of layer %d", i)
+		}
+		layerFailures, err := checkLayer(layer, test)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to check layer %d (%s)", i, digest)
+		}
+		for _, failure := range layerFailures {
+			failures = append(failures, fmt.Sprintf("layer %d (%s): %s", i, digest, failure))
+		}
+	}
+	return failures, nil
+}
+
+func checkLayer(layer v1.Layer, test distgo.DockerImageLayersTest) (failures []string, rErr error) {
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read layer")
+	}
+	defer func() {
+		if err := rc.Close(); err != nil && rErr == nil {
+			rErr = errors.Wrap(err, "failed to close layer")
+		}
+	}()
+
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return failures, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read layer")
+		}
+		p := imagePath(hdr.Name)
+		if strings.HasPrefix(path.Base(p), ".wh.") {
+			// whiteouts record removals and contribute no content
+			continue
+		}
+		for _, pattern := range test.ForbiddenPaths {
+			if matchesForbiddenPath(pattern, p) {
+				failures = append(failures, fmt.Sprintf("contains %s, which matches forbidden path %q", p, pattern))
+			}
+		}
+		if len(test.ForbiddenContents) == 0 || hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read content of %s", p)
+		}
+		for _, re := range test.ForbiddenContents {
+			if re.Match(content) {
+				failures = append(failures, fmt.Sprintf("content of %s matches forbidden expression %q", p, re.String()))
+			}
+		}
+	}
+}
+
+func matchesForbiddenPath(pattern, p string) bool {
+	name := path.Base(p)
+	if strings.Contains(pattern, "/") {
+		name = p
+	}
+	// patterns are validated when the configuration is loaded
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
+
+// imagePath returns the absolute, cleaned form of the provided path of a file in an image.
+func imagePath(name string) string {
+	return path.Clean("/" + name)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -21,7 +21,6 @@ import (
 	"path/filepath"
 	"sort"
 
-	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/layout"
@@ -76,6 +75,7 @@ func RunPush(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, d
 			productParam.ID,
 			dockerID,
 			productTaskOutputInfo,
+			productParam.Docker.Registries,
 			dryRun,
 			insecure,
 			stdout,
@@ -90,15 +90,16 @@ func runSingleDockerPush(
 	productID distgo.ProductID,
 	dockerID distgo.DockerID,
 	productTaskOutputInfo distgo.ProductTaskOutputInfo,
+	registries map[string]distgo.DockerRegistryParam,
 	dryRun bool,
 	insecure bool,
 	stdout io.Writer) (rErr error) {
 
 	// if an OCI artifact exists, push that. Otherwise, default to pushing the artifact in the docker daemon
 	if outputDir := dockerOCIOutputDir(productTaskOutputInfo, dockerID); outputDir != "" {
-		return runOCIPush(productID, dockerID, productTaskOutputInfo, outputDir, dryRun, insecure, stdout)
+		return runOCIPush(productID, dockerID, productTaskOutputInfo, outputDir, registries, dryRun, insecure, stdout)
 	}
-	return runDockerDaemonPush(productID, dockerID, productTaskOutputInfo, dryRun, stdout)
+	return runDockerDaemonPush(productID, dockerID, productTaskOutputInfo, registries, dryRun, stdout)
 }
 
 // dockerOCIOutputDir returns the output directory that holds the OCI layout for the given Docker configuration, or an
@@ -113,20 +114,16 @@ func dockerOCIOutputDir(productTaskOutputInfo distgo.ProductTaskOutputInfo, dock
 	return ""
 }
 
-func runOCIPush(productID distgo.ProductID, dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo, outputDir string, dryRun bool, insecure bool, stdout io.Writer) error {
+func runOCIPush(productID distgo.ProductID, dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo, outputDir string, registries map[string]distgo.DockerRegistryParam, dryRun bool, insecure bool, stdout io.Writer) error {
 	index, err := layout.ImageIndexFromPath(outputDir)
 	if err != nil {
 		return errors.Wrapf(err, "failed to construct image index from OCI layout at path %s", outputDir)
 	}
 
-	for _, tag := range productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID].RenderedTags {
-		var opts []name.Option
-		if insecure {
-			opts = append(opts, name.Insecure)
-		}
-		ref, err := name.ParseReference(tag, opts...)
+	for _, tag := range productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID].PushTags() {
+		ref, opts, err := remoteReference(tag, registries, insecure)
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse reference from tag %s", tag)
+			return err
 		}
 
 		// need to resolve what we're pushing:
@@ -140,11 +137,11 @@ func runOCIPush(productID distgo.ProductID, dockerID distgo.DockerID, productTas
 		}
 		switch idxManifest.MediaType {
 		case types.OCIImageIndex:
-			if err := handleImageIndex(index, idxManifest, ref, productID, dockerID, dryRun, stdout); err != nil {
+			if err := handleImageIndex(index, idxManifest, ref, opts, productID, dockerID, dryRun, stdout); err != nil {
 				return errors.Wrapf(err, "failed to publish image index for configuration %s for product %s", dockerID, productID)
 			}
 		case types.OCIManifestSchema1:
-			if err := handleImageManifest(ref, productID, dockerID, outputDir, dryRun, stdout); err != nil {
+			if err := handleImageManifest(ref, opts, productID, dockerID, outputDir, dryRun, stdout); err != nil {
 				return errors.Wrapf(err, "failed to image manifest for configuration %s for product %s", dockerID, productID)
 			}
 		default:
@@ -154,7 +151,7 @@ func runOCIPush(productID distgo.ProductID, dockerID distgo.DockerID, productTas
 	return nil
 }
 
-func handleImageIndex(index v1.ImageIndex, idxManifest *v1.IndexManifest, ref name.Reference, productID distgo.ProductID, dockerID distgo.DockerID, dryRun bool, stdout io.Writer) error {
+func handleImageIndex(index v1.ImageIndex, idxManifest *v1.IndexManifest, ref name.Reference, opts []remote.Option, productID distgo.ProductID, dockerID distgo.DockerID, dryRun bool, stdout io.Writer) error {
 	manifestMetadata, err := manifestMetadataFromIndexManifest(idxManifest)
 	if err != nil {
 		return errors.Wrap(err, "encountered unexpected index manifest state")
@@ -167,14 +164,14 @@ func handleImageIndex(index v1.ImageIndex, idxManifest *v1.IndexManifest, ref na
 		if err != nil {
 			return errors.Wrapf(err, "failed to read image index digest %s from OCI layout", manifestMetadata.digest)
 		}
-		if err := writeIndex(innerIndex, ref, productID, dockerID, dryRun, stdout); err != nil {
+		if err := writeIndex(innerIndex, ref, opts, productID, dockerID, dryRun, stdout); err != nil {
 			return errors.Wrapf(err, "failed to write image index for tag %s of configuration %s for product %s", ref, dockerID, productID)
 		}
 		return nil
 	case types.OCIManifestSchema1:
 		if manifestMetadata.hasPlatformInfo {
 			// if we have platform information, we should push our current image index
-			if err := writeIndex(index, ref, productID, dockerID, dryRun, stdout); err != nil {
+			if err := writeIndex(index, ref, opts, productID, dockerID, dryRun, stdout); err != nil {
 				return errors.Wrapf(err, "failed to write image index for tag %s of configuration %s for product %s", ref, dockerID, productID)
 			}
 			return nil
@@ -187,7 +184,7 @@ func handleImageIndex(index v1.ImageIndex, idxManifest *v1.IndexManifest, ref na
 		if err != nil {
 			return errors.Wrapf(err, "failed to read image digest %s from OCI layout", manifestMetadata.digest)
 		}
-		if err := writeImage(image, ref, productID, dockerID, dryRun, stdout); err != nil {
+		if err := writeImage(image, ref, opts, productID, dockerID, dryRun, stdout); err != nil {
 			return errors.Wrapf(err, "failed to write image for tag %s of configuration %s for product %s", ref, dockerID, productID)
 		}
 		return nil
@@ -196,32 +193,32 @@ func handleImageIndex(index v1.ImageIndex, idxManifest *v1.IndexManifest, ref na
 	}
 }
 
-func handleImageManifest(ref name.Reference, productID distgo.ProductID, dockerID distgo.DockerID, outputDir string, dryRun bool, stdout io.Writer) error {
+func handleImageManifest(ref name.Reference, opts []remote.Option, productID distgo.ProductID, dockerID distgo.DockerID, outputDir string, dryRun bool, stdout io.Writer) error {
 	path := filepath.Join(outputDir, "image.tar")
 	image, err := tarball.ImageFromPath(path, nil)
 	if err != nil {
 		return errors.Wrapf(err, "failed to read image from path %s", path)
 	}
-	if err := writeImage(image, ref, productID, dockerID, dryRun, stdout); err != nil {
+	if err := writeImage(image, ref, opts, productID, dockerID, dryRun, stdout); err != nil {
 		return errors.Wrapf(err, "failed to write image for tag %s of configuration %s for product %s", ref, dockerID, productID)
 	}
 	return nil
 }
 
-func writeIndex(index v1.ImageIndex, ref name.Reference, productID distgo.ProductID, dockerID distgo.DockerID, dryRun bool, stdout io.Writer) error {
+func writeIndex(index v1.ImageIndex, ref name.Reference, opts []remote.Option, productID distgo.ProductID, dockerID distgo.DockerID, dryRun bool, stdout io.Writer) error {
 	distgo.PrintlnOrDryRunPrintln(stdout, fmt.Sprintf("Writing image index for tag %s of docker configuration %s of product %s...", ref, dockerID, productID), dryRun)
 	if !dryRun {
-		if err := remote.WriteIndex(ref, index, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+		if err := remote.WriteIndex(ref, index, opts...); err != nil {
 			return errors.Wrap(err, "failed to write image index to remote")
 		}
 	}
 	return nil
 }
 
-func writeImage(image v1.Image, ref name.Reference, productID distgo.ProductID, dockerID distgo.DockerID, dryRun bool, stdout io.Writer) error {
+func writeImage(image v1.Image, ref name.Reference, opts []remote.Option, productID distgo.ProductID, dockerID distgo.DockerID, dryRun bool, stdout io.Writer) error {
 	distgo.PrintlnOrDryRunPrintln(stdout, fmt.Sprintf("Writing image for tag %s of docker configuration %s of product %s...", ref, dockerID, productID), dryRun)
 	if !dryRun {
-		if err := remote.Write(ref, image, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+		if err := remote.Write(ref, image, opts...); err != nil {
 			return errors.Wrap(err, "failed to write image to remote")
 		}
 	}
@@ -265,15 +262,54 @@ func runDockerDaemonPush(
 	productID distgo.ProductID,
 	dockerID distgo.DockerID,
 	productTaskOutputInfo distgo.ProductTaskOutputInfo,
+	registries map[string]distgo.DockerRegistryParam,
 	dryRun bool,
 	stdout io.Writer,
 ) error {
 	distgo.PrintlnOrDryRunPrintln(stdout, fmt.Sprintf("Running Docker push for configuration %s of product %s...", dockerID, productID), dryRun)
-	for _, tag := range productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID].RenderedTags {
-		cmd := exec.Command("docker", "push", tag)
-		if err := distgo.RunCommandWithVerboseOption(cmd, true, dryRun, stdout); err != nil {
+	renderedTags := productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID].RenderedTags
+	pushTags := productTaskOutputInfo.Product.DockerOutputInfos.DockerBuilderOutputInfos[dockerID].PushTags()
+	// the registries of all of the tags are checked before any tag is pushed
+	registryParams := make([]distgo.DockerRegistryParam, len(pushTags))
+	for i, tag := range pushTags {
+		registryParam, err := daemonRegistryParamForTag(tag, registries)
+		if err != nil {
+			return err
+		}
+		registryParams[i] = registryParam
+	}
+	for i, tag := range pushTags {
+		// the DockerBuilder only applies the rendered tags of the configuration, so the image is tagged with the tags of
+		// its mirrors before they are pushed
+		if i >= len(renderedTags) {
+			if len(renderedTags) == 0 {
+				return errors.Errorf("configuration %s of product %s has no rendered tags that identify the image to push to mirror tag %s", dockerID, productID, tag)
+			}
+			cmd := exec.Command("docker", "tag", renderedTags[0], tag)
+			if err := distgo.RunCommandWithVerboseOption(cmd, true, dryRun, stdout); err != nil {
+				return err
+			}
+		}
+		if err := runWithRetry(registryParams[i].Retry, func() error {
+			cmd := exec.Command("docker", "push", tag)
+			return distgo.RunCommandWithVerboseOption(cmd, true, dryRun, stdout)
+		}); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// daemonRegistryParamForTag returns the registry parameter that applies to the provided tag when it is pushed using the
+// Docker daemon. The daemon connects to the registry using its own settings, so an error is returned if the
+// configuration of the registry specifies insecure or credentials rather than silently ignoring them.
+func daemonRegistryParamForTag(tag string, registries map[string]distgo.DockerRegistryParam) (distgo.DockerRegistryParam, error) {
+	registryParam, err := registryParamForTag(tag, registries)
+	if err != nil {
+		return distgo.DockerRegistryParam{}, err
+	}
+	if registryParam.Insecure || registryParam.Credentials != nil {
+		return distgo.DockerRegistryParam{}, errors.Errorf("the configuration of the registry of tag %s specifies insecure or credentials, which are not supported when pushing an image using the Docker daemon: configure the registry in the Docker daemon or use a Docker builder that writes an OCI layout", tag)
+	}
+	return registryParam, nil
+}
@@ -282,6 +282,56 @@ func TestDockerPublish(t *testing.T) {
 			`[DRY RUN] Running Docker push for configuration print-dockerfile of product foo...
 [DRY RUN] Run [docker push foo:latest]
 [DRY RUN] Run [docker push foo:0.1.0]
+`,
+		},
+		{
+			"publish pushes Docker images to mirrors",
+			distgoconfig.ProjectConfig{
+				Products: distgoconfig.ToProductsMap(map[distgo.ProductID]distgoconfig.ProductConfig{
+					"foo": {
+						Build: distgoconfig.ToBuildConfig(&distgoconfig.BuildConfig{
+							MainPkg: new("./foo"),
+						}),
+						Docker: distgoconfig.ToDockerConfig(&distgoconfig.DockerConfig{
+							DockerBuildersConfig: distgoconfig.ToDockerBuildersConfig(&distgoconfig.DockerBuildersConfig{
+								printDockerfileDockerBuilderTypeName: distgoconfig.ToDockerBuilderConfig(distgoconfig.DockerBuilderConfig{
+									Type:       new(printDockerfileDockerBuilderTypeName),
+									ContextDir: new("docker-context-dir"),
+									TagTemplates: distgoconfig.ToTagTemplatesMap(mustTagTemplatesMap(
+										"latest", "{{Repository}}foo:latest",
+										"versioned", "{{Repository}}foo:{{Version}}",
+									)),
+									Mirrors: distgoconfig.ToDockerMirrorsConfig(&distgoconfig.DockerMirrorsConfig{
+										"internal": distgoconfig.ToDockerMirrorConfig(distgoconfig.DockerMirrorConfig{
+											Repository: new("registry.internal.example.com/"),
+										}),
+										"customer": distgoconfig.ToDockerMirrorConfig(distgoconfig.DockerMirrorConfig{
+											Repository: new("registry.example.com/"),
+											TagTemplates: distgoconfig.ToTagTemplatesMap(mustTagTemplatesMap(
+												"versioned", "{{Repository}}customer/foo:{{Version}}",
+											)),
+										}),
+									}),
+								}),
+							}),
+						}),
+					},
+				}),
+			},
+			nil,
+			[]string{
+				"versioned",
+			},
+			func(t *testing.T, projectDir string, projectCfg distgoconfig.ProjectConfig) {
+				gittest.CreateGitTag(t, projectDir, "0.1.0")
+			},
+			"",
+			`[DRY RUN] Running Docker push for configuration print-dockerfile of product foo...
+[DRY RUN] Run [docker push foo:0.1.0]
+[DRY RUN] Run [docker tag foo:0.1.0 registry.example.com/customer/foo:0.1.0]
+[DRY RUN] Run [docker push registry.example.com/customer/foo:0.1.0]
+[DRY RUN] Run [docker tag foo:0.1.0 registry.internal.example.com/foo:0.1.0]
+[DRY RUN] Run [docker push registry.internal.example.com/foo:0.1.0]
 `,
 		},
 		{
@@ -0,0 +1,124 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"os"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/palantir/distgo/distgo"
+	"github.com/pkg/errors"
+)
+
+// registryParamForTag returns the registry parameter that applies to the provided tag. Returns a zero-value parameter
+// if no registry configuration is specified for the registry of the tag.
+func registryParamForTag(tag string, registries map[string]distgo.DockerRegistryParam) (distgo.DockerRegistryParam, error) {
+	ref, err := name.ParseReference(tag)
+	if err != nil {
+		return distgo.DockerRegistryParam{}, errors.Wrapf(err, "failed to parse reference from tag %s", tag)
+	}
+	return registries[ref.Context().RegistryStr()], nil
+}
+
+// remoteReference parses the provided tag and returns the reference along with the options used to push to its
+// registry. Connections to the registry are insecure if insecure is true or if the configuration of the registry sets
+// insecure. Credentials are read from the environment variables specified by the configuration of the registry if it
+// sets credentials and from the Docker configuration of the user otherwise.
+func remoteReference(tag string, registries map[string]distgo.DockerRegistryParam, insecure bool) (name.Reference, []remote.Option, error) {
+	registryParam, err := registryParamForTag(tag, registries)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nameOpts []name.Option
+	if insecure || registryParam.Insecure {
+		nameOpts = append(nameOpts, name.Insecure)
+	}
+	ref, err := name.ParseReference(tag, nameOpts...)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to parse reference from tag %s", tag)
+	}
+
+	var opts []remote.Option
+	if registryParam.Credentials == nil {
+		opts = append(opts, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	} else {
+		auth, err := registryAuthenticator(*registryParam.Credentials)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to resolve credentials for registry %s", ref.Context().RegistryStr())
+		}
+		opts = append(opts, remote.WithAuth(auth))
+	}
+	if registryParam.Retry != nil {
+		// Steps is the maximum number of times a request is made rather than the number of retries, so it matches the
+		// number of attempts of runWithRetry
+		opts = append(opts, remote.WithRetryBackoff(remote.Backoff{
+			Duration: registryParam.Retry.InitialBackoff,
+			Factor:   2,
+			Steps:    registryParam.Retry.Attempts,
+		}))
+	}
+	return ref, opts, nil
+}
+
+func registryAuthenticator(credentials distgo.DockerRegistryCredentialsParam) (authn.Authenticator, error) {
+	if credentials.TokenEnvVar != "" {
+		token, err := requiredEnvVar(credentials.TokenEnvVar)
+		if err != nil {
+			return nil, err
+		}
+		return &authn.Bearer{Token: token}, nil
+	}
+	username, err := requiredEnvVar(credentials.UsernameEnvVar)
+	if err != nil {
+		return nil, err
+	}
+	password, err := requiredEnvVar(credentials.PasswordEnvVar)
+	if err != nil {
+		return nil, err
+	}
+	return &authn.Basic{Username: username, Password: password}, nil
+}
+
+func requiredEnvVar(envVar string) (string, error) {
+	val, ok := os.LookupEnv(envVar)
+	if !ok || val == "" {
+		return "", errors.Errorf("environment variable %s is not set", envVar)
+	}
+	return val, nil
+}
+
+// runWithRetry runs fn until it succeeds or the number of attempts specified by retry is exhausted, doubling the wait
+// between attempts. fn is run once if retry is nil.
+func runWithRetry(retry *distgo.DockerRegistryRetryParam, fn func() error) error {
+	attempts, backoff := 1, time.Duration(0)
+	if retry != nil {
+		attempts, backoff = retry.Attempts, retry.InitialBackoff
+	}
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt < attempts {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	return err
+}
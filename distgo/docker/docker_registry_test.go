@@ -0,0 +1,204 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package docker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/palantir/distgo/distgo"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteReference(t *testing.T) {
+	t.Setenv("DISTGO_TEST_REGISTRY_TOKEN", "token")
+	registries := map[string]distgo.DockerRegistryParam{
+		"registry.internal.example.com:5000": {
+			Insecure: true,
+		},
+		"index.docker.io": {
+			Credentials: &distgo.DockerRegistryCredentialsParam{
+				TokenEnvVar: "DISTGO_TEST_REGISTRY_TOKEN",
+			},
+		},
+		"registry.example.com": {
+			Credentials: &distgo.DockerRegistryCredentialsParam{
+				UsernameEnvVar: "DISTGO_TEST_REGISTRY_USERNAME_UNSET",
+				PasswordEnvVar: "DISTGO_TEST_REGISTRY_PASSWORD_UNSET",
+			},
+		},
+	}
+
+	for i, tc := range []struct {
+		name       string
+		tag        string
+		insecure   bool
+		wantScheme string
+		wantErr    string
+	}{
+		{
+			name:       "registry without configuration is secure",
+			tag:        "registry.other.example.com/foo:1.0.0",
+			wantScheme: "https",
+		},
+		{
+			name:       "global insecure flag applies to all registries",
+			tag:        "registry.other.example.com/foo:1.0.0",
+			insecure:   true,
+			wantScheme: "http",
+		},
+		{
+			name:       "registry configured as insecure",
+			tag:        "registry.internal.example.com:5000/foo:1.0.0",
+			wantScheme: "http",
+		},
+		{
+			name:       "Docker Hub tag matches canonical registry",
+			tag:        "foo:1.0.0",
+			wantScheme: "https",
+		},
+		{
+			name:    "unset credentials environment variable",
+			tag:     "registry.example.com/foo:1.0.0",
+			wantErr: "failed to resolve credentials for registry registry.example.com: environment variable DISTGO_TEST_REGISTRY_USERNAME_UNSET is not set",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, opts, err := remoteReference(tc.tag, registries, tc.insecure)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+				return
+			}
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			assert.Equal(t, tc.wantScheme, ref.Context().Scheme(), "Case %d: %s", i, tc.name)
+			assert.NotEmpty(t, opts, "Case %d: %s", i, tc.name)
+		})
+	}
+}
+
+// TestRemoteReferenceRetryAttempts verifies that a request to a registry with a retry policy is made at most the
+// configured number of times.
+func TestRemoteReferenceRetryAttempts(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	registry := strings.TrimPrefix(server.URL, "http://")
+	ref, opts, err := remoteReference(registry+"/foo:1.0.0", map[string]distgo.DockerRegistryParam{
+		registry: {
+			Insecure: true,
+			Retry: &distgo.DockerRegistryRetryParam{
+				Attempts:       3,
+				InitialBackoff: time.Millisecond,
+			},
+		},
+	}, false)
+	require.NoError(t, err)
+
+	_, err = remote.Head(ref, opts...)
+	require.Error(t, err)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestDaemonRegistryParamForTag(t *testing.T) {
+	registries := map[string]distgo.DockerRegistryParam{
+		"registry.internal.example.com:5000": {
+			Insecure: true,
+		},
+		"registry.example.com": {
+			Credentials: &distgo.DockerRegistryCredentialsParam{
+				TokenEnvVar: "DISTGO_TEST_REGISTRY_TOKEN",
+			},
+		},
+		"registry.retry.example.com": {
+			Retry: &distgo.DockerRegistryRetryParam{
+				Attempts: 3,
+			},
+		},
+	}
+
+	for i, tc := range []struct {
+		name         string
+		tag          string
+		wantAttempts int
+		wantErr      string
+	}{
+		{
+			name: "registry without configuration",
+			tag:  "registry.other.example.com/foo:1.0.0",
+		},
+		{
+			name:         "registry with retry policy",
+			tag:          "registry.retry.example.com/foo:1.0.0",
+			wantAttempts: 3,
+		},
+		{
+			name:    "registry configured as insecure",
+			tag:     "registry.internal.example.com:5000/foo:1.0.0",
+			wantErr: "the configuration of the registry of tag registry.internal.example.com:5000/foo:1.0.0 specifies insecure or credentials, which are not supported when pushing an image using the Docker daemon: configure the registry in the Docker daemon or use a Docker builder that writes an OCI layout",
+		},
+		{
+			name:    "registry configured with credentials",
+			tag:     "registry.example.com/foo:1.0.0",
+			wantErr: "the configuration of the registry of tag registry.example.com/foo:1.0.0 specifies insecure or credentials, which are not supported when pushing an image using the Docker daemon: configure the registry in the Docker daemon or use a Docker builder that writes an OCI layout",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			registryParam, err := daemonRegistryParamForTag(tc.tag, registries)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+				return
+			}
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			if tc.wantAttempts == 0 {
+				assert.Nil(t, registryParam.Retry, "Case %d: %s", i, tc.name)
+				return
+			}
+			require.NotNil(t, registryParam.Retry, "Case %d: %s", i, tc.name)
+			assert.Equal(t, tc.wantAttempts, registryParam.Retry.Attempts, "Case %d: %s", i, tc.name)
+		})
+	}
+}
+
+func TestRunWithRetry(t *testing.T) {
+	calls := 0
+	err := runWithRetry(&distgo.DockerRegistryRetryParam{Attempts: 3}, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("push failed")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	err = runWithRetry(nil, func() error {
+		calls++
+		return errors.New("push failed")
+	})
+	require.EqualError(t, err, "push failed")
+	assert.Equal(t, 1, calls)
+}
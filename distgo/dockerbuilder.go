@@ -31,6 +31,18 @@ type DockerBuilder interface {
 	RunDockerBuild(dockerID DockerID, productTaskOutputInfo ProductTaskOutputInfo, verbose, dryRun bool, stdout io.Writer) error
 }
 
+// StagedDockerfileDockerBuilder is implemented by a DockerBuilder that builds from the Dockerfile returned by
+// ProductDockerfilePath, and can therefore build from the rendered Dockerfile that the Docker build task stages outside
+// of the source tree. The Docker build task fails if rendering the templates in a Dockerfile changes its content and
+// the DockerBuilder does not support staged Dockerfiles, since the rendered file cannot be provided to it otherwise.
+type StagedDockerfileDockerBuilder interface {
+	DockerBuilder
+
+	// SupportsStagedDockerfile returns true if the DockerBuilder builds from the RenderedDockerfilePath of its
+	// DockerBuilderOutputInfo when it is set.
+	SupportsStagedDockerfile() (bool, error)
+}
+
 type DockerBuilderFactory interface {
 	NewDockerBuilder(typeName string, cfgYMLBytes []byte) (DockerBuilder, error)
 	ConfigUpgrader(typeName string) (ConfigUpgrader, error)
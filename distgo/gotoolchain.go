@@ -0,0 +1,81 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// GoToolchainParam specifies the Go toolchain used to build a product.
+type GoToolchainParam struct {
+	// Binary is the path to the "go" binary that is used to build the product. A relative path that contains a path
+	// separator is resolved against the project directory and a name without a path separator is looked up on the
+	// PATH (for example, "go1.22.3" for a toolchain installed using "golang.org/dl"). If empty, "go" is used.
+	Binary string `json:"binary,omitempty"`
+
+	// Toolchain is the value of the GOTOOLCHAIN environment variable that is set when the "go" binary is invoked. For
+	// example, "go1.22.3+path" uses the "go1.22.3" toolchain installed on the PATH.
+	Toolchain string `json:"toolchain,omitempty"`
+}
+
+// Command returns a command that runs the "go" binary of the toolchain with the provided arguments. A relative binary
+// path is resolved against the provided project directory. The working directory and the GOTOOLCHAIN environment
+// variable of the command are not set: the caller must set them (the values returned by Environment must be added to
+// the environment of the command).
+func (p GoToolchainParam) Command(projectDir string, args ...string) *exec.Cmd {
+	binary := "go"
+	if p.Binary != "" {
+		binary = p.Binary
+		if !filepath.IsAbs(binary) && strings.ContainsRune(binary, filepath.Separator) {
+			binary = filepath.Join(projectDir, binary)
+		}
+	}
+	return exec.Command(binary, args...)
+}
+
+// Environment returns the environment variables that select the toolchain in the form "KEY=value".
+func (p GoToolchainParam) Environment() []string {
+	if p.Toolchain == "" {
+		return nil
+	}
+	return []string{"GOTOOLCHAIN=" + p.Toolchain}
+}
+
+// Version returns the version of the Go toolchain used to build products in the provided project directory (for
+// example, "go1.22.3").
+func (p GoToolchainParam) Version(projectDir string) (string, error) {
+	cmd := p.Command(projectDir, "env", "GOVERSION")
+	cmd.Dir = projectDir
+	cmd.Env = append(cmd.Environ(), p.Environment()...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to determine version of Go toolchain")
+	}
+	return GoVersion(string(output)), nil
+}
+
+// GoVersion returns the Go release version of the provided version string, which omits any suffix such as the enabled
+// experiments (for example, "go1.22.3" for "go1.22.3 X:boringcrypto").
+func GoVersion(version string) string {
+	version = strings.TrimSpace(version)
+	if idx := strings.IndexAny(version, " \t"); idx != -1 {
+		version = version[:idx]
+	}
+	return version
+}
@@ -16,6 +16,10 @@ package distgo
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/pkg/errors"
@@ -45,6 +49,30 @@ type BuildParam struct {
 	// "distgo/main".
 	MainPkg string
 
+	// Mode is the build mode of the product, which determines the kind and names of the files that the build creates.
+	// If empty, the product is built as an executable.
+	Mode BuildMode
+
+	// GoToolchain specifies the Go toolchain that is used to build the product. If empty, the "go" binary on the PATH
+	// is used.
+	GoToolchain GoToolchainParam
+
+	// PGOProfile is the path to the profile used for profile-guided optimization relative to the project directory. If
+	// empty, the "default.pgo" file in the directory of the main package is used if it exists. If "off", profile-guided
+	// optimization is disabled.
+	PGOProfile string
+
+	// Cover specifies the coverage-instrumented builds of the product. They are only created when requested (for
+	// example, using the "--cover" flag of the "build" task) and are written to their own output directory.
+	Cover BuildCoverParam
+
+	// SizeBudget specifies the maximum sizes of the outputs of the build. A build whose output exceeds its budget fails.
+	SizeBudget BuildSizeBudgetParam
+
+	// WindowsResources specifies the version information, icon and application manifest that are compiled into the
+	// executables built for Windows. If nil, no resources are compiled into the executables.
+	WindowsResources *WindowsResourcesParam
+
 	// BuildArgsScript is the content of a script that is written to a file and run before this product is built
 	// to provide supplemental build arguments for the product. The content of this value is written to a file and
 	// executed. The script process uses the project directory as its working directory and inherits the environment
@@ -64,6 +92,22 @@ type BuildParam struct {
 	// ldflag.
 	VersionVar string
 
+	// Flags specifies the flags provided to the "build" command for every build target.
+	Flags BuildFlagsParam
+
+	// OSFlags specifies the flags provided to the "build" command that are specific to an OS. The key is the OS portion
+	// of the "{OS}-{Arch}" target. Values in this map are applied after "Flags" but before "ArchFlags".
+	OSFlags map[string]BuildFlagsParam
+
+	// ArchFlags specifies the flags provided to the "build" command that are specific to an architecture. The key is the
+	// Arch portion of the "{OS}-{Arch}" target. Values in this map are applied after "OSFlags" but before
+	// "OSArchsFlags".
+	ArchFlags map[string]BuildFlagsParam
+
+	// OSArchsFlags specifies the flags provided to the "build" command that are specific to an OS/Architecture. The key
+	// is the OS/Arch formatted in the form "{OS}-{Arch}". Values in this map are applied last.
+	OSArchsFlags map[string]BuildFlagsParam
+
 	// Environment specifies values for the environment variables that should be set for the build. For example,
 	// a value of map[string]string{"CGO_ENABLED": "0"} would build with CGo disabled.
 	Environment map[string]string
@@ -87,15 +131,225 @@ type BuildParam struct {
 	// Refer to the documentation for the distgo.BuildScriptEnvVariables function for the extra environment variables.
 	Script string
 
-	// OSArchs specifies the GOOS and GOARCH pairs for which the product is built.
+	// OSArchs specifies the GOOS and GOARCH pairs for which the product is built. May be empty if only the variants of
+	// the product are built.
+	OSArchs []osarch.OSArch
+
+	// Variants specifies the named variants of the build. Each variant is built in addition to the default build and is
+	// written to its own output path.
+	Variants map[BuildVariantID]BuildVariantParam
+}
+
+// BuildCoverParam specifies the coverage-instrumented builds of a product.
+type BuildCoverParam struct {
+	// Enabled specifies that the build is instrumented for coverage using the "-cover" flag. It is only set for the
+	// build parameters returned by BuildParam.CoverBuild.
+	Enabled bool
+
+	// OutputDir is the output directory for the coverage-instrumented builds. It is used in place of the OutputDir of the
+	// build, so the coverage-instrumented executables never overwrite the regular ones. If empty, "out/cover" is used.
+	OutputDir string
+
+	// Coverpkg are the patterns of the packages that are instrumented, which are provided to the "build" command using
+	// the "-coverpkg" flag. If empty, only the main package is instrumented.
+	Coverpkg []string
+}
+
+const defaultCoverOutputDir = "out/cover"
+
+// OutputDirOrDefault returns the output directory for the coverage-instrumented builds.
+func (p BuildCoverParam) OutputDirOrDefault() string {
+	if p.OutputDir == "" {
+		return defaultCoverOutputDir
+	}
+	return p.OutputDir
+}
+
+// CoverBuild returns a copy of the build parameter for the coverage-instrumented builds of the product. The size budget
+// does not apply to the coverage-instrumented builds.
+func (p BuildParam) CoverBuild() BuildParam {
+	p.OutputDir = p.Cover.OutputDirOrDefault()
+	p.Cover.Enabled = true
+	p.SizeBudget = BuildSizeBudgetParam{}
+	return p
+}
+
+// BuildSizeBudgetParam specifies the maximum sizes in bytes of the outputs of a build. A value of 0 specifies that the
+// size is not limited.
+type BuildSizeBudgetParam struct {
+	// MaxSize is the maximum size of the output for every OS/Arch that does not have an entry in OSArchsMaxSize.
+	MaxSize int64
+
+	// OSArchsMaxSize specifies the maximum sizes that are specific to an OS/Arch. The key is the OS/Arch formatted in
+	// the form "{OS}-{Arch}".
+	OSArchsMaxSize map[string]int64
+}
+
+// MaxSizeForOSArch returns the maximum size of the output for the provided OS/Arch. Returns 0 if the size is not
+// limited.
+func (p BuildSizeBudgetParam) MaxSizeForOSArch(osArch osarch.OSArch) int64 {
+	if maxSize, ok := p.OSArchsMaxSize[osArch.String()]; ok {
+		return maxSize
+	}
+	return p.MaxSize
+}
+
+// CheckSize returns an error if size, the size in bytes of the output at outputPath, exceeds the maximum size of the
+// output for the provided OS/Arch.
+func (p BuildSizeBudgetParam) CheckSize(osArch osarch.OSArch, outputPath string, size int64) error {
+	maxSize := p.MaxSizeForOSArch(osArch)
+	if maxSize == 0 || size <= maxSize {
+		return nil
+	}
+	return errors.Errorf("size of output %s is %d bytes, which exceeds its size budget of %d bytes by %d bytes", outputPath, size, maxSize, size-maxSize)
+}
+
+// BuildMode is the kind of output created by the build of a product.
+type BuildMode string
+
+const (
+	// BuildModeExe builds an executable. It is the default build mode.
+	BuildModeExe BuildMode = "exe"
+	// BuildModeCShared builds a C shared library and the C header file for its exported functions.
+	BuildModeCShared BuildMode = "c-shared"
+	// BuildModeCArchive builds a C archive and the C header file for its exported functions.
+	BuildModeCArchive BuildMode = "c-archive"
+	// BuildModePlugin builds a Go plugin.
+	BuildModePlugin BuildMode = "plugin"
+)
+
+// BuildModes returns all of the supported build modes.
+func BuildModes() []BuildMode {
+	return []BuildMode{BuildModeExe, BuildModeCShared, BuildModeCArchive, BuildModePlugin}
+}
+
+// IsLibrary returns true if the build mode creates a library or plugin rather than an executable.
+func (m BuildMode) IsLibrary() bool {
+	return m != "" && m != BuildModeExe
+}
+
+// BuildVariantID identifies a variant of the build of a product.
+type BuildVariantID string
+
+// BuildVariantParam specifies the differences between a build variant and the default build of a product.
+type BuildVariantParam struct {
+	// Environment specifies values for the environment variables that are set for the variant. These values are set
+	// after all of the environment variables of the default build.
+	Environment map[string]string
+
+	// Flags specifies the flags for the variant, which are applied on top of the flags of the default build for the
+	// target.
+	Flags BuildFlagsParam
+
+	// OSArchs specifies the GOOS and GOARCH pairs for which the variant is built.
 	OSArchs []osarch.OSArch
 }
 
+// BuildFlagsParam specifies the flags provided to the "build" command. A nil value means that the value is not
+// specified, in which case the value from the previous layer (if any) is used.
+type BuildFlagsParam struct {
+	// Tags are the build tags provided using the "-tags" flag.
+	Tags []string
+
+	// LdflagsVars is a map from the full path of a string variable to its value that is set using a "-X" ldflag. The
+	// values are templates that are rendered for every build target: refer to the documentation of BuildArgs for the
+	// template functions that can be used. When layers are applied, the maps are merged.
+	LdflagsVars map[string]string
+
+	// Gcflags is the value of the "-gcflags" flag.
+	Gcflags *string
+
+	// Asmflags is the value of the "-asmflags" flag.
+	Asmflags *string
+
+	// Trimpath specifies whether the "-trimpath" flag is provided.
+	Trimpath *bool
+
+	// Buildmode is the value of the "-buildmode" flag.
+	Buildmode *string
+
+	// Race specifies whether the "-race" flag is provided.
+	Race *bool
+
+	// Strip specifies whether the "-s -w" ldflags are provided.
+	Strip *bool
+}
+
+// withOverrides returns the flags that result from applying the values specified in the provided flags on top of the
+// receiver.
+func (f BuildFlagsParam) withOverrides(overrides BuildFlagsParam) BuildFlagsParam {
+	if overrides.Tags != nil {
+		f.Tags = overrides.Tags
+	}
+	if len(overrides.LdflagsVars) > 0 {
+		ldflagsVars := maps.Clone(f.LdflagsVars)
+		if ldflagsVars == nil {
+			ldflagsVars = make(map[string]string)
+		}
+		maps.Copy(ldflagsVars, overrides.LdflagsVars)
+		f.LdflagsVars = ldflagsVars
+	}
+	if overrides.Gcflags != nil {
+		f.Gcflags = overrides.Gcflags
+	}
+	if overrides.Asmflags != nil {
+		f.Asmflags = overrides.Asmflags
+	}
+	if overrides.Trimpath != nil {
+		f.Trimpath = overrides.Trimpath
+	}
+	if overrides.Buildmode != nil {
+		f.Buildmode = overrides.Buildmode
+	}
+	if overrides.Race != nil {
+		f.Race = overrides.Race
+	}
+	if overrides.Strip != nil {
+		f.Strip = overrides.Strip
+	}
+	return f
+}
+
+// FlagsForOSArch returns the flags for the provided build target, which are the result of applying the OS, Arch and
+// OS/Arch-specific flags on top of Flags in that order.
+func (p *BuildParam) FlagsForOSArch(osArch osarch.OSArch) BuildFlagsParam {
+	return p.Flags.
+		withOverrides(p.OSFlags[osArch.OS]).
+		withOverrides(p.ArchFlags[osArch.Arch]).
+		withOverrides(p.OSArchsFlags[osArch.String()])
+}
+
 type BuildOutputInfo struct {
 	BuildNameTemplateRendered string          `json:"buildNameTemplateRendered"`
 	BuildOutputDir            string          `json:"buildOutputDir"`
 	MainPkg                   string          `json:"mainPkg"`
 	OSArchs                   []osarch.OSArch `json:"osArchs"`
+	// Mode is the build mode of the product. Empty if the product is built as an executable.
+	Mode BuildMode `json:"mode,omitempty"`
+	// Variants is a map from the ID of each build variant to the OS/architectures for which it is built.
+	Variants map[BuildVariantID][]osarch.OSArch `json:"variants,omitempty"`
+	// GoToolchain is the Go toolchain that builds the product.
+	GoToolchain GoToolchainParam `json:"goToolchain,omitzero"`
+}
+
+// VariantIDs returns the IDs of the build variants in sorted order.
+func (boi *BuildOutputInfo) VariantIDs() []BuildVariantID {
+	return slices.Sorted(maps.Keys(boi.Variants))
+}
+
+// VariantOSArchs returns the OS/Archs for which the provided build variant is built. If the variant is empty, returns
+// the OS/Archs of the default build.
+func (boi *BuildOutputInfo) VariantOSArchs(variant BuildVariantID) []osarch.OSArch {
+	if variant == "" {
+		return boi.OSArchs
+	}
+	return boi.Variants[variant]
+}
+
+// ArtifactNames returns the names of the files created by building the product for the provided OS/Arch. Refer to the
+// documentation of BuildArtifactNames for the order of the names.
+func (boi *BuildOutputInfo) ArtifactNames(osArch osarch.OSArch) []string {
+	return BuildArtifactNames(boi.BuildNameTemplateRendered, osArch, boi.Mode)
 }
 
 func (p *BuildParam) ToBuildOutputInfo(productName string, version string) (BuildOutputInfo, error) {
@@ -103,21 +357,123 @@ func (p *BuildParam) ToBuildOutputInfo(productName string, version string) (Buil
 	if err != nil {
 		return BuildOutputInfo{}, errors.Wrapf(err, "failed to render name template")
 	}
+	var variants map[BuildVariantID][]osarch.OSArch
+	if len(p.Variants) > 0 {
+		variants = make(map[BuildVariantID][]osarch.OSArch, len(p.Variants))
+		for variantID, variantParam := range p.Variants {
+			variants[variantID] = variantParam.OSArchs
+		}
+	}
 	return BuildOutputInfo{
 		BuildNameTemplateRendered: renderedName,
 		BuildOutputDir:            p.OutputDir,
 		MainPkg:                   p.MainPkg,
+		Mode:                      p.Mode,
 		OSArchs:                   p.OSArchs,
+		Variants:                  variants,
+		GoToolchain:               p.GoToolchain,
 	}, nil
 }
 
-func (p *BuildParam) BuildArgs(productTaskOutputInfo ProductTaskOutputInfo) ([]string, error) {
+// BuildArgs returns the arguments provided to the "build" command when building the provided variant of the product
+// for the provided target (an empty variant is the default build). The arguments consist of the output of
+// BuildArgsScript followed by the arguments for the flags for the target and variant. The ldflag for VersionVar, the
+// "-X" ldflags for the ldflags variables and the "-s -w" ldflags for Strip are provided as a single "-ldflags"
+// argument. The values of the ldflags variables are rendered with the following template functions:
+//   - {{Product}}: the name of the product
+//   - {{Version}}: the version of the project
+//   - {{OSArch}}: the OS/architecture of the build target
+//   - {{BuildDate}}: the current time in RFC 3339 format (UTC)
+//   - {{Dirty}}: "true" if the version of the project has the ".dirty" suffix, "false" otherwise
+func (p *BuildParam) BuildArgs(productTaskOutputInfo ProductTaskOutputInfo, osArch osarch.OSArch, variant BuildVariantID) ([]string, error) {
 	buildArgs, err := BuildArgsFromScript(productTaskOutputInfo, p.BuildArgsScript)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to execute script to generate build arguments")
 	}
+
+	flags := p.FlagsForOSArch(osArch)
+	if variant != "" {
+		flags = flags.withOverrides(p.Variants[variant].Flags)
+	}
+	if len(flags.Tags) > 0 {
+		buildArgs = append(buildArgs, "-tags", strings.Join(flags.Tags, ","))
+	}
+	if flags.Gcflags != nil && *flags.Gcflags != "" {
+		buildArgs = append(buildArgs, "-gcflags", *flags.Gcflags)
+	}
+	if flags.Asmflags != nil && *flags.Asmflags != "" {
+		buildArgs = append(buildArgs, "-asmflags", *flags.Asmflags)
+	}
+	if flags.Trimpath != nil && *flags.Trimpath {
+		buildArgs = append(buildArgs, "-trimpath")
+	}
+	if p.Mode.IsLibrary() {
+		buildArgs = append(buildArgs, "-buildmode", string(p.Mode))
+	} else if flags.Buildmode != nil && *flags.Buildmode != "" {
+		buildArgs = append(buildArgs, "-buildmode", *flags.Buildmode)
+	}
+	if flags.Race != nil && *flags.Race {
+		buildArgs = append(buildArgs, "-race")
+	}
+	if p.Cover.Enabled {
+		buildArgs = append(buildArgs, "-cover")
+		if len(p.Cover.Coverpkg) > 0 {
+			buildArgs = append(buildArgs, "-coverpkg", strings.Join(p.Cover.Coverpkg, ","))
+		}
+	}
+	if p.PGOProfile == PGOProfileOff {
+		buildArgs = append(buildArgs, "-pgo", PGOProfileOff)
+	} else if pgoProfile := p.PGOProfilePath(productTaskOutputInfo.Project.ProjectDir); pgoProfile != "" {
+		buildArgs = append(buildArgs, "-pgo", pgoProfile)
+	}
+
+	var ldflags []string
 	if versionVar := p.VersionVar; versionVar != "" {
-		buildArgs = append(buildArgs, "-ldflags", fmt.Sprintf("-X %s=%s", versionVar, productTaskOutputInfo.Project.Version))
+		ldflags = append(ldflags, fmt.Sprintf("-X %s=%s", versionVar, productTaskOutputInfo.Project.Version))
+	}
+	for _, k := range slices.Sorted(maps.Keys(flags.LdflagsVars)) {
+		val, err := renderLdflagsVarValue(flags.LdflagsVars[k], productTaskOutputInfo, osArch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render value of ldflags variable %s", k)
+		}
+		quoted, err := quoteLdflagsArg(fmt.Sprintf("%s=%s", k, val))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value for ldflags variable %s", k)
+		}
+		ldflags = append(ldflags, "-X "+quoted)
+	}
+	if flags.Strip != nil && *flags.Strip {
+		ldflags = append(ldflags, "-s", "-w")
+	}
+	if len(ldflags) > 0 {
+		buildArgs = append(buildArgs, "-ldflags", strings.Join(ldflags, " "))
 	}
 	return buildArgs, nil
 }
+
+func renderLdflagsVarValue(val string, productTaskOutputInfo ProductTaskOutputInfo, osArch osarch.OSArch) (string, error) {
+	if !strings.Contains(val, "{{") {
+		return val, nil
+	}
+	return RenderTemplate(val, nil,
+		ProductTemplateFunction(productTaskOutputInfo.Product.Name),
+		VersionTemplateFunction(productTaskOutputInfo.Project.Version),
+		OSArchTemplateFunction(osArch),
+		BuildDateTemplateFunction(time.Now()),
+		DirtyTemplateFunction(strings.HasSuffix(productTaskOutputInfo.Project.Version, ".dirty")),
+	)
+}
+
+// quoteLdflagsArg quotes the provided argument so that the "go" command parses it as a single ldflags argument.
+func quoteLdflagsArg(arg string) (string, error) {
+	if !strings.ContainsAny(arg, " \t\n'\"") {
+		return arg, nil
+	}
+	if !strings.Contains(arg, "'") {
+		return "'" + arg + "'", nil
+	}
+	if !strings.Contains(arg, `"`) {
+		return `"` + arg + `"`, nil
+	}
+	return "", errors.Errorf("%q contains both single and double quotes", arg)
+}
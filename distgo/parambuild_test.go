@@ -0,0 +1,180 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo_test
+
+import (
+	"testing"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/godel/v2/pkg/osarch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildParamBuildArgs(t *testing.T) {
+	for i, tc := range []struct {
+		name       string
+		buildParam distgo.BuildParam
+		osArch     osarch.OSArch
+		want       []string
+	}{
+		{
+			"no flags",
+			distgo.BuildParam{},
+			osarch.OSArch{OS: "linux", Arch: "amd64"},
+			nil,
+		},
+		{
+			"all flags with version variable",
+			distgo.BuildParam{
+				VersionVar: "main.version",
+				Flags: distgo.BuildFlagsParam{
+					Tags: []string{"netgo", "osusergo"},
+					LdflagsVars: map[string]string{
+						"main.target":  "{{Product}}-{{OSArch}}",
+						"main.message": "hello world",
+					},
+					Gcflags:   new("all=-N -l"),
+					Asmflags:  new("-trimpath"),
+					Trimpath:  new(true),
+					Buildmode: new("pie"),
+					Strip:     new(true),
+				},
+			},
+			osarch.OSArch{OS: "linux", Arch: "amd64"},
+			[]string{
+				"-tags", "netgo,osusergo",
+				"-gcflags", "all=-N -l",
+				"-asmflags", "-trimpath",
+				"-trimpath",
+				"-buildmode", "pie",
+				"-ldflags", "-X main.version=1.0.0 -X 'main.message=hello world' -X main.target=foo-linux-amd64 -s -w",
+			},
+		},
+		{
+			"OS, arch and OS/arch flags are applied in order",
+			distgo.BuildParam{
+				Flags: distgo.BuildFlagsParam{
+					Tags: []string{"base"},
+					LdflagsVars: map[string]string{
+						"main.a": "base",
+						"main.b": "base",
+					},
+					Strip: new(true),
+				},
+				OSFlags: map[string]distgo.BuildFlagsParam{
+					"darwin": {
+						Tags: []string{"darwin"},
+						LdflagsVars: map[string]string{
+							"main.b": "darwin",
+						},
+					},
+				},
+				ArchFlags: map[string]distgo.BuildFlagsParam{
+					"arm64": {
+						Strip: new(false),
+					},
+				},
+				OSArchsFlags: map[string]distgo.BuildFlagsParam{
+					"darwin-arm64": {
+						Tags: []string{},
+					},
+				},
+			},
+			osarch.OSArch{OS: "darwin", Arch: "arm64"},
+			[]string{
+				"-ldflags", "-X main.a=base -X main.b=darwin",
+			},
+		},
+		{
+			"flags for other targets are not applied",
+			distgo.BuildParam{
+				Flags: distgo.BuildFlagsParam{
+					Tags: []string{"base"},
+				},
+				OSFlags: map[string]distgo.BuildFlagsParam{
+					"darwin": {
+						Tags: []string{"darwin"},
+					},
+				},
+			},
+			osarch.OSArch{OS: "linux", Arch: "arm64"},
+			[]string{
+				"-tags", "base",
+			},
+		},
+		{
+			"library build mode is provided as the buildmode flag",
+			distgo.BuildParam{
+				Mode: distgo.BuildModeCShared,
+				Flags: distgo.BuildFlagsParam{
+					Trimpath: new(true),
+				},
+			},
+			osarch.OSArch{OS: "linux", Arch: "amd64"},
+			[]string{
+				"-trimpath",
+				"-buildmode", "c-shared",
+			},
+		},
+		{
+			"PGO profile is provided as the pgo flag",
+			distgo.BuildParam{
+				PGOProfile: "profiles/service.pgo",
+			},
+			osarch.OSArch{OS: "linux", Arch: "amd64"},
+			[]string{
+				"-pgo", "profiles/service.pgo",
+			},
+		},
+		{
+			"coverage-instrumented build provides the cover and coverpkg flags",
+			distgo.BuildParam{
+				OutputDir: "out/build",
+				Cover: distgo.BuildCoverParam{
+					Coverpkg: []string{"./foo/...", "./bar/..."},
+				},
+			}.CoverBuild(),
+			osarch.OSArch{OS: "linux", Arch: "amd64"},
+			[]string{
+				"-cover",
+				"-coverpkg", "./foo/...,./bar/...",
+			},
+		},
+		{
+			"PGO can be disabled",
+			distgo.BuildParam{
+				PGOProfile: distgo.PGOProfileOff,
+			},
+			osarch.OSArch{OS: "linux", Arch: "amd64"},
+			[]string{
+				"-pgo", "off",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.buildParam.BuildArgs(distgo.ProductTaskOutputInfo{
+				Project: distgo.ProjectInfo{
+					Version: "1.0.0",
+				},
+				Product: distgo.ProductOutputInfo{
+					Name: "foo",
+				},
+			}, tc.osArch, "")
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			assert.Equal(t, tc.want, got, "Case %d: %s", i, tc.name)
+		})
+	}
+}
@@ -88,6 +88,16 @@ type DisterParam struct {
 
 	// Dister is the Dister that performs the dist operation for this parameter.
 	Dister Dister
+
+	// InputDockerExports stores the ProductDockerIDs of the Docker configurations whose exported archives are copied to
+	// the dist work directory before the dist operation is run. The IDs must be unique and in expanded form
+	// ("{{ProductID}}.{{DockerID}}").
+	InputDockerExports []ProductDockerID
+
+	// BuildVariant is the build variant of the product whose executables are used by the dist. If empty, the
+	// executables of the default build are used. The executables of the dependencies of the product are always those
+	// of their default builds.
+	BuildVariant BuildVariantID
 }
 
 type InputDirParam struct {
@@ -99,6 +109,12 @@ type DistOutputInfo struct {
 	DistNameTemplateRendered string   `json:"distNameTemplateRendered"`
 	DistArtifactNames        []string `json:"distArtifactNames"`
 	PackagingExtension       string   `json:"packagingExtension"`
+	// InputDockerExports are the ProductDockerIDs of the Docker configurations whose exported archives are copied to
+	// the dist work directory before the Dister runs.
+	InputDockerExports []ProductDockerID `json:"inputDockerExports"`
+	// BuildVariant is the build variant of the product whose executables are used by the dist. Empty for the default
+	// build.
+	BuildVariant BuildVariantID `json:"buildVariant,omitempty"`
 }
 
 func (p *DisterParam) ToDistOutputInfo(productName, version string) (DistOutputInfo, error) {
@@ -118,6 +134,8 @@ func (p *DisterParam) ToDistOutputInfo(productName, version string) (DistOutputI
 		DistNameTemplateRendered: renderedName,
 		DistArtifactNames:        artifactNames,
 		PackagingExtension:       packagingExtension,
+		InputDockerExports:       p.InputDockerExports,
+		BuildVariant:             p.BuildVariant,
 	}, nil
 }
 
@@ -15,7 +15,9 @@
 package distgo
 
 import (
+	"fmt"
 	"sort"
+	"time"
 
 	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/pkg/errors"
@@ -37,6 +39,14 @@ func (a ByDockerTagID) Len() int           { return len(a) }
 func (a ByDockerTagID) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a ByDockerTagID) Less(i, j int) bool { return a[i] < a[j] }
 
+type DockerMirrorID string
+
+type ByDockerMirrorID []DockerMirrorID
+
+func (a ByDockerMirrorID) Len() int           { return len(a) }
+func (a ByDockerMirrorID) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a ByDockerMirrorID) Less(i, j int) bool { return a[i] < a[j] }
+
 type DockerParam struct {
 	// Repository is the Docker repository. This value is made available to TagTemplates as {{Repository}}.
 	Repository string
@@ -56,6 +66,38 @@ type DockerParam struct {
 
 	// DockerBuilderParams contains the Docker params for this distribution.
 	DockerBuilderParams map[DockerID]DockerBuilderParam
+
+	// Registries contains the settings for the registries images are pushed to, keyed by registry host in the form
+	// returned by name.Registry.RegistryStr (for example, "index.docker.io" for Docker Hub).
+	Registries map[string]DockerRegistryParam
+}
+
+type DockerRegistryParam struct {
+	// Insecure allows pushing to the registry over plain HTTP or with an untrusted TLS certificate.
+	Insecure bool
+
+	// Credentials specifies where the credentials for the registry are read from. If nil, the credentials from the
+	// Docker configuration of the user are used.
+	Credentials *DockerRegistryCredentialsParam
+
+	// Retry specifies the retry policy for requests to the registry. If nil, the default policy is used.
+	Retry *DockerRegistryRetryParam
+}
+
+// DockerRegistryCredentialsParam specifies the environment variables that hold the credentials for a registry. Either
+// both UsernameEnvVar and PasswordEnvVar or TokenEnvVar are non-empty.
+type DockerRegistryCredentialsParam struct {
+	UsernameEnvVar string
+	PasswordEnvVar string
+	TokenEnvVar    string
+}
+
+type DockerRegistryRetryParam struct {
+	// Attempts is the maximum number of times a request is attempted.
+	Attempts int
+
+	// InitialBackoff is the time waited before the first retry. The wait doubles after each retry.
+	InitialBackoff time.Duration
 }
 
 type DockerOutputInfos struct {
@@ -78,15 +120,29 @@ type DockerBuilderOutputInfo struct {
 	// running the task resolves it so that a DockerBuilder built from a different version of distgo does not have to
 	// derive it. Empty if the output info came from a distgo that predates the field or if the product has no Docker
 	// output directory.
-	OutputDir             string                              `json:"outputDir"`
-	ContextDir            string                              `json:"contextDir"`
-	DockerfilePath        string                              `json:"dockerfilePath"`
-	InputProductsDir      string                              `json:"inputProductsDir"`
-	RenderedTags          []string                            `json:"renderedDockerTags"`
-	RenderedTagsMap       map[DockerTagID]string              `json:"renderedDockerTagsMap"`
-	InputBuilds           map[ProductID]map[OSArchID]struct{} `json:"inputBuilds"`
-	InputDists            map[ProductID]map[DistID]struct{}   `json:"inputDists"`
-	InputDistsOutputPaths map[ProductID]map[DistID][]string   `json:"inputDistsOutputPaths"`
+	OutputDir      string `json:"outputDir"`
+	ContextDir     string `json:"contextDir"`
+	DockerfilePath string `json:"dockerfilePath"`
+	// RenderedDockerfilePath is the absolute path of the rendered Dockerfile the DockerBuilder should build from. The
+	// Docker build task writes the rendered file to a staging location outside of the source tree for the duration of
+	// the build, so the Dockerfile in ContextDir is not modified. Empty if the output info came from a distgo that
+	// predates the field: use ProductDockerfilePath to resolve the file to build from.
+	RenderedDockerfilePath string                              `json:"renderedDockerfilePath"`
+	InputProductsDir       string                              `json:"inputProductsDir"`
+	RenderedTags           []string                            `json:"renderedDockerTags"`
+	RenderedTagsMap        map[DockerTagID]string              `json:"renderedDockerTagsMap"`
+	InputBuilds            map[ProductID]map[OSArchID]struct{} `json:"inputBuilds"`
+	InputDists             map[ProductID]map[DistID]struct{}   `json:"inputDists"`
+	InputDistsOutputPaths  map[ProductID]map[DistID][]string   `json:"inputDistsOutputPaths"`
+	// InputBuildVariants stores the build variant of the input builds that are builds of a variant rather than of the
+	// default build. The builds of variants are placed at the same location in the context directory as the default
+	// builds.
+	InputBuildVariants map[ProductID]map[OSArchID]BuildVariantID `json:"inputBuildVariants,omitempty"`
+	// ExportArtifactNames are the file names of the archives exported for the image, which are written next to
+	// OutputDir. Use ProductDockerExportArtifactPaths to resolve their paths.
+	ExportArtifactNames []string `json:"exportArtifactNames"`
+	// RenderedMirrorTags are the rendered tags that the image is pushed to in each of the mirrors of the configuration.
+	RenderedMirrorTags map[DockerMirrorID][]string `json:"renderedMirrorTags"`
 }
 
 func (doi *DockerBuilderOutputInfo) InputBuildProductIDs() []ProductID {
@@ -136,6 +192,9 @@ func (p *DockerParam) ToDockerOutputInfos(productName, version string) (DockerOu
 			if err != nil {
 				return DockerOutputInfos{}, err
 			}
+			if dockerBuilderParam.Export != nil {
+				currDockerOutputInfo.ExportArtifactNames = dockerBuilderParam.Export.ArtifactNames(productName, version, dockerID)
+			}
 			dockerOutputInfos[dockerID] = currDockerOutputInfo
 		}
 	}
@@ -170,6 +229,10 @@ type DockerBuilderParam struct {
 	//   * {{InputDistArtifacts(productID, distID string) ([]string, error)}}: the paths to the dist artifacts for the specified input product
 	//   * {{Tag(productID, dockerID, tagKey string) (string, error)}}: the rendered tag for the specified Docker image tag
 	//   * {{Tags(productID, dockerID string) ([]string, error)}}: the rendered tags for the specified Docker image. Returned in the same order as defined in configuration.
+	//
+	// The rendered Dockerfile is written to a staging location for the build and provided to the DockerBuilder as
+	// DockerBuilderOutputInfo.RenderedDockerfilePath. If rendering changes the content of the file, the build fails
+	// unless the DockerBuilder implements StagedDockerfileDockerBuilder and supports staged Dockerfiles.
 	DockerfilePath string
 
 	// DisableTemplateRendering disables rendering the Go templates in the Dockerfile when set to true. This should only
@@ -209,6 +272,63 @@ type DockerBuilderParam struct {
 	//   * {{Repository}}: the Docker repository. If the repository is non-empty and does not end in a '/', appends '/'.
 	//   * {{RepositoryLiteral}}: the Docker repository exactly as specified (does not append a trailing '/')
 	TagTemplates TagTemplatesMap
+
+	// Tests specifies the assertions the "docker test" task makes about the image built by this builder. Nil if the
+	// configuration does not declare any tests.
+	Tests *DockerImageTestsParam
+
+	// Mirrors contains the additional repositories the "docker push" task pushes the image to.
+	Mirrors map[DockerMirrorID]DockerMirrorParam
+
+	// Export specifies the offline archives of the image that the "docker build" task writes after the image is built.
+	// Nil if the configuration does not export any archives.
+	Export *DockerExportParam
+}
+
+type DockerMirrorParam struct {
+	// Repository is the repository of the mirror. This value is made available to TagTemplates as {{Repository}} and
+	// {{RepositoryLiteral}}.
+	Repository string
+
+	// TagTemplates contains the templates for the tags the image is pushed to in the mirror. Supports the same template
+	// parameters as DockerBuilderParam.TagTemplates.
+	TagTemplates TagTemplatesMap
+}
+
+// DockerExportParam specifies the archives written from the OCI layout of a built image so that the image can be
+// distributed as files rather than pushed to a registry. The archives are written next to the Docker output directory
+// of the configuration (see ProductDockerExportArtifactPaths).
+type DockerExportParam struct {
+	// DockerArchive specifies whether a tarball that can be loaded using "docker load" is written. The image in the
+	// archive is tagged with all of the rendered tags of the configuration.
+	DockerArchive bool
+
+	// DockerArchivePlatform is the platform ("os/arch" or "os/arch/variant") of the image written to the Docker
+	// archive. If empty, the OCI layout must contain an image for a single platform.
+	DockerArchivePlatform string
+
+	// OCIArchive specifies whether a gzip-compressed tarball of the OCI layout is written. The index of the archived
+	// layout names the image by each of the rendered tags of the configuration.
+	OCIArchive bool
+}
+
+const (
+	DockerArchiveExtension = ".docker.tar"
+	OCIArchiveExtension    = ".oci.tar.gz"
+)
+
+// ArtifactNames returns the file names of the archives specified by the receiver, which are
+// "{{Product}}-{{Version}}-{{DockerID}}" followed by DockerArchiveExtension or OCIArchiveExtension.
+func (p *DockerExportParam) ArtifactNames(productName, version string, dockerID DockerID) []string {
+	baseName := fmt.Sprintf("%s-%s-%s", productName, version, dockerID)
+	var names []string
+	if p.DockerArchive {
+		names = append(names, baseName+DockerArchiveExtension)
+	}
+	if p.OCIArchive {
+		names = append(names, baseName+OCIArchiveExtension)
+	}
+	return names
 }
 
 type TagTemplatesMap struct {
@@ -217,22 +337,23 @@ type TagTemplatesMap struct {
 }
 
 func (p *DockerBuilderParam) ToDockerBuilderOutputInfo(productName, version, repository string) (DockerBuilderOutputInfo, error) {
-	renderedTagsMap := make(map[DockerTagID]string)
-	var renderedTags []string
-	for _, currTagTemplateKey := range p.TagTemplates.OrderedKeys {
-		currRenderedTag, err := RenderTemplate(p.TagTemplates.Templates[currTagTemplateKey], nil,
-			ProductTemplateFunction(productName),
-			VersionTemplateFunction(version),
-			RepositoryTemplateFunction(repository),
-			RepositoryLiteralTemplateFunction(repository),
-		)
-		if err != nil {
-			return DockerBuilderOutputInfo{}, err
+	renderedTags, renderedTagsMap, err := renderTagTemplates(p.TagTemplates, productName, version, repository)
+	if err != nil {
+		return DockerBuilderOutputInfo{}, err
+	}
+	var renderedMirrorTags map[DockerMirrorID][]string
+	if len(p.Mirrors) > 0 {
+		renderedMirrorTags = make(map[DockerMirrorID][]string)
+		for mirrorID, mirrorParam := range p.Mirrors {
+			currRenderedTags, _, err := renderTagTemplates(mirrorParam.TagTemplates, productName, version, mirrorParam.Repository)
+			if err != nil {
+				return DockerBuilderOutputInfo{}, errors.Wrapf(err, "failed to render tags for mirror %s", mirrorID)
+			}
+			renderedMirrorTags[mirrorID] = currRenderedTags
 		}
-		renderedTags = append(renderedTags, currRenderedTag)
-		renderedTagsMap[currTagTemplateKey] = currRenderedTag
 	}
 	var inputBuilds map[ProductID]map[OSArchID]struct{}
+	var inputBuildVariants map[ProductID]map[OSArchID]BuildVariantID
 	if len(p.InputBuilds) > 0 {
 		inputBuilds = make(map[ProductID]map[OSArchID]struct{})
 		for _, productBuildID := range p.InputBuilds {
@@ -247,6 +368,15 @@ func (p *DockerBuilderParam) ToDockerBuilderOutputInfo(productName, version, rep
 				inputBuilds[productID] = make(map[OSArchID]struct{})
 			}
 			inputBuilds[productID][OSArchID(buildID.String())] = struct{}{}
+			if variant := productBuildID.Variant(); variant != "" {
+				if inputBuildVariants == nil {
+					inputBuildVariants = make(map[ProductID]map[OSArchID]BuildVariantID)
+				}
+				if _, ok := inputBuildVariants[productID]; !ok {
+					inputBuildVariants[productID] = make(map[OSArchID]BuildVariantID)
+				}
+				inputBuildVariants[productID][OSArchID(buildID.String())] = variant
+			}
 		}
 	}
 	var inputDists map[ProductID]map[DistID]struct{}
@@ -286,5 +416,43 @@ func (p *DockerBuilderParam) ToDockerBuilderOutputInfo(productName, version, rep
 		InputBuilds:           inputBuilds,
 		InputDists:            inputDists,
 		InputDistsOutputPaths: inputDistsOutputPaths,
+		InputBuildVariants:    inputBuildVariants,
+		RenderedMirrorTags:    renderedMirrorTags,
 	}, nil
 }
+
+// PushTags returns the tags that the image is pushed to: the rendered tags followed by the rendered tags of each of the
+// mirrors in the order of the mirror IDs.
+func (i DockerBuilderOutputInfo) PushTags() []string {
+	tags := append([]string(nil), i.RenderedTags...)
+	var mirrorIDs []DockerMirrorID
+	for mirrorID := range i.RenderedMirrorTags {
+		mirrorIDs = append(mirrorIDs, mirrorID)
+	}
+	sort.Sort(ByDockerMirrorID(mirrorIDs))
+	for _, mirrorID := range mirrorIDs {
+		tags = append(tags, i.RenderedMirrorTags[mirrorID]...)
+	}
+	return tags
+}
+
+// renderTagTemplates renders the provided tag templates in order and returns the rendered tags along with a map from
+// tag key to rendered tag.
+func renderTagTemplates(tagTemplates TagTemplatesMap, productName, version, repository string) ([]string, map[DockerTagID]string, error) {
+	renderedTagsMap := make(map[DockerTagID]string)
+	var renderedTags []string
+	for _, currTagTemplateKey := range tagTemplates.OrderedKeys {
+		currRenderedTag, err := RenderTemplate(tagTemplates.Templates[currTagTemplateKey], nil,
+			ProductTemplateFunction(productName),
+			VersionTemplateFunction(version),
+			RepositoryTemplateFunction(repository),
+			RepositoryLiteralTemplateFunction(repository),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		renderedTags = append(renderedTags, currRenderedTag)
+		renderedTagsMap[currTagTemplateKey] = currRenderedTag
+	}
+	return renderedTags, renderedTagsMap, nil
+}
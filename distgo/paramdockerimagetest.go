@@ -0,0 +1,113 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo
+
+import (
+	"regexp"
+)
+
+// DockerImageTestsParam specifies the assertions the "docker test" task makes about the image built by a
+// DockerBuilder. The assertions are evaluated against the OCI layout the DockerBuilder wrote to its output directory,
+// so no Docker daemon is required and no command is ever run in the image.
+type DockerImageTestsParam struct {
+	// Platforms restricts the tests to the images for the specified platforms, which are of the form "os/arch" or
+	// "os/arch/variant" (for example, "linux/amd64" or "linux/arm64/v8"). If empty, the tests are run against the image
+	// for every platform in the image index.
+	Platforms []string
+
+	// FileExistenceTests verify the presence, absence and attributes of files in the filesystem of the image.
+	FileExistenceTests []DockerImageFileExistenceTest
+
+	// FileContentTests verify the content of files in the filesystem of the image.
+	FileContentTests []DockerImageFileContentTest
+
+	// MetadataTest verifies the configuration of the image. May be nil.
+	MetadataTest *DockerImageMetadataTest
+
+	// LayersTest verifies the content of every layer of the image. Unlike the file tests, which inspect the filesystem
+	// a container started from the image would see, it also catches files that were added by one layer and removed by
+	// a later one, which are still present in the published image. May be nil.
+	LayersTest *DockerImageLayersTest
+}
+
+type DockerImageFileExistenceTest struct {
+	// Path is the absolute path of the file in the image. Symbolic links are resolved within the filesystem of the
+	// image, so the attributes verified are those of the file the path refers to.
+	Path string
+
+	// ShouldExist specifies whether the file is expected to exist.
+	ShouldExist bool
+
+	// Mode, if non-nil, is the expected permission bits of the file in Unix form, including the setuid, setgid and
+	// sticky bits (for example, 0755 or 04755).
+	Mode *int64
+
+	// UID, if non-nil, is the expected user ID of the owner of the file.
+	UID *int
+
+	// GID, if non-nil, is the expected group ID of the owner of the file.
+	GID *int
+}
+
+type DockerImageFileContentTest struct {
+	// Path is the absolute path of the file in the image. If the path is a symbolic link, it is resolved within the
+	// filesystem of the image.
+	Path string
+
+	// ExpectedContents are expressions that must each match the content of the file.
+	ExpectedContents []*regexp.Regexp
+
+	// ExcludedContents are expressions that must not match the content of the file.
+	ExcludedContents []*regexp.Regexp
+}
+
+type DockerImageMetadataTest struct {
+	// Entrypoint, if non-nil, is the expected entrypoint of the image.
+	Entrypoint *[]string
+
+	// Cmd, if non-nil, is the expected default arguments of the image.
+	Cmd *[]string
+
+	// User, if non-nil, is the expected user of the image.
+	User *string
+
+	// WorkingDir, if non-nil, is the expected working directory of the image.
+	WorkingDir *string
+
+	// Env is a map from environment variable name to its expected value.
+	Env map[string]string
+
+	// Labels is a map from label key to its expected value.
+	Labels map[string]string
+
+	// RequiredLabels are label keys that must be present with any value.
+	RequiredLabels []string
+
+	// ExposedPorts are ports (for example, "8080/tcp") that must be exposed by the image.
+	ExposedPorts []string
+
+	// Volumes are paths that must be declared as volumes by the image.
+	Volumes []string
+}
+
+type DockerImageLayersTest struct {
+	// ForbiddenPaths are glob patterns (as supported by path.Match) for files that must not appear in any layer. A
+	// pattern that contains a '/' is matched against the absolute path of the file, while any other pattern is matched
+	// against the base name of the file.
+	ForbiddenPaths []string
+
+	// ForbiddenContents are expressions that must not match the content of any regular file in any layer.
+	ForbiddenContents []*regexp.Regexp
+}
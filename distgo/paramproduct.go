@@ -31,6 +31,12 @@ type ProductParam struct {
 	// products that want to render output with the same logical name.
 	Name string
 
+	// Labels are the key/value pairs that describe the product. Used by product selectors.
+	Labels map[string]string
+
+	// Groups are the names of the groups that the product belongs to. Used by product selectors.
+	Groups []string
+
 	// Build specifies the build configuration for the product.
 	Build *BuildParam
 
@@ -17,4 +17,8 @@ package distgo
 type RunParam struct {
 	// Args contain the arguments provided to the product when invoked using the "run" task.
 	Args []string
+
+	// WasmRuntime is the command used to run the product when invoked using the "run" task. If non-empty, the product
+	// is built for "wasip1-wasm" and the resulting module is executed using the command (for example, "wasmtime").
+	WasmRuntime string
 }
@@ -0,0 +1,55 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const (
+	// PGOProfileOff is the value of the PGO profile of a build that disables profile-guided optimization.
+	PGOProfileOff = "off"
+
+	// DefaultPGOProfileName is the name of the profile in the directory of the main package that is used for
+	// profile-guided optimization if a profile is not specified. It matches the profile detected by "go build".
+	DefaultPGOProfileName = "default.pgo"
+)
+
+// PGOProfilePath returns the path of the profile that is used for profile-guided optimization when building the product
+// in the provided project directory. If a profile is specified, its path is returned (a relative path is resolved
+// against the project directory). Otherwise, the path of the "default.pgo" file in the directory of the main package is
+// returned if the file exists. Returns an empty string if profile-guided optimization is disabled or no profile exists.
+func (p *BuildParam) PGOProfilePath(projectDir string) string {
+	switch p.PGOProfile {
+	case PGOProfileOff:
+		return ""
+	case "":
+		defaultProfile := p.DefaultPGOProfilePath(projectDir)
+		if fi, err := os.Stat(defaultProfile); err != nil || fi.IsDir() {
+			return ""
+		}
+		return defaultProfile
+	}
+	if filepath.IsAbs(p.PGOProfile) {
+		return p.PGOProfile
+	}
+	return filepath.Join(projectDir, p.PGOProfile)
+}
+
+// DefaultPGOProfilePath returns the path of the "default.pgo" file in the directory of the main package of the product.
+func (p *BuildParam) DefaultPGOProfilePath(projectDir string) string {
+	return filepath.Join(projectDir, p.MainPkg, DefaultPGOProfileName)
+}
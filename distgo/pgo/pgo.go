@@ -0,0 +1,87 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgo
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/pkg/errors"
+)
+
+// Merge merges the CPU profiles in the provided directory into the profile used for the profile-guided optimization of
+// the provided product. The profile is the one specified in the build configuration of the product or, if a profile is
+// not specified, the "default.pgo" file in the directory of the main package. The profiles are merged using "go tool
+// pprof" of the Go toolchain of the product and any existing content of the profile is replaced.
+func Merge(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, profilesDir string, stdout io.Writer) error {
+	if productParam.Build == nil {
+		return errors.Errorf("product %s has no build configuration defined", productParam.ID)
+	}
+	if productParam.Build.PGOProfile == distgo.PGOProfileOff {
+		return errors.Errorf("profile-guided optimization is disabled for product %s", productParam.ID)
+	}
+	dst := productParam.Build.PGOProfilePath(projectInfo.ProjectDir)
+	if dst == "" {
+		dst = productParam.Build.DefaultPGOProfilePath(projectInfo.ProjectDir)
+	}
+
+	profiles, err := profileFiles(profilesDir)
+	if err != nil {
+		return err
+	}
+	if len(profiles) == 0 {
+		return errors.Errorf("no profiles exist in directory %s", profilesDir)
+	}
+
+	cmd := productParam.Build.GoToolchain.Command(projectInfo.ProjectDir, append([]string{"tool", "pprof", "-proto"}, profiles...)...)
+	cmd.Dir = projectInfo.ProjectDir
+	cmd.Env = append(os.Environ(), productParam.Build.GoToolchain.Environment()...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	merged, err := cmd.Output()
+	if err != nil {
+		return errors.Wrapf(err, "failed to merge profiles: %s", strings.TrimSpace(stderr.String()))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return errors.Wrapf(err, "failed to create directory for profile")
+	}
+	if err := os.WriteFile(dst, merged, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write profile")
+	}
+	_, _ = fmt.Fprintf(stdout, "Merged %d profiles from %s into %s\n", len(profiles), profilesDir, dst)
+	return nil
+}
+
+// profileFiles returns the paths of the regular files in the provided directory in lexical order.
+func profileFiles(profilesDir string) ([]string, error) {
+	entries, err := os.ReadDir(profilesDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list profiles")
+	}
+	var profiles []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		profiles = append(profiles, filepath.Join(profilesDir, entry.Name()))
+	}
+	return profiles, nil
+}
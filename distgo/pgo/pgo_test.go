@@ -0,0 +1,109 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pgo_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/build"
+	"github.com/palantir/distgo/distgo/pgo"
+	"github.com/palantir/godel/v2/pkg/osarch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMain = `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hello")
+}
+`
+
+func TestMerge(t *testing.T) {
+	projectDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module foo"), 0644)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(projectDir, "main.go"), []byte(testMain), 0644)
+	require.NoError(t, err)
+
+	profilesDir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		writeCPUProfile(t, filepath.Join(profilesDir, fmt.Sprintf("cpu-%d.pprof", i)))
+	}
+
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: projectDir,
+		Version:    "1.0.0",
+	}
+	productParam := distgo.ProductParam{
+		ID:   "foo",
+		Name: "foo",
+		Build: &distgo.BuildParam{
+			NameTemplate: "{{Product}}",
+			MainPkg:      ".",
+			OutputDir:    "out/build",
+			OSArchs:      []osarch.OSArch{osarch.Current()},
+		},
+	}
+
+	outBuf := &bytes.Buffer{}
+	err = pgo.Merge(projectInfo, productParam, profilesDir, outBuf)
+	require.NoError(t, err)
+
+	// the profiles are merged into the "default.pgo" file of the main package, which is used by the build
+	profilePath := filepath.Join(projectDir, distgo.DefaultPGOProfileName)
+	assert.Equal(t, fmt.Sprintf("Merged 2 profiles from %s into %s\n", profilesDir, profilePath), outBuf.String())
+	assert.FileExists(t, profilePath)
+	err = build.Run(projectInfo, []distgo.ProductParam{productParam}, build.Options{}, io.Discard)
+	require.NoError(t, err)
+
+	// the profiles are merged into the configured profile
+	productParam.Build.PGOProfile = "profiles/foo.pgo"
+	err = pgo.Merge(projectInfo, productParam, profilesDir, io.Discard)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(projectDir, "profiles", "foo.pgo"))
+
+	productParam.Build.PGOProfile = distgo.PGOProfileOff
+	err = pgo.Merge(projectInfo, productParam, profilesDir, io.Discard)
+	assert.EqualError(t, err, "profile-guided optimization is disabled for product foo")
+
+	productParam.Build.PGOProfile = ""
+	err = pgo.Merge(projectInfo, productParam, t.TempDir(), io.Discard)
+	assert.Regexp(t, `^no profiles exist in directory .+$`, err.Error())
+}
+
+func writeCPUProfile(t *testing.T, profilePath string) {
+	f, err := os.Create(profilePath)
+	require.NoError(t, err)
+	defer func() {
+		_ = f.Close()
+	}()
+	require.NoError(t, pprof.StartCPUProfile(f))
+	start := time.Now()
+	for n := 0; time.Since(start) < 20*time.Millisecond; n++ {
+		_ = fmt.Sprint(n)
+	}
+	pprof.StopCPUProfile()
+}
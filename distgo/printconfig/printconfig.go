@@ -0,0 +1,174 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config"
+	"github.com/pkg/errors"
+	"go.yaml.in/yaml/v3"
+	yamlv2 "gopkg.in/yaml.v2"
+)
+
+// Format is the output format of the resolved configuration.
+type Format string
+
+const (
+	// FormatYAML prints the configuration as YAML in which the source of each value is a line comment.
+	FormatYAML Format = "yaml"
+	// FormatJSON prints the configuration and the sources of its values as separate JSON objects for each product.
+	FormatJSON Format = "json"
+)
+
+// Run prints the resolved configuration of the specified products in the provided format. If productIDs is empty, the
+// configuration of all of the products is printed.
+func Run(resolved map[distgo.ProductID]config.ResolvedProductConfig, productIDs []distgo.ProductID, format Format, stdout io.Writer) error {
+	if len(productIDs) == 0 {
+		for productID := range resolved {
+			productIDs = append(productIDs, productID)
+		}
+	}
+	productIDs = append([]distgo.ProductID(nil), productIDs...)
+	sort.Sort(distgo.ByProductID(productIDs))
+	for _, productID := range productIDs {
+		if _, ok := resolved[productID]; !ok {
+			var validProductIDs []distgo.ProductID
+			for currProductID := range resolved {
+				validProductIDs = append(validProductIDs, currProductID)
+			}
+			sort.Sort(distgo.ByProductID(validProductIDs))
+			return errors.Errorf("invalid product ID %q: valid values are %v", productID, validProductIDs)
+		}
+	}
+
+	switch format {
+	case FormatYAML:
+		return printYAML(resolved, productIDs, stdout)
+	case FormatJSON:
+		return printJSON(resolved, productIDs, stdout)
+	default:
+		return errors.Errorf("invalid format %q: must be one of %q or %q", format, FormatYAML, FormatJSON)
+	}
+}
+
+func printYAML(resolved map[distgo.ProductID]config.ResolvedProductConfig, productIDs []distgo.ProductID, stdout io.Writer) error {
+	productsNode := &yaml.Node{Kind: yaml.MappingNode}
+	for _, productID := range productIDs {
+		productNode, err := configNode(resolved[productID].Config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert configuration of product %s", productID)
+		}
+		annotate(productNode, "", resolved[productID].Sources)
+		productsNode.Content = append(productsNode.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: string(productID)}, productNode)
+	}
+	doc := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "products"},
+			productsNode,
+		},
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return errors.Wrapf(err, "failed to marshal YAML")
+	}
+	if err := encoder.Close(); err != nil {
+		return errors.Wrapf(err, "failed to marshal YAML")
+	}
+	_, err := stdout.Write(buf.Bytes())
+	return err
+}
+
+type jsonProductConfig struct {
+	Config  any                           `json:"config"`
+	Sources map[string]config.ValueSource `json:"sources"`
+}
+
+func printJSON(resolved map[distgo.ProductID]config.ResolvedProductConfig, productIDs []distgo.ProductID, stdout io.Writer) error {
+	products := make(map[distgo.ProductID]jsonProductConfig, len(productIDs))
+	for _, productID := range productIDs {
+		productNode, err := configNode(resolved[productID].Config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert configuration of product %s", productID)
+		}
+		var productCfg any
+		if err := productNode.Decode(&productCfg); err != nil {
+			return errors.Wrapf(err, "failed to convert configuration of product %s", productID)
+		}
+		products[productID] = jsonProductConfig{
+			Config:  productCfg,
+			Sources: resolved[productID].Sources,
+		}
+	}
+	out, err := json.MarshalIndent(map[string]any{"products": products}, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal JSON")
+	}
+	_, err = fmt.Fprintln(stdout, string(out))
+	return err
+}
+
+// configNode returns the YAML node for the provided configuration. The configuration is marshalled using the same YAML
+// library that is used to unmarshal it so that the output uses the same representation as the configuration file.
+func configNode(cfg config.ProductConfig) (*yaml.Node, error) {
+	cfgYAML, err := yamlv2.Marshal(config.ToProductConfig(&cfg))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal YAML")
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(cfgYAML, &doc); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal YAML")
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode}, nil
+	}
+	return doc.Content[0], nil
+}
+
+// annotate adds the source of each value in the provided mapping as a line comment.
+func annotate(node *yaml.Node, path string, sources map[string]config.ValueSource) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+		keyPath := keyNode.Value
+		if path != "" {
+			keyPath = path + "." + keyNode.Value
+		}
+		source, ok := sources[keyPath]
+		if !ok {
+			annotate(valueNode, keyPath, sources)
+			continue
+		}
+		// comments for block collections are written after the key, while the comments for scalars and flow
+		// collections are written after the value
+		if valueNode.Kind == yaml.ScalarNode || valueNode.Style&yaml.FlowStyle != 0 {
+			valueNode.LineComment = string(source)
+		} else {
+			keyNode.LineComment = string(source)
+		}
+		annotate(valueNode, keyPath, sources)
+	}
+}
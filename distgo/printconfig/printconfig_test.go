@@ -0,0 +1,215 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package printconfig_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/palantir/distgo/distgo"
+	distgoconfig "github.com/palantir/distgo/distgo/config"
+	"github.com/palantir/distgo/distgo/printconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+const testConfigYAML = `script-includes: set -e
+products:
+  foo:
+    build:
+      main-pkg: foo
+      os-archs:
+        - os: linux
+          arch: amd64
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+          script: echo dist
+        extra:
+          type: bin
+    publish:
+      info:
+        github:
+          config:
+            owner: octocat
+  bar:
+    run:
+      args: [--verbose]
+product-defaults:
+  name: default-name
+  build:
+    version-var: main.version
+  run:
+    args: [--quiet]
+  dist:
+    disters:
+      os-arch-bin:
+        type: os-arch-bin
+        config:
+          os-archs:
+            - os: linux
+              arch: amd64
+  publish:
+    group-id: com.palantir
+    info:
+      maven-local: {}
+`
+
+func TestRun(t *testing.T) {
+	var projectCfg distgoconfig.ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte(testConfigYAML), &projectCfg))
+
+	for _, tc := range []struct {
+		name       string
+		legacy     bool
+		productIDs []distgo.ProductID
+		format     printconfig.Format
+		want       string
+	}{
+		{
+			name:       "YAML output annotates the source of each value",
+			productIDs: []distgo.ProductID{"foo"},
+			format:     printconfig.FormatYAML,
+			want: `products:
+  foo:
+    name: default-name # product-defaults
+    build:
+      name-template: '{{Product}}' # default
+      output-dir: out/build # default
+      main-pkg: ./foo # explicit
+      version-var: main.version # product-defaults
+      os-archs: # explicit
+        - os: linux
+          arch: amd64
+    dist:
+      output-dir: out/dist # default
+      disters:
+        extra:
+          type: bin # explicit
+          name-template: '{{Product}}-{{Version}}' # default
+        os-arch-bin:
+          type: os-arch-bin # explicit
+          config: # product-defaults
+            os-archs:
+              - os: linux
+                arch: amd64
+          name-template: '{{Product}}-{{Version}}' # default
+          script: |- # explicit
+            set -e
+            echo dist
+    publish:
+      group-id: com.palantir # product-defaults
+      info:
+        github: # explicit
+          config:
+            owner: octocat
+        maven-local: {} # product-defaults
+`,
+		},
+		{
+			name:   "JSON output includes sources",
+			format: printconfig.FormatJSON,
+			productIDs: []distgo.ProductID{
+				"bar",
+			},
+			want: `{
+  "products": {
+    "bar": {
+      "config": {
+        "name": "default-name",
+        "run": {
+          "args": [
+            "--verbose"
+          ]
+        }
+      },
+      "sources": {
+        "name": "product-defaults",
+        "run.args": "explicit"
+      }
+    }
+  }
+}
+`,
+		},
+		{
+			name:   "values from legacy configuration are attributed to the upgrade",
+			legacy: true,
+			format: printconfig.FormatYAML,
+			want: `products:
+  bar:
+    name: default-name # legacy-upgrade
+    run:
+      args: # legacy-upgrade
+        - --verbose
+  foo:
+    name: default-name # legacy-upgrade
+    build:
+      name-template: '{{Product}}' # default
+      output-dir: out/build # default
+      main-pkg: ./foo # legacy-upgrade
+      version-var: main.version # legacy-upgrade
+      os-archs: # legacy-upgrade
+        - os: linux
+          arch: amd64
+    dist:
+      output-dir: out/dist # default
+      disters:
+        extra:
+          type: bin # legacy-upgrade
+          name-template: '{{Product}}-{{Version}}' # default
+        os-arch-bin:
+          type: os-arch-bin # legacy-upgrade
+          config: # legacy-upgrade
+            os-archs:
+              - os: linux
+                arch: amd64
+          name-template: '{{Product}}-{{Version}}' # default
+          script: |- # legacy-upgrade
+            set -e
+            echo dist
+    publish:
+      group-id: com.palantir # legacy-upgrade
+      info:
+        github: # legacy-upgrade
+          config:
+            owner: octocat
+        maven-local: {} # legacy-upgrade
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resolved, err := projectCfg.ResolveProductConfigs("", distgoconfig.DisterConfig{}, tc.legacy)
+			require.NoError(t, err)
+
+			buf := &bytes.Buffer{}
+			err = printconfig.Run(resolved, tc.productIDs, tc.format, buf)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestRunInvalidProduct(t *testing.T) {
+	var projectCfg distgoconfig.ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte(testConfigYAML), &projectCfg))
+	resolved, err := projectCfg.ResolveProductConfigs("", distgoconfig.DisterConfig{}, false)
+	require.NoError(t, err)
+
+	err = printconfig.Run(resolved, []distgo.ProductID{"baz"}, printconfig.FormatYAML, &bytes.Buffer{})
+	assert.EqualError(t, err, `invalid product ID "baz": valid values are [bar foo]`)
+}
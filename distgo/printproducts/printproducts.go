@@ -20,14 +20,38 @@ import (
 	"sort"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/productinfo"
 )
 
-func Run(projectParam distgo.ProjectParam, stdout io.Writer) error {
-	var productIDs []distgo.ProductID
-	for currProductID := range projectParam.Products {
-		productIDs = append(productIDs, currProductID)
+// Run prints the IDs of the specified products in sorted order. If no product IDs are specified, the IDs of all of the
+// products in the project are printed. If the format is productinfo.FormatJSON, the information about the products is
+// printed as JSON instead.
+func Run(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productIDs []distgo.ProductID, format productinfo.Format, stdout io.Writer) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
+	if len(productIDs) == 0 {
+		for currProductID := range projectParam.Products {
+			productIDs = append(productIDs, currProductID)
+		}
+	} else {
+		productParams, err := distgo.ProductParamsForProductArgs(projectParam.Products, productIDs...)
+		if err != nil {
+			return err
+		}
+		productIDs = nil
+		for _, currProductParam := range productParams {
+			productIDs = append(productIDs, currProductParam.ID)
+		}
 	}
 	sort.Sort(distgo.ByProductID(productIDs))
+	if format == productinfo.FormatJSON {
+		var productParams []distgo.ProductParam
+		for _, currProductID := range productIDs {
+			productParams = append(productParams, projectParam.Products[currProductID])
+		}
+		return productinfo.Print(projectInfo, productParams, false, stdout)
+	}
 	for _, currProductID := range productIDs {
 		_, _ = fmt.Fprintln(stdout, currProductID)
 	}
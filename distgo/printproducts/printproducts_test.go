@@ -26,6 +26,7 @@ import (
 	"github.com/palantir/distgo/distgo"
 	distgoconfig "github.com/palantir/distgo/distgo/config"
 	"github.com/palantir/distgo/distgo/printproducts"
+	"github.com/palantir/distgo/distgo/productinfo"
 	"github.com/palantir/distgo/distgo/testfuncs"
 	"github.com/palantir/distgo/internal/files"
 	"github.com/palantir/pkg/gittest"
@@ -127,7 +128,7 @@ foo
 
 			projectParam := testfuncs.NewProjectParam(t, tc.projectCfg, projectDir, fmt.Sprintf("Case %d: %s", i, tc.name))
 			buf := &bytes.Buffer{}
-			err = printproducts.Run(projectParam, buf)
+			err = printproducts.Run(distgo.ProjectInfo{ProjectDir: projectDir}, projectParam, nil, productinfo.FormatText, buf)
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 			assert.Equal(t, tc.want(projectDir), buf.String(), "Case %d: %s", i, tc.name)
 		})
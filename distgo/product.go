@@ -15,6 +15,7 @@
 package distgo
 
 import (
+	"fmt"
 	"maps"
 	"path"
 	"slices"
@@ -75,6 +76,14 @@ func (p *ProductTaskOutputInfo) ProductBuildArtifactPaths() map[osarch.OSArch]st
 	return ProductBuildArtifactPaths(p.Project, p.Product)
 }
 
+func (p *ProductTaskOutputInfo) ProductBuildVariantArtifactPaths(variant BuildVariantID) map[osarch.OSArch]string {
+	return ProductBuildVariantArtifactPaths(p.Project, p.Product, variant)
+}
+
+func (p *ProductTaskOutputInfo) ProductBuildArtifactFilePaths(variant BuildVariantID) map[osarch.OSArch][]string {
+	return ProductBuildArtifactFilePaths(p.Project, p.Product, variant)
+}
+
 func (p *ProductTaskOutputInfo) ProductDistOutputDir(distID DistID) string {
 	return ProductDistOutputDir(p.Project, p.Product, distID)
 }
@@ -99,6 +108,14 @@ func (p *ProductTaskOutputInfo) ProductDockerDistArtifactPaths() map[DockerID]ma
 	return ProductDockerDistArtifactPaths(p.Project, p.Product, p.Deps)
 }
 
+func (p *ProductTaskOutputInfo) ProductDockerfilePath(dockerID DockerID) string {
+	return ProductDockerfilePath(p.Project, p.Product, dockerID)
+}
+
+func (p *ProductTaskOutputInfo) ProductDockerExportArtifactPaths() map[DockerID][]string {
+	return ProductDockerExportArtifactPaths(p.Project, p.Product)
+}
+
 func ExecutableName(productName, goos string) string {
 	executableName := productName
 	if goos == "windows" {
@@ -107,6 +124,41 @@ func ExecutableName(productName, goos string) string {
 	return executableName
 }
 
+// BuildArtifactNames returns the names of the files created by building a product with the provided rendered name for
+// the provided OS/Arch in the provided build mode. The first name is the name of the primary output of the build (the
+// executable, library or plugin), which is the output path provided to the "build" command. The C header file created
+// for the "c-shared" and "c-archive" build modes follows it. The names are:
+//   - exe: "{{Name}}" ("{{Name}}.exe" on Windows and "{{Name}}.wasm" for WebAssembly targets)
+//   - c-shared: "{{Name}}.so" ("{{Name}}.dylib" on macOS and "{{Name}}.dll" on Windows) and "{{Name}}.h"
+//   - c-archive: "{{Name}}.a" ("{{Name}}.lib" on Windows) and "{{Name}}.h"
+//   - plugin: "{{Name}}.so"
+func BuildArtifactNames(productName string, osArch osarch.OSArch, mode BuildMode) []string {
+	switch mode {
+	case BuildModeCShared:
+		ext := ".so"
+		switch osArch.OS {
+		case "darwin":
+			ext = ".dylib"
+		case "windows":
+			ext = ".dll"
+		}
+		return []string{productName + ext, productName + ".h"}
+	case BuildModeCArchive:
+		ext := ".a"
+		if osArch.OS == "windows" {
+			ext = ".lib"
+		}
+		return []string{productName + ext, productName + ".h"}
+	case BuildModePlugin:
+		return []string{productName + ".so"}
+	default:
+		if IsWebAssembly(osArch) {
+			return []string{productName + ".wasm"}
+		}
+		return []string{ExecutableName(productName, osArch.OS)}
+	}
+}
+
 // ProductBuildOutputDir returns the output directory for the build outputs, which is
 // "{{ProjectDir}}/{{BuildOutputDir}}/{{ProductID}}/{{Version}}".
 func ProductBuildOutputDir(projectInfo ProjectInfo, productOutputInfo ProductOutputInfo) string {
@@ -120,15 +172,45 @@ func ProductBuildOutputDir(projectInfo ProjectInfo, productOutputInfo ProductOut
 // for the provided project. The keys in the map are the OS/architecture of the executable and the values are the
 // executable output paths for that OS/architecture. The output paths are of the form
 // "{{ProjectDir}}/{{BuildOutputDir}}/{{ProductID}}/{{Version}}/{{OSArch}}/{{NameTemplateRendered}}" (and if the OS is
-// Windows, the ".exe" extension is appended).
+// Windows, the ".exe" extension is appended). WebAssembly targets and library build modes use the file names returned
+// by BuildArtifactNames.
 func ProductBuildArtifactPaths(projectInfo ProjectInfo, productOutputInfo ProductOutputInfo) map[osarch.OSArch]string {
+	return ProductBuildVariantArtifactPaths(projectInfo, productOutputInfo, "")
+}
+
+// ProductBuildVariantArtifactPaths returns a map that contains the paths to the executables created by the provided
+// build variant of the provided product. The output paths are of the form
+// "{{ProjectDir}}/{{BuildOutputDir}}/{{ProductID}}/{{Version}}/{{OSArch}}-{{Variant}}/{{NameTemplateRendered}}" (and if
+// the OS is Windows, the ".exe" extension is appended). If the variant is empty, the paths of the default build are
+// returned.
+func ProductBuildVariantArtifactPaths(projectInfo ProjectInfo, productOutputInfo ProductOutputInfo, variant BuildVariantID) map[osarch.OSArch]string {
+	filePaths := ProductBuildArtifactFilePaths(projectInfo, productOutputInfo, variant)
+	if filePaths == nil {
+		return nil
+	}
+	paths := make(map[osarch.OSArch]string, len(filePaths))
+	for osArch, currFilePaths := range filePaths {
+		paths[osArch] = currFilePaths[0]
+	}
+	return paths
+}
+
+// ProductBuildArtifactFilePaths returns a map that contains the paths to all of the files created by the builds of the
+// provided build variant (or of the default build if the variant is empty) of the provided product. The values are in
+// the order returned by BuildArtifactNames, so the first path is the path returned by ProductBuildVariantArtifactPaths.
+func ProductBuildArtifactFilePaths(projectInfo ProjectInfo, productOutputInfo ProductOutputInfo, variant BuildVariantID) map[osarch.OSArch][]string {
 	if productOutputInfo.BuildOutputInfo == nil {
 		return nil
 	}
-	paths := make(map[osarch.OSArch]string)
-	for _, osArch := range productOutputInfo.BuildOutputInfo.OSArchs {
-		executableName := ExecutableName(productOutputInfo.BuildOutputInfo.BuildNameTemplateRendered, osArch.OS)
-		paths[osArch] = path.Join(ProductBuildOutputDir(projectInfo, productOutputInfo), osArch.String(), executableName)
+	paths := make(map[osarch.OSArch][]string)
+	for _, osArch := range productOutputInfo.BuildOutputInfo.VariantOSArchs(variant) {
+		dir := osArch.String()
+		if variant != "" {
+			dir = fmt.Sprintf("%s-%s", osArch, variant)
+		}
+		for _, name := range productOutputInfo.BuildOutputInfo.ArtifactNames(osArch) {
+			paths[osArch] = append(paths[osArch], path.Join(ProductBuildOutputDir(projectInfo, productOutputInfo), dir, name))
+		}
 	}
 	return paths
 }
@@ -157,6 +239,27 @@ func ProductDockerOutputDir(projectInfo ProjectInfo, productOutputInfo ProductOu
 	return path.Join(projectInfo.ProjectDir, relDir)
 }
 
+// ProductDockerExportArtifactPaths returns a map from DockerID to the paths of the archives exported for the Docker
+// image (see DockerBuilderParam.Export), which are "{{ProjectDir}}/{{DockerOutputDir}}/{{ProductID}}/{{Version}}/{{ExportArtifactName}}".
+// Docker configurations that do not export any archives are not included in the map.
+func ProductDockerExportArtifactPaths(projectInfo ProjectInfo, productOutputInfo ProductOutputInfo) map[DockerID][]string {
+	if productOutputInfo.DockerOutputInfos == nil {
+		return nil
+	}
+	out := make(map[DockerID][]string)
+	for _, dockerID := range productOutputInfo.DockerOutputInfos.DockerIDs {
+		exportArtifactNames := productOutputInfo.DockerOutputInfos.DockerBuilderOutputInfos[dockerID].ExportArtifactNames
+		outputDir := ProductDockerOutputDir(projectInfo, productOutputInfo, dockerID)
+		if len(exportArtifactNames) == 0 || outputDir == "" {
+			continue
+		}
+		for _, name := range exportArtifactNames {
+			out[dockerID] = append(out[dockerID], path.Join(path.Dir(outputDir), name))
+		}
+	}
+	return out
+}
+
 // ProductDockerOutputRelDir returns the Docker output directory for the given DockerID relative to the project
 // directory, which is "{{DockerOutputDir}}/{{ProductID}}/{{Version}}/{{DockerID}}". An empty dockerOutputDir yields an
 // empty result rather than a path that would resolve into the source tree.
@@ -199,6 +302,20 @@ func productDockerLegacyOutputDir(projectInfo ProjectInfo, productOutputInfo Pro
 	return ProductDistOutputDir(projectInfo, productOutputInfo, DistID("oci-"+dockerID))
 }
 
+// ProductDockerfilePath returns the path of the Dockerfile a DockerBuilder should build the image for the given
+// DockerID from: the rendered Dockerfile staged by the Docker build task if there is one, and otherwise
+// "{{ProjectDir}}/{{DockerID.ContextDir}}/{{DockerID.DockerfilePath}}".
+func ProductDockerfilePath(projectInfo ProjectInfo, productOutputInfo ProductOutputInfo, dockerID DockerID) string {
+	if productOutputInfo.DockerOutputInfos == nil {
+		return ""
+	}
+	dockerOutputInfo := productOutputInfo.DockerOutputInfos.DockerBuilderOutputInfos[dockerID]
+	if dockerOutputInfo.RenderedDockerfilePath != "" {
+		return dockerOutputInfo.RenderedDockerfilePath
+	}
+	return path.Join(projectInfo.ProjectDir, dockerOutputInfo.ContextDir, dockerOutputInfo.DockerfilePath)
+}
+
 // ProductDockerOCIDistOutputDir returns the legacy Docker OCI dist output directory for the given DockerID, which is
 // "{{ProjectDir}}/{{DistOutputDir}}/{{ProductID}}/{{Version}}/oci-{{DockerID}}".
 //
@@ -276,7 +393,7 @@ func ProductDockerBuildArtifactPaths(projectInfo ProjectInfo, productOutputInfo
 				if err != nil {
 					panic(errors.Wrapf(err, "OSArchID was not in a valid state"))
 				}
-				artifactPath := path.Join(pathToInputProductsDir, string(productID), "build", string(osArchID), ExecutableName(currProductOutputInfo.BuildOutputInfo.BuildNameTemplateRendered, osArch.OS))
+				artifactPath := path.Join(pathToInputProductsDir, string(productID), "build", string(osArchID), currProductOutputInfo.BuildOutputInfo.ArtifactNames(osArch)[0])
 				out[dockerID][productID][osArch] = artifactPath
 			}
 		}
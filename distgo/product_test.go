@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -91,3 +92,87 @@ func TestToProductTaskOutputInfoResolvesDockerOutputDir(t *testing.T) {
 		distgo.ProductDockerOutputDir(info.Project, info.Product, "builder"),
 		filepath.Join(info.Project.ProjectDir, info.Product.DockerOutputInfos.DockerBuilderOutputInfos["builder"].OutputDir))
 }
+
+func TestProductBuildArtifactFilePaths(t *testing.T) {
+	project := distgo.ProjectInfo{ProjectDir: "/project", Version: "1.2.3"}
+	darwinARM64 := osarch.OSArch{OS: "darwin", Arch: "arm64"}
+	linuxAMD64 := osarch.OSArch{OS: "linux", Arch: "amd64"}
+	windowsAMD64 := osarch.OSArch{OS: "windows", Arch: "amd64"}
+	osArchs := []osarch.OSArch{darwinARM64, linuxAMD64, windowsAMD64}
+
+	for _, tc := range []struct {
+		mode distgo.BuildMode
+		want map[osarch.OSArch][]string
+	}{
+		{
+			mode: distgo.BuildModeExe,
+			want: map[osarch.OSArch][]string{
+				darwinARM64:  {"/project/out/build/foo/1.2.3/darwin-arm64/foo"},
+				linuxAMD64:   {"/project/out/build/foo/1.2.3/linux-amd64/foo"},
+				windowsAMD64: {"/project/out/build/foo/1.2.3/windows-amd64/foo.exe"},
+			},
+		},
+		{
+			mode: distgo.BuildModeCShared,
+			want: map[osarch.OSArch][]string{
+				darwinARM64:  {"/project/out/build/foo/1.2.3/darwin-arm64/foo.dylib", "/project/out/build/foo/1.2.3/darwin-arm64/foo.h"},
+				linuxAMD64:   {"/project/out/build/foo/1.2.3/linux-amd64/foo.so", "/project/out/build/foo/1.2.3/linux-amd64/foo.h"},
+				windowsAMD64: {"/project/out/build/foo/1.2.3/windows-amd64/foo.dll", "/project/out/build/foo/1.2.3/windows-amd64/foo.h"},
+			},
+		},
+		{
+			mode: distgo.BuildModeCArchive,
+			want: map[osarch.OSArch][]string{
+				darwinARM64:  {"/project/out/build/foo/1.2.3/darwin-arm64/foo.a", "/project/out/build/foo/1.2.3/darwin-arm64/foo.h"},
+				linuxAMD64:   {"/project/out/build/foo/1.2.3/linux-amd64/foo.a", "/project/out/build/foo/1.2.3/linux-amd64/foo.h"},
+				windowsAMD64: {"/project/out/build/foo/1.2.3/windows-amd64/foo.lib", "/project/out/build/foo/1.2.3/windows-amd64/foo.h"},
+			},
+		},
+		{
+			mode: distgo.BuildModePlugin,
+			want: map[osarch.OSArch][]string{
+				darwinARM64:  {"/project/out/build/foo/1.2.3/darwin-arm64/foo.so"},
+				linuxAMD64:   {"/project/out/build/foo/1.2.3/linux-amd64/foo.so"},
+				windowsAMD64: {"/project/out/build/foo/1.2.3/windows-amd64/foo.so"},
+			},
+		},
+	} {
+		t.Run(string(tc.mode), func(t *testing.T) {
+			product := distgo.ProductOutputInfo{
+				ID: "foo",
+				BuildOutputInfo: &distgo.BuildOutputInfo{
+					BuildNameTemplateRendered: "foo",
+					BuildOutputDir:            "out/build",
+					OSArchs:                   osArchs,
+					Mode:                      tc.mode,
+				},
+			}
+			assert.Equal(t, tc.want, distgo.ProductBuildArtifactFilePaths(project, product, ""))
+
+			// the primary artifact is the first file
+			wantPrimary := make(map[osarch.OSArch]string)
+			for osArch, paths := range tc.want {
+				wantPrimary[osArch] = paths[0]
+			}
+			assert.Equal(t, wantPrimary, distgo.ProductBuildArtifactPaths(project, product))
+		})
+	}
+}
+
+func TestProductBuildArtifactFilePathsWebAssembly(t *testing.T) {
+	project := distgo.ProjectInfo{ProjectDir: "/project", Version: "1.2.3"}
+	jsWasm := osarch.OSArch{OS: "js", Arch: "wasm"}
+	wasip1Wasm := osarch.OSArch{OS: "wasip1", Arch: "wasm"}
+	product := distgo.ProductOutputInfo{
+		ID: "foo",
+		BuildOutputInfo: &distgo.BuildOutputInfo{
+			BuildNameTemplateRendered: "foo",
+			BuildOutputDir:            "out/build",
+			OSArchs:                   []osarch.OSArch{jsWasm, wasip1Wasm},
+		},
+	}
+	assert.Equal(t, map[osarch.OSArch][]string{
+		jsWasm:     {"/project/out/build/foo/1.2.3/js-wasm/foo.wasm"},
+		wasip1Wasm: {"/project/out/build/foo/1.2.3/wasip1-wasm/foo.wasm"},
+	}, distgo.ProductBuildArtifactFilePaths(project, product, ""))
+}
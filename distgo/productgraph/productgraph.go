@@ -0,0 +1,390 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package productgraph
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/pkg/errors"
+)
+
+// Format is the output format of the product graph.
+type Format string
+
+const (
+	// FormatDOT prints the graph in the Graphviz DOT language.
+	FormatDOT Format = "dot"
+	// FormatMermaid prints the graph as a Mermaid flowchart.
+	FormatMermaid Format = "mermaid"
+)
+
+// EdgeType is the type of the relationship between two products.
+type EdgeType string
+
+const (
+	// EdgeDependency is a product that is declared as a dependency of another product.
+	EdgeDependency EdgeType = "dependency"
+	// EdgeInputBuild is a product whose build outputs are an input to a Docker image of another product.
+	EdgeInputBuild EdgeType = "input-build"
+	// EdgeInputDist is a product whose dist outputs are an input to a Docker image of another product.
+	EdgeInputDist EdgeType = "input-dist"
+	// EdgeDockerFrom is a product whose Docker image is used in a FROM instruction of the Dockerfile of a Docker image of
+	// another product. The image is provided to the build from the build context layout of the dependency.
+	EdgeDockerFrom EdgeType = "docker-from"
+)
+
+// Edge is a directed edge from a product to a product that it uses.
+type Edge struct {
+	From distgo.ProductID
+	To   distgo.ProductID
+	Type EdgeType
+	// Label describes the configuration that defines the edge, such as the Docker IDs of the images. Empty for
+	// dependency edges.
+	Label string
+}
+
+// Graph is the graph of the products of a project.
+type Graph struct {
+	// Products are the IDs of all of the products in sorted order.
+	Products []distgo.ProductID
+	// Edges are the edges of the graph, sorted by source product.
+	Edges []Edge
+	// Cycle is the path of the first dependency cycle that was detected, which starts and ends with the same product.
+	// Empty if the dependencies do not contain a cycle.
+	Cycle []distgo.ProductID
+}
+
+// Run prints the graph of the provided products in the provided format. The products do not need to have their
+// AllDependencies set, so the graph can be printed even if their dependencies contain a cycle.
+func Run(projectInfo distgo.ProjectInfo, products map[distgo.ProductID]distgo.ProductParam, format Format, stdout io.Writer) error {
+	graph, err := New(projectInfo, products)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatDOT:
+		_, _ = fmt.Fprint(stdout, graph.DOT())
+	case FormatMermaid:
+		_, _ = fmt.Fprint(stdout, graph.Mermaid())
+	default:
+		return errors.Errorf("invalid format %q: must be one of %q or %q", format, FormatDOT, FormatMermaid)
+	}
+	return nil
+}
+
+// New returns the graph of the provided products. Edges to products that are not part of the provided products are
+// omitted.
+func New(projectInfo distgo.ProjectInfo, products map[distgo.ProductID]distgo.ProductParam) (Graph, error) {
+	var graph Graph
+	for productID := range products {
+		graph.Products = append(graph.Products, productID)
+	}
+	sort.Sort(distgo.ByProductID(graph.Products))
+
+	// rendered Docker tags of all products, used to match the images in the FROM instructions of Dockerfiles
+	dockerTags := make(map[distgo.ProductID]map[distgo.DockerID][]string)
+	for _, productID := range graph.Products {
+		productParam := products[productID]
+		if productParam.Docker == nil {
+			continue
+		}
+		dockerTags[productID] = make(map[distgo.DockerID][]string)
+		for dockerID, dockerBuilderParam := range productParam.Docker.DockerBuilderParams {
+			// the inputs are not expanded for graph parameters and are not needed to render the tags
+			dockerBuilderParam.InputBuilds = nil
+			dockerBuilderParam.InputDists = nil
+			dockerOutputInfo, err := dockerBuilderParam.ToDockerBuilderOutputInfo(productParam.Name, projectInfo.Version, productParam.Docker.Repository)
+			if err != nil {
+				return Graph{}, errors.Wrapf(err, "failed to render tags for Docker image %s of product %s", dockerID, productID)
+			}
+			dockerTags[productID][dockerID] = dockerOutputInfo.RenderedTags
+		}
+	}
+
+	for _, productID := range graph.Products {
+		productParam := products[productID]
+		edges := newEdgeSet()
+		for _, depID := range productParam.FirstLevelDependencies {
+			if _, ok := products[depID]; ok {
+				edges.add(Edge{From: productID, To: depID, Type: EdgeDependency})
+			}
+		}
+		if productParam.Docker != nil {
+			var dockerIDs []distgo.DockerID
+			for dockerID := range productParam.Docker.DockerBuilderParams {
+				dockerIDs = append(dockerIDs, dockerID)
+			}
+			sort.Sort(distgo.ByDockerID(dockerIDs))
+			deps := transitiveDependencies(productID, products)
+			for _, dockerID := range dockerIDs {
+				dockerBuilderParam := productParam.Docker.DockerBuilderParams[dockerID]
+				for _, inputBuild := range dockerBuilderParam.InputBuilds {
+					inputProductID, _, _ := inputBuild.Parse()
+					if _, ok := products[inputProductID]; ok {
+						edges.add(Edge{From: productID, To: inputProductID, Type: EdgeInputBuild, Label: string(dockerID)})
+					}
+				}
+				for _, inputDist := range dockerBuilderParam.InputDists {
+					inputProductID, _ := inputDist.Parse()
+					if _, ok := products[inputProductID]; ok {
+						edges.add(Edge{From: productID, To: inputProductID, Type: EdgeInputDist, Label: string(dockerID)})
+					}
+				}
+				dockerfilePath := filepath.Join(projectInfo.ProjectDir, dockerBuilderParam.ContextDir, dockerBuilderParam.DockerfilePath)
+				for _, ref := range dockerfileFromRefs(dockerfilePath, deps, dockerTags) {
+					edges.add(Edge{From: productID, To: ref.productID, Type: EdgeDockerFrom, Label: fmt.Sprintf("%s -> %s", dockerID, ref.dockerID)})
+				}
+			}
+		}
+		graph.Edges = append(graph.Edges, edges.edges...)
+	}
+	graph.Cycle = findCycle(graph.Products, products)
+	return graph, nil
+}
+
+// DOT returns the graph in the Graphviz DOT language. The products and dependency edges that are part of the detected
+// cycle are drawn in red.
+func (g Graph) DOT() string {
+	cycleProducts, cycleEdges := g.cycleSets()
+	buf := &bytes.Buffer{}
+	_, _ = fmt.Fprintln(buf, "digraph products {")
+	_, _ = fmt.Fprintln(buf, "  rankdir=LR;")
+	for _, productID := range g.Products {
+		attrs := ""
+		if _, ok := cycleProducts[productID]; ok {
+			attrs = " [color=red, fontcolor=red]"
+		}
+		_, _ = fmt.Fprintf(buf, "  %q%s;\n", productID, attrs)
+	}
+	for _, edge := range g.Edges {
+		attrs := []string{fmt.Sprintf("label=%q", edge.description())}
+		switch edge.Type {
+		case EdgeInputBuild:
+			attrs = append(attrs, "style=dashed")
+		case EdgeInputDist:
+			attrs = append(attrs, "style=dotted")
+		case EdgeDockerFrom:
+			attrs = append(attrs, "style=bold")
+		}
+		if _, ok := cycleEdges[edge]; ok {
+			attrs = append(attrs, "color=red", "fontcolor=red", "penwidth=2")
+		}
+		_, _ = fmt.Fprintf(buf, "  %q -> %q [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+	}
+	_, _ = fmt.Fprintln(buf, "}")
+	return buf.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart. The products and dependency edges that are part of the detected
+// cycle are styled in red.
+func (g Graph) Mermaid() string {
+	cycleProducts, cycleEdges := g.cycleSets()
+	nodeIDs := make(map[distgo.ProductID]string)
+	buf := &bytes.Buffer{}
+	_, _ = fmt.Fprintln(buf, "flowchart LR")
+	for i, productID := range g.Products {
+		nodeIDs[productID] = fmt.Sprintf("p%d", i)
+		_, _ = fmt.Fprintf(buf, "  %s[%q]\n", nodeIDs[productID], productID)
+	}
+	var cycleLinks []string
+	for i, edge := range g.Edges {
+		arrow := "-->"
+		switch edge.Type {
+		case EdgeInputBuild, EdgeInputDist:
+			arrow = "-.->"
+		case EdgeDockerFrom:
+			arrow = "==>"
+		}
+		_, _ = fmt.Fprintf(buf, "  %s %s|%q| %s\n", nodeIDs[edge.From], arrow, edge.description(), nodeIDs[edge.To])
+		if _, ok := cycleEdges[edge]; ok {
+			cycleLinks = append(cycleLinks, fmt.Sprint(i))
+		}
+	}
+	if len(cycleProducts) > 0 {
+		var cycleNodes []string
+		for _, productID := range g.Products {
+			if _, ok := cycleProducts[productID]; ok {
+				cycleNodes = append(cycleNodes, nodeIDs[productID])
+			}
+		}
+		_, _ = fmt.Fprintln(buf, "  classDef cycle stroke:#d00,stroke-width:2px,color:#d00")
+		_, _ = fmt.Fprintf(buf, "  class %s cycle\n", strings.Join(cycleNodes, ","))
+		_, _ = fmt.Fprintf(buf, "  linkStyle %s stroke:#d00,stroke-width:2px\n", strings.Join(cycleLinks, ","))
+	}
+	return buf.String()
+}
+
+func (g Graph) cycleSets() (map[distgo.ProductID]struct{}, map[Edge]struct{}) {
+	products := make(map[distgo.ProductID]struct{})
+	edges := make(map[Edge]struct{})
+	for i, productID := range g.Cycle {
+		products[productID] = struct{}{}
+		if i > 0 {
+			edges[Edge{From: g.Cycle[i-1], To: productID, Type: EdgeDependency}] = struct{}{}
+		}
+	}
+	return products, edges
+}
+
+func (e Edge) description() string {
+	if e.Label == "" {
+		return string(e.Type)
+	}
+	return fmt.Sprintf("%s: %s", e.Type, e.Label)
+}
+
+type edgeSet struct {
+	edges []Edge
+	seen  map[Edge]struct{}
+}
+
+func newEdgeSet() *edgeSet {
+	return &edgeSet{
+		seen: make(map[Edge]struct{}),
+	}
+}
+
+func (s *edgeSet) add(edge Edge) {
+	if _, ok := s.seen[edge]; ok {
+		return
+	}
+	s.seen[edge] = struct{}{}
+	s.edges = append(s.edges, edge)
+}
+
+// transitiveDependencies returns the IDs of all of the products that the provided product depends on. Terminates even
+// if the dependencies contain a cycle.
+func transitiveDependencies(productID distgo.ProductID, products map[distgo.ProductID]distgo.ProductParam) map[distgo.ProductID]struct{} {
+	deps := make(map[distgo.ProductID]struct{})
+	queue := []distgo.ProductID{productID}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, depID := range products[curr].FirstLevelDependencies {
+			if _, ok := deps[depID]; ok {
+				continue
+			}
+			deps[depID] = struct{}{}
+			queue = append(queue, depID)
+		}
+	}
+	return deps
+}
+
+// findCycle returns the path of the first dependency cycle found by a depth-first search of the products in sorted
+// order, or nil if the dependencies do not contain a cycle.
+func findCycle(productIDs []distgo.ProductID, products map[distgo.ProductID]distgo.ProductParam) []distgo.ProductID {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[distgo.ProductID]int)
+	var path []distgo.ProductID
+	var visit func(productID distgo.ProductID) []distgo.ProductID
+	visit = func(productID distgo.ProductID) []distgo.ProductID {
+		switch state[productID] {
+		case visiting:
+			start := slices.Index(path, productID)
+			return append(slices.Clone(path[start:]), productID)
+		case visited:
+			return nil
+		}
+		state[productID] = visiting
+		path = append(path, productID)
+		deps := append([]distgo.ProductID(nil), products[productID].FirstLevelDependencies...)
+		sort.Sort(distgo.ByProductID(deps))
+		for _, depID := range deps {
+			if _, ok := products[depID]; !ok {
+				continue
+			}
+			if cycle := visit(depID); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[productID] = visited
+		return nil
+	}
+	for _, productID := range productIDs {
+		if cycle := visit(productID); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+var tagFunctionRegexp = regexp.MustCompile(`{{\s*Tag\s+"([^"]+)"\s+"([^"]+)"`)
+
+type dockerRef struct {
+	productID distgo.ProductID
+	dockerID  distgo.DockerID
+}
+
+// dockerfileFromRefs returns the Docker images of the provided dependencies that are used in the FROM instructions of
+// the Dockerfile at the provided path. An image is matched either by a "Tag" template function or by one of its
+// rendered tags. Returns nil if the Dockerfile cannot be read, which is the case if it is generated by a script.
+func dockerfileFromRefs(dockerfilePath string, deps map[distgo.ProductID]struct{}, dockerTags map[distgo.ProductID]map[distgo.DockerID][]string) []dockerRef {
+	content, err := os.ReadFile(dockerfilePath)
+	if err != nil {
+		return nil
+	}
+	var refs []dockerRef
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		fields := strings.Fields(line)
+		if len(fields) < 2 || !strings.EqualFold(fields[0], "FROM") {
+			continue
+		}
+		if match := tagFunctionRegexp.FindStringSubmatch(line); match != nil {
+			productID := distgo.ProductID(match[1])
+			if _, ok := deps[productID]; ok {
+				refs = append(refs, dockerRef{productID: productID, dockerID: distgo.DockerID(match[2])})
+			}
+			continue
+		}
+		var image string
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "--") {
+				image = field
+				break
+			}
+		}
+		for depID := range deps {
+			for dockerID, tags := range dockerTags[depID] {
+				if slices.Contains(tags, image) {
+					refs = append(refs, dockerRef{productID: depID, dockerID: dockerID})
+				}
+			}
+		}
+	}
+	sort.SliceStable(refs, func(i, j int) bool {
+		if refs[i].productID != refs[j].productID {
+			return refs[i].productID < refs[j].productID
+		}
+		return refs[i].dockerID < refs[j].dockerID
+	})
+	return refs
+}
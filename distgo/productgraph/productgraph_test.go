@@ -0,0 +1,198 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package productgraph_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/distgo/dister/disterfactory"
+	"github.com/palantir/distgo/distgo"
+	distgoconfig "github.com/palantir/distgo/distgo/config"
+	"github.com/palantir/distgo/distgo/productgraph"
+	"github.com/palantir/distgo/dockerbuilder/dockerbuilderfactory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestProductGraph(t *testing.T) {
+	for i, tc := range []struct {
+		name        string
+		yml         string
+		dockerfiles map[string]string
+		format      productgraph.Format
+		want        string
+	}{
+		{
+			"DOT output with typed edges",
+			`
+products:
+  base:
+    build:
+      main-pkg: ./base
+    docker:
+      docker-builders:
+        base-image:
+          type: default
+          context-dir: base-docker
+          tag-templates:
+            - base:{{Version}}
+  server:
+    build:
+      main-pkg: ./server
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+    docker:
+      docker-builders:
+        server-image:
+          type: default
+          context-dir: server-docker
+          input-builds:
+            - server
+          input-dists:
+            - server.os-arch-bin
+          tag-templates:
+            - server:{{Version}}
+    dependencies:
+      - base
+  client:
+    docker:
+      docker-builders:
+        client-image:
+          type: default
+          context-dir: client-docker
+          tag-templates:
+            - client:{{Version}}
+    dependencies:
+      - server
+`,
+			map[string]string{
+				"server-docker/Dockerfile": "FROM --platform=linux/amd64 base:1.0.0 AS base\nFROM alpine\n",
+				"client-docker/Dockerfile": `FROM {{Tag "server" "server-image" "0"}}` + "\n",
+			},
+			productgraph.FormatDOT,
+			`digraph products {
+  rankdir=LR;
+  "base";
+  "client";
+  "server";
+  "client" -> "server" [label="dependency"];
+  "client" -> "server" [label="docker-from: client-image -> server-image", style=bold];
+  "server" -> "base" [label="dependency"];
+  "server" -> "server" [label="input-build: server-image", style=dashed];
+  "server" -> "server" [label="input-dist: server-image", style=dotted];
+  "server" -> "base" [label="docker-from: server-image -> base-image", style=bold];
+}
+`,
+		},
+		{
+			"DOT output highlights cycle",
+			`
+products:
+  foo:
+    dependencies:
+      - bar
+  bar:
+    dependencies:
+      - baz
+  baz:
+    dependencies:
+      - foo
+  other:
+    dependencies:
+      - foo
+`,
+			nil,
+			productgraph.FormatDOT,
+			`digraph products {
+  rankdir=LR;
+  "bar" [color=red, fontcolor=red];
+  "baz" [color=red, fontcolor=red];
+  "foo" [color=red, fontcolor=red];
+  "other";
+  "bar" -> "baz" [label="dependency", color=red, fontcolor=red, penwidth=2];
+  "baz" -> "foo" [label="dependency", color=red, fontcolor=red, penwidth=2];
+  "foo" -> "bar" [label="dependency", color=red, fontcolor=red, penwidth=2];
+  "other" -> "foo" [label="dependency"];
+}
+`,
+		},
+		{
+			"Mermaid output highlights cycle",
+			`
+products:
+  foo:
+    dependencies:
+      - bar
+  bar:
+    dependencies:
+      - foo
+  other:
+    docker:
+      docker-builders:
+        image:
+          type: default
+          context-dir: docker
+          input-builds:
+            - foo
+          tag-templates:
+            - other:latest
+    dependencies:
+      - foo
+`,
+			nil,
+			productgraph.FormatMermaid,
+			`flowchart LR
+  p0["bar"]
+  p1["foo"]
+  p2["other"]
+  p0 -->|"dependency"| p1
+  p1 -->|"dependency"| p0
+  p2 -->|"dependency"| p1
+  p2 -.->|"input-build: image"| p1
+  classDef cycle stroke:#d00,stroke-width:2px,color:#d00
+  class p0,p1 cycle
+  linkStyle 0,1 stroke:#d00,stroke-width:2px
+`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			for relPath, content := range tc.dockerfiles {
+				require.NoError(t, os.MkdirAll(filepath.Join(projectDir, filepath.Dir(relPath)), 0755))
+				require.NoError(t, os.WriteFile(filepath.Join(projectDir, relPath), []byte(content), 0644))
+			}
+
+			var projectCfg distgoconfig.ProjectConfig
+			require.NoError(t, yaml.Unmarshal([]byte(tc.yml), &projectCfg), "Case %d: %s", i, tc.name)
+			disterFactory, err := disterfactory.New(nil, nil)
+			require.NoError(t, err)
+			dockerBuilderFactory, err := dockerbuilderfactory.New(nil, nil)
+			require.NoError(t, err)
+			projectParam, err := projectCfg.ToGraphParam(projectDir, nil, disterFactory, distgoconfig.DisterConfig{}, dockerBuilderFactory, nil)
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+
+			buf := &bytes.Buffer{}
+			err = productgraph.Run(distgo.ProjectInfo{ProjectDir: projectDir, Version: "1.0.0"}, projectParam.Products, tc.format, buf)
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			assert.Equal(t, tc.want, buf.String(), "Case %d: %s", i, tc.name)
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package productinfo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/pkg/errors"
+)
+
+// SchemaVersion is the version of the schema of the JSON output. The version is incremented when a field is removed or
+// the meaning of a field changes: fields may be added without changing the version.
+const SchemaVersion = 1
+
+// Format is the output format of the commands that print information about products.
+type Format string
+
+const (
+	// FormatText prints the information as plain text lines.
+	FormatText Format = "text"
+	// FormatJSON prints the information as an Output JSON object.
+	FormatJSON Format = "json"
+)
+
+// Validate returns an error if the format is not a valid value.
+func (f Format) Validate() error {
+	if f != FormatText && f != FormatJSON {
+		return errors.Errorf("invalid format %q: must be one of %q or %q", f, FormatText, FormatJSON)
+	}
+	return nil
+}
+
+// Output is the JSON output of the commands that print information about products.
+type Output struct {
+	SchemaVersion int       `json:"schemaVersion"`
+	Project       Project   `json:"project"`
+	Products      []Product `json:"products"`
+}
+
+// Project is the information about the project that the products belong to.
+type Project struct {
+	// Dir is the absolute path of the project directory.
+	Dir     string `json:"dir"`
+	Version string `json:"version"`
+}
+
+// Product is the information about a single product and its outputs.
+type Product struct {
+	ID           distgo.ProductID   `json:"id"`
+	Name         string             `json:"name"`
+	Labels       map[string]string  `json:"labels,omitempty"`
+	Groups       []string           `json:"groups,omitempty"`
+	Dependencies []distgo.ProductID `json:"dependencies"`
+	// MavenCoordinate is the "group:name:version" coordinate of the product. Empty if the product does not specify a
+	// group ID.
+	MavenCoordinate string   `json:"mavenCoordinate,omitempty"`
+	Build           *Build   `json:"build,omitempty"`
+	Dists           []Dist   `json:"dists,omitempty"`
+	Dockers         []Docker `json:"dockers,omitempty"`
+}
+
+// Build is the information about the build outputs of a product.
+type Build struct {
+	MainPkg string `json:"mainPkg"`
+	// Mode is the build mode of the product. Empty if the product is built as an executable.
+	Mode      distgo.BuildMode `json:"mode,omitempty"`
+	OutputDir string           `json:"outputDir"`
+	Artifacts []BuildArtifact  `json:"artifacts"`
+}
+
+// BuildArtifact is the build output of a product for a single OS/Arch and build variant.
+type BuildArtifact struct {
+	OSArch string `json:"osArch"`
+	// Variant is the build variant that the artifact is built for. Empty for the default build.
+	Variant distgo.BuildVariantID `json:"variant,omitempty"`
+	Path    string                `json:"path"`
+	// AdditionalPaths are the paths of the other files created by the build, such as the C header file created for
+	// the "c-shared" and "c-archive" build modes.
+	AdditionalPaths []string `json:"additionalPaths,omitempty"`
+}
+
+// Dist is the information about the outputs of a single dist of a product.
+type Dist struct {
+	ID        distgo.DistID `json:"id"`
+	Type      string        `json:"type"`
+	Packaging string        `json:"packaging,omitempty"`
+	OutputDir string        `json:"outputDir"`
+	Artifacts []string      `json:"artifacts"`
+}
+
+// Docker is the information about the image built by a single Docker configuration of a product, including the
+// paths to the archives exported for the image.
+type Docker struct {
+	ID      distgo.DockerID `json:"id"`
+	Type    string          `json:"type"`
+	Tags    []string        `json:"tags"`
+	Exports []string        `json:"exports,omitempty"`
+}
+
+// New returns the output for the provided products, which is sorted by product ID. The paths in the output are
+// relative to the project directory unless absPaths is true.
+func New(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam, absPaths bool) (Output, error) {
+	output := Output{
+		SchemaVersion: SchemaVersion,
+		Project: Project{
+			Dir:     projectInfo.ProjectDir,
+			Version: projectInfo.Version,
+		},
+		Products: []Product{},
+	}
+	for _, productParam := range productParams {
+		product, err := newProduct(projectInfo, productParam, absPaths)
+		if err != nil {
+			return Output{}, errors.Wrapf(err, "failed to compute output for product %s", productParam.ID)
+		}
+		output.Products = append(output.Products, product)
+	}
+	sort.Slice(output.Products, func(i, j int) bool {
+		return output.Products[i].ID < output.Products[j].ID
+	})
+	return output, nil
+}
+
+// Write writes the provided output as indented JSON.
+func Write(output Output, stdout io.Writer) error {
+	outputJSON, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal output as JSON")
+	}
+	_, _ = fmt.Fprintln(stdout, string(outputJSON))
+	return nil
+}
+
+// Print writes the output for the provided products as indented JSON.
+func Print(projectInfo distgo.ProjectInfo, productParams []distgo.ProductParam, absPaths bool, stdout io.Writer) error {
+	output, err := New(projectInfo, productParams, absPaths)
+	if err != nil {
+		return err
+	}
+	return Write(output, stdout)
+}
+
+func newProduct(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, absPaths bool) (Product, error) {
+	outputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, productParam)
+	if err != nil {
+		return Product{}, err
+	}
+	pathFn := func(p string) (string, error) {
+		if absPaths || p == "" || !filepath.IsAbs(p) {
+			return p, nil
+		}
+		relPath, err := filepath.Rel(projectInfo.ProjectDir, p)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to convert path to relative path")
+		}
+		return relPath, nil
+	}
+
+	product := Product{
+		ID:           productParam.ID,
+		Name:         outputInfo.Product.Name,
+		Labels:       productParam.Labels,
+		Groups:       productParam.Groups,
+		Dependencies: append([]distgo.ProductID{}, productParam.FirstLevelDependencies...),
+	}
+	sort.Sort(distgo.ByProductID(product.Dependencies))
+	if outputInfo.Product.PublishOutputInfo != nil && outputInfo.Product.PublishOutputInfo.GroupID != "" {
+		product.MavenCoordinate = fmt.Sprintf("%s:%s:%s", outputInfo.Product.PublishOutputInfo.GroupID, outputInfo.Product.Name, projectInfo.Version)
+	}
+
+	if buildOutputInfo := outputInfo.Product.BuildOutputInfo; buildOutputInfo != nil {
+		outputDir, err := pathFn(outputInfo.ProductBuildOutputDir())
+		if err != nil {
+			return Product{}, err
+		}
+		product.Build = &Build{
+			MainPkg:   buildOutputInfo.MainPkg,
+			Mode:      buildOutputInfo.Mode,
+			OutputDir: outputDir,
+			Artifacts: []BuildArtifact{},
+		}
+		for _, variant := range append([]distgo.BuildVariantID{""}, buildOutputInfo.VariantIDs()...) {
+			artifactPaths := outputInfo.ProductBuildArtifactFilePaths(variant)
+			for _, osArch := range buildOutputInfo.VariantOSArchs(variant) {
+				var paths []string
+				for _, currPath := range artifactPaths[osArch] {
+					artifactPath, err := pathFn(currPath)
+					if err != nil {
+						return Product{}, err
+					}
+					paths = append(paths, artifactPath)
+				}
+				artifact := BuildArtifact{
+					OSArch:  osArch.String(),
+					Variant: variant,
+					Path:    paths[0],
+				}
+				if len(paths) > 1 {
+					artifact.AdditionalPaths = paths[1:]
+				}
+				product.Build.Artifacts = append(product.Build.Artifacts, artifact)
+			}
+		}
+	}
+
+	if distOutputInfos := outputInfo.Product.DistOutputInfos; distOutputInfos != nil {
+		artifactPaths := outputInfo.ProductDistArtifactPaths()
+		for _, distID := range distOutputInfos.DistIDs {
+			typeName, err := productParam.Dist.DistParams[distID].Dister.TypeName()
+			if err != nil {
+				return Product{}, errors.Wrapf(err, "failed to determine type of dister %s", distID)
+			}
+			outputDir, err := pathFn(outputInfo.ProductDistOutputDir(distID))
+			if err != nil {
+				return Product{}, err
+			}
+			dist := Dist{
+				ID:        distID,
+				Type:      typeName,
+				Packaging: distOutputInfos.DistInfos[distID].PackagingExtension,
+				OutputDir: outputDir,
+				Artifacts: []string{},
+			}
+			for _, artifactPath := range artifactPaths[distID] {
+				artifactPath, err := pathFn(artifactPath)
+				if err != nil {
+					return Product{}, err
+				}
+				dist.Artifacts = append(dist.Artifacts, artifactPath)
+			}
+			product.Dists = append(product.Dists, dist)
+		}
+	}
+
+	if dockerOutputInfos := outputInfo.Product.DockerOutputInfos; dockerOutputInfos != nil {
+		exportPaths := outputInfo.ProductDockerExportArtifactPaths()
+		for _, dockerID := range dockerOutputInfos.DockerIDs {
+			typeName, err := productParam.Docker.DockerBuilderParams[dockerID].DockerBuilder.TypeName()
+			if err != nil {
+				return Product{}, errors.Wrapf(err, "failed to determine type of Docker builder %s", dockerID)
+			}
+			docker := Docker{
+				ID:   dockerID,
+				Type: typeName,
+				Tags: append([]string{}, dockerOutputInfos.DockerBuilderOutputInfos[dockerID].PushTags()...),
+			}
+			for _, exportPath := range exportPaths[dockerID] {
+				exportPath, err := pathFn(exportPath)
+				if err != nil {
+					return Product{}, err
+				}
+				docker.Exports = append(docker.Exports, exportPath)
+			}
+			product.Dockers = append(product.Dockers, docker)
+		}
+	}
+	return product, nil
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package productinfo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/palantir/distgo/distgo"
+	distgoconfig "github.com/palantir/distgo/distgo/config"
+	"github.com/palantir/distgo/distgo/productinfo"
+	"github.com/palantir/distgo/distgo/testfuncs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestPrint(t *testing.T) {
+	projectDir := t.TempDir()
+	var projectCfg distgoconfig.ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+products:
+  foo:
+    labels:
+      team: payments
+    build:
+      main-pkg: ./foo
+      os-archs:
+        - os: linux
+          arch: amd64
+        - os: windows
+          arch: amd64
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+          config:
+            os-archs:
+              - os: linux
+                arch: amd64
+    publish:
+      group-id: com.palantir.foo
+    docker:
+      docker-builders:
+        image:
+          type: default
+          context-dir: docker
+          tag-templates:
+            - foo:{{Version}}
+          export:
+            oci-archive: true
+    dependencies:
+      - bar
+  bar:
+    build:
+      main-pkg: ./bar
+      os-archs:
+        - os: linux
+          arch: amd64
+`), &projectCfg))
+	projectParam := testfuncs.NewProjectParam(t, projectCfg, projectDir, "")
+	projectInfo := distgo.ProjectInfo{
+		ProjectDir: projectDir,
+		Version:    "1.0.0",
+	}
+
+	for i, tc := range []struct {
+		name       string
+		productIDs []distgo.ProductID
+		absPaths   bool
+		want       func(projectDir string) productinfo.Output
+	}{
+		{
+			"relative paths",
+			[]distgo.ProductID{"foo", "bar"},
+			false,
+			func(projectDir string) productinfo.Output {
+				return productinfo.Output{
+					SchemaVersion: 1,
+					Project: productinfo.Project{
+						Dir:     projectDir,
+						Version: "1.0.0",
+					},
+					Products: []productinfo.Product{
+						{
+							ID:           "bar",
+							Name:         "bar",
+							Dependencies: []distgo.ProductID{},
+							Build: &productinfo.Build{
+								MainPkg:   "./bar",
+								OutputDir: "out/build/bar/1.0.0",
+								Artifacts: []productinfo.BuildArtifact{
+									{OSArch: "linux-amd64", Path: "out/build/bar/1.0.0/linux-amd64/bar"},
+								},
+							},
+						},
+						{
+							ID:              "foo",
+							Name:            "foo",
+							Labels:          map[string]string{"team": "payments"},
+							Dependencies:    []distgo.ProductID{"bar"},
+							MavenCoordinate: "com.palantir.foo:foo:1.0.0",
+							Build: &productinfo.Build{
+								MainPkg:   "./foo",
+								OutputDir: "out/build/foo/1.0.0",
+								Artifacts: []productinfo.BuildArtifact{
+									{OSArch: "linux-amd64", Path: "out/build/foo/1.0.0/linux-amd64/foo"},
+									{OSArch: "windows-amd64", Path: "out/build/foo/1.0.0/windows-amd64/foo.exe"},
+								},
+							},
+							Dists: []productinfo.Dist{
+								{
+									ID:        "os-arch-bin",
+									Type:      "os-arch-bin",
+									Packaging: "tgz",
+									OutputDir: "out/dist/foo/1.0.0/os-arch-bin",
+									Artifacts: []string{"out/dist/foo/1.0.0/os-arch-bin/foo-1.0.0-linux-amd64.tgz"},
+								},
+							},
+							Dockers: []productinfo.Docker{
+								{
+									ID:      "image",
+									Type:    "default",
+									Tags:    []string{"foo:1.0.0"},
+									Exports: []string{"out/docker/foo/1.0.0/foo-1.0.0-image.oci.tar.gz"},
+								},
+							},
+						},
+					},
+				}
+			},
+		},
+		{
+			"absolute paths",
+			[]distgo.ProductID{"bar"},
+			true,
+			func(projectDir string) productinfo.Output {
+				return productinfo.Output{
+					SchemaVersion: 1,
+					Project: productinfo.Project{
+						Dir:     projectDir,
+						Version: "1.0.0",
+					},
+					Products: []productinfo.Product{
+						{
+							ID:           "bar",
+							Name:         "bar",
+							Dependencies: []distgo.ProductID{},
+							Build: &productinfo.Build{
+								MainPkg:   "./bar",
+								OutputDir: projectDir + "/out/build/bar/1.0.0",
+								Artifacts: []productinfo.BuildArtifact{
+									{OSArch: "linux-amd64", Path: projectDir + "/out/build/bar/1.0.0/linux-amd64/bar"},
+								},
+							},
+						},
+					},
+				}
+			},
+		},
+		{
+			"no products",
+			nil,
+			false,
+			func(projectDir string) productinfo.Output {
+				return productinfo.Output{
+					SchemaVersion: 1,
+					Project: productinfo.Project{
+						Dir:     projectDir,
+						Version: "1.0.0",
+					},
+					Products: []productinfo.Product{},
+				}
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var productParams []distgo.ProductParam
+			for _, productID := range tc.productIDs {
+				productParams = append(productParams, projectParam.Products[productID])
+			}
+			buf := &bytes.Buffer{}
+			err := productinfo.Print(projectInfo, productParams, tc.absPaths, buf)
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+
+			var got productinfo.Output
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &got), "Case %d: %s", i, tc.name)
+			assert.Equal(t, tc.want(projectDir), got, "Case %d: %s", i, tc.name)
+		})
+	}
+}
+
+func TestFormatValidate(t *testing.T) {
+	assert.NoError(t, productinfo.FormatText.Validate())
+	assert.NoError(t, productinfo.FormatJSON.Validate())
+	assert.EqualError(t, productinfo.Format("yaml").Validate(), `invalid format "yaml": must be one of "text" or "json"`)
+}
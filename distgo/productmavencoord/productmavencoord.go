@@ -19,14 +19,23 @@ import (
 	"io"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/productinfo"
 	"github.com/palantir/distgo/publisher"
 )
 
-func Run(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, specifiedProductIDs []distgo.ProductID, stdout io.Writer) error {
+// Run prints the maven coordinates of the specified products. If the format is productinfo.FormatJSON, the information
+// about the products, including their maven coordinates, is printed as JSON instead.
+func Run(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, specifiedProductIDs []distgo.ProductID, format productinfo.Format, stdout io.Writer) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
 	productParams, err := distgo.ProductParamsForProductArgs(projectParam.Products, specifiedProductIDs...)
 	if err != nil {
 		return err
 	}
+	if format == productinfo.FormatJSON {
+		return productinfo.Print(projectInfo, productParams, false, stdout)
+	}
 	for _, productParam := range productParams {
 		productTaskOutputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, productParam)
 		if err != nil {
@@ -22,6 +22,7 @@ import (
 	"github.com/nmiyake/pkg/dirs"
 	"github.com/palantir/distgo/distgo"
 	distgoconfig "github.com/palantir/distgo/distgo/config"
+	"github.com/palantir/distgo/distgo/productinfo"
 	"github.com/palantir/distgo/distgo/productmavencoord"
 	"github.com/palantir/distgo/distgo/testfuncs"
 	"github.com/palantir/pkg/gittest"
@@ -215,7 +216,7 @@ com.zebra:zebra:3.0.0
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 
 			buf := &bytes.Buffer{}
-			err = productmavencoord.Run(projectInfo, projectParam, tc.specifiedProductIDs, buf)
+			err = productmavencoord.Run(projectInfo, projectParam, tc.specifiedProductIDs, productinfo.FormatText, buf)
 
 			if tc.wantError != "" {
 				require.Error(t, err, "Case %d: %s", i, tc.name)
@@ -71,24 +71,40 @@ func ProductParamsForProductArgs(inputProducts map[ProductID]ProductParam, produ
 }
 
 // ProductBuildID identifies a product or a specific build for a product. A ProductBuildID is one of the following:
-//   - {{ProductID}} (e.g. "foo"), which specifies that all OS/Archs for the product should be built
+//   - {{ProductID}} (e.g. "foo"), which specifies that all OS/Archs for the product and all of its variants should be
+//     built
 //   - {{ProductID}}.{{OSArch}} (e.g. "foo.darwin-amd64"), which specifies that the specified OS/Arch for the specified
+//     product and all of its variants should be built
+//   - {{ProductID}}@{{Variant}} (e.g. "foo@race"), which specifies that all OS/Archs for the specified variant of the
 //     product should be built
+//   - {{ProductID}}.{{OSArch}}@{{Variant}} (e.g. "foo.linux-amd64@race"), which specifies that the specified OS/Arch
+//     for the specified variant of the product should be built
 type ProductBuildID string
 
 func NewProductBuildID(productID ProductID, osArch osarch.OSArch) ProductBuildID {
-	if osArch == (osarch.OSArch{}) {
-		return ProductBuildID(productID)
+	return NewProductBuildVariantID(productID, osArch, "")
+}
+
+func NewProductBuildVariantID(productID ProductID, osArch osarch.OSArch, variant BuildVariantID) ProductBuildID {
+	id := string(productID)
+	if osArch != (osarch.OSArch{}) {
+		id = fmt.Sprintf("%s.%s", id, osArch.String())
 	}
-	return ProductBuildID(fmt.Sprintf("%s.%s", productID, osArch.String()))
+	if variant != "" {
+		id = fmt.Sprintf("%s@%s", id, variant)
+	}
+	return ProductBuildID(id)
 }
 
+// Parse returns the ProductID and OSArch specified by the ProductBuildID. The variant, if any, is ignored: use Variant
+// to retrieve it.
 func (id ProductBuildID) Parse() (ProductID, osarch.OSArch, error) {
-	currProductID := ProductID(id)
+	idWithoutVariant, _, _ := strings.Cut(string(id), "@")
+	currProductID := ProductID(idWithoutVariant)
 	var osArch osarch.OSArch
-	if dotIdx := strings.Index(string(id), "."); dotIdx != -1 {
-		currProductID = ProductID(id[:dotIdx])
-		osArchVal, err := osarch.New(string(id[dotIdx+1:]))
+	if dotIdx := strings.Index(idWithoutVariant, "."); dotIdx != -1 {
+		currProductID = ProductID(idWithoutVariant[:dotIdx])
+		osArchVal, err := osarch.New(idWithoutVariant[dotIdx+1:])
 		if err != nil {
 			return "", osarch.OSArch{}, errors.Wrapf(err, "failed to parse os-arch for %s", id)
 		}
@@ -97,6 +113,13 @@ func (id ProductBuildID) Parse() (ProductID, osarch.OSArch, error) {
 	return currProductID, osArch, nil
 }
 
+// Variant returns the build variant specified by the ProductBuildID. Returns an empty value if the ProductBuildID does
+// not specify a variant.
+func (id ProductBuildID) Variant() BuildVariantID {
+	_, variant, _ := strings.Cut(string(id), "@")
+	return BuildVariantID(variant)
+}
+
 func ToProductBuildIDs(in []string) []ProductBuildID {
 	var ids []ProductBuildID
 	for _, id := range in {
@@ -110,8 +133,9 @@ func ToProductBuildIDs(in []string) []ProductBuildID {
 // the osArchs parameter is non-empty, then the returned results will only include ProductParam values that match the
 // provided osArchs. For example, if the project defines a product "foo" with OS-Archs "darwin-amd64" and "linux-amd64"
 // and the productBuildID is "foo.darwin-amd64", the returned ProductParam will only contain "darwin-amd64" in the build
-// configuration. Returns an error if any of the productBuildID values cannot be resolved to a configuration in the
-// provided inputProducts.
+// configuration. If all of the build IDs for a product specify a variant, the Build.OSArchs of the returned
+// ProductParam is empty and its Build.Variants only contains the specified variants. Returns an error if any of the
+// productBuildID values cannot be resolved to a configuration in the provided inputProducts.
 func ProductParamsForBuildProductArgs(inputProducts map[ProductID]ProductParam, osArchs []osarch.OSArch, productBuildIDs ...ProductBuildID) ([]ProductParam, error) {
 	// error if project does not contain any productBuildIDs
 	if len(inputProducts) == 0 {
@@ -123,12 +147,23 @@ func ProductParamsForBuildProductArgs(inputProducts map[ProductID]ProductParam,
 	}
 
 	productIDToOSArchs := make(map[ProductID][]osarch.OSArch)
+	productIDToVariantOSArchs := make(map[ProductID]map[BuildVariantID][]osarch.OSArch)
+	var requestedIDs []string
 	for _, currProductBuildID := range productBuildIDs {
 		currProductID, osArch, err := currProductBuildID.Parse()
 		if err != nil {
 			return nil, err
 		}
-		productIDToOSArchs[currProductID] = append(productIDToOSArchs[currProductID], osArch)
+		variant := currProductBuildID.Variant()
+		requestedIDs = append(requestedIDs, string(NewProductBuildVariantID(currProductID, osArch, variant)))
+		if variant == "" {
+			productIDToOSArchs[currProductID] = append(productIDToOSArchs[currProductID], osArch)
+			continue
+		}
+		if productIDToVariantOSArchs[currProductID] == nil {
+			productIDToVariantOSArchs[currProductID] = make(map[BuildVariantID][]osarch.OSArch)
+		}
+		productIDToVariantOSArchs[currProductID][variant] = append(productIDToVariantOSArchs[currProductID][variant], osArch)
 	}
 	validIDs := make(map[string]struct{})
 	for productID, productParam := range inputProducts {
@@ -139,52 +174,85 @@ func ProductParamsForBuildProductArgs(inputProducts map[ProductID]ProductParam,
 		for _, osArch := range productParam.Build.OSArchs {
 			validIDs[fmt.Sprintf("%s.%s", productID, osArch)] = struct{}{}
 		}
+		for variant, variantParam := range productParam.Build.Variants {
+			validIDs[fmt.Sprintf("%s@%s", productID, variant)] = struct{}{}
+			for _, osArch := range variantParam.OSArchs {
+				validIDs[fmt.Sprintf("%s.%s@%s", productID, osArch, variant)] = struct{}{}
+			}
+		}
 	}
 	validIDsSorted := stringSetToSortedSlice(validIDs)
 
 	var invalidIDs []string
-	for productID, osArchs := range productIDToOSArchs {
-		for _, currOSArch := range osArchs {
-			currCombinedID := string(productID)
-			if currOSArch != (osarch.OSArch{}) {
-				currCombinedID = fmt.Sprintf("%s.%s", productID, currOSArch.String())
-			}
-			if _, ok := validIDs[currCombinedID]; ok {
-				continue
-			}
-			invalidIDs = append(invalidIDs, currCombinedID)
+	for _, currID := range requestedIDs {
+		if _, ok := validIDs[currID]; ok {
+			continue
 		}
+		invalidIDs = append(invalidIDs, currID)
 	}
 	sort.Strings(invalidIDs)
 	if len(invalidIDs) > 0 {
 		return nil, errors.Errorf("build product(s) %v not valid -- valid values are %v", invalidIDs, validIDsSorted)
 	}
 
-	// all IDs are valid. For any ID that has an empty OS/Arch as a value, expand to all OS/Archs.
+	// all IDs are valid. For any ID that has an empty OS/Arch as a value, expand to all OS/Archs. Such an ID also
+	// selects all of the variants of the product with all of their OS/Archs.
+	allSelected := make(map[ProductID]bool)
 	for productID, osArchs := range productIDToOSArchs {
 		allVals := slices.Contains(osArchs, (osarch.OSArch{}))
 		if !allVals || inputProducts[productID].Build == nil {
 			continue
 		}
-
-		var allOSArchs []osarch.OSArch
-		for _, osArch := range inputProducts[productID].Build.OSArchs {
-			allOSArchs = append(allOSArchs, osArch)
+		productIDToOSArchs[productID] = allOSArchsSorted(inputProducts[productID].Build.OSArchs)
+		allSelected[productID] = true
+	}
+	for productID, variantOSArchs := range productIDToVariantOSArchs {
+		for variant, osArchs := range variantOSArchs {
+			if !slices.Contains(osArchs, (osarch.OSArch{})) {
+				continue
+			}
+			variantOSArchs[variant] = inputProducts[productID].Build.Variants[variant].OSArchs
 		}
-		sort.Sort(byOSArch(allOSArchs))
-		productIDToOSArchs[productID] = allOSArchs
 	}
 
 	filteredProducts := make(map[ProductID]ProductParam)
-	for productID, osArchs := range productIDToOSArchs {
-		currProductParam := inputProducts[productID]
+	for productID, currProductParam := range inputProducts {
 		if currProductParam.Build == nil {
 			continue
 		}
+		osArchs, defaultSelected := productIDToOSArchs[productID]
+		variantOSArchs, variantSelected := productIDToVariantOSArchs[productID]
+		if !defaultSelected && !variantSelected {
+			continue
+		}
 
 		// modify copy so that original value remains the same
 		buildCopy := *currProductParam.Build
 		buildCopy.OSArchs = osArchs
+		buildCopy.Variants = nil
+		for variant, variantParam := range currProductParam.Build.Variants {
+			if allSelected[productID] {
+				if buildCopy.Variants == nil {
+					buildCopy.Variants = make(map[BuildVariantID]BuildVariantParam)
+				}
+				buildCopy.Variants[variant] = variantParam
+				continue
+			}
+			// an ID with an OS/Arch but without a variant selects all of the variants for the OS/Archs that it selects
+			selected := osArchSet(append(append([]osarch.OSArch(nil), osArchs...), variantOSArchs[variant]...))
+			if len(selected) == 0 {
+				continue
+			}
+			selectedOSArchs := filterOSArch(variantParam.OSArchs, selected)
+			if len(selectedOSArchs) == 0 {
+				continue
+			}
+			if buildCopy.Variants == nil {
+				buildCopy.Variants = make(map[BuildVariantID]BuildVariantParam)
+			}
+			variantParam.OSArchs = selectedOSArchs
+			buildCopy.Variants[variant] = variantParam
+		}
 		currProductParam.Build = &buildCopy
 
 		filteredProducts[productID] = currProductParam
@@ -192,28 +260,55 @@ func ProductParamsForBuildProductArgs(inputProducts map[ProductID]ProductParam,
 	return filterProductParamsToOSArch(toSortedProductParams(filteredProducts), osArchs), nil
 }
 
+func allOSArchsSorted(in []osarch.OSArch) []osarch.OSArch {
+	allOSArchs := append([]osarch.OSArch(nil), in...)
+	sort.Sort(byOSArch(allOSArchs))
+	return allOSArchs
+}
+
+func osArchSet(in []osarch.OSArch) map[osarch.OSArch]struct{} {
+	out := make(map[osarch.OSArch]struct{}, len(in))
+	for _, osArch := range in {
+		out[osArch] = struct{}{}
+	}
+	return out
+}
+
 // If osArchs is non-empty, returns a new ProductParam slice that contains only ProductParam values in the input where
-// at least one of the OSArchs in Build.OSArchs of the ProductParam is in the provided osArchs param. The Build.OSArchs
-// of the ProductParam values in the returned slice will also only contain the OSArchs that match the filter input. If
-// osArchs is empty, then the input is returned unmodified.
+// at least one of the OSArchs in Build.OSArchs or in the OSArchs of one of the Build.Variants of the ProductParam is
+// in the provided osArchs param. The Build.OSArchs and variant OSArchs of the ProductParam values in the returned slice
+// will also only contain the OSArchs that match the filter input. If osArchs is empty, then the input is returned
+// unmodified.
 func filterProductParamsToOSArch(in []ProductParam, osArchs []osarch.OSArch) []ProductParam {
 	// if filter set is empty, no need to filter
 	if len(osArchs) == 0 {
 		return in
 	}
 
-	osArchsMap := make(map[osarch.OSArch]struct{})
-	for _, osArch := range osArchs {
-		osArchsMap[osArch] = struct{}{}
-	}
+	osArchsMap := osArchSet(osArchs)
 
 	var out []ProductParam
 	for _, currParam := range in {
 		filtered := filterOSArch(currParam.Build.OSArchs, osArchsMap)
-		if len(filtered) == 0 {
+		var filteredVariants map[BuildVariantID]BuildVariantParam
+		for variant, variantParam := range currParam.Build.Variants {
+			variantParam.OSArchs = filterOSArch(variantParam.OSArchs, osArchsMap)
+			if len(variantParam.OSArchs) == 0 {
+				continue
+			}
+			if filteredVariants == nil {
+				filteredVariants = make(map[BuildVariantID]BuildVariantParam)
+			}
+			filteredVariants[variant] = variantParam
+		}
+		if len(filtered) == 0 && len(filteredVariants) == 0 {
 			continue
 		}
-		currParam.Build.OSArchs = filtered
+		// modify copy so that original value remains the same
+		buildCopy := *currParam.Build
+		buildCopy.OSArchs = filtered
+		buildCopy.Variants = filteredVariants
+		currParam.Build = &buildCopy
 		out = append(out, currParam)
 	}
 	return out
@@ -545,8 +640,9 @@ func ProductParamsForDockerProductArgs(inputProducts map[ProductID]ProductParam,
 // ProductParamsForDockerTagKeys returns the ProductParams from the provided inputProducts that have Docker tags whose
 // keys are contained in the provided keys. For example, if the provided keys are "release" and "snapshot", the returned
 // ProductParams will only contain Docker configurations that have tags that match one or both of those keys (and the
-// Docker configuration will be updated to contain only the tags that match those keys). Returns the provided input
-// unmodified if tagKeys does not contain any elements.
+// Docker configuration will be updated to contain only the tags that match those keys). The tag templates of the
+// mirrors of each retained Docker configuration are filtered in the same manner, and mirrors that have no matching tags
+// are removed. Returns the provided input unmodified if tagKeys does not contain any elements.
 func ProductParamsForDockerTagKeys(inputProducts []ProductParam, tagKeys []string) []ProductParam {
 	// if no products or tag keys were specified, return unmodified products
 	if len(inputProducts) == 0 || len(tagKeys) == 0 {
@@ -565,20 +661,22 @@ func ProductParamsForDockerTagKeys(inputProducts []ProductParam, tagKeys []strin
 		}
 		newDockerBuilders := make(map[DockerID]DockerBuilderParam)
 		for dockerID, dockerBuilderParam := range currProductParam.Docker.DockerBuilderParams {
-			filteredTagTemplates := TagTemplatesMap{
-				Templates: make(map[DockerTagID]string),
-			}
-			for _, currTagKey := range dockerBuilderParam.TagTemplates.OrderedKeys {
-				if _, ok := tagKeysMap[string(currTagKey)]; !ok {
-					continue
-				}
-				filteredTagTemplates.Templates[currTagKey] = dockerBuilderParam.TagTemplates.Templates[currTagKey]
-				filteredTagTemplates.OrderedKeys = append(filteredTagTemplates.OrderedKeys, currTagKey)
-			}
+			filteredTagTemplates := filterTagTemplates(dockerBuilderParam.TagTemplates, tagKeysMap)
 			if len(filteredTagTemplates.Templates) == 0 {
 				continue
 			}
 			dockerBuilderParam.TagTemplates = filteredTagTemplates
+			if len(dockerBuilderParam.Mirrors) > 0 {
+				filteredMirrors := make(map[DockerMirrorID]DockerMirrorParam)
+				for mirrorID, mirrorParam := range dockerBuilderParam.Mirrors {
+					mirrorParam.TagTemplates = filterTagTemplates(mirrorParam.TagTemplates, tagKeysMap)
+					if len(mirrorParam.TagTemplates.Templates) == 0 {
+						continue
+					}
+					filteredMirrors[mirrorID] = mirrorParam
+				}
+				dockerBuilderParam.Mirrors = filteredMirrors
+			}
 			newDockerBuilders[dockerID] = dockerBuilderParam
 		}
 
@@ -591,6 +689,22 @@ func ProductParamsForDockerTagKeys(inputProducts []ProductParam, tagKeys []strin
 	return filteredProducts
 }
 
+// filterTagTemplates returns a copy of the provided tag templates that only contains the templates whose keys are in
+// tagKeys.
+func filterTagTemplates(tagTemplates TagTemplatesMap, tagKeys map[string]struct{}) TagTemplatesMap {
+	filteredTagTemplates := TagTemplatesMap{
+		Templates: make(map[DockerTagID]string),
+	}
+	for _, currTagKey := range tagTemplates.OrderedKeys {
+		if _, ok := tagKeys[string(currTagKey)]; !ok {
+			continue
+		}
+		filteredTagTemplates.Templates[currTagKey] = tagTemplates.Templates[currTagKey]
+		filteredTagTemplates.OrderedKeys = append(filteredTagTemplates.OrderedKeys, currTagKey)
+	}
+	return filteredTagTemplates
+}
+
 func ClassifyProductParams(productParams []ProductParam) (allProducts map[ProductID]struct{}, specifiedProducts map[ProductID]struct{}, dependentProducts map[ProductID]struct{}) {
 	allProducts = make(map[ProductID]struct{})
 	specifiedProducts = make(map[ProductID]struct{})
@@ -21,6 +21,7 @@ import (
 	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProductParamsForProductArgs(t *testing.T) {
@@ -79,6 +80,90 @@ func TestProductParamsForProductArgs(t *testing.T) {
 	}
 }
 
+func TestExpandProductSelectors(t *testing.T) {
+	products := map[distgo.ProductID]distgo.ProductParam{
+		"api": {
+			ID:     "api",
+			Labels: map[string]string{"team": "payments"},
+			AllDependencies: map[distgo.ProductID]distgo.ProductParam{
+				"lib":    {ID: "lib"},
+				"worker": {ID: "worker"},
+			},
+		},
+		"cli": {
+			ID:     "cli",
+			Labels: map[string]string{"team": "payments"},
+			Groups: []string{"cli"},
+		},
+		"lib": {
+			ID:     "lib",
+			Labels: map[string]string{"team": "platform"},
+		},
+		"worker": {
+			ID:     "worker",
+			Groups: []string{"cli", "daemons"},
+		},
+	}
+	for i, tc := range []struct {
+		args      []string
+		want      []string
+		wantError string
+	}{
+		{
+			args: nil,
+			want: nil,
+		},
+		{
+			args: []string{"lib", "api.linux-amd64"},
+			want: []string{"lib", "api.linux-amd64"},
+		},
+		{
+			args: []string{"team=payments"},
+			want: []string{"api", "cli"},
+		},
+		{
+			args: []string{"team!=payments"},
+			want: []string{"lib", "worker"},
+		},
+		{
+			args: []string{"group:cli", "lib"},
+			want: []string{"cli", "worker", "lib"},
+		},
+		{
+			args: []string{"team=payments,!group:cli"},
+			want: []string{"api"},
+		},
+		{
+			args: []string{"!lib", "worker"},
+			want: []string{"api", "cli", "worker"},
+		},
+		{
+			args: []string{"deps-of:api"},
+			want: []string{"lib", "worker"},
+		},
+		{
+			args:      []string{"team=search"},
+			wantError: `product selector "team=search" does not match any products`,
+		},
+		{
+			args:      []string{"deps-of:missing"},
+			wantError: `invalid product selector "deps-of:missing": product "missing" does not exist`,
+		},
+		{
+			args:      []string{"team=payments,"},
+			wantError: `invalid product selector "team=payments,": invalid expression ""`,
+		},
+	} {
+		got, err := distgo.ExpandProductSelectors(products, tc.args)
+		if tc.wantError != "" {
+			assert.EqualError(t, err, tc.wantError, "Case %d", i)
+			continue
+		}
+		assert.NoError(t, err, "Case %d", i)
+		assert.Equal(t, tc.want, got, "Case %d", i)
+	}
+}
+
 func TestProductParamsForBuildProductArgs(t *testing.T) {
 	mustOSArch := func(in string) osarch.OSArch {
 		out, err := osarch.New(in)
@@ -287,6 +372,177 @@ func TestProductParamsForBuildProductArgs(t *testing.T) {
 	}
 }
 
+func TestProductParamsForBuildProductArgsVariants(t *testing.T) {
+	darwinAMD64 := osarch.OSArch{OS: "darwin", Arch: "amd64"}
+	linuxAMD64 := osarch.OSArch{OS: "linux", Arch: "amd64"}
+	raceVariant := distgo.BuildVariantParam{
+		Environment: map[string]string{"CGO_ENABLED": "1"},
+		OSArchs:     []osarch.OSArch{linuxAMD64},
+	}
+	v3Variant := distgo.BuildVariantParam{
+		Environment: map[string]string{"GOAMD64": "v3"},
+		OSArchs:     []osarch.OSArch{darwinAMD64, linuxAMD64},
+	}
+	products := map[distgo.ProductID]distgo.ProductParam{
+		"foo": {
+			ID: "foo",
+			Build: &distgo.BuildParam{
+				OSArchs: []osarch.OSArch{darwinAMD64, linuxAMD64},
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"race": raceVariant,
+					"v3":   v3Variant,
+				},
+			},
+		},
+	}
+	withOSArchs := func(param distgo.BuildVariantParam, osArchs ...osarch.OSArch) distgo.BuildVariantParam {
+		param.OSArchs = osArchs
+		return param
+	}
+	darwinARM64 := osarch.OSArch{OS: "darwin", Arch: "arm64"}
+	variantOSArchsProducts := map[distgo.ProductID]distgo.ProductParam{
+		"foo": {
+			ID: "foo",
+			Build: &distgo.BuildParam{
+				OSArchs: []osarch.OSArch{darwinARM64},
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"race": raceVariant,
+				},
+			},
+		},
+	}
+	variantOnlyProducts := map[distgo.ProductID]distgo.ProductParam{
+		"foo": {
+			ID: "foo",
+			Build: &distgo.BuildParam{
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"race": raceVariant,
+					"v3":   v3Variant,
+				},
+			},
+		},
+	}
+
+	for i, tc := range []struct {
+		// products are the input products. If nil, the products defined above are used.
+		products        map[distgo.ProductID]distgo.ProductParam
+		osArchs         []osarch.OSArch
+		productBuildIDs []distgo.ProductBuildID
+		want            *distgo.BuildParam
+		wantError       string
+	}{
+		{
+			productBuildIDs: []distgo.ProductBuildID{"foo"},
+			want: &distgo.BuildParam{
+				OSArchs: []osarch.OSArch{darwinAMD64, linuxAMD64},
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"race": raceVariant,
+					"v3":   v3Variant,
+				},
+			},
+		},
+		{
+			// a product ID selects the variants with all of their OS/Archs, even if they are not OS/Archs of the
+			// default build
+			products:        variantOSArchsProducts,
+			productBuildIDs: []distgo.ProductBuildID{"foo"},
+			want: &distgo.BuildParam{
+				OSArchs: []osarch.OSArch{darwinARM64},
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"race": raceVariant,
+				},
+			},
+		},
+		{
+			// a product ID selects all of the variants of a product that only has variants
+			products:        variantOnlyProducts,
+			productBuildIDs: []distgo.ProductBuildID{"foo"},
+			want: &distgo.BuildParam{
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"race": raceVariant,
+					"v3":   v3Variant,
+				},
+			},
+		},
+		{
+			productBuildIDs: []distgo.ProductBuildID{"foo.darwin-amd64"},
+			want: &distgo.BuildParam{
+				OSArchs: []osarch.OSArch{darwinAMD64},
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"v3": withOSArchs(v3Variant, darwinAMD64),
+				},
+			},
+		},
+		{
+			productBuildIDs: []distgo.ProductBuildID{"foo@race"},
+			want: &distgo.BuildParam{
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"race": raceVariant,
+				},
+			},
+		},
+		{
+			productBuildIDs: []distgo.ProductBuildID{"foo.darwin-amd64@v3", "foo@race"},
+			want: &distgo.BuildParam{
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"race": raceVariant,
+					"v3":   withOSArchs(v3Variant, darwinAMD64),
+				},
+			},
+		},
+		{
+			osArchs: []osarch.OSArch{darwinAMD64},
+			want: &distgo.BuildParam{
+				OSArchs: []osarch.OSArch{darwinAMD64},
+				Variants: map[distgo.BuildVariantID]distgo.BuildVariantParam{
+					"v3": withOSArchs(v3Variant, darwinAMD64),
+				},
+			},
+		},
+		{
+			productBuildIDs: []distgo.ProductBuildID{"foo.darwin-amd64@race", "foo@debug"},
+			wantError:       "build product(s) [foo.darwin-amd64@race foo@debug] not valid -- valid values are [foo foo.darwin-amd64 foo.darwin-amd64@v3 foo.linux-amd64 foo.linux-amd64@race foo.linux-amd64@v3 foo@race foo@v3]",
+		},
+	} {
+		inputProducts := tc.products
+		if inputProducts == nil {
+			inputProducts = products
+		}
+		got, err := distgo.ProductParamsForBuildProductArgs(inputProducts, tc.osArchs, tc.productBuildIDs...)
+		if tc.wantError != "" {
+			assert.EqualError(t, err, tc.wantError, "Case %d", i)
+			continue
+		}
+		require.NoError(t, err, "Case %d", i)
+		require.Len(t, got, 1, "Case %d", i)
+		assert.Equal(t, tc.want, got[0].Build, "Case %d", i)
+	}
+	// the input products are not modified
+	assert.Equal(t, []osarch.OSArch{darwinAMD64, linuxAMD64}, products["foo"].Build.OSArchs)
+	assert.Len(t, products["foo"].Build.Variants, 2)
+}
+
+func TestProductBuildIDParse(t *testing.T) {
+	for i, tc := range []struct {
+		id          distgo.ProductBuildID
+		wantProduct distgo.ProductID
+		wantOSArch  osarch.OSArch
+		wantVariant distgo.BuildVariantID
+	}{
+		{"foo", "foo", osarch.OSArch{}, ""},
+		{"foo.linux-amd64", "foo", osarch.OSArch{OS: "linux", Arch: "amd64"}, ""},
+		{"foo@race", "foo", osarch.OSArch{}, "race"},
+		{"foo.linux-amd64@race", "foo", osarch.OSArch{OS: "linux", Arch: "amd64"}, "race"},
+	} {
+		productID, osArch, err := tc.id.Parse()
+		require.NoError(t, err, "Case %d", i)
+		assert.Equal(t, tc.wantProduct, productID, "Case %d", i)
+		assert.Equal(t, tc.wantOSArch, osArch, "Case %d", i)
+		assert.Equal(t, tc.wantVariant, tc.id.Variant(), "Case %d", i)
+		assert.Equal(t, tc.id, distgo.NewProductBuildVariantID(productID, osArch, tc.id.Variant()), "Case %d", i)
+	}
+}
+
 func TestProductParamsForDistProductArgs(t *testing.T) {
 	for i, tc := range []struct {
 		projectParam   distgo.ProjectParam
@@ -0,0 +1,163 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	groupSelectorPrefix  = "group:"
+	depsOfSelectorPrefix = "deps-of:"
+
+	// SelectorNameRequirements describes the valid values for label keys and group names.
+	SelectorNameRequirements = "must be non-empty and consist only of alphanumeric characters, '-', '_', '.' and '/'"
+)
+
+var selectorNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_./-]+$`)
+
+// ProductSelectorHelp describes the syntax of product selector expressions. It can be used in the help text of
+// commands that accept products.
+const ProductSelectorHelp = `Products can also be specified using selector expressions:
+  key=value       products whose label "key" has the value "value"
+  key!=value      products whose label "key" does not have the value "value"
+  group:name      products in the group "name"
+  deps-of:id      all of the products that the product "id" depends on
+  !expr           products that do not match the expression "expr"
+Expressions separated by commas in a single argument must all match (for example, "team=payments,!group:cli"), and
+the products matched by multiple arguments are combined.`
+
+// IsValidSelectorName returns true if the provided value is a valid label key or group name.
+func IsValidSelectorName(name string) bool {
+	return selectorNameRegexp.MatchString(name)
+}
+
+// IsProductSelector returns true if the provided argument is a product selector expression rather than a product ID
+// or an ID derived from one (such as a ProductBuildID).
+func IsProductSelector(arg string) bool {
+	return strings.ContainsAny(arg, "=:!,")
+}
+
+// ExpandProductSelectors returns the provided arguments with every product selector expression replaced by the IDs of
+// the products that it matches in sorted order. Arguments that are not selector expressions are returned unmodified,
+// and an ID is only returned once. Returns an error if a selector expression is invalid or does not match any
+// products.
+func ExpandProductSelectors(products map[ProductID]ProductParam, args []string) ([]string, error) {
+	var out []string
+	seen := make(map[string]struct{})
+	add := func(arg string) {
+		if _, ok := seen[arg]; ok {
+			return
+		}
+		seen[arg] = struct{}{}
+		out = append(out, arg)
+	}
+	for _, arg := range args {
+		if !IsProductSelector(arg) {
+			add(arg)
+			continue
+		}
+		matches, err := selectProducts(products, arg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid product selector %q", arg)
+		}
+		if len(matches) == 0 {
+			return nil, errors.Errorf("product selector %q does not match any products", arg)
+		}
+		for _, productID := range matches {
+			add(string(productID))
+		}
+	}
+	return out, nil
+}
+
+func selectProducts(products map[ProductID]ProductParam, selector string) ([]ProductID, error) {
+	var predicates []func(ProductParam) bool
+	for term := range strings.SplitSeq(selector, ",") {
+		predicate, err := parseSelectorTerm(products, strings.TrimSpace(term))
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+	var matches []ProductID
+	for _, productID := range slices.Sorted(maps.Keys(products)) {
+		if !slices.ContainsFunc(predicates, func(predicate func(ProductParam) bool) bool {
+			return !predicate(products[productID])
+		}) {
+			matches = append(matches, productID)
+		}
+	}
+	return matches, nil
+}
+
+func parseSelectorTerm(products map[ProductID]ProductParam, term string) (func(ProductParam) bool, error) {
+	if negated, ok := strings.CutPrefix(term, "!"); ok {
+		predicate, err := parseSelectorTerm(products, negated)
+		if err != nil {
+			return nil, err
+		}
+		return func(p ProductParam) bool {
+			return !predicate(p)
+		}, nil
+	}
+	if group, ok := strings.CutPrefix(term, groupSelectorPrefix); ok {
+		if !IsValidSelectorName(group) {
+			return nil, errors.Errorf("invalid group %q: %s", group, SelectorNameRequirements)
+		}
+		return func(p ProductParam) bool {
+			return slices.Contains(p.Groups, group)
+		}, nil
+	}
+	if productID, ok := strings.CutPrefix(term, depsOfSelectorPrefix); ok {
+		product, ok := products[ProductID(productID)]
+		if !ok {
+			return nil, errors.Errorf("product %q does not exist", productID)
+		}
+		return func(p ProductParam) bool {
+			_, ok := product.AllDependencies[p.ID]
+			return ok
+		}, nil
+	}
+	if key, val, ok := strings.Cut(term, "!="); ok {
+		if !IsValidSelectorName(key) {
+			return nil, errors.Errorf("invalid label key %q: %s", key, SelectorNameRequirements)
+		}
+		return func(p ProductParam) bool {
+			return p.Labels[key] != val
+		}, nil
+	}
+	if key, val, ok := strings.Cut(term, "="); ok {
+		if !IsValidSelectorName(key) {
+			return nil, errors.Errorf("invalid label key %q: %s", key, SelectorNameRequirements)
+		}
+		return func(p ProductParam) bool {
+			labelVal, ok := p.Labels[key]
+			return ok && labelVal == val
+		}, nil
+	}
+	if term == "" || IsProductSelector(term) {
+		return nil, errors.Errorf("invalid expression %q", term)
+	}
+	// a term without selector syntax matches the product with that ID
+	return func(p ProductParam) bool {
+		return p.ID == ProductID(term)
+	}, nil
+}
@@ -19,9 +19,18 @@ import (
 	"io"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/productinfo"
 )
 
-func Run(projectInfo distgo.ProjectInfo, stdout io.Writer) error {
+// Run prints the version of the project. If the format is productinfo.FormatJSON, the information about the project is
+// printed as JSON instead.
+func Run(projectInfo distgo.ProjectInfo, format productinfo.Format, stdout io.Writer) error {
+	if err := format.Validate(); err != nil {
+		return err
+	}
+	if format == productinfo.FormatJSON {
+		return productinfo.Print(projectInfo, nil, false, stdout)
+	}
 	_, _ = fmt.Fprintln(stdout, projectInfo.Version)
 	return nil
 }
@@ -23,6 +23,7 @@ import (
 
 	"github.com/nmiyake/pkg/dirs"
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/productinfo"
 	"github.com/palantir/distgo/distgo/projectversion"
 	"github.com/palantir/distgo/projectversioner/git"
 	"github.com/palantir/distgo/projectversioner/script"
@@ -104,7 +105,7 @@ func TestProjectVersionDefaultParam(t *testing.T) {
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 
 			buf := &bytes.Buffer{}
-			err = projectversion.Run(projectInfo, buf)
+			err = projectversion.Run(projectInfo, productinfo.FormatText, buf)
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 			assert.Regexp(t, tc.want, buf.String(), "Case %d: %s", i, tc.name)
 		})
@@ -149,7 +150,7 @@ echo "3.2.1"
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 
 			buf := &bytes.Buffer{}
-			err = projectversion.Run(projectInfo, buf)
+			err = projectversion.Run(projectInfo, productinfo.FormatText, buf)
 			require.NoError(t, err, "Case %d: %s", i, tc.name)
 			assert.Regexp(t, tc.want, buf.String(), "Case %d: %s", i, tc.name)
 		})
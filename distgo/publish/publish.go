@@ -61,6 +61,26 @@ func Products(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam,
 	return nil
 }
 
+// Run publishes the dist artifacts of the provided product using the provided publisher. The dist artifacts of the
+// product must already exist in their proper locations.
+func Run(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, publisher distgo.Publisher, flagVals map[distgo.PublisherFlagName]any, dryRun bool, stdout io.Writer) error {
+	publisherType, err := publisher.TypeName()
+	if err != nil {
+		return errors.Wrapf(err, "failed to determine type of publisher")
+	}
+	input, err := getProductPublishInfo(projectInfo, productParam, publisherType, dryRun, stdout)
+	if err != nil {
+		return err
+	}
+	if input == nil {
+		return nil
+	}
+	if err := publisher.RunPublish([]distgo.ProductPublishInfo{*input}, flagVals, dryRun, stdout); err != nil {
+		return errors.Wrapf(err, "failed to publish product %s using %s publisher", productParam.ID, publisherType)
+	}
+	return nil
+}
+
 // getProductPublishInfo computes and returns the [distgo.ProductPublishInfo] for the specified productParam, or nil
 // if it has no dist outputs and should be skipped. The outputs for any dependent products of the param must already
 // exist in their proper locations.
@@ -0,0 +1,397 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/build"
+	"github.com/palantir/distgo/distgo/dist"
+	"github.com/palantir/distgo/distgo/docker"
+	"github.com/palantir/distgo/distgo/publish"
+)
+
+const (
+	UnitTypeBuild       = "build"
+	UnitTypeDist        = "dist"
+	UnitTypeDockerBuild = "docker-build"
+	UnitTypeDockerPush  = "docker-push"
+	UnitTypePublish     = "publish"
+)
+
+// Options configure the units that are run by a release.
+type Options struct {
+	ExecuteOptions
+
+	DryRun bool
+	// ConfigModTime is the modification time of the configuration. As for the "dist" task, a dist is only run if it is
+	// out-of-date with respect to the configuration or its inputs. If nil, all of the dists are run.
+	ConfigModTime *time.Time
+	// BuildParallel specifies that the builds of a product for its OS/Archs and build variants are run in parallel.
+	BuildParallel bool
+	// SizeReport is the path of the file to which the size report of the build outputs of the released products is
+	// written once all of the units have completed. No report is written if empty.
+	SizeReport string
+	// SizeReportPackages specifies that the sizes of the build outputs are attributed to Go packages in the size report.
+	SizeReportPackages bool
+	// Push specifies that the Docker images of the specified products are pushed.
+	Push bool
+	// Insecure allows Docker images to be pushed to insecure registries.
+	Insecure bool
+	// Publisher is the publisher used to publish the dist artifacts of the specified products. If nil, the products are
+	// not published.
+	Publisher         distgo.Publisher
+	PublisherFlagVals map[distgo.PublisherFlagName]any
+}
+
+// Run builds, creates the distributions of, builds the Docker images of and (optionally) pushes the Docker images of
+// and publishes the specified products. If no product IDs are specified, all of the products in the project are
+// released. The units of work are run using Execute. If opts.SizeReport is non-empty, the size report of the build
+// outputs of the products that are built by the release is written once all of the units have completed.
+func Run(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productIDs []distgo.ProductID, opts Options, stdout io.Writer) error {
+	units, err := Units(projectInfo, projectParam, productIDs, opts)
+	if err != nil {
+		return err
+	}
+	if err := Execute(units, opts.ExecuteOptions, stdout); err != nil {
+		return err
+	}
+	var builtProductParams []distgo.ProductParam
+	for _, unit := range units {
+		if unit.ID == buildUnitID(unit.Product) {
+			builtProductParams = append(builtProductParams, projectParam.Products[unit.Product])
+		}
+	}
+	return build.WriteSizeReport(projectInfo, builtProductParams, opts.buildOptions(), stdout)
+}
+
+// buildOptions returns the options used to run the build units.
+func (o Options) buildOptions() build.Options {
+	return build.Options{
+		Parallel:           o.BuildParallel,
+		DryRun:             o.DryRun,
+		SizeReport:         o.SizeReport,
+		SizeReportPackages: o.SizeReportPackages,
+	}
+}
+
+// Units returns the units of work for releasing the specified products in sorted order. If no product IDs are
+// specified, all of the products in the project are released. The build, dist and Docker build units are created for
+// the specified products and all of the products that they depend on, while the Docker push and publish units are only
+// created for the specified products. The dependencies of the units are:
+//   - dist units depend on the build units of their product and of all of the products it depends on, on the dist units
+//     of the products it declares as dependencies and on the Docker build units of their input Docker exports
+//   - Docker build units depend on the build and dist units of their product and of all of the products it depends on
+//     and on the units for their input builds and input dists (except for the dists that take Docker exports as input)
+//     and on the Docker build units of the products it declares as dependencies
+//   - Docker push units depend on the Docker build unit for the same Docker builder
+//   - publish units depend on the dist units of their product
+func Units(projectInfo distgo.ProjectInfo, projectParam distgo.ProjectParam, productIDs []distgo.ProductID, opts Options) ([]Unit, error) {
+	var specifiedParams []distgo.ProductParam
+	if len(productIDs) == 0 {
+		for _, productID := range slices.Sorted(maps.Keys(projectParam.Products)) {
+			specifiedParams = append(specifiedParams, projectParam.Products[productID])
+		}
+	} else {
+		var err error
+		if specifiedParams, err = distgo.ProductParamsForProductArgs(projectParam.Products, productIDs...); err != nil {
+			return nil, err
+		}
+	}
+	allProducts, _, _ := distgo.ClassifyProductParams(specifiedParams)
+
+	var units []Unit
+	rebuilt := &rebuiltProducts{}
+	for _, productID := range slices.Sorted(maps.Keys(allProducts)) {
+		units = append(units, productUnits(projectInfo, projectParam.Products[productID], opts, rebuilt)...)
+	}
+	for _, productParam := range specifiedParams {
+		units = append(units, releaseUnits(projectInfo, productParam, opts)...)
+	}
+
+	// remove the dependencies on units that are not part of the release, such as the build unit of a product without a
+	// build configuration
+	unitIDs := make(map[UnitID]struct{}, len(units))
+	for _, unit := range units {
+		unitIDs[unit.ID] = struct{}{}
+	}
+	for i := range units {
+		var deps []UnitID
+		for _, depID := range units[i].Deps {
+			if _, ok := unitIDs[depID]; ok && !slices.Contains(deps, depID) {
+				deps = append(deps, depID)
+			}
+		}
+		slices.Sort(deps)
+		units[i].Deps = deps
+	}
+	slices.SortFunc(units, func(a, b Unit) int {
+		if a.ID < b.ID {
+			return -1
+		} else if a.ID > b.ID {
+			return 1
+		}
+		return 0
+	})
+	return units, nil
+}
+
+// rebuiltProducts records the products whose build units built their outputs because they were out-of-date.
+type rebuiltProducts struct {
+	mu         sync.Mutex
+	productIDs map[distgo.ProductID]struct{}
+}
+
+func (r *rebuiltProducts) add(productID distgo.ProductID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.productIDs == nil {
+		r.productIDs = make(map[distgo.ProductID]struct{})
+	}
+	r.productIDs[productID] = struct{}{}
+}
+
+func (r *rebuiltProducts) containsAny(productIDs []distgo.ProductID) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, productID := range productIDs {
+		if _, ok := r.productIDs[productID]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// productUnits returns the build, dist and Docker build units for the provided product. The build and dist units only
+// run the build and dist tasks if their outputs are out-of-date, using the same checks as the "build" and "dist"
+// tasks that the "docker build" task runs for its inputs.
+func productUnits(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, opts Options, rebuilt *rebuiltProducts) []Unit {
+	var units []Unit
+	if productParam.Build != nil {
+		units = append(units, Unit{
+			ID:      buildUnitID(productParam.ID),
+			Product: productParam.ID,
+			Run: func(stdout io.Writer) error {
+				requiresBuildParam, err := build.RequiresBuild(projectInfo, productParam)
+				if err != nil {
+					return err
+				}
+				if requiresBuildParam == nil {
+					distgo.PrintlnOrDryRunPrintln(stdout, fmt.Sprintf("%s is up-to-date; skipping build", productParam.ID), opts.DryRun)
+					return nil
+				}
+				rebuilt.add(productParam.ID)
+				// the size report covers all of the products of the release, so it is written once by Run
+				buildOpts := opts.buildOptions()
+				buildOpts.SizeReport = ""
+				return build.Run(projectInfo, []distgo.ProductParam{*requiresBuildParam}, buildOpts, stdout)
+			},
+		})
+	}
+
+	// the units of a product depend on the build units of the product and all of the products it depends on
+	buildProductIDs := append([]distgo.ProductID{productParam.ID}, productParam.AllDependenciesSortedIDs()...)
+	var buildDeps []UnitID
+	for _, currID := range buildProductIDs {
+		buildDeps = append(buildDeps, buildUnitID(currID))
+	}
+
+	if productParam.Dist != nil {
+		for _, distID := range slices.Sorted(maps.Keys(productParam.Dist.DistParams)) {
+			disterParam := productParam.Dist.DistParams[distID]
+			deps := slices.Clone(buildDeps)
+			for _, depID := range productParam.FirstLevelDependencies {
+				deps = append(deps, productDistUnitIDs(productParam.AllDependencies[depID])...)
+			}
+			for _, productDockerID := range disterParam.InputDockerExports {
+				inputProductID, dockerID, _ := productDockerID.Parse()
+				deps = append(deps, dockerBuildUnitID(inputProductID, dockerID))
+			}
+			distParam := withDistParams(productParam, distID)
+			units = append(units, Unit{
+				ID:      distUnitID(productParam.ID, distID),
+				Product: productParam.ID,
+				Deps:    deps,
+				Run: func(stdout io.Writer) error {
+					configModTime := opts.ConfigModTime
+					if rebuilt.containsAny(buildProductIDs) {
+						// as for the "dist" task, the dist is run if any of the products it depends on were rebuilt
+						configModTime = nil
+					}
+					requiresDistParam, err := dist.RequiresDist(projectInfo, distParam, configModTime)
+					if err != nil {
+						return err
+					}
+					if requiresDistParam == nil {
+						distgo.PrintlnOrDryRunPrintln(stdout, fmt.Sprintf("dist %s of %s is up-to-date; skipping dist", distID, productParam.ID), opts.DryRun)
+						return nil
+					}
+					return dist.Run(projectInfo, *requiresDistParam, opts.DryRun, stdout)
+				},
+			})
+		}
+	}
+
+	if productParam.Docker != nil {
+		for _, dockerID := range slices.Sorted(maps.Keys(productParam.Docker.DockerBuilderParams)) {
+			dockerBuilderParam := productParam.Docker.DockerBuilderParams[dockerID]
+			deps := slices.Clone(buildDeps)
+			for _, currParam := range productParam.AllProductParams() {
+				deps = append(deps, productDistUnitIDs(currParam)...)
+			}
+			for _, depID := range productParam.FirstLevelDependencies {
+				deps = append(deps, productDockerBuildUnitIDs(productParam.AllDependencies[depID])...)
+			}
+			for _, productBuildID := range dockerBuilderParam.InputBuilds {
+				inputProductID, _, _ := productBuildID.Parse()
+				deps = append(deps, buildUnitID(inputProductID))
+			}
+			for _, productDistID := range dockerBuilderParam.InputDists {
+				inputProductID, distID := productDistID.Parse()
+				if !takesDockerExports(productParam, inputProductID, distID) {
+					deps = append(deps, distUnitID(inputProductID, distID))
+				}
+			}
+			dockerParam := withDockerBuilderParams(productParam, dockerID)
+			units = append(units, Unit{
+				ID:      dockerBuildUnitID(productParam.ID, dockerID),
+				Product: productParam.ID,
+				Deps:    deps,
+				Run: func(stdout io.Writer) error {
+					return docker.RunBuild(projectInfo, dockerParam, false, opts.DryRun, stdout)
+				},
+			})
+		}
+	}
+	return units
+}
+
+// releaseUnits returns the Docker push and publish units for the provided product.
+func releaseUnits(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, opts Options) []Unit {
+	var units []Unit
+	if opts.Push && productParam.Docker != nil {
+		for _, dockerID := range slices.Sorted(maps.Keys(productParam.Docker.DockerBuilderParams)) {
+			if productParam.Docker.DockerBuilderParams[dockerID].SkipPush {
+				continue
+			}
+			dockerParam := withDockerBuilderParams(productParam, dockerID)
+			units = append(units, Unit{
+				ID:      dockerPushUnitID(productParam.ID, dockerID),
+				Product: productParam.ID,
+				Deps:    []UnitID{dockerBuildUnitID(productParam.ID, dockerID)},
+				Run: func(stdout io.Writer) error {
+					return docker.RunPush(projectInfo, dockerParam, opts.DryRun, opts.Insecure, stdout)
+				},
+			})
+		}
+	}
+	if opts.Publisher != nil && productParam.Dist != nil {
+		units = append(units, Unit{
+			ID:      publishUnitID(productParam.ID),
+			Product: productParam.ID,
+			Deps:    productDistUnitIDs(productParam),
+			Run: func(stdout io.Writer) error {
+				return publish.Run(projectInfo, productParam, opts.Publisher, opts.PublisherFlagVals, opts.DryRun, stdout)
+			},
+		})
+	}
+	return units
+}
+
+// withDistParams returns a copy of the provided product whose dist configuration only contains the specified dist.
+func withDistParams(productParam distgo.ProductParam, distID distgo.DistID) distgo.ProductParam {
+	distParam := *productParam.Dist
+	distParam.DistParams = map[distgo.DistID]distgo.DisterParam{
+		distID: productParam.Dist.DistParams[distID],
+	}
+	productParam.Dist = &distParam
+	return productParam
+}
+
+// withDockerBuilderParams returns a copy of the provided product whose Docker configuration only contains the specified
+// Docker builder.
+func withDockerBuilderParams(productParam distgo.ProductParam, dockerID distgo.DockerID) distgo.ProductParam {
+	dockerParam := *productParam.Docker
+	dockerParam.DockerBuilderParams = map[distgo.DockerID]distgo.DockerBuilderParam{
+		dockerID: productParam.Docker.DockerBuilderParams[dockerID],
+	}
+	productParam.Docker = &dockerParam
+	return productParam
+}
+
+// productDistUnitIDs returns the IDs of the dist units of the provided product, excluding the dists that take Docker
+// exports as input.
+func productDistUnitIDs(productParam distgo.ProductParam) []UnitID {
+	if productParam.Dist == nil {
+		return nil
+	}
+	var ids []UnitID
+	for distID, disterParam := range productParam.Dist.DistParams {
+		if len(disterParam.InputDockerExports) == 0 {
+			ids = append(ids, distUnitID(productParam.ID, distID))
+		}
+	}
+	return ids
+}
+
+// takesDockerExports returns true if the specified dist of the provided product or one of its dependencies takes Docker
+// exports as input. Such dists consume the output of Docker builds, so they are not inputs of Docker builds.
+func takesDockerExports(productParam distgo.ProductParam, inputProductID distgo.ProductID, distID distgo.DistID) bool {
+	inputProductParam, ok := productParam.AllDependencies[inputProductID]
+	if inputProductID == productParam.ID {
+		inputProductParam, ok = productParam, true
+	}
+	if !ok || inputProductParam.Dist == nil {
+		return false
+	}
+	return len(inputProductParam.Dist.DistParams[distID].InputDockerExports) != 0
+}
+
+func productDockerBuildUnitIDs(productParam distgo.ProductParam) []UnitID {
+	if productParam.Docker == nil {
+		return nil
+	}
+	var ids []UnitID
+	for dockerID := range productParam.Docker.DockerBuilderParams {
+		ids = append(ids, dockerBuildUnitID(productParam.ID, dockerID))
+	}
+	return ids
+}
+
+func buildUnitID(productID distgo.ProductID) UnitID {
+	return UnitID(fmt.Sprintf("%s:%s", UnitTypeBuild, productID))
+}
+
+func distUnitID(productID distgo.ProductID, distID distgo.DistID) UnitID {
+	return UnitID(fmt.Sprintf("%s:%s.%s", UnitTypeDist, productID, distID))
+}
+
+func dockerBuildUnitID(productID distgo.ProductID, dockerID distgo.DockerID) UnitID {
+	return UnitID(fmt.Sprintf("%s:%s.%s", UnitTypeDockerBuild, productID, dockerID))
+}
+
+func dockerPushUnitID(productID distgo.ProductID, dockerID distgo.DockerID) UnitID {
+	return UnitID(fmt.Sprintf("%s:%s.%s", UnitTypeDockerPush, productID, dockerID))
+}
+
+func publishUnitID(productID distgo.ProductID) UnitID {
+	return UnitID(fmt.Sprintf("%s:%s", UnitTypePublish, productID))
+}
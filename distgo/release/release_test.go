@@ -0,0 +1,456 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/palantir/distgo/dister/disterfactory"
+	"github.com/palantir/distgo/distgo"
+	distgoconfig "github.com/palantir/distgo/distgo/config"
+	"github.com/palantir/distgo/distgo/release"
+	"github.com/palantir/distgo/dockerbuilder/dockerbuilderfactory"
+	"github.com/palantir/distgo/publisher/mavenlocal"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestExecute(t *testing.T) {
+	for i, tc := range []struct {
+		name      string
+		units     map[release.UnitID][]release.UnitID
+		failing   map[release.UnitID]bool
+		keepGoing bool
+		wantRun   []release.UnitID
+		wantErrs  map[distgo.ProductID]string
+	}{
+		{
+			"units run after their dependencies",
+			map[release.UnitID][]release.UnitID{
+				"build:foo":             nil,
+				"build:bar":             nil,
+				"dist:foo.bin":          {"build:foo", "build:bar"},
+				"docker-build:foo.prod": {"dist:foo.bin"},
+			},
+			nil,
+			false,
+			[]release.UnitID{"build:bar", "build:foo", "dist:foo.bin", "docker-build:foo.prod"},
+			nil,
+		},
+		{
+			"keep-going skips dependents of failed unit and runs other units",
+			map[release.UnitID][]release.UnitID{
+				"build:foo":             nil,
+				"build:bar":             nil,
+				"dist:foo.bin":          {"build:foo"},
+				"docker-build:foo.prod": {"dist:foo.bin"},
+				"dist:bar.bin":          {"build:bar"},
+			},
+			map[release.UnitID]bool{
+				"build:foo": true,
+			},
+			true,
+			[]release.UnitID{"build:bar", "build:foo", "dist:bar.bin"},
+			map[distgo.ProductID]string{
+				"foo": "build:foo failed: build:foo failed",
+			},
+		},
+		{
+			"fail-fast does not start new units after failure",
+			map[release.UnitID][]release.UnitID{
+				"build:foo":    nil,
+				"dist:foo.bin": {"build:foo"},
+				"build:bar":    {"dist:foo.bin"},
+				"dist:bar.bin": {"dist:foo.bin"},
+			},
+			map[release.UnitID]bool{
+				"dist:foo.bin": true,
+			},
+			false,
+			[]release.UnitID{"build:foo", "dist:foo.bin"},
+			map[distgo.ProductID]string{
+				"foo": "dist:foo.bin failed: dist:foo.bin failed",
+			},
+		},
+		{
+			"errors are aggregated per product",
+			map[release.UnitID][]release.UnitID{
+				"build:foo":    nil,
+				"dist:foo.bin": nil,
+				"build:bar":    nil,
+			},
+			map[release.UnitID]bool{
+				"build:foo":    true,
+				"dist:foo.bin": true,
+				"build:bar":    true,
+			},
+			true,
+			[]release.UnitID{"build:bar", "build:foo", "dist:foo.bin"},
+			map[distgo.ProductID]string{
+				"foo": "build:foo failed: build:foo failed\ndist:foo.bin failed: dist:foo.bin failed",
+				"bar": "build:bar failed: build:bar failed",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var mu sync.Mutex
+			ran := make(map[release.UnitID]bool)
+			var units []release.Unit
+			for id, deps := range tc.units {
+				units = append(units, release.Unit{
+					ID:      id,
+					Product: unitProduct(id),
+					Deps:    deps,
+					Run: func(stdout io.Writer) error {
+						mu.Lock()
+						defer mu.Unlock()
+						for _, depID := range deps {
+							assert.True(t, ran[depID], "Case %d: %s: %s ran before its dependency %s", i, tc.name, id, depID)
+						}
+						ran[id] = true
+						if tc.failing[id] {
+							return errors.Errorf("%s failed", id)
+						}
+						return nil
+					},
+				})
+			}
+			// sort units so that the order in which ready units are started is deterministic
+			slices.SortFunc(units, func(a, b release.Unit) int {
+				return strings.Compare(string(a.ID), string(b.ID))
+			})
+
+			err := release.Execute(units, release.ExecuteOptions{Concurrency: 1, KeepGoing: tc.keepGoing}, io.Discard)
+
+			var gotRun []release.UnitID
+			for _, unit := range units {
+				if ran[unit.ID] {
+					gotRun = append(gotRun, unit.ID)
+				}
+			}
+			assert.Equal(t, tc.wantRun, gotRun, "Case %d: %s", i, tc.name)
+			if tc.wantErrs == nil {
+				require.NoError(t, err, "Case %d: %s", i, tc.name)
+				return
+			}
+			var productErrs *distgo.ProductErrors
+			require.True(t, errors.As(err, &productErrs), "Case %d: %s: unexpected error %v", i, tc.name, err)
+			gotErrs := make(map[distgo.ProductID]string)
+			for productID, productErr := range productErrs.Errors {
+				gotErrs[productID] = productErr.Error()
+			}
+			assert.Equal(t, tc.wantErrs, gotErrs, "Case %d: %s", i, tc.name)
+		})
+	}
+}
+
+func TestExecuteConcurrencyLimit(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	var units []release.Unit
+	for _, id := range []release.UnitID{"build:a", "build:b", "build:c", "build:d", "build:e", "build:f"} {
+		units = append(units, release.Unit{
+			ID:      id,
+			Product: unitProduct(id),
+			Run: func(stdout io.Writer) error {
+				curr := running.Add(1)
+				defer running.Add(-1)
+				for {
+					prevMax := maxRunning.Load()
+					if curr <= prevMax || maxRunning.CompareAndSwap(prevMax, curr) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				return nil
+			},
+		})
+	}
+	require.NoError(t, release.Execute(units, release.ExecuteOptions{Concurrency: 2}, io.Discard))
+	assert.Equal(t, int32(2), maxRunning.Load())
+}
+
+func TestExecuteInvalidGraph(t *testing.T) {
+	noop := func(stdout io.Writer) error {
+		return nil
+	}
+	err := release.Execute([]release.Unit{
+		{ID: "build:foo", Deps: []release.UnitID{"dist:foo.bin"}, Run: noop},
+		{ID: "dist:foo.bin", Deps: []release.UnitID{"build:foo"}, Run: noop},
+	}, release.ExecuteOptions{}, io.Discard)
+	assert.EqualError(t, err, "units contain a cycle: build:foo -> dist:foo.bin -> build:foo")
+
+	err = release.Execute([]release.Unit{
+		{ID: "dist:foo.bin", Deps: []release.UnitID{"build:foo"}, Run: noop},
+	}, release.ExecuteOptions{}, io.Discard)
+	assert.EqualError(t, err, "unit dist:foo.bin depends on unit build:foo, which does not exist")
+}
+
+func TestUnits(t *testing.T) {
+	for i, tc := range []struct {
+		name       string
+		yml        string
+		productIDs []distgo.ProductID
+		opts       release.Options
+		want       map[release.UnitID][]release.UnitID
+	}{
+		{
+			"units and dependencies for product and its dependencies",
+			`
+products:
+  base:
+    build:
+      main-pkg: ./base
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+    docker:
+      docker-builders:
+        base-image:
+          type: default
+          context-dir: base-docker
+          tag-templates:
+            - base:{{Version}}
+  server:
+    build:
+      main-pkg: ./server
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+    docker:
+      docker-builders:
+        server-image:
+          type: default
+          context-dir: server-docker
+          input-builds:
+            - server
+          input-dists:
+            - server.os-arch-bin
+          tag-templates:
+            - server:{{Version}}
+    dependencies:
+      - base
+  other:
+    build:
+      main-pkg: ./other
+`,
+			[]distgo.ProductID{"server"},
+			release.Options{},
+			map[release.UnitID][]release.UnitID{
+				"build:base":                   nil,
+				"build:server":                 nil,
+				"dist:base.os-arch-bin":        {"build:base"},
+				"dist:server.os-arch-bin":      {"build:base", "build:server", "dist:base.os-arch-bin"},
+				"docker-build:base.base-image": {"build:base", "dist:base.os-arch-bin"},
+				"docker-build:server.server-image": {
+					"build:base",
+					"build:server",
+					"dist:base.os-arch-bin",
+					"dist:server.os-arch-bin",
+					"docker-build:base.base-image",
+				},
+			},
+		},
+		{
+			"push and publish units are only created for specified products",
+			`
+products:
+  base:
+    docker:
+      docker-builders:
+        base-image:
+          type: default
+          context-dir: base-docker
+          tag-templates:
+            - base:{{Version}}
+        base-test-image:
+          type: default
+          context-dir: base-docker
+          skip-push: true
+          tag-templates:
+            - base-test:{{Version}}
+  server:
+    build:
+      main-pkg: ./server
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+    docker:
+      docker-builders:
+        server-image:
+          type: default
+          context-dir: server-docker
+          tag-templates:
+            - server:{{Version}}
+    dependencies:
+      - base
+`,
+			nil,
+			release.Options{
+				Push:      true,
+				Publisher: mavenlocal.PublisherCreator().Publisher(),
+			},
+			map[release.UnitID][]release.UnitID{
+				"build:server":                      nil,
+				"dist:server.os-arch-bin":           {"build:server"},
+				"docker-build:base.base-image":      nil,
+				"docker-build:base.base-test-image": nil,
+				"docker-build:server.server-image": {
+					"build:server",
+					"dist:server.os-arch-bin",
+					"docker-build:base.base-image",
+					"docker-build:base.base-test-image",
+				},
+				"docker-push:base.base-image":     {"docker-build:base.base-image"},
+				"docker-push:server.server-image": {"docker-build:server.server-image"},
+				"publish:server":                  {"dist:server.os-arch-bin"},
+			},
+		},
+		{
+			"dist with input Docker exports depends on Docker build",
+			`
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+        image-bundle:
+          type: os-arch-bin
+          input-docker-exports:
+            - foo.image
+    docker:
+      docker-builders:
+        image:
+          type: default
+          context-dir: docker
+          export:
+            oci-archive: true
+          tag-templates:
+            - foo:{{Version}}
+`,
+			nil,
+			release.Options{},
+			map[release.UnitID][]release.UnitID{
+				"build:foo":              nil,
+				"dist:foo.image-bundle":  {"build:foo", "docker-build:foo.image"},
+				"dist:foo.os-arch-bin":   {"build:foo"},
+				"docker-build:foo.image": {"build:foo", "dist:foo.os-arch-bin"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			var projectCfg distgoconfig.ProjectConfig
+			require.NoError(t, yaml.Unmarshal([]byte(tc.yml), &projectCfg), "Case %d: %s", i, tc.name)
+			disterFactory, err := disterfactory.New(nil, nil)
+			require.NoError(t, err)
+			dockerBuilderFactory, err := dockerbuilderfactory.New(nil, nil)
+			require.NoError(t, err)
+			projectParam, err := projectCfg.ToParam(projectDir, nil, disterFactory, distgoconfig.DisterConfig{}, dockerBuilderFactory, nil)
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+
+			units, err := release.Units(distgo.ProjectInfo{ProjectDir: projectDir, Version: "1.0.0"}, projectParam, tc.productIDs, tc.opts)
+			require.NoError(t, err, "Case %d: %s", i, tc.name)
+			got := make(map[release.UnitID][]release.UnitID)
+			for _, unit := range units {
+				got[unit.ID] = unit.Deps
+			}
+			assert.Equal(t, tc.want, got, "Case %d: %s", i, tc.name)
+		})
+	}
+}
+
+// TestRunSkipsUpToDateOutputs verifies that the build and dist units only run the build and dist tasks if their outputs
+// are out-of-date and that the size report of the build outputs is written once the release has completed.
+func TestRunSkipsUpToDateOutputs(t *testing.T) {
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module foo\n"), 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(projectDir, "foo"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "foo", "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644))
+
+	var projectCfg distgoconfig.ProjectConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+products:
+  foo:
+    build:
+      main-pkg: ./foo
+    dist:
+      disters:
+        os-arch-bin:
+          type: os-arch-bin
+`), &projectCfg))
+	disterFactory, err := disterfactory.New(nil, nil)
+	require.NoError(t, err)
+	dockerBuilderFactory, err := dockerbuilderfactory.New(nil, nil)
+	require.NoError(t, err)
+	projectParam, err := projectCfg.ToParam(projectDir, nil, disterFactory, distgoconfig.DisterConfig{}, dockerBuilderFactory, nil)
+	require.NoError(t, err)
+	projectInfo := distgo.ProjectInfo{ProjectDir: projectDir, Version: "1.0.0"}
+
+	sizeReportPath := filepath.Join(t.TempDir(), "size-report.json")
+	opts := release.Options{
+		ConfigModTime: new(time.Now().Add(-time.Hour)),
+		BuildParallel: true,
+		SizeReport:    sizeReportPath,
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, release.Run(projectInfo, projectParam, nil, opts, buf))
+	assert.NotContains(t, buf.String(), "up-to-date")
+	assert.FileExists(t, sizeReportPath)
+
+	require.NoError(t, os.Remove(sizeReportPath))
+	buf.Reset()
+	require.NoError(t, release.Run(projectInfo, projectParam, nil, opts, buf))
+	assert.Contains(t, buf.String(), "foo is up-to-date; skipping build\n")
+	assert.Contains(t, buf.String(), "dist os-arch-bin of foo is up-to-date; skipping dist\n")
+	assert.FileExists(t, sizeReportPath)
+}
+
+func TestExecuteWritesUnitOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := release.Execute([]release.Unit{
+		{ID: "build:foo", Product: "foo", Run: func(stdout io.Writer) error {
+			return errors.New("compile error")
+		}},
+		{ID: "dist:foo.bin", Product: "foo", Deps: []release.UnitID{"build:foo"}, Run: func(stdout io.Writer) error {
+			return nil
+		}},
+	}, release.ExecuteOptions{KeepGoing: true}, buf)
+	require.Error(t, err)
+	assert.Equal(t, "Skipping dist:foo.bin because build:foo failed\n", buf.String())
+}
+
+func unitProduct(id release.UnitID) distgo.ProductID {
+	_, rest, _ := strings.Cut(string(id), ":")
+	productID, _, _ := strings.Cut(rest, ".")
+	return distgo.ProductID(productID)
+}
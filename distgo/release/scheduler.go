@@ -0,0 +1,217 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package release
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/internal/syncwriter"
+	"github.com/pkg/errors"
+)
+
+// UnitID is the unique identifier of a unit of work, which has the form "<UnitType>:<ProductID>" or
+// "<UnitType>:<ProductID>.<DistID or DockerID>".
+type UnitID string
+
+// Unit is a unit of work that is run by Execute.
+type Unit struct {
+	ID UnitID
+	// Product is the product that the unit belongs to. Errors are reported per product.
+	Product distgo.ProductID
+	// Deps are the units that must complete successfully before this unit is run.
+	Deps []UnitID
+	Run  func(stdout io.Writer) error
+}
+
+// ExecuteOptions configure how Execute runs units.
+type ExecuteOptions struct {
+	// Concurrency is the maximum number of units that run at the same time. If it is not positive, the number of
+	// logical processors reported by Go is used.
+	Concurrency int
+	// KeepGoing specifies that the units that do not depend on a failed unit continue to run after a unit fails. If
+	// false, no units are started after the first failure, but the units that are already running are allowed to
+	// finish.
+	KeepGoing bool
+}
+
+// Execute runs the provided units, starting each unit as soon as all of its dependencies have completed successfully.
+// A unit whose dependency failed is not run. Returns an error if a unit depends on a unit that does not exist or if
+// the dependencies of the units contain a cycle. If any unit fails, returns a *distgo.ProductErrors that contains the
+// failures of each product.
+func Execute(units []Unit, opts ExecuteOptions, stdout io.Writer) error {
+	unitsByID := make(map[UnitID]Unit, len(units))
+	for _, unit := range units {
+		if _, ok := unitsByID[unit.ID]; ok {
+			return errors.Errorf("unit %s is specified more than once", unit.ID)
+		}
+		unitsByID[unit.ID] = unit
+	}
+	dependents := make(map[UnitID][]UnitID)
+	remainingDeps := make(map[UnitID]int)
+	for _, unit := range units {
+		for _, depID := range unit.Deps {
+			if _, ok := unitsByID[depID]; !ok {
+				return errors.Errorf("unit %s depends on unit %s, which does not exist", unit.ID, depID)
+			}
+			dependents[depID] = append(dependents[depID], unit.ID)
+		}
+		remainingDeps[unit.ID] = len(unit.Deps)
+	}
+	if cycle := findUnitCycle(units, unitsByID); cycle != nil {
+		return errors.Errorf("units contain a cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+	stdout = syncwriter.New(stdout)
+
+	type result struct {
+		id  UnitID
+		err error
+	}
+	results := make(chan result)
+	var ready []UnitID
+	for _, unit := range units {
+		if remainingDeps[unit.ID] == 0 {
+			ready = append(ready, unit.ID)
+		}
+	}
+
+	failures := make(map[distgo.ProductID][]string)
+	var wg sync.WaitGroup
+	running, failed, pending := 0, false, len(units)
+	for pending > 0 {
+		for len(ready) > 0 && running < concurrency && (opts.KeepGoing || !failed) {
+			unit := unitsByID[ready[0]]
+			ready = ready[1:]
+			running++
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				start := time.Now()
+				err := unit.Run(stdout)
+				if err == nil {
+					_, _ = fmt.Fprintf(stdout, "Finished %s (%.3fs)\n", unit.ID, time.Since(start).Seconds())
+				}
+				results <- result{id: unit.ID, err: err}
+			}()
+		}
+		if running == 0 {
+			break
+		}
+		res := <-results
+		running--
+		pending--
+		if res.err != nil {
+			failed = true
+			unit := unitsByID[res.id]
+			failures[unit.Product] = append(failures[unit.Product], fmt.Sprintf("%s failed: %v", res.id, res.err))
+			pending -= skipDependents(res.id, dependents, remainingDeps, stdout)
+			continue
+		}
+		for _, dependentID := range dependents[res.id] {
+			remainingDeps[dependentID]--
+			if remainingDeps[dependentID] == 0 {
+				ready = append(ready, dependentID)
+			}
+		}
+	}
+	wg.Wait()
+
+	if len(failures) == 0 {
+		return nil
+	}
+	productErrs := make(map[distgo.ProductID]error, len(failures))
+	for productID, msgs := range failures {
+		productErrs[productID] = errors.New(strings.Join(msgs, "\n"))
+	}
+	return &distgo.ProductErrors{Errors: productErrs}
+}
+
+// skipDependents marks all of the units that transitively depend on the failed unit as skipped so that they are never
+// run, and returns the number of units that were skipped.
+func skipDependents(failedID UnitID, dependents map[UnitID][]UnitID, remainingDeps map[UnitID]int, stdout io.Writer) int {
+	skipped := 0
+	queue := []UnitID{failedID}
+	for len(queue) > 0 {
+		curr := queue[0]
+		queue = queue[1:]
+		for _, dependentID := range dependents[curr] {
+			if remainingDeps[dependentID] < 0 {
+				continue
+			}
+			// a negative count marks the unit as skipped: it can never become ready
+			remainingDeps[dependentID] = -1
+			skipped++
+			_, _ = fmt.Fprintf(stdout, "Skipping %s because %s failed\n", dependentID, failedID)
+			queue = append(queue, dependentID)
+		}
+	}
+	return skipped
+}
+
+func findUnitCycle(units []Unit, unitsByID map[UnitID]Unit) []string {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[UnitID]int)
+	var path []string
+	var visit func(id UnitID) []string
+	visit = func(id UnitID) []string {
+		switch state[id] {
+		case visiting:
+			for i, curr := range path {
+				if curr == string(id) {
+					return append(append([]string(nil), path[i:]...), string(id))
+				}
+			}
+		case visited:
+			return nil
+		}
+		state[id] = visiting
+		path = append(path, string(id))
+		for _, depID := range unitsByID[id].Deps {
+			if cycle := visit(depID); cycle != nil {
+				return cycle
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		return nil
+	}
+	ids := make([]UnitID, 0, len(units))
+	for _, unit := range units {
+		ids = append(ids, unit.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	for _, id := range ids {
+		if cycle := visit(id); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
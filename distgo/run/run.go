@@ -21,18 +21,24 @@ import (
 	"go/token"
 	"io"
 	"os"
-	"os/exec"
 	"path"
 	"strings"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/pkg/errors"
 )
 
+// wasip1OSArch is the OS/Arch for which products are built when they are run using a WebAssembly runtime.
+var wasip1OSArch = osarch.OSArch{OS: "wasip1", Arch: "wasm"}
+
 func Product(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, runArgs []string, stdout, stderr io.Writer) error {
 	if productParam.Build == nil {
 		return errors.Errorf("product %s has no build configuration defined", productParam.ID)
 	}
+	if productParam.Build.Mode.IsLibrary() {
+		return errors.Errorf("product %s cannot be run because its build mode is %s", productParam.ID, productParam.Build.Mode)
+	}
 
 	mainPkgDir := path.Join(projectInfo.ProjectDir, productParam.Build.MainPkg)
 	mainPkgGoFiles, err := mainPkgGoFiles(mainPkgDir)
@@ -40,15 +46,24 @@ func Product(projectInfo distgo.ProjectInfo, productParam distgo.ProductParam, r
 		return errors.Wrapf(err, "failed to find Go files for main package")
 	}
 
-	cmd := exec.Command("go")
+	cmd := productParam.Build.GoToolchain.Command(projectInfo.ProjectDir)
 	args := []string{cmd.Path, "run"}
+	cmd.Env = append(os.Environ(), productParam.Build.GoToolchain.Environment()...)
+
+	// products with a WebAssembly runtime are built for WASI and executed by the runtime
+	runOSArch := osarch.Current()
+	if productParam.Run != nil && productParam.Run.WasmRuntime != "" {
+		runOSArch = wasip1OSArch
+		args = append(args, "-exec", productParam.Run.WasmRuntime)
+		cmd.Env = append(cmd.Env, "GOOS="+runOSArch.OS, "GOARCH="+runOSArch.Arch)
+	}
 
 	// add build arguments for product
 	productTaskOutputInfo, err := distgo.ToProductTaskOutputInfo(projectInfo, productParam)
 	if err != nil {
 		return errors.Wrapf(err, "failed to compute output info")
 	}
-	buildArgs, err := productParam.Build.BuildArgs(productTaskOutputInfo)
+	buildArgs, err := productParam.Build.BuildArgs(productTaskOutputInfo, runOSArch, "")
 	if err != nil {
 		return err
 	}
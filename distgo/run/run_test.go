@@ -197,6 +197,32 @@ func main() {
 				assert.Equal(t, "0.1.0", string(bytes))
 			},
 		},
+		{
+			`"run" executes the product built for wasip1-wasm using the WebAssembly runtime`,
+			distgoconfig.ProductConfig{
+				Build: distgoconfig.ToBuildConfig(&distgoconfig.BuildConfig{
+					MainPkg: new("."),
+				}),
+				Run: distgoconfig.ToRunConfig(&distgoconfig.RunConfig{
+					Args:        &[]string{"foo"},
+					WasmRuntime: new(path.Join(tmp, "wasm-runtime.sh")),
+				}),
+			},
+			[]string{"bar"},
+			func(t *testing.T, projectDir string) {
+				err := os.WriteFile(path.Join(projectDir, "main.go"), []byte(strings.Replace(runTestMain, "{{OUTPUT_PATH}}", projectDir, -1)), 0644)
+				require.NoError(t, err)
+				// the runtime records the module and the arguments that it is invoked with
+				err = os.WriteFile(path.Join(tmp, "wasm-runtime.sh"), []byte(fmt.Sprintf("#!/bin/sh\necho \"$@\" > %s\n", path.Join(projectDir, "wasmRuntimeArgs.txt"))), 0755)
+				require.NoError(t, err)
+			},
+			func(t *testing.T, runErr error, caseNum int, projectDir string) {
+				assert.NoError(t, runErr, "Case %d", caseNum)
+				bytes, err := os.ReadFile(path.Join(projectDir, "wasmRuntimeArgs.txt"))
+				require.NoError(t, err, "Case %d", caseNum)
+				assert.Regexp(t, regexp.MustCompile(`^\S+ foo bar\n$`), string(bytes))
+			},
+		},
 		{
 			`"run" works with multiple main package files as long as there is a single main function`,
 			distgoconfig.ProductConfig{
@@ -16,9 +16,15 @@ package distgo
 
 import (
 	"bytes"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"text/template"
+	"text/template/parse"
+	"time"
 
+	"github.com/palantir/godel/v2/pkg/osarch"
 	"github.com/pkg/errors"
 )
 
@@ -60,6 +66,59 @@ func RepositoryLiteralTemplateFunction(repository string) TemplateFunction {
 	return TemplateValueFunction("RepositoryLiteral", repository)
 }
 
+// EnvTemplateFunction provides the "Env" function, which returns the value of the environment variable with the
+// provided name. If the variable is unset or empty and a second argument is provided, that argument is returned
+// instead: for example, {{Env "REGISTRY" "docker.io"}}.
+func EnvTemplateFunction() TemplateFunction {
+	return func(fnMap template.FuncMap) {
+		fnMap["Env"] = func(name string, defaultVal ...string) (string, error) {
+			if len(defaultVal) > 1 {
+				return "", errors.Errorf("Env accepts at most one default value, was %v", defaultVal)
+			}
+			if val := os.Getenv(name); val != "" || len(defaultVal) == 0 {
+				return val, nil
+			}
+			return defaultVal[0], nil
+		}
+	}
+}
+
+// VarTemplateFunction provides the "Var" function, which returns the value of the variable with the provided name
+// from the provided variables. Referencing a variable that does not exist is an error.
+func VarTemplateFunction(vars map[string]string) TemplateFunction {
+	return func(fnMap template.FuncMap) {
+		fnMap["Var"] = func(name string) (string, error) {
+			val, ok := vars[name]
+			if !ok {
+				return "", errors.Errorf("variable %q is not defined", name)
+			}
+			return val, nil
+		}
+	}
+}
+
+// CommitTemplateFunction provides the "Commit" function, which returns the provided commit hash.
+func CommitTemplateFunction(commit string) TemplateFunction {
+	return TemplateValueFunction("Commit", commit)
+}
+
+// BuildDateTemplateFunction provides the "BuildDate" function, which returns the provided time in UTC formatted using
+// RFC 3339.
+func BuildDateTemplateFunction(buildDate time.Time) TemplateFunction {
+	return TemplateValueFunction("BuildDate", buildDate.UTC().Format(time.RFC3339))
+}
+
+// DirtyTemplateFunction provides the "Dirty" function, which returns "true" or "false".
+func DirtyTemplateFunction(dirty bool) TemplateFunction {
+	return TemplateValueFunction("Dirty", strconv.FormatBool(dirty))
+}
+
+// OSArchTemplateFunction provides the "OSArch" function, which returns the provided OS/architecture. It renders as
+// "{os}-{arch}", and its fields can be accessed using {{(OSArch).OS}} and {{(OSArch).Arch}}.
+func OSArchTemplateFunction(osArch osarch.OSArch) TemplateFunction {
+	return TemplateValueFunction("OSArch", osArch)
+}
+
 func TemplateValueFunction(key string, val any) TemplateFunction {
 	return func(fnMap template.FuncMap) {
 		fnMap[key] = func() any {
@@ -87,6 +146,132 @@ func RenderTemplate(tmplContent string, data any, fns ...TemplateFunction) (stri
 	return output.String(), nil
 }
 
+// PartiallyRenderTemplate renders the actions of the provided template that call at least one of the provided functions
+// and otherwise only use the provided functions and builtin functions. The result has every other part of the template,
+// such as actions that use functions that are only provided when a task runs or that only use builtin functions,
+// unchanged. This allows configuration values to be rendered in multiple stages.
+func PartiallyRenderTemplate(tmplContent string, fns ...TemplateFunction) (string, error) {
+	if !strings.Contains(tmplContent, "{{") {
+		return tmplContent, nil
+	}
+	tmplFuncs := make(map[string]any)
+	for _, fn := range fns {
+		fn(tmplFuncs)
+	}
+	tree := parse.New("distgoTemplate")
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(tmplContent, "", "", make(map[string]*parse.Tree)); err != nil {
+		return "", errors.Wrapf(err, "failed to parse template %s", tmplContent)
+	}
+	output := &strings.Builder{}
+	renderedAny := false
+	for _, node := range tree.Root.Nodes {
+		if textNode, ok := node.(*parse.TextNode); ok {
+			output.Write(textNode.Text)
+			continue
+		}
+		nodeContent := node.String()
+		if !onlyUsesFunctions(node, tmplFuncs) || !callsFunction(node, tmplFuncs) {
+			output.WriteString(nodeContent)
+			continue
+		}
+		rendered, err := RenderTemplate(nodeContent, nil, fns...)
+		if err != nil {
+			return "", err
+		}
+		output.WriteString(rendered)
+		renderedAny = true
+	}
+	if !renderedAny {
+		// return the original content so that templates that are only rendered when a task runs are not reformatted
+		return tmplContent, nil
+	}
+	return output.String(), nil
+}
+
+// onlyUsesFunctions returns true if the provided template node does not reference data or variables and every
+// function that it calls is in the provided map.
+func onlyUsesFunctions(node parse.Node, fns map[string]any) bool {
+	builtins := []string{"and", "call", "html", "index", "slice", "js", "len", "not", "or", "print", "printf", "println", "urlquery", "eq", "ge", "gt", "le", "lt", "ne"}
+	switch n := node.(type) {
+	case nil:
+		return true
+	case *parse.IdentifierNode:
+		_, ok := fns[n.Ident]
+		return ok || slices.Contains(builtins, n.Ident)
+	case *parse.ActionNode:
+		return onlyUsesFunctions(n.Pipe, fns)
+	case *parse.PipeNode:
+		if n == nil {
+			return true
+		}
+		if len(n.Decl) > 0 {
+			return false
+		}
+		for _, cmd := range n.Cmds {
+			if !onlyUsesFunctions(cmd, fns) {
+				return false
+			}
+		}
+		return true
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if !onlyUsesFunctions(arg, fns) {
+				return false
+			}
+		}
+		return true
+	case *parse.ChainNode:
+		return onlyUsesFunctions(n.Node, fns)
+	case *parse.ListNode:
+		if n == nil {
+			return true
+		}
+		for _, child := range n.Nodes {
+			if !onlyUsesFunctions(child, fns) {
+				return false
+			}
+		}
+		return true
+	case *parse.IfNode:
+		return onlyUsesFunctions(n.Pipe, fns) && onlyUsesFunctions(n.List, fns) && onlyUsesFunctions(n.ElseList, fns)
+	case *parse.TextNode, *parse.StringNode, *parse.NumberNode, *parse.BoolNode, *parse.NilNode, *parse.CommentNode:
+		return true
+	default:
+		// data, variables, range and with are only rendered when the full template is rendered
+		return false
+	}
+}
+
+// callsFunction returns true if the provided template node calls any of the functions in the provided map.
+func callsFunction(node parse.Node, fns map[string]any) bool {
+	switch n := node.(type) {
+	case *parse.IdentifierNode:
+		_, ok := fns[n.Ident]
+		return ok
+	case *parse.ActionNode:
+		return callsFunction(n.Pipe, fns)
+	case *parse.PipeNode:
+		return n != nil && slices.ContainsFunc(n.Cmds, func(cmd *parse.CommandNode) bool {
+			return callsFunction(cmd, fns)
+		})
+	case *parse.CommandNode:
+		return slices.ContainsFunc(n.Args, func(arg parse.Node) bool {
+			return callsFunction(arg, fns)
+		})
+	case *parse.ChainNode:
+		return callsFunction(n.Node, fns)
+	case *parse.ListNode:
+		return n != nil && slices.ContainsFunc(n.Nodes, func(child parse.Node) bool {
+			return callsFunction(child, fns)
+		})
+	case *parse.IfNode:
+		return callsFunction(n.Pipe, fns) || callsFunction(n.List, fns) || callsFunction(n.ElseList, fns)
+	default:
+		return false
+	}
+}
+
 func renderNameTemplate(nameTemplate, productName, version string) (string, error) {
 	return RenderTemplate(nameTemplate, nil,
 		ProductTemplateFunction(productName),
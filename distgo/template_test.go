@@ -0,0 +1,91 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo_test
+
+import (
+	"testing"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/godel/v2/pkg/osarch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartiallyRenderTemplate(t *testing.T) {
+	t.Setenv("DISTGO_TEST_REGISTRY", "registry.example.com")
+	fns := []distgo.TemplateFunction{
+		distgo.EnvTemplateFunction(),
+		distgo.VarTemplateFunction(map[string]string{"team": "infra"}),
+		distgo.CommitTemplateFunction("0123456789abcdef"),
+	}
+	for _, tc := range []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "renders provided functions",
+			tmpl: `{{Env "DISTGO_TEST_REGISTRY"}}/{{Var "team"}}:{{Commit}}`,
+			want: "registry.example.com/infra:0123456789abcdef",
+		},
+		{
+			name: "uses default value for unset environment variable",
+			tmpl: `{{Env "DISTGO_TEST_UNSET" "docker.io"}}`,
+			want: "docker.io",
+		},
+		{
+			name: "keeps functions that are not provided",
+			tmpl: `{{Repository}}{{Var "team"}}/{{Product}}:{{Version}}`,
+			want: "{{Repository}}infra/{{Product}}:{{Version}}",
+		},
+		{
+			name: "keeps templates that reference data unchanged",
+			tmpl: `docker inspect --format '{{ .Id }}' {{ printf "%s" .Name }}`,
+			want: `docker inspect --format '{{ .Id }}' {{ printf "%s" .Name }}`,
+		},
+		{
+			name: "keeps actions that only use builtin functions unchanged",
+			tmpl: `echo "{{print}}" {{printf "%d" 1}} {{Var "team"}}`,
+			want: `echo "{{print}}" {{printf "%d" 1}} infra`,
+		},
+		{
+			name: "renders conditionals that only use provided functions",
+			tmpl: `{{if eq (Var "team") "infra"}}internal{{else}}public{{end}}`,
+			want: "internal",
+		},
+		{
+			name:    "undefined variable is an error",
+			tmpl:    `{{Var "missing"}}`,
+			wantErr: `failed to execute template: template: distgoTemplate:1:2: executing "distgoTemplate" at <Var "missing">: error calling Var: variable "missing" is not defined`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := distgo.PartiallyRenderTemplate(tc.tmpl, fns...)
+			if tc.wantErr != "" {
+				require.EqualError(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestOSArchTemplateFunction(t *testing.T) {
+	got, err := distgo.RenderTemplate(`{{OSArch}} {{(OSArch).OS}} {{(OSArch).Arch}}`, nil, distgo.OSArchTemplateFunction(osarch.OSArch{OS: "linux", Arch: "arm64"}))
+	require.NoError(t, err)
+	assert.Equal(t, "linux-arm64 linux arm64", got)
+}
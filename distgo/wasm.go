@@ -0,0 +1,51 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/palantir/godel/v2/pkg/osarch"
+	"github.com/pkg/errors"
+)
+
+// IsWebAssembly returns true if the provided OS/Arch is a WebAssembly target such as "js-wasm" or "wasip1-wasm". The
+// executables built for WebAssembly targets are modules with the ".wasm" extension.
+func IsWebAssembly(osArch osarch.OSArch) bool {
+	return osArch.Arch == "wasm"
+}
+
+// WasmExecJSPath returns the path to the "wasm_exec.js" file of the provided Go toolchain for the project in the
+// provided directory. The file is the JavaScript support code that is required to run modules built for "js-wasm".
+func WasmExecJSPath(projectDir string, goToolchain GoToolchainParam) (string, error) {
+	cmd := goToolchain.Command(projectDir, "env", "GOROOT")
+	cmd.Dir = projectDir
+	cmd.Env = append(cmd.Environ(), goToolchain.Environment()...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to determine GOROOT of the Go toolchain")
+	}
+	goRoot := strings.TrimSpace(string(output))
+	// the file is in "lib/wasm" as of Go 1.24 and in "misc/wasm" in earlier versions
+	for _, relPath := range []string{"lib/wasm/wasm_exec.js", "misc/wasm/wasm_exec.js"} {
+		candidate := filepath.Join(goRoot, relPath)
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", errors.Errorf("wasm_exec.js does not exist in GOROOT %s", goRoot)
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWasmExecJSPathUsesGoToolchain(t *testing.T) {
+	// the GOROOT reported by the stub toolchain depends on the GOTOOLCHAIN environment variable
+	goRoot := t.TempDir()
+	wasmExecJSPath := filepath.Join(goRoot, "go1.0.0", "lib", "wasm", "wasm_exec.js")
+	require.NoError(t, os.MkdirAll(filepath.Dir(wasmExecJSPath), 0755))
+	require.NoError(t, os.WriteFile(wasmExecJSPath, []byte("// wasm_exec.js"), 0644))
+	goBinary := filepath.Join(t.TempDir(), "go")
+	require.NoError(t, os.WriteFile(goBinary, []byte("#!/bin/sh\necho "+goRoot+"/$GOTOOLCHAIN\n"), 0755))
+
+	got, err := distgo.WasmExecJSPath(t.TempDir(), distgo.GoToolchainParam{
+		Binary:    goBinary,
+		Toolchain: "go1.0.0",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, wasmExecJSPath, got)
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package distgo
+
+import (
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// WindowsResourcesParam specifies the resources that are compiled into the executables built for Windows: the version
+// information, an icon and an application manifest. The string values are templates that can use the following
+// template functions:
+//   - {{Product}}: the name of the product
+//   - {{Version}}: the version of the project
+type WindowsResourcesParam struct {
+	// CompanyName is the value of the "CompanyName" string of the version information.
+	CompanyName string
+
+	// FileDescription is the value of the "FileDescription" string of the version information.
+	FileDescription string
+
+	// ProductName is the value of the "ProductName" string of the version information. If empty, the name of the
+	// product is used.
+	ProductName string
+
+	// Copyright is the value of the "LegalCopyright" string of the version information.
+	Copyright string
+
+	// Icon is the path to the ICO file that is used as the icon of the executable relative to the project directory. No
+	// icon is included if empty.
+	Icon string
+
+	// Manifest is the path to the application manifest of the executable relative to the project directory. No manifest
+	// is included if empty.
+	Manifest string
+}
+
+// VersionStrings returns the strings of the version information (other than the file and product version) rendered for
+// the provided product and version. The keys are the names of the strings in the version information.
+func (p *WindowsResourcesParam) VersionStrings(productName, version string) (map[string]string, error) {
+	productNameTmpl := p.ProductName
+	if productNameTmpl == "" {
+		productNameTmpl = "{{Product}}"
+	}
+	strs := make(map[string]string)
+	for k, tmpl := range map[string]string{
+		"CompanyName":     p.CompanyName,
+		"FileDescription": p.FileDescription,
+		"ProductName":     productNameTmpl,
+		"LegalCopyright":  p.Copyright,
+	} {
+		val, err := RenderTemplate(tmpl, nil,
+			ProductTemplateFunction(productName),
+			VersionTemplateFunction(version),
+		)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render %s", k)
+		}
+		strs[k] = val
+	}
+	return strs, nil
+}
+
+// IconPath returns the path of the icon resolved against the provided project directory. Returns an empty string if no
+// icon is specified.
+func (p *WindowsResourcesParam) IconPath(projectDir string) string {
+	return resolvePath(projectDir, p.Icon)
+}
+
+// ManifestPath returns the path of the application manifest resolved against the provided project directory. Returns
+// an empty string if no manifest is specified.
+func (p *WindowsResourcesParam) ManifestPath(projectDir string) string {
+	return resolvePath(projectDir, p.Manifest)
+}
+
+// FilePaths returns the paths of the icon and manifest files (the ones that are specified) resolved against the
+// provided project directory. Returns nil if the receiver is nil.
+func (p *WindowsResourcesParam) FilePaths(projectDir string) []string {
+	if p == nil {
+		return nil
+	}
+	var paths []string
+	for _, currPath := range []string{p.IconPath(projectDir), p.ManifestPath(projectDir)} {
+		if currPath != "" {
+			paths = append(paths, currPath)
+		}
+	}
+	return paths
+}
+
+func resolvePath(projectDir, filePath string) string {
+	if filePath == "" || filepath.IsAbs(filePath) {
+		return filePath
+	}
+	return filepath.Join(projectDir, filePath)
+}
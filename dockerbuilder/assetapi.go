@@ -36,7 +36,9 @@ func AssetRootCmd(creator Creator, upgradeConfigFn pluginapi.UpgradeConfigFn, sh
 	rootCmd.AddCommand(newVerifyConfigCmd(creatorFn))
 	rootCmd.AddCommand(assetapi.NewAssetTypeCmd(assetapi.DockerBuilder))
 	rootCmd.AddCommand(newRunDockerBuildCmd(creatorFn))
+	rootCmd.AddCommand(newSupportsStagedDockerfileCmd(creatorFn))
 	rootCmd.AddCommand(pluginapi.CobraUpgradeConfigCmd(upgradeConfigFn))
+	rootCmd.AddCommand(assetapi.NewConfigSchemaCmd(configSchemaFn(creator)))
 
 	return rootCmd
 }
@@ -126,6 +128,37 @@ func newRunDockerBuildCmd(creatorFn CreatorFunction) *cobra.Command {
 	return runDockerBuildCmd
 }
 
+const supportsStagedDockerfileCmdName = "supports-staged-dockerfile"
+
+func newSupportsStagedDockerfileCmd(creatorFn CreatorFunction) *cobra.Command {
+	var configYMLFlagVal string
+	supportsStagedDockerfileCmd := &cobra.Command{
+		Use:   supportsStagedDockerfileCmdName,
+		Short: "Prints the JSON representation of whether the DockerBuilder builds from a rendered Dockerfile staged outside of the context directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dockerBuilder, err := creatorFn([]byte(configYMLFlagVal))
+			if err != nil {
+				return err
+			}
+			supported := false
+			if stagedBuilder, ok := dockerBuilder.(distgo.StagedDockerfileDockerBuilder); ok {
+				if supported, err = stagedBuilder.SupportsStagedDockerfile(); err != nil {
+					return err
+				}
+			}
+			outputJSON, err := json.Marshal(supported)
+			if err != nil {
+				return errors.Wrapf(err, "failed to marshal output as JSON")
+			}
+			cmd.Print(string(outputJSON))
+			return nil
+		},
+	}
+	supportsStagedDockerfileCmd.Flags().StringVar(&configYMLFlagVal, commonCmdConfigYMLFlagName, "", "YML of DockerBuilder configuration")
+	mustMarkFlagsRequired(supportsStagedDockerfileCmd, commonCmdConfigYMLFlagName)
+	return supportsStagedDockerfileCmd
+}
+
 func mustMarkFlagsRequired(cmd *cobra.Command, flagNames ...string) {
 	for _, currFlagName := range flagNames {
 		if err := cmd.MarkFlagRequired(currFlagName); err != nil {
@@ -133,3 +166,13 @@ func mustMarkFlagsRequired(cmd *cobra.Command, flagNames ...string) {
 		}
 	}
 }
+
+// configSchemaFn returns the function that provides the configuration schema for the creator, or nil if the creator
+// does not provide one.
+func configSchemaFn(creator Creator) func() ([]byte, error) {
+	schemaCreator, ok := creator.(ConfigSchemaCreator)
+	if !ok {
+		return nil
+	}
+	return schemaCreator.ConfigSchema
+}
@@ -0,0 +1,162 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package defaultdockerbuilder
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/pkg/errors"
+)
+
+type CacheType string
+
+const (
+	// CacheTypeLocal is a cache stored in a directory on the local filesystem.
+	CacheTypeLocal CacheType = "local"
+	// CacheTypeRegistry is a cache stored as an image in a registry.
+	CacheTypeRegistry CacheType = "registry"
+	// CacheTypeInline is a cache embedded in the built image. It can only be exported.
+	CacheTypeInline CacheType = "inline"
+)
+
+// Cache is an external build cache that is imported from or exported to by "docker buildx build".
+type Cache struct {
+	Type CacheType
+	// Ref is the directory of a local cache or the image reference of a registry cache. It is rendered as a template
+	// that supports {{Product}}, {{DockerID}}, {{ProductDockerID}}, {{Version}} and {{Repository}}.
+	Ref string
+	// Mode is the export mode ("min" or "max") of an exported cache. Empty uses the buildx default.
+	Mode string
+}
+
+// ValidateCacheFrom returns an error if the provided cache cannot be imported from.
+func ValidateCacheFrom(cache Cache) error {
+	if err := validateCache(cache); err != nil {
+		return err
+	}
+	if cache.Type == CacheTypeInline {
+		return errors.Errorf("an inline cache cannot be imported from directly: specify a registry cache whose ref is the image that embeds it")
+	}
+	if cache.Mode != "" {
+		return errors.Errorf("mode can only be specified for exported caches")
+	}
+	return nil
+}
+
+// ValidateCacheTo returns an error if the provided cache cannot be exported to.
+func ValidateCacheTo(cache Cache) error {
+	if err := validateCache(cache); err != nil {
+		return err
+	}
+	switch cache.Mode {
+	case "", "min", "max":
+	default:
+		return errors.Errorf(`mode must be "min" or "max", was %q`, cache.Mode)
+	}
+	if cache.Type == CacheTypeInline && cache.Mode != "" {
+		return errors.Errorf("mode cannot be specified for an inline cache")
+	}
+	return nil
+}
+
+func validateCache(cache Cache) error {
+	switch cache.Type {
+	case CacheTypeLocal, CacheTypeRegistry:
+		if cache.Ref == "" {
+			return errors.Errorf("ref must be specified for a %s cache", cache.Type)
+		}
+	case CacheTypeInline:
+		if cache.Ref != "" {
+			return errors.Errorf("ref cannot be specified for an inline cache")
+		}
+	default:
+		return errors.Errorf(`cache type must be one of "local", "registry" or "inline", was %q`, cache.Type)
+	}
+	return nil
+}
+
+// cacheFromArgs returns the "--cache-from" arguments for the provided caches.
+func cacheFromArgs(caches []Cache, dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo) ([]string, error) {
+	var args []string
+	for _, cache := range caches {
+		ref, err := renderCacheRef(cache, dockerID, productTaskOutputInfo)
+		if err != nil {
+			return nil, err
+		}
+		var val string
+		switch cache.Type {
+		case CacheTypeLocal:
+			val = fmt.Sprintf("type=local,src=%s", ref)
+		default:
+			val = fmt.Sprintf("type=%s,ref=%s", cache.Type, ref)
+		}
+		args = append(args, "--cache-from", val)
+	}
+	return args, nil
+}
+
+// cacheToArgs returns the "--cache-to" arguments for the provided caches.
+func cacheToArgs(caches []Cache, dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo) ([]string, error) {
+	var args []string
+	for _, cache := range caches {
+		ref, err := renderCacheRef(cache, dockerID, productTaskOutputInfo)
+		if err != nil {
+			return nil, err
+		}
+		var val string
+		switch cache.Type {
+		case CacheTypeInline:
+			val = "type=inline"
+		case CacheTypeLocal:
+			val = fmt.Sprintf("type=local,dest=%s", ref)
+		default:
+			val = fmt.Sprintf("type=%s,ref=%s", cache.Type, ref)
+		}
+		if cache.Mode != "" {
+			val += ",mode=" + cache.Mode
+		}
+		args = append(args, "--cache-to", val)
+	}
+	return args, nil
+}
+
+// renderCacheRef renders the ref of the provided cache for the given Docker configuration. The directory of a local
+// cache is resolved against the project directory.
+func renderCacheRef(cache Cache, dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo) (string, error) {
+	if cache.Ref == "" {
+		return "", nil
+	}
+	var repository string
+	if productTaskOutputInfo.Product.DockerOutputInfos != nil {
+		repository = productTaskOutputInfo.Product.DockerOutputInfos.Repository
+	}
+	productID := productTaskOutputInfo.Product.ID
+	ref, err := distgo.RenderTemplate(cache.Ref, nil,
+		distgo.ProductTemplateFunction(productTaskOutputInfo.Product.Name),
+		distgo.VersionTemplateFunction(productTaskOutputInfo.Project.Version),
+		distgo.RepositoryTemplateFunction(repository),
+		distgo.TemplateValueFunction("DockerID", string(dockerID)),
+		distgo.TemplateValueFunction("ProductDockerID", string(distgo.NewProductDockerID(productID, dockerID, ""))),
+	)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to render ref of %s cache", cache.Type)
+	}
+	if cache.Type == CacheTypeLocal && !filepath.IsAbs(ref) {
+		ref = filepath.Join(productTaskOutputInfo.Project.ProjectDir, ref)
+	}
+	return ref, nil
+}
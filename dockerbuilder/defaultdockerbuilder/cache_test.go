@@ -0,0 +1,112 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package defaultdockerbuilder
+
+import (
+	"testing"
+
+	"github.com/palantir/distgo/distgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheArgs(t *testing.T) {
+	info := distgo.ProductTaskOutputInfo{
+		Project: distgo.ProjectInfo{ProjectDir: "/project", Version: "1.0.0"},
+		Product: distgo.ProductOutputInfo{
+			ID:   "foo",
+			Name: "foo",
+			DockerOutputInfos: &distgo.DockerOutputInfos{
+				Repository: "registry.example.com",
+			},
+		},
+	}
+
+	fromArgs, err := cacheFromArgs([]Cache{
+		{Type: CacheTypeLocal, Ref: "out/docker-cache/{{ProductDockerID}}"},
+		{Type: CacheTypeRegistry, Ref: "{{Repository}}{{Product}}:cache-{{DockerID}}"},
+	}, "prod", info)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"--cache-from", "type=local,src=/project/out/docker-cache/foo.prod",
+		"--cache-from", "type=registry,ref=registry.example.com/foo:cache-prod",
+	}, fromArgs)
+
+	toArgs, err := cacheToArgs([]Cache{
+		{Type: CacheTypeLocal, Ref: "/tmp/cache/{{Product}}-{{Version}}", Mode: "max"},
+		{Type: CacheTypeRegistry, Ref: "{{Repository}}{{Product}}:cache-{{DockerID}}"},
+		{Type: CacheTypeInline},
+	}, "prod", info)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"--cache-to", "type=local,dest=/tmp/cache/foo-1.0.0,mode=max",
+		"--cache-to", "type=registry,ref=registry.example.com/foo:cache-prod",
+		"--cache-to", "type=inline",
+	}, toArgs)
+}
+
+func TestValidateCache(t *testing.T) {
+	for i, tc := range []struct {
+		name     string
+		cache    Cache
+		validate func(Cache) error
+		wantErr  string
+	}{
+		{
+			name:     "valid cache-to",
+			cache:    Cache{Type: CacheTypeRegistry, Ref: "foo:cache", Mode: "max"},
+			validate: ValidateCacheTo,
+		},
+		{
+			name:     "unknown type",
+			cache:    Cache{Type: "s3", Ref: "bucket"},
+			validate: ValidateCacheFrom,
+			wantErr:  `cache type must be one of "local", "registry" or "inline", was "s3"`,
+		},
+		{
+			name:     "missing ref",
+			cache:    Cache{Type: CacheTypeLocal},
+			validate: ValidateCacheTo,
+			wantErr:  "ref must be specified for a local cache",
+		},
+		{
+			name:     "inline cache-from",
+			cache:    Cache{Type: CacheTypeInline},
+			validate: ValidateCacheFrom,
+			wantErr:  "an inline cache cannot be imported from directly: specify a registry cache whose ref is the image that embeds it",
+		},
+		{
+			name:     "mode on cache-from",
+			cache:    Cache{Type: CacheTypeLocal, Ref: "cache", Mode: "max"},
+			validate: ValidateCacheFrom,
+			wantErr:  "mode can only be specified for exported caches",
+		},
+		{
+			name:     "invalid mode",
+			cache:    Cache{Type: CacheTypeLocal, Ref: "cache", Mode: "all"},
+			validate: ValidateCacheTo,
+			wantErr:  `mode must be "min" or "max", was "all"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.validate(tc.cache)
+			if tc.wantErr == "" {
+				require.NoError(t, err, "Case %d: %s", i, tc.name)
+				return
+			}
+			require.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+		})
+	}
+}
@@ -18,14 +18,66 @@ import (
 	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/distgo/dockerbuilder/defaultdockerbuilder"
 	v0 "github.com/palantir/distgo/dockerbuilder/defaultdockerbuilder/config/internal/v0"
+	"github.com/pkg/errors"
 )
 
 type Default v0.Config
 
+// Validate returns an error if the cache or builder configuration is invalid. It should be called before
+// ToDockerBuilder, which does not validate the configuration.
+func (cfg *Default) Validate() error {
+	if _, err := toCaches(cfg.CacheFrom, defaultdockerbuilder.ValidateCacheFrom); err != nil {
+		return errors.Wrapf(err, "invalid cache-from")
+	}
+	if _, err := toCaches(cfg.CacheTo, defaultdockerbuilder.ValidateCacheTo); err != nil {
+		return errors.Wrapf(err, "invalid cache-to")
+	}
+	if cfg.Builder != nil && *cfg.Builder == "" {
+		return errors.Errorf("builder must be non-empty if specified")
+	}
+	return nil
+}
+
 func (cfg *Default) ToDockerBuilder() distgo.DockerBuilder {
 	var buildArgsScript string
 	if cfg.BuildArgsScript != nil {
 		buildArgsScript = *cfg.BuildArgsScript
 	}
-	return defaultdockerbuilder.NewDefaultDockerBuilder(cfg.BuildArgs, buildArgsScript)
+	var options []defaultdockerbuilder.Option
+	if len(cfg.CacheFrom) > 0 {
+		cacheFrom, _ := toCaches(cfg.CacheFrom, nil)
+		options = append(options, defaultdockerbuilder.WithCacheFrom(cacheFrom))
+	}
+	if len(cfg.CacheTo) > 0 {
+		cacheTo, _ := toCaches(cfg.CacheTo, nil)
+		options = append(options, defaultdockerbuilder.WithCacheTo(cacheTo))
+	}
+	if cfg.Builder != nil {
+		options = append(options, defaultdockerbuilder.WithBuilder(*cfg.Builder))
+	}
+	return defaultdockerbuilder.NewDefaultDockerBuilder(cfg.BuildArgs, buildArgsScript, options...)
+}
+
+// toCaches converts the provided cache configurations. If validate is non-nil, it is called for each cache and the
+// first error it returns is returned.
+func toCaches(cfgs []v0.CacheConfig, validate func(defaultdockerbuilder.Cache) error) ([]defaultdockerbuilder.Cache, error) {
+	var caches []defaultdockerbuilder.Cache
+	for i, cacheCfg := range cfgs {
+		cache := defaultdockerbuilder.Cache{
+			Type: defaultdockerbuilder.CacheType(cacheCfg.Type),
+		}
+		if cacheCfg.Ref != nil {
+			cache.Ref = *cacheCfg.Ref
+		}
+		if cacheCfg.Mode != nil {
+			cache.Mode = *cacheCfg.Mode
+		}
+		if validate != nil {
+			if err := validate(cache); err != nil {
+				return nil, errors.Wrapf(err, "invalid cache at index %d", i)
+			}
+		}
+		caches = append(caches, cache)
+	}
+	return caches, nil
 }
@@ -28,6 +28,40 @@ type Config struct {
 	// variables of the Go process. Each line of output of the script is provided to the "docker build" command as a
 	// separate argument. The arguments produced by the script are appended to any arguments specified in BuildArgs.
 	BuildArgsScript *string `yaml:"build-args-script,omitempty"`
+
+	// CacheFrom specifies the external caches that the build imports layers from. Each cache is passed to
+	// "docker buildx build" as a "--cache-from" argument.
+	CacheFrom []CacheConfig `yaml:"cache-from,omitempty"`
+
+	// CacheTo specifies the external caches that the build exports its layers to. Each cache is passed to
+	// "docker buildx build" as a "--cache-to" argument. The cache is only exported by the first "docker buildx build"
+	// invocation of a build, which is the one that produces the OCI layout if that output is enabled.
+	CacheTo []CacheConfig `yaml:"cache-to,omitempty"`
+
+	// Builder is the name of the buildx builder that is used for the build. If the builder does not exist, it is
+	// created using the docker-container driver; otherwise, the existing builder (and the layer cache it holds) is
+	// reused, so repeated builds are incremental. If unspecified, the current buildx builder is used if it uses the
+	// docker-container driver, and a new builder is created otherwise.
+	Builder *string `yaml:"builder,omitempty"`
+}
+
+type CacheConfig struct {
+	// Type is the type of the cache. Must be one of "local" (a directory on the local filesystem), "registry" (an image
+	// reference in a registry) or "inline" (the cache is embedded in the built image). "inline" is only valid in
+	// CacheTo: to import an inline cache, specify a "registry" cache whose ref is the image that embeds it.
+	Type string `yaml:"type"`
+
+	// Ref is the directory of a "local" cache or the image reference of a "registry" cache. Relative directories are
+	// resolved against the project directory. The value is rendered as a template that supports {{Product}},
+	// {{DockerID}}, {{ProductDockerID}}, {{Version}} and {{Repository}}, so that a single configuration can key the
+	// cache per Docker configuration (for example, "out/docker-cache/{{ProductDockerID}}"). Must be empty for an
+	// "inline" cache.
+	Ref *string `yaml:"ref,omitempty"`
+
+	// Mode is the cache export mode for a CacheTo entry: "min" exports only the layers of the final image and "max"
+	// exports the layers of all intermediate build stages. If unspecified, buildx uses "min". Must be empty for
+	// CacheFrom entries and "inline" caches.
+	Mode *string `yaml:"mode,omitempty"`
 }
 
 func UpgradeConfig(cfgBytes []byte) ([]byte, error) {
@@ -56,16 +56,27 @@ type DefaultDockerBuilder struct {
 	BuildxDriverOpts  []string
 	BuildxPlatformArg string
 	OutputType        OutputType
+	// CacheFrom are the external caches that the build imports layers from.
+	CacheFrom []Cache
+	// CacheTo are the external caches that the build exports its layers to.
+	CacheTo []Cache
+	// BuilderName is the name of the buildx builder used for the build. If empty, the current builder is used if it
+	// uses the docker-container driver.
+	BuilderName string
 }
 
-func NewDefaultDockerBuilder(buildArgs []string, buildArgsScript string) distgo.DockerBuilder {
-	return &DefaultDockerBuilder{
+func NewDefaultDockerBuilder(buildArgs []string, buildArgsScript string, options ...Option) distgo.DockerBuilder {
+	builder := &DefaultDockerBuilder{
 		BuildArgs:       buildArgs,
 		BuildArgsScript: buildArgsScript,
 		// Produce an OCI layout (the reproducible published artifact, and the on-disk base a dependent product's FROM
 		// resolves against) and load the host-arch image into the local daemon for `docker run`.
 		OutputType: OCILayout | DockerDaemon,
 	}
+	for _, opt := range options {
+		opt(builder)
+	}
+	return builder
 }
 
 func NewDefaultDockerBuilderWithOptions(options ...Option) distgo.DockerBuilder {
@@ -80,6 +91,10 @@ func (d *DefaultDockerBuilder) TypeName() (string, error) {
 	return TypeName, nil
 }
 
+func (d *DefaultDockerBuilder) SupportsStagedDockerfile() (bool, error) {
+	return true, nil
+}
+
 func (d *DefaultDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo, verbose, dryRun bool, stdout io.Writer) error {
 	if d.OutputType&allOutputs == 0 {
 		return errors.New("a valid output type of docker builder must be specified")
@@ -93,7 +108,7 @@ func (d *DefaultDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, productT
 	baseArgs := []string{
 		"buildx",
 		"build",
-		"--file", filepath.Join(contextDirPath, dockerBuilderOutputInfo.DockerfilePath),
+		"--file", productTaskOutputInfo.ProductDockerfilePath(dockerID),
 		"--build-arg", "SOURCE_DATE_EPOCH=0",
 	}
 	for _, tag := range dockerBuilderOutputInfo.RenderedTags {
@@ -109,8 +124,24 @@ func (d *DefaultDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, productT
 	}
 	// Resolve a "FROM <dependency image tag>" from the dependency's on-disk OCI layout instead of a registry.
 	baseArgs = append(baseArgs, dependencyImageBuildContextArgs(productTaskOutputInfo, dryRun)...)
+	cacheFrom, err := cacheFromArgs(d.CacheFrom, dockerID, productTaskOutputInfo)
+	if err != nil {
+		return err
+	}
+	baseArgs = append(baseArgs, cacheFrom...)
+	// the cache is exported by the first invocation only: the invocations build the same image, so exporting it again
+	// would only repeat the upload
+	cacheTo, err := cacheToArgs(d.CacheTo, dockerID, productTaskOutputInfo)
+	if err != nil {
+		return err
+	}
 
-	if err := d.ensureDockerContainerDriver(dockerID, verbose, dryRun, stdout); err != nil {
+	if d.BuilderName != "" {
+		if err := d.ensureNamedBuilder(verbose, dryRun, stdout); err != nil {
+			return err
+		}
+		baseArgs = append(baseArgs, "--builder", d.BuilderName)
+	} else if err := d.ensureDockerContainerDriver(dockerID, verbose, dryRun, stdout); err != nil {
 		return err
 	}
 
@@ -130,6 +161,8 @@ func (d *DefaultDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, productT
 		if d.BuildxPlatformArg != "" {
 			ociArgs = append(ociArgs, d.BuildxPlatformArg)
 		}
+		ociArgs = append(ociArgs, cacheTo...)
+		cacheTo = nil
 		ociArgs = append(ociArgs, fmt.Sprintf("--output=type=oci,rewrite-timestamp=true,dest=%s", destFile), contextDirPath)
 		if err := distgo.RunCommandWithVerboseOption(exec.Command("docker", ociArgs...), verbose, dryRun, stdout); err != nil {
 			return err
@@ -141,7 +174,8 @@ func (d *DefaultDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, productT
 		}
 	}
 	if d.OutputType&DockerDaemon != 0 {
-		daemonArgs := append(slices.Clone(baseArgs), "--output=type=docker,rewrite-timestamp=true", contextDirPath)
+		daemonArgs := append(slices.Clone(baseArgs), cacheTo...)
+		daemonArgs = append(daemonArgs, "--output=type=docker,rewrite-timestamp=true", contextDirPath)
 		if err := distgo.RunCommandWithVerboseOption(exec.Command("docker", daemonArgs...), verbose, dryRun, stdout); err != nil {
 			return err
 		}
@@ -257,6 +291,29 @@ func (d *DefaultDockerBuilder) ensureDockerContainerDriver(dockerID distgo.Docke
 	return nil
 }
 
+// ensureNamedBuilder ensures that the buildx builder named by BuilderName exists. An existing builder is reused as-is
+// so that the layer cache it holds carries over between builds; otherwise, a builder that uses the docker-container
+// driver is created. The builder is only created if buildx reports that it does not exist: any other error inspecting
+// it is returned. The builder is selected per build using "--builder" rather than made the current builder, so other
+// buildx invocations on the host are unaffected. If dryRun is true, no command is run.
+func (d *DefaultDockerBuilder) ensureNamedBuilder(verbose, dryRun bool, stdout io.Writer) error {
+	if dryRun {
+		distgo.DryRunPrintln(stdout, fmt.Sprintf("Create buildx builder %s if it does not exist", d.BuilderName))
+		return nil
+	}
+	inspectCmd := exec.Command("docker", "buildx", "inspect", d.BuilderName)
+	if output, err := inspectCmd.CombinedOutput(); err == nil {
+		return nil
+	} else if !strings.Contains(string(output), fmt.Sprintf("no builder %q found", d.BuilderName)) {
+		return errors.Wrapf(err, "failed to inspect buildx builder %s, output: %s", d.BuilderName, string(output))
+	}
+	args := []string{"buildx", "create", "--name", d.BuilderName, "--bootstrap", "--driver", "docker-container"}
+	for _, opt := range d.BuildxDriverOpts {
+		args = append(args, "--driver-opt", opt)
+	}
+	return distgo.RunCommandWithVerboseOption(exec.Command("docker", args...), verbose, dryRun, stdout)
+}
+
 func WithBuildArgs(buildArgs []string) Option {
 	return func(d *DefaultDockerBuilder) {
 		d.BuildArgs = buildArgs
@@ -291,3 +348,24 @@ func WithBuildxPlatforms(buildxPlatforms []string) Option {
 		}
 	}
 }
+
+// WithCacheFrom configures the external caches that the build imports layers from.
+func WithCacheFrom(caches []Cache) Option {
+	return func(d *DefaultDockerBuilder) {
+		d.CacheFrom = caches
+	}
+}
+
+// WithCacheTo configures the external caches that the build exports its layers to.
+func WithCacheTo(caches []Cache) Option {
+	return func(d *DefaultDockerBuilder) {
+		d.CacheTo = caches
+	}
+}
+
+// WithBuilder configures the build to use the buildx builder with the provided name, creating it if it does not exist.
+func WithBuilder(builderName string) Option {
+	return func(d *DefaultDockerBuilder) {
+		d.BuilderName = builderName
+	}
+}
@@ -15,6 +15,7 @@
 package defaultdockerbuilder
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -135,3 +136,65 @@ func TestOCIOutputDir(t *testing.T) {
 	_, err = ociOutputDir(info("", "", nil), "builder")
 	require.EqualError(t, err, "no output directory is available for OCI output for configuration builder: the product declares neither a Docker nor a dist output directory")
 }
+
+// TestEnsureNamedBuilderDryRun verifies that ensuring a named builder in dry run mode does not run any docker command,
+// including the "docker buildx inspect" command used to determine whether the builder exists.
+func TestEnsureNamedBuilderDryRun(t *testing.T) {
+	binDir := t.TempDir()
+	markerPath := filepath.Join(t.TempDir(), "docker-invoked")
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "docker"), []byte("#!/bin/sh\ntouch "+markerPath+"\n"), 0755))
+	t.Setenv("PATH", binDir)
+
+	builder := &DefaultDockerBuilder{BuilderName: "distgo-test"}
+	buf := &bytes.Buffer{}
+	require.NoError(t, builder.ensureNamedBuilder(false, true, buf))
+	require.NoFileExists(t, markerPath)
+	require.Contains(t, buf.String(), "Create buildx builder distgo-test if it does not exist")
+}
+
+// TestEnsureNamedBuilder verifies that the named builder is only created if "docker buildx inspect" reports that it
+// does not exist.
+func TestEnsureNamedBuilder(t *testing.T) {
+	for i, tc := range []struct {
+		name          string
+		inspectScript string
+		wantCommands  string
+		wantErr       string
+	}{
+		{
+			name:          "existing builder is reused",
+			inspectScript: "exit 0",
+			wantCommands:  "buildx inspect distgo-test\n",
+		},
+		{
+			name:          "missing builder is created",
+			inspectScript: `echo 'ERROR: no builder "distgo-test" found' >&2; exit 1`,
+			wantCommands:  "buildx inspect distgo-test\nbuildx create --name distgo-test --bootstrap --driver docker-container\n",
+		},
+		{
+			name:          "other inspect errors are returned",
+			inspectScript: "echo 'Cannot connect to the Docker daemon' >&2; exit 1",
+			wantCommands:  "buildx inspect distgo-test\n",
+			wantErr:       "failed to inspect buildx builder distgo-test, output: Cannot connect to the Docker daemon\n: exit status 1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			binDir := t.TempDir()
+			logPath := filepath.Join(t.TempDir(), "docker-commands")
+			script := "#!/bin/sh\necho \"$@\" >> " + logPath + "\nif [ \"$2\" = inspect ]; then " + tc.inspectScript + "; fi\n"
+			require.NoError(t, os.WriteFile(filepath.Join(binDir, "docker"), []byte(script), 0755))
+			t.Setenv("PATH", binDir)
+
+			builder := &DefaultDockerBuilder{BuilderName: "distgo-test"}
+			err := builder.ensureNamedBuilder(false, false, &bytes.Buffer{})
+			if tc.wantErr == "" {
+				require.NoError(t, err, "Case %d: %s", i, tc.name)
+			} else {
+				require.EqualError(t, err, tc.wantErr, "Case %d: %s", i, tc.name)
+			}
+			commands, err := os.ReadFile(logPath)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantCommands, string(commands), "Case %d: %s", i, tc.name)
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/internal/assetapi"
 	"github.com/pkg/errors"
 )
 
@@ -55,6 +56,26 @@ func (d *assetDockerBuilder) VerifyConfig() error {
 	return nil
 }
 
+// SupportsStagedDockerfile returns false for an asset that predates the supports-staged-dockerfile command, since such
+// an asset may have been built against a version of distgo whose DockerBuilderOutputInfo has no RenderedDockerfilePath.
+func (d *assetDockerBuilder) SupportsStagedDockerfile() (bool, error) {
+	if !assetapi.SupportsCommand(d.assetPath, supportsStagedDockerfileCmdName) {
+		return false, nil
+	}
+	supportsCmd := exec.Command(d.assetPath, supportsStagedDockerfileCmdName,
+		"--"+commonCmdConfigYMLFlagName, d.cfgYML,
+	)
+	outputBytes, err := runCommand(supportsCmd)
+	if err != nil {
+		return false, err
+	}
+	var supported bool
+	if err := json.Unmarshal(outputBytes, &supported); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal JSON")
+	}
+	return supported, nil
+}
+
 func (d *assetDockerBuilder) RunDockerBuild(dockerID distgo.DockerID, productTaskOutputInfo distgo.ProductTaskOutputInfo, verbose, dryRun bool, stdout io.Writer) error {
 	productTaskOutputInfoJSON, err := json.Marshal(productTaskOutputInfo)
 	if err != nil {
@@ -17,6 +17,7 @@ package dockerbuilderfactory
 import (
 	"github.com/palantir/distgo/dister/osarchbin"
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config/configschema"
 	"github.com/palantir/distgo/dockerbuilder"
 	"github.com/palantir/distgo/dockerbuilder/defaultdockerbuilder"
 	defaultdockerbuilderconfig "github.com/palantir/distgo/dockerbuilder/defaultdockerbuilder/config"
@@ -25,8 +26,9 @@ import (
 )
 
 type creatorWithUpgrader struct {
-	creator  dockerbuilder.CreatorFunction
-	upgrader distgo.ConfigUpgrader
+	creator      dockerbuilder.CreatorFunction
+	upgrader     distgo.ConfigUpgrader
+	configSchema func() ([]byte, error)
 }
 
 func builtinDockerBuilders() map[string]creatorWithUpgrader {
@@ -37,9 +39,15 @@ func builtinDockerBuilders() map[string]creatorWithUpgrader {
 				if err := yaml.UnmarshalStrict(cfgYML, &cfg); err != nil {
 					return nil, errors.Wrapf(err, "failed to unmarshal YAML")
 				}
+				if err := cfg.Validate(); err != nil {
+					return nil, err
+				}
 				return cfg.ToDockerBuilder(), nil
 			},
 			upgrader: distgo.NewConfigUpgrader(osarchbin.TypeName, defaultdockerbuilderconfig.UpgradeConfig),
+			configSchema: func() ([]byte, error) {
+				return configschema.JSON(defaultdockerbuilderconfig.Default{})
+			},
 		},
 	}
 }
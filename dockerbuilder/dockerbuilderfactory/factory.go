@@ -25,11 +25,13 @@ func New(providedDockerBuilderCreators []dockerbuilder.Creator, providedConfigUp
 	seenTypes := make(map[string]struct{})
 	dockerBuilderCreators := make(map[string]dockerbuilder.CreatorFunction)
 	configUpgraders := make(map[string]distgo.ConfigUpgrader)
+	configSchemas := make(map[string]func() ([]byte, error))
 	for k, v := range builtinDockerBuilders() {
 		types = append(types, k)
 		seenTypes[k] = struct{}{}
 		dockerBuilderCreators[k] = v.creator
 		configUpgraders[k] = v.upgrader
+		configSchemas[k] = v.configSchema
 	}
 	for _, currCreator := range providedDockerBuilderCreators {
 		if _, ok := seenTypes[currCreator.TypeName()]; ok {
@@ -38,6 +40,9 @@ func New(providedDockerBuilderCreators []dockerbuilder.Creator, providedConfigUp
 		seenTypes[currCreator.TypeName()] = struct{}{}
 		types = append(types, currCreator.TypeName())
 		dockerBuilderCreators[currCreator.TypeName()] = currCreator.Creator()
+		if schemaCreator, ok := currCreator.(dockerbuilder.ConfigSchemaCreator); ok {
+			configSchemas[currCreator.TypeName()] = schemaCreator.ConfigSchema
+		}
 	}
 	for _, currUpgrader := range providedConfigUpgraders {
 		configUpgraders[currUpgrader.TypeName()] = currUpgrader
@@ -46,6 +51,7 @@ func New(providedDockerBuilderCreators []dockerbuilder.Creator, providedConfigUp
 		types:                        types,
 		dockerBuilderCreators:        dockerBuilderCreators,
 		dockerBuilderConfigUpgraders: configUpgraders,
+		dockerBuilderConfigSchemas:   configSchemas,
 	}, nil
 }
 
@@ -53,8 +59,11 @@ type dockerBuilderFactory struct {
 	types                        []string
 	dockerBuilderCreators        map[string]dockerbuilder.CreatorFunction
 	dockerBuilderConfigUpgraders map[string]distgo.ConfigUpgrader
+	dockerBuilderConfigSchemas   map[string]func() ([]byte, error)
 }
 
+var _ distgo.ConfigSchemaProvider = (*dockerBuilderFactory)(nil)
+
 func (f *dockerBuilderFactory) Types() []string {
 	return f.types
 }
@@ -77,3 +86,14 @@ func (f *dockerBuilderFactory) ConfigUpgrader(typeName string) (distgo.ConfigUpg
 	}
 	return upgrader, nil
 }
+
+func (f *dockerBuilderFactory) ConfigSchema(typeName string) ([]byte, error) {
+	if _, ok := f.dockerBuilderCreators[typeName]; !ok {
+		return nil, errors.Errorf("no docker builder registered for docker builder type %q (registered docker builders: %v)", typeName, f.types)
+	}
+	configSchemaFn, ok := f.dockerBuilderConfigSchemas[typeName]
+	if !ok {
+		return nil, nil
+	}
+	return configSchemaFn()
+}
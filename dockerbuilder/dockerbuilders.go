@@ -18,6 +18,7 @@ import (
 	"sort"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/internal/assetapi"
 	"github.com/pkg/errors"
 )
 
@@ -48,6 +49,33 @@ func NewCreator(typeName string, creatorFn CreatorFunction) Creator {
 	}
 }
 
+// ConfigSchemaCreator is a Creator that provides the JSON Schema for the configuration of the DockerBuilder that it
+// creates.
+type ConfigSchemaCreator interface {
+	Creator
+	ConfigSchema() ([]byte, error)
+}
+
+type configSchemaCreatorStruct struct {
+	creatorStruct
+	configSchema func() ([]byte, error)
+}
+
+func (c *configSchemaCreatorStruct) ConfigSchema() ([]byte, error) {
+	return c.configSchema()
+}
+
+// NewCreatorWithConfigSchema returns a ConfigSchemaCreator whose configuration schema is provided by configSchemaFn.
+func NewCreatorWithConfigSchema(typeName string, creatorFn CreatorFunction, configSchemaFn func() ([]byte, error)) Creator {
+	return &configSchemaCreatorStruct{
+		creatorStruct: creatorStruct{
+			typeName: typeName,
+			creator:  creatorFn,
+		},
+		configSchema: configSchemaFn,
+	}
+}
+
 func AssetDockerBuilderCreators(assetPaths ...string) ([]Creator, []distgo.ConfigUpgrader, error) {
 	var dockerBuilderCreators []Creator
 	var configUpgraders []distgo.ConfigUpgrader
@@ -61,7 +89,7 @@ func AssetDockerBuilderCreators(assetPaths ...string) ([]Creator, []distgo.Confi
 			return nil, nil, errors.Wrapf(err, "failed to determine DockerBuilder type name for asset %s", currAssetPath)
 		}
 		dockerBuilderNameToAssets[dockerBuilderName] = append(dockerBuilderNameToAssets[dockerBuilderName], currAssetPath)
-		dockerBuilderCreators = append(dockerBuilderCreators, NewCreator(dockerBuilderName,
+		dockerBuilderCreators = append(dockerBuilderCreators, NewCreatorWithConfigSchema(dockerBuilderName,
 			func(cfgYML []byte) (distgo.DockerBuilder, error) {
 				newDockerBuilder := assetDockerBuilder{
 					assetPath: currAssetPath,
@@ -71,6 +99,9 @@ func AssetDockerBuilderCreators(assetPaths ...string) ([]Creator, []distgo.Confi
 					return nil, err
 				}
 				return &newDockerBuilder, nil
+			},
+			func() ([]byte, error) {
+				return assetapi.GetConfigSchema(currAssetPath)
 			}))
 		configUpgraders = append(configUpgraders, &assetConfigUpgrader{
 			typeName:  dockerBuilderName,
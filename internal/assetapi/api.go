@@ -122,3 +122,9 @@ func getAssetType(assetPath string) (AssetType, error) {
 		return "", errors.Errorf("unrecognized asset type: %s", assetType)
 	}
 }
+
+// SupportsCommand reports whether the asset at assetPath registers the command with the provided name. Requesting the
+// help of a command that an asset does not register exits with a non-zero exit code.
+func SupportsCommand(assetPath, commandName string) bool {
+	return exec.Command(assetPath, commandName, "--help").Run() == nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Palantir Technologies, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package assetapi
+
+import (
+	"bytes"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const ConfigSchemaCommand = "config-schema"
+
+// NewConfigSchemaCmd returns the command that prints the JSON Schema for the configuration of an asset. Prints "null"
+// if configSchemaFn is nil.
+func NewConfigSchemaCmd(configSchemaFn func() ([]byte, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:   ConfigSchemaCommand,
+		Short: "Prints the JSON Schema for the configuration of the asset",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if configSchemaFn == nil {
+				cmd.Print("null")
+				return nil
+			}
+			schema, err := configSchemaFn()
+			if err != nil {
+				return err
+			}
+			if schema == nil {
+				schema = []byte("null")
+			}
+			cmd.Print(string(schema))
+			return nil
+		},
+	}
+}
+
+// GetConfigSchema returns the JSON Schema for the configuration of the asset at the provided path. Returns nil if the
+// asset does not provide a schema, which includes assets that predate the config-schema command.
+func GetConfigSchema(assetPath string) ([]byte, error) {
+	if !SupportsCommand(assetPath, ConfigSchemaCommand) {
+		return nil, nil
+	}
+	cmd := exec.Command(assetPath, ConfigSchemaCommand)
+	outputBytes, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to run command %v, output: %s", cmd.Args, string(outputBytes))
+	}
+	if trimmed := bytes.TrimSpace(outputBytes); len(trimmed) == 0 || string(trimmed) == "null" {
+		return nil, nil
+	}
+	return outputBytes, nil
+}
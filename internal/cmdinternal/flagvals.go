@@ -16,10 +16,12 @@ package cmdinternal
 
 import (
 	"os"
+	"strings"
 
 	"github.com/palantir/distgo/distgo"
 	"github.com/palantir/distgo/distgo/config"
 	godelconfig "github.com/palantir/godel/v2/framework/godel/config"
+	"github.com/palantir/godel/v2/pkg/versionedconfig"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 )
@@ -31,6 +33,7 @@ type GlobalFlagValsAndFactories struct {
 	DistgoConfigFileFlagVal string
 	GodelConfigFileFlagVal  string
 	AssetsFlagVal           []string
+	ProfilesFlagVal         []string
 
 	CLIProjectVersionerFactory distgo.ProjectVersionerFactory
 	CLIDisterFactory           distgo.DisterFactory
@@ -39,16 +42,50 @@ type GlobalFlagValsAndFactories struct {
 	CLIPublisherFactory        distgo.PublisherFactory
 }
 
+// Profiles returns the configuration profiles to apply: the profiles specified by flag or, if none are specified, the
+// comma-separated profiles specified by the config.ProfileEnvVar environment variable.
+func (f GlobalFlagValsAndFactories) Profiles() []string {
+	if len(f.ProfilesFlagVal) > 0 {
+		return f.ProfilesFlagVal
+	}
+	var profiles []string
+	for profile := range strings.SplitSeq(os.Getenv(config.ProfileEnvVar), ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
 func DistgoProjectParamFromFlagVals(flagValsAndFactories GlobalFlagValsAndFactories) (distgo.ProjectInfo, distgo.ProjectParam, error) {
 	return distgoProjectParamFromVals(
 		flagValsAndFactories.ProjectDirFlagVal,
 		flagValsAndFactories.DistgoConfigFileFlagVal,
 		flagValsAndFactories.GodelConfigFileFlagVal,
+		flagValsAndFactories.Profiles(),
+		flagValsAndFactories.CLIProjectVersionerFactory,
+		flagValsAndFactories.CLIDisterFactory,
+		flagValsAndFactories.CLIDefaultDisterCfg,
+		flagValsAndFactories.CLIDockerBuilderFactory,
+		flagValsAndFactories.CLIPublisherFactory,
+		true,
+	)
+}
+
+// DistgoGraphProjectParamFromFlagVals returns the project parameter without resolving the dependencies of its products
+// so that the product graph can be inspected even if it is invalid. See config.ProjectConfig.ToGraphParam.
+func DistgoGraphProjectParamFromFlagVals(flagValsAndFactories GlobalFlagValsAndFactories) (distgo.ProjectInfo, distgo.ProjectParam, error) {
+	return distgoProjectParamFromVals(
+		flagValsAndFactories.ProjectDirFlagVal,
+		flagValsAndFactories.DistgoConfigFileFlagVal,
+		flagValsAndFactories.GodelConfigFileFlagVal,
+		flagValsAndFactories.Profiles(),
 		flagValsAndFactories.CLIProjectVersionerFactory,
 		flagValsAndFactories.CLIDisterFactory,
 		flagValsAndFactories.CLIDefaultDisterCfg,
 		flagValsAndFactories.CLIDockerBuilderFactory,
 		flagValsAndFactories.CLIPublisherFactory,
+		false,
 	)
 }
 
@@ -56,59 +93,119 @@ func distgoProjectParamFromVals(
 	projectDir,
 	distgoConfigFile,
 	godelConfigFile string,
+	profiles []string,
 	projectVersionerFactory distgo.ProjectVersionerFactory,
 	disterFactory distgo.DisterFactory,
 	defaultDisterCfg config.DisterConfig,
 	dockerBuilderFactory distgo.DockerBuilderFactory,
 	publisherFactory distgo.PublisherFactory,
+	resolveDependencies bool,
 ) (distgo.ProjectInfo, distgo.ProjectParam, error) {
 
+	distgoCfg, _, err := loadProjectConfig(
+		distgoConfigFile,
+		godelConfigFile,
+		profiles,
+		projectVersionerFactory,
+		disterFactory,
+		dockerBuilderFactory,
+		publisherFactory,
+	)
+	if err != nil {
+		return distgo.ProjectInfo{}, distgo.ProjectParam{}, err
+	}
+	toParam := distgoCfg.ToParam
+	if !resolveDependencies {
+		toParam = distgoCfg.ToGraphParam
+	}
+	projectParam, err := toParam(projectDir, projectVersionerFactory, disterFactory, defaultDisterCfg, dockerBuilderFactory, publisherFactory)
+	if err != nil {
+		return distgo.ProjectInfo{}, distgo.ProjectParam{}, err
+	}
+	projectInfo, err := projectParam.ProjectInfo(projectDir)
+	if err != nil {
+		return distgo.ProjectInfo{}, distgo.ProjectParam{}, err
+	}
+	return projectInfo, projectParam, nil
+}
+
+// ResolvedDistgoConfigFromFlagVals returns the effective configuration of every product in the project specified by
+// the provided flag values.
+func ResolvedDistgoConfigFromFlagVals(flagValsAndFactories GlobalFlagValsAndFactories) (map[distgo.ProductID]config.ResolvedProductConfig, error) {
+	distgoCfg, legacy, err := loadProjectConfig(
+		flagValsAndFactories.DistgoConfigFileFlagVal,
+		flagValsAndFactories.GodelConfigFileFlagVal,
+		flagValsAndFactories.Profiles(),
+		flagValsAndFactories.CLIProjectVersionerFactory,
+		flagValsAndFactories.CLIDisterFactory,
+		flagValsAndFactories.CLIDockerBuilderFactory,
+		flagValsAndFactories.CLIPublisherFactory,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return distgoCfg.ResolveProductConfigs(flagValsAndFactories.ProjectDirFlagVal, flagValsAndFactories.CLIDefaultDisterCfg, legacy)
+}
+
+// loadProjectConfig loads the distgo configuration from the provided file, if any, including the files that it
+// includes and applying the provided profiles, and adds the excludes specified in the provided godel configuration
+// file, if any. Returns true if the distgo configuration file is legacy configuration.
+func loadProjectConfig(
+	distgoConfigFile,
+	godelConfigFile string,
+	profiles []string,
+	projectVersionerFactory distgo.ProjectVersionerFactory,
+	disterFactory distgo.DisterFactory,
+	dockerBuilderFactory distgo.DockerBuilderFactory,
+	publisherFactory distgo.PublisherFactory,
+) (config.ProjectConfig, bool, error) {
+
 	var distgoCfg config.ProjectConfig
+	var legacy bool
 	if distgoConfigFile != "" {
-		cfg, err := loadConfigFromFile(
+		cfg, cfgIsLegacy, err := loadConfigFromFile(
 			distgoConfigFile,
+			profiles,
 			projectVersionerFactory,
 			disterFactory,
 			dockerBuilderFactory,
 			publisherFactory,
 		)
 		if err != nil {
-			return distgo.ProjectInfo{}, distgo.ProjectParam{}, err
+			return config.ProjectConfig{}, false, err
 		}
-		distgoCfg = cfg
+		distgoCfg, legacy = cfg, cfgIsLegacy
+	} else if len(profiles) > 0 {
+		return config.ProjectConfig{}, false, errors.Errorf("profiles cannot be applied without a configuration file")
 	}
 	if godelConfigFile != "" {
 		excludes, err := godelconfig.ReadGodelConfigExcludesFromFile(godelConfigFile)
 		if err != nil {
-			return distgo.ProjectInfo{}, distgo.ProjectParam{}, err
+			return config.ProjectConfig{}, false, err
 		}
 		distgoCfg.Exclude.Add(excludes)
 	}
-	projectParam, err := distgoCfg.ToParam(projectDir, projectVersionerFactory, disterFactory, defaultDisterCfg, dockerBuilderFactory, publisherFactory)
-	if err != nil {
-		return distgo.ProjectInfo{}, distgo.ProjectParam{}, err
-	}
-	projectInfo, err := projectParam.ProjectInfo(projectDir)
-	if err != nil {
-		return distgo.ProjectInfo{}, distgo.ProjectParam{}, err
-	}
-	return projectInfo, projectParam, nil
+	return distgoCfg, legacy, nil
 }
 
 func loadConfigFromFile(
 	cfgFile string,
+	profiles []string,
 	projectVersionerFactory distgo.ProjectVersionerFactory,
 	disterFactory distgo.DisterFactory,
 	dockerBuilderFactory distgo.DockerBuilderFactory,
 	publisherFactory distgo.PublisherFactory,
-) (config.ProjectConfig, error) {
+) (config.ProjectConfig, bool, error) {
 
 	cfgBytes, err := os.ReadFile(cfgFile)
 	if os.IsNotExist(err) {
-		return config.ProjectConfig{}, nil
+		if len(profiles) > 0 {
+			return config.ProjectConfig{}, false, errors.Errorf("profiles cannot be applied because configuration file %s does not exist", cfgFile)
+		}
+		return config.ProjectConfig{}, false, nil
 	}
 	if err != nil {
-		return config.ProjectConfig{}, errors.Wrapf(err, "failed to read configuration file")
+		return config.ProjectConfig{}, false, errors.Wrapf(err, "failed to read configuration file")
 	}
 	upgradedCfgBytes, err := config.UpgradeConfig(
 		cfgBytes,
@@ -118,12 +215,18 @@ func loadConfigFromFile(
 		publisherFactory,
 	)
 	if err != nil {
-		return config.ProjectConfig{}, errors.Wrapf(err, "failed to upgrade configuration")
+		return config.ProjectConfig{}, false, errors.Wrapf(err, "failed to upgrade configuration")
+	}
+	resolvedCfgBytes, err := config.ResolveIncludesAndProfiles(cfgFile, upgradedCfgBytes, profiles, func(cfgBytes []byte) ([]byte, error) {
+		return config.UpgradeConfig(cfgBytes, projectVersionerFactory, disterFactory, dockerBuilderFactory, publisherFactory)
+	})
+	if err != nil {
+		return config.ProjectConfig{}, false, err
 	}
 
 	var cfg config.ProjectConfig
-	if err := yaml.Unmarshal(upgradedCfgBytes, &cfg); err != nil {
-		return config.ProjectConfig{}, errors.Wrapf(err, "failed to unmarshal configuration")
+	if err := yaml.Unmarshal(resolvedCfgBytes, &cfg); err != nil {
+		return config.ProjectConfig{}, false, errors.Wrapf(err, "failed to unmarshal configuration")
 	}
-	return cfg, nil
+	return cfg, versionedconfig.IsLegacyConfig(cfgBytes), nil
 }
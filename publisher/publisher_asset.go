@@ -21,6 +21,7 @@ import (
 	"strconv"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/internal/assetapi"
 	"github.com/pkg/errors"
 )
 
@@ -83,7 +84,7 @@ func (p *assetPublisher) RunPublish(inputs []distgo.ProductPublishInfo, flagVals
 
 // assetSupportsV2Publish reports whether the asset at assetPath registers the run-publish-v2 command.
 func assetSupportsV2Publish(assetPath string) bool {
-	return exec.Command(assetPath, runPublishV2CmdName, "--help").Run() == nil
+	return assetapi.SupportsCommand(assetPath, runPublishV2CmdName)
 }
 
 // legacyAssetPublisher wraps an assetPublisher to support the legacy per-product publishing.
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/internal/assetapi"
 	"github.com/palantir/distgo/publisher/internal/publishfixtures"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -32,6 +33,20 @@ func TestAssetSupportsV2Publish(t *testing.T) {
 	assert.False(t, assetSupportsV2Publish(legacyOnlyPath))
 }
 
+// TestAssetConfigSchema verifies that assets that predate the config-schema command are detected by the exit code of
+// the command rather than by its output and are treated as not providing a schema.
+func TestAssetConfigSchema(t *testing.T) {
+	v2Path := publishfixtures.Build(t, publishfixtures.V2)
+	legacyOnlyPath := publishfixtures.Build(t, publishfixtures.LegacyOnly)
+
+	assert.True(t, assetapi.SupportsCommand(v2Path, assetapi.ConfigSchemaCommand))
+	assert.False(t, assetapi.SupportsCommand(legacyOnlyPath, assetapi.ConfigSchemaCommand))
+
+	schema, err := assetapi.GetConfigSchema(legacyOnlyPath)
+	require.NoError(t, err)
+	assert.Nil(t, schema)
+}
+
 // TestAssetPublisher_RunPublish verifies that assetPublisher marshals the full batch of inputs once, passes it to
 // the run-publish-v2 command as a flag, and streams the asset's output back.
 func TestAssetPublisher_RunPublish(t *testing.T) {
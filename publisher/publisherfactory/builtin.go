@@ -16,6 +16,7 @@ package publisherfactory
 
 import (
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/distgo/config/configschema"
 	"github.com/palantir/distgo/publisher"
 	"github.com/palantir/distgo/publisher/artifactory"
 	artifactoryconfig "github.com/palantir/distgo/publisher/artifactory/config"
@@ -26,8 +27,9 @@ import (
 )
 
 type creatorWithUpgrader struct {
-	Creator  publisher.Creator
-	Upgrader distgo.ConfigUpgrader
+	Creator      publisher.Creator
+	Upgrader     distgo.ConfigUpgrader
+	ConfigSchema func() ([]byte, error)
 }
 
 func builtinPublishers() map[string]creatorWithUpgrader {
@@ -35,14 +37,23 @@ func builtinPublishers() map[string]creatorWithUpgrader {
 		mavenlocal.TypeName: {
 			Creator:  mavenlocal.PublisherCreator(),
 			Upgrader: distgo.NewConfigUpgrader(mavenlocal.TypeName, mavenlocalconfig.UpgradeConfig),
+			ConfigSchema: func() ([]byte, error) {
+				return configschema.JSON(mavenlocalconfig.MavenLocal{})
+			},
 		},
 		artifactory.TypeName: {
 			Creator:  artifactory.PublisherCreator(),
 			Upgrader: distgo.NewConfigUpgrader(artifactory.TypeName, artifactoryconfig.UpgradeConfig),
+			ConfigSchema: func() ([]byte, error) {
+				return configschema.JSON(artifactoryconfig.Artifactory{})
+			},
 		},
 		github.TypeName: {
 			Creator:  github.PublisherCreator(),
 			Upgrader: distgo.NewConfigUpgrader(github.TypeName, githubconfig.UpgradeConfig),
+			ConfigSchema: func() ([]byte, error) {
+				return configschema.JSON(githubconfig.GitHub{})
+			},
 		},
 	}
 }
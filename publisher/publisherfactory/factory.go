@@ -27,6 +27,7 @@ func New(providedPublisherCreators []publisher.Creator, providedConfigUpgraders
 	seenTypes := make(map[string]struct{})
 	publisherCreators := make(map[string]publisher.Creator)
 	configUpgraders := make(map[string]distgo.ConfigUpgrader)
+	configSchemas := make(map[string]func() ([]byte, error))
 
 	var sortedKeys []string
 	for k := range builtinPublishers() {
@@ -41,6 +42,7 @@ func New(providedPublisherCreators []publisher.Creator, providedConfigUpgraders
 		seenTypes[k] = struct{}{}
 		publisherCreators[k] = v.Creator
 		configUpgraders[k] = v.Upgrader
+		configSchemas[k] = v.ConfigSchema
 	}
 	for _, currCreator := range providedPublisherCreators {
 		if _, ok := seenTypes[currCreator.TypeName()]; ok {
@@ -49,6 +51,9 @@ func New(providedPublisherCreators []publisher.Creator, providedConfigUpgraders
 		seenTypes[currCreator.TypeName()] = struct{}{}
 		types = append(types, currCreator.TypeName())
 		publisherCreators[currCreator.TypeName()] = currCreator
+		if schemaCreator, ok := currCreator.(publisher.ConfigSchemaCreator); ok {
+			configSchemas[currCreator.TypeName()] = schemaCreator.ConfigSchema
+		}
 	}
 	for _, currUpgrader := range providedConfigUpgraders {
 		configUpgraders[currUpgrader.TypeName()] = currUpgrader
@@ -57,6 +62,7 @@ func New(providedPublisherCreators []publisher.Creator, providedConfigUpgraders
 		types:                    types,
 		publisherCreators:        publisherCreators,
 		publisherConfigUpgraders: configUpgraders,
+		publisherConfigSchemas:   configSchemas,
 	}, nil
 }
 
@@ -64,8 +70,11 @@ type publisherFactoryImpl struct {
 	types                    []string
 	publisherCreators        map[string]publisher.Creator
 	publisherConfigUpgraders map[string]distgo.ConfigUpgrader
+	publisherConfigSchemas   map[string]func() ([]byte, error)
 }
 
+var _ distgo.ConfigSchemaProvider = (*publisherFactoryImpl)(nil)
+
 func (f *publisherFactoryImpl) Types() []string {
 	return f.types
 }
@@ -88,3 +97,14 @@ func (f *publisherFactoryImpl) ConfigUpgrader(typeName string) (distgo.ConfigUpg
 	}
 	return upgrader, nil
 }
+
+func (f *publisherFactoryImpl) ConfigSchema(typeName string) ([]byte, error) {
+	if _, ok := f.publisherCreators[typeName]; !ok {
+		return nil, errors.Errorf("no publisher registered for publisher type %q (registered publishers: %v)", typeName, f.types)
+	}
+	configSchemaFn, ok := f.publisherConfigSchemas[typeName]
+	if !ok {
+		return nil, nil
+	}
+	return configSchemaFn()
+}
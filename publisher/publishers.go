@@ -18,6 +18,7 @@ import (
 	"sort"
 
 	"github.com/palantir/distgo/distgo"
+	"github.com/palantir/distgo/internal/assetapi"
 	"github.com/pkg/errors"
 )
 
@@ -46,6 +47,33 @@ func NewCreator(typeName string, publisherCreator func() distgo.Publisher) Creat
 	}
 }
 
+// ConfigSchemaCreator is a Creator that provides the JSON Schema for the configuration of the publisher that it
+// creates.
+type ConfigSchemaCreator interface {
+	Creator
+	ConfigSchema() ([]byte, error)
+}
+
+type configSchemaCreatorStruct struct {
+	creatorStruct
+	configSchema func() ([]byte, error)
+}
+
+func (c *configSchemaCreatorStruct) ConfigSchema() ([]byte, error) {
+	return c.configSchema()
+}
+
+// NewCreatorWithConfigSchema returns a ConfigSchemaCreator whose configuration schema is provided by configSchemaFn.
+func NewCreatorWithConfigSchema(typeName string, publisherCreator func() distgo.Publisher, configSchemaFn func() ([]byte, error)) Creator {
+	return &configSchemaCreatorStruct{
+		creatorStruct: creatorStruct{
+			typeName:  typeName,
+			publisher: publisherCreator,
+		},
+		configSchema: configSchemaFn,
+	}
+}
+
 func AssetPublisherCreators(assetPaths ...string) ([]Creator, []distgo.ConfigUpgrader, error) {
 	var publisherCreators []Creator
 	var configUpgraders []distgo.ConfigUpgrader
@@ -60,7 +88,7 @@ func AssetPublisherCreators(assetPaths ...string) ([]Creator, []distgo.ConfigUpg
 		}
 		publisherNameToAssets[publisherName] = append(publisherNameToAssets[publisherName], currAssetPath)
 		supportsV2Publish := assetSupportsV2Publish(currAssetPath)
-		publisherCreators = append(publisherCreators, NewCreator(publisherName, func() distgo.Publisher {
+		publisherCreators = append(publisherCreators, NewCreatorWithConfigSchema(publisherName, func() distgo.Publisher {
 			if supportsV2Publish {
 				return &assetPublisher{
 					assetPath: currAssetPath,
@@ -71,6 +99,8 @@ func AssetPublisherCreators(assetPaths ...string) ([]Creator, []distgo.ConfigUpg
 					assetPath: currAssetPath,
 				},
 			}
+		}, func() ([]byte, error) {
+			return assetapi.GetConfigSchema(currAssetPath)
 		}))
 		configUpgraders = append(configUpgraders, &assetConfigUpgrader{
 			typeName:  publisherName,